@@ -0,0 +1,302 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/kleister/kleister-api/pkg/backup"
+	"github.com/kleister/kleister-api/pkg/config"
+	"github.com/kleister/kleister-api/pkg/store"
+	"github.com/kleister/kleister-api/pkg/upload"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/urfave/cli.v2"
+)
+
+// Backup provides the sub-command to write a backup archive.
+func Backup(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:   "backup",
+		Usage:  "write a backup archive",
+		Flags:  append(backupFlags(cfg), uploadKeyFlags(cfg)...),
+		Before: backupBefore(cfg),
+		Action: backupAction(cfg),
+	}
+}
+
+// Restore provides the sub-command to restore a backup archive.
+func Restore(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:   "restore",
+		Usage:  "restore a backup archive",
+		Flags:  append(restoreFlags(cfg), uploadKeyFlags(cfg)...),
+		Before: backupBefore(cfg),
+		Action: restoreAction(cfg),
+	}
+}
+
+func archiveFlags(cfg *config.Config) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:        "db-dsn",
+			Value:       "boltdb://kleister.db",
+			Usage:       "database dsn",
+			EnvVars:     []string{"KLEISTER_API_DB_DSN"},
+			Destination: &cfg.Database.DSN,
+		},
+		&cli.StringFlag{
+			Name:        "upload-dsn",
+			Value:       "file://storage/",
+			Usage:       "uploads dsn",
+			EnvVars:     []string{"KLEISTER_API_UPLOAD_DSN"},
+			Destination: &cfg.Upload.DSN,
+		},
+		&cli.BoolFlag{
+			Name:  "uploads",
+			Value: false,
+			Usage: "include the uploaded objects",
+		},
+	}
+}
+
+func backupFlags(cfg *config.Config) []cli.Flag {
+	return append(
+		archiveFlags(cfg),
+		&cli.StringFlag{
+			Name:    "output",
+			Aliases: []string{"o"},
+			Value:   "",
+			Usage:   "path to write the archive, - for stdout",
+		},
+	)
+}
+
+func restoreFlags(cfg *config.Config) []cli.Flag {
+	return append(
+		archiveFlags(cfg),
+		&cli.StringFlag{
+			Name:    "input",
+			Aliases: []string{"i"},
+			Value:   "",
+			Usage:   "path to read the archive",
+		},
+		&cli.BoolFlag{
+			Name:  "force",
+			Value: false,
+			Usage: "overwrite a database or uploads with content",
+		},
+		&cli.BoolFlag{
+			Name:  "migrate",
+			Value: false,
+			Usage: "apply pending migrations on the database",
+		},
+	)
+}
+
+func backupBefore(cfg *config.Config) cli.BeforeFunc {
+	return func(c *cli.Context) error {
+		setupLogger(cfg)
+		return nil
+	}
+}
+
+func backupAction(cfg *config.Config) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		ctx := context.Background()
+
+		if c.String("output") == "" {
+			err := errors.New("--output is required")
+
+			log.Error().
+				Err(err).
+				Msg("missing archive path")
+
+			return err
+		}
+
+		storage, err := setupStorage(cfg)
+
+		if err != nil {
+			log.Error().
+				Err(err).
+				Msg("failed to setup database")
+
+			return err
+		}
+
+		defer storage.Close()
+
+		if pending, err := store.Pending(ctx, storage); err != nil || pending > 0 {
+			if err == nil {
+				err = errors.Errorf("database has %d pending migrations", pending)
+			}
+
+			log.Error().
+				Err(err).
+				Msg("failed to check database")
+
+			return err
+		}
+
+		uploads, err := archiveUploads(c, cfg)
+
+		if err != nil {
+			log.Error().
+				Err(err).
+				Msg("failed to setup uploads")
+
+			return err
+		}
+
+		if uploads != nil {
+			defer uploads.Close()
+		}
+
+		var (
+			w io.Writer = os.Stdout
+		)
+
+		if c.String("output") != "-" {
+			f, err := os.Create(c.String("output"))
+
+			if err != nil {
+				log.Error().
+					Err(err).
+					Msg("failed to create archive")
+
+				return err
+			}
+
+			defer f.Close()
+			w = f
+		}
+
+		m, err := backup.Write(ctx, w, storage, uploads)
+
+		if err != nil {
+			log.Error().
+				Err(err).
+				Msg("failed to write archive")
+
+			if c.String("output") != "-" {
+				os.Remove(c.String("output"))
+			}
+
+			return err
+		}
+
+		log.Info().
+			Int("files", len(m.Files)).
+			Msg("successfully wrote archive")
+
+		return nil
+	}
+}
+
+func restoreAction(cfg *config.Config) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		ctx := context.Background()
+
+		if c.String("input") == "" {
+			err := errors.New("--input is required")
+
+			log.Error().
+				Err(err).
+				Msg("missing archive path")
+
+			return err
+		}
+
+		f, err := os.Open(c.String("input"))
+
+		if err != nil {
+			log.Error().
+				Err(err).
+				Msg("failed to open archive")
+
+			return err
+		}
+
+		defer f.Close()
+
+		storage, err := setupStorage(cfg)
+
+		if err != nil {
+			log.Error().
+				Err(err).
+				Msg("failed to setup database")
+
+			return err
+		}
+
+		defer storage.Close()
+
+		if pending, err := store.Pending(ctx, storage); err != nil || pending > 0 {
+			if err == nil && !c.Bool("migrate") {
+				err = errors.Errorf("database has %d pending migrations, run the migrate command or enable --migrate", pending)
+			}
+
+			if err == nil {
+				var records []*store.Migration
+
+				records, err = storage.Migrations().Up(ctx, false)
+				logMigrations(records, false, "applied migration")
+			}
+
+			if err != nil {
+				log.Error().
+					Err(err).
+					Msg("failed to migrate database")
+
+				return err
+			}
+		}
+
+		uploads, err := archiveUploads(c, cfg)
+
+		if err != nil {
+			log.Error().
+				Err(err).
+				Msg("failed to setup uploads")
+
+			return err
+		}
+
+		if uploads != nil {
+			defer uploads.Close()
+		}
+
+		m, err := backup.Restore(ctx, f, storage, uploads, c.Bool("force"))
+
+		if err != nil {
+			if errors.Cause(err) == backup.ErrNotEmpty {
+				log.Error().
+					Err(err).
+					Msg("refused to restore archive, enable --force to overwrite")
+			} else {
+				log.Error().
+					Err(err).
+					Msg("failed to restore archive")
+			}
+
+			return err
+		}
+
+		log.Info().
+			Time("created", m.CreatedAt).
+			Int("files", len(m.Files)).
+			Msg("successfully restored archive")
+
+		return nil
+	}
+}
+
+// archiveUploads opens the uploaded objects if they should be included.
+func archiveUploads(c *cli.Context, cfg *config.Config) (upload.Upload, error) {
+	if !c.Bool("uploads") {
+		return nil, nil
+	}
+
+	return setupUploads(cfg)
+}
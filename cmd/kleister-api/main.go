@@ -80,6 +80,12 @@ func globalFlags(cfg *config.Config) []cli.Flag {
 func globalCommands(cfg *config.Config) []*cli.Command {
 	return []*cli.Command{
 		Server(cfg),
+		Migrate(cfg),
+		Store(cfg),
+		Storage(cfg),
+		Backup(cfg),
+		Restore(cfg),
+		Solder(cfg),
 		Health(cfg),
 	}
 }
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kleister/kleister-api/pkg/config"
+	"github.com/kleister/kleister-api/pkg/store"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/urfave/cli.v2"
+)
+
+// Migrate provides the sub-command to manage the database schema.
+func Migrate(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:   "migrate",
+		Usage:  "manage database migrations",
+		Before: migrateBefore(cfg),
+		Subcommands: []*cli.Command{
+			{
+				Name:   "up",
+				Usage:  "apply all pending migrations",
+				Flags:  migrateFlags(cfg, true),
+				Action: migrateUpAction(cfg),
+			},
+			{
+				Name:   "down",
+				Usage:  "revert the latest migration",
+				Flags:  migrateFlags(cfg, true),
+				Action: migrateDownAction(cfg),
+			},
+			{
+				Name:   "status",
+				Usage:  "list all migrations",
+				Flags:  migrateFlags(cfg, false),
+				Action: migrateStatusAction(cfg),
+			},
+		},
+	}
+}
+
+func migrateFlags(cfg *config.Config, dry bool) []cli.Flag {
+	flags := []cli.Flag{
+		&cli.StringFlag{
+			Name:        "db-dsn",
+			Value:       "boltdb://kleister.db",
+			Usage:       "database dsn",
+			EnvVars:     []string{"KLEISTER_API_DB_DSN"},
+			Destination: &cfg.Database.DSN,
+		},
+	}
+
+	if dry {
+		flags = append(flags, &cli.BoolFlag{
+			Name:  "dry-run",
+			Value: false,
+			Usage: "only list the affected migrations",
+		})
+	}
+
+	return flags
+}
+
+func migrateBefore(cfg *config.Config) cli.BeforeFunc {
+	return func(c *cli.Context) error {
+		setupLogger(cfg)
+		return nil
+	}
+}
+
+func migrateUpAction(cfg *config.Config) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		storage, err := setupStorage(cfg)
+
+		if err != nil {
+			log.Error().
+				Err(err).
+				Msg("failed to setup database")
+
+			return err
+		}
+
+		defer storage.Close()
+
+		records, err := storage.Migrations().Up(context.Background(), c.Bool("dry-run"))
+		logMigrations(records, c.Bool("dry-run"), "applied migration")
+
+		if err != nil {
+			log.Error().
+				Err(err).
+				Msg("failed to apply migrations")
+
+			return err
+		}
+
+		if len(records) == 0 {
+			log.Info().
+				Msg("database is already up to date")
+		}
+
+		return nil
+	}
+}
+
+func migrateDownAction(cfg *config.Config) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		storage, err := setupStorage(cfg)
+
+		if err != nil {
+			log.Error().
+				Err(err).
+				Msg("failed to setup database")
+
+			return err
+		}
+
+		defer storage.Close()
+
+		records, err := storage.Migrations().Down(context.Background(), c.Bool("dry-run"))
+		logMigrations(records, c.Bool("dry-run"), "reverted migration")
+
+		if err != nil {
+			log.Error().
+				Err(err).
+				Msg("failed to revert migration")
+
+			return err
+		}
+
+		if len(records) == 0 {
+			log.Info().
+				Msg("no migration has been applied yet")
+		}
+
+		return nil
+	}
+}
+
+func migrateStatusAction(cfg *config.Config) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		storage, err := setupStorage(cfg)
+
+		if err != nil {
+			log.Error().
+				Err(err).
+				Msg("failed to setup database")
+
+			return err
+		}
+
+		defer storage.Close()
+
+		records, err := storage.Migrations().Status(context.Background())
+
+		if err != nil {
+			log.Error().
+				Err(err).
+				Msg("failed to fetch migrations")
+
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED")
+
+		for _, record := range records {
+			applied := "pending"
+
+			if record.Applied() {
+				applied = record.AppliedAt.Format(time.RFC3339)
+			}
+
+			fmt.Fprintf(w, "%d\t%s\t%s\n", record.Version, record.Name, applied)
+		}
+
+		return w.Flush()
+	}
+}
+
+func logMigrations(records []*store.Migration, dry bool, msg string) {
+	for _, record := range records {
+		log.Info().
+			Int("version", record.Version).
+			Str("name", record.Name).
+			Bool("dry", dry).
+			Msg(msg)
+	}
+}
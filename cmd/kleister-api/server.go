@@ -8,7 +8,10 @@ import (
 	"time"
 
 	"github.com/kleister/kleister-api/pkg/config"
+	"github.com/kleister/kleister-api/pkg/gc"
 	"github.com/kleister/kleister-api/pkg/router"
+	"github.com/kleister/kleister-api/pkg/store"
+	"github.com/kleister/kleister-api/pkg/upload"
 	"github.com/oklog/oklog/pkg/group"
 	"github.com/rs/zerolog/log"
 	"gopkg.in/urfave/cli.v2"
@@ -19,7 +22,7 @@ func Server(cfg *config.Config) *cli.Command {
 	return &cli.Command{
 		Name:   "server",
 		Usage:  "start integrated server",
-		Flags:  serverFlags(cfg),
+		Flags:  append(serverFlags(cfg), uploadKeyFlags(cfg)...),
 		Before: serverBefore(cfg),
 		Action: serverAction(cfg),
 	}
@@ -83,6 +86,13 @@ func serverFlags(cfg *config.Config) []cli.Flag {
 			EnvVars:     []string{"KLEISTER_API_DB_DSN"},
 			Destination: &cfg.Database.DSN,
 		},
+		&cli.BoolFlag{
+			Name:        "db-migrate",
+			Value:       false,
+			Usage:       "apply pending migrations on start",
+			EnvVars:     []string{"KLEISTER_API_DB_MIGRATE"},
+			Destination: &cfg.Database.Migrate,
+		},
 		&cli.StringFlag{
 			Name:        "upload-dsn",
 			Value:       "file://storage/",
@@ -90,6 +100,48 @@ func serverFlags(cfg *config.Config) []cli.Flag {
 			EnvVars:     []string{"KLEISTER_API_UPLOAD_DSN"},
 			Destination: &cfg.Upload.DSN,
 		},
+		&cli.DurationFlag{
+			Name:        "upload-gc-interval",
+			Value:       0,
+			Usage:       "interval to remove orphaned uploads, disabled if zero",
+			EnvVars:     []string{"KLEISTER_API_UPLOAD_GC_INTERVAL"},
+			Destination: &cfg.Upload.GCInterval,
+		},
+		&cli.DurationFlag{
+			Name:        "upload-gc-grace",
+			Value:       24 * time.Hour,
+			Usage:       "keep orphaned uploads which have been modified within this period",
+			EnvVars:     []string{"KLEISTER_API_UPLOAD_GC_GRACE"},
+			Destination: &cfg.Upload.GCGrace,
+		},
+		&cli.DurationFlag{
+			Name:        "upload-expire",
+			Value:       time.Hour,
+			Usage:       "lifetime of signed download URLs for private and hidden packs",
+			EnvVars:     []string{"KLEISTER_API_UPLOAD_EXPIRE"},
+			Destination: &cfg.Upload.Expire,
+		},
+		&cli.StringFlag{
+			Name:        "token-secret",
+			Value:       "",
+			Usage:       "base32 encoded secret to sign tokens, generated on start if empty",
+			EnvVars:     []string{"KLEISTER_API_TOKEN_SECRET"},
+			Destination: &cfg.Token.Secret,
+		},
+		&cli.Int64Flag{
+			Name:        "quota-user",
+			Value:       0,
+			Usage:       "default storage quota of users in bytes, disabled if zero",
+			EnvVars:     []string{"KLEISTER_API_QUOTA_USER"},
+			Destination: &cfg.Quota.User,
+		},
+		&cli.Int64Flag{
+			Name:        "quota-team",
+			Value:       0,
+			Usage:       "default storage quota of teams in bytes, disabled if zero",
+			EnvVars:     []string{"KLEISTER_API_QUOTA_TEAM"},
+			Destination: &cfg.Quota.Team,
+		},
 		&cli.BoolFlag{
 			Name:        "admin-create",
 			Value:       true,
@@ -118,6 +170,13 @@ func serverFlags(cfg *config.Config) []cli.Flag {
 			EnvVars:     []string{"KLEISTER_API_ADMIN_EMAIL"},
 			Destination: &cfg.Admin.Email,
 		},
+		&cli.StringFlag{
+			Name:        "admin-token",
+			Value:       "",
+			Usage:       "token to access admin endpoints like backups",
+			EnvVars:     []string{"KLEISTER_API_ADMIN_TOKEN"},
+			Destination: &cfg.Admin.Token,
+		},
 		&cli.BoolFlag{
 			Name:        "tracing-enabled",
 			Value:       false,
@@ -156,6 +215,12 @@ func serverAction(cfg *config.Config) cli.ActionFunc {
 			defer tracing.Close()
 		}
 
+		if err := setupSecret(cfg); err != nil {
+			log.Fatal().
+				Err(err).
+				Msg("failed to setup token secret")
+		}
+
 		storage, err := setupStorage(cfg)
 
 		if err != nil {
@@ -168,6 +233,12 @@ func serverAction(cfg *config.Config) cli.ActionFunc {
 			defer storage.Close()
 		}
 
+		if err := setupMigrations(cfg, storage); err != nil {
+			log.Fatal().
+				Err(err).
+				Msg("failed to migrate database")
+		}
+
 		uploads, err := setupUploads(cfg)
 
 		if err != nil {
@@ -249,6 +320,35 @@ func serverAction(cfg *config.Config) cli.ActionFunc {
 			})
 		}
 
+		if cfg.Upload.GCInterval > 0 {
+			stop := make(chan struct{})
+
+			gr.Add(func() error {
+				log.Info().
+					Dur("interval", cfg.Upload.GCInterval).
+					Dur("grace", cfg.Upload.GCGrace).
+					Msg("starting upload collector")
+
+				ticker := time.NewTicker(cfg.Upload.GCInterval)
+				defer ticker.Stop()
+
+				for {
+					select {
+					case <-ticker.C:
+						collectUploads(cfg, storage, uploads)
+					case <-stop:
+						return nil
+					}
+				}
+			}, func(reason error) {
+				close(stop)
+
+				log.Info().
+					Err(reason).
+					Msg("upload collector stopped")
+			})
+		}
+
 		{
 			stop := make(chan os.Signal, 1)
 
@@ -266,3 +366,24 @@ func serverAction(cfg *config.Config) cli.ActionFunc {
 		return gr.Run()
 	}
 }
+
+// collectUploads deletes the expired orphans of the uploads, failures only
+// get logged as the next run simply tries again.
+func collectUploads(cfg *config.Config, storage store.Store, uploads upload.Upload) {
+	report, err := gc.Collect(context.Background(), storage, uploads, cfg.Upload.GCGrace, true)
+
+	if err != nil {
+		log.Error().
+			Err(err).
+			Msg("failed to collect orphaned uploads")
+
+		return
+	}
+
+	log.Info().
+		Int("orphans", len(report.Orphans)).
+		Int64("size", report.Size).
+		Int("deleted", report.Deleted).
+		Int64("freed", report.Freed).
+		Msg("collected orphaned uploads")
+}
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"io"
+	"io/ioutil"
 	"net/url"
 	"os"
 	"strings"
@@ -9,11 +11,15 @@ import (
 	"github.com/kleister/kleister-api/pkg/config"
 	"github.com/kleister/kleister-api/pkg/store"
 	"github.com/kleister/kleister-api/pkg/store/boltdb"
+	"github.com/kleister/kleister-api/pkg/store/memory"
 	"github.com/kleister/kleister-api/pkg/store/mysql"
 	"github.com/kleister/kleister-api/pkg/store/postgres"
+	"github.com/kleister/kleister-api/pkg/token"
 	"github.com/kleister/kleister-api/pkg/upload"
 	"github.com/kleister/kleister-api/pkg/upload/file"
 	"github.com/kleister/kleister-api/pkg/upload/s3"
+	"github.com/kleister/kleister-api/pkg/upload/sftp"
+	"github.com/kleister/kleister-api/pkg/upload/webdav"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
@@ -80,26 +86,95 @@ func setupTracing(cfg *config.Config) (io.Closer, error) {
 }
 
 func setupUploads(cfg *config.Config) (upload.Upload, error) {
-	parsed, err := url.Parse(cfg.Upload.DSN)
+	return openUploads(cfg, cfg.Upload.DSN)
+}
+
+func openUploads(cfg *config.Config, dsn string) (upload.Upload, error) {
+	parsed, err := url.Parse(dsn)
 
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to parse dsn")
 	}
 
+	keys, err := setupKeys(cfg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(keys) > 0 && parsed.Scheme != "file" {
+		log.Warn().
+			Str("driver", parsed.Scheme).
+			Msg("encryption keys are only supported by the file driver")
+	}
+
 	switch parsed.Scheme {
 	case "file":
-		return file.New(parsed)
+		return file.New(parsed, keys...)
 	case "s3":
 		return s3.New(parsed)
 	case "minio":
 		return s3.New(parsed)
+	case "sftp":
+		return sftp.New(parsed)
+	case "webdav":
+		return webdav.New(parsed)
 	}
 
 	return nil, upload.ErrUnknownDriver
 }
 
+// setupKeys loads the encryption keys of the uploads, the current key comes
+// first while the previous key is only used to decrypt until it's rotated.
+func setupKeys(cfg *config.Config) ([][]byte, error) {
+	keys := make([][]byte, 0, 2)
+
+	for _, source := range []struct {
+		name  string
+		value string
+		path  string
+	}{
+		{"upload key", cfg.Upload.Key, cfg.Upload.KeyFile},
+		{"previous upload key", cfg.Upload.PreviousKey, cfg.Upload.PreviousKeyFile},
+	} {
+		value := source.value
+
+		if source.path != "" {
+			content, err := ioutil.ReadFile(source.path)
+
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to read %s", source.name)
+			}
+
+			value = string(content)
+		}
+
+		if value == "" {
+			continue
+		}
+
+		key, err := file.Key(value)
+
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %s", source.name)
+		}
+
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 1 && cfg.Upload.Key == "" && cfg.Upload.KeyFile == "" {
+		return nil, errors.New("previous upload key requires an upload key")
+	}
+
+	return keys, nil
+}
+
 func setupStorage(cfg *config.Config) (store.Store, error) {
-	parsed, err := url.Parse(cfg.Database.DSN)
+	return openStorage(cfg.Database.DSN)
+}
+
+func openStorage(dsn string) (store.Store, error) {
+	parsed, err := url.Parse(dsn)
 
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to parse dsn")
@@ -114,7 +189,50 @@ func setupStorage(cfg *config.Config) (store.Store, error) {
 		return mysql.New(parsed)
 	case "mariadb":
 		return mysql.New(parsed)
+	case "memory":
+		return memory.New(parsed)
 	}
 
 	return nil, store.ErrUnknownDriver
 }
+
+func setupMigrations(cfg *config.Config, storage store.Store) error {
+	ctx := context.Background()
+	pending, err := store.Pending(ctx, storage)
+
+	if err != nil {
+		return errors.Wrap(err, "failed to check migrations")
+	}
+
+	if pending == 0 {
+		return nil
+	}
+
+	if !cfg.Database.Migrate {
+		return errors.Errorf("database has %d pending migrations, run the migrate command or enable db-migrate", pending)
+	}
+
+	records, err := storage.Migrations().Up(ctx, false)
+	logMigrations(records, false, "applied migration")
+
+	return err
+}
+
+func setupSecret(cfg *config.Config) error {
+	if cfg.Token.Secret != "" {
+		_, err := token.Key(cfg.Token.Secret)
+		return err
+	}
+
+	secret, err := token.Generate()
+
+	if err != nil {
+		return err
+	}
+
+	log.Warn().
+		Msg("generated a token secret, signed download URLs get invalid on restart")
+
+	cfg.Token.Secret = secret
+	return nil
+}
@@ -0,0 +1,496 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kleister/kleister-api/pkg/config"
+	"github.com/kleister/kleister-api/pkg/gc"
+	"github.com/kleister/kleister-api/pkg/store"
+	"github.com/kleister/kleister-api/pkg/transfer"
+	"github.com/kleister/kleister-api/pkg/upload/file"
+	"github.com/kleister/kleister-api/pkg/verify"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/urfave/cli.v2"
+)
+
+// Storage provides the sub-command to manage the uploaded objects.
+func Storage(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:   "storage",
+		Usage:  "manage the uploaded objects",
+		Before: storageBefore(cfg),
+		Subcommands: []*cli.Command{
+			{
+				Name:   "gc",
+				Usage:  "remove objects which are not referenced anymore",
+				Flags:  append(storageGCFlags(cfg), uploadKeyFlags(cfg)...),
+				Action: storageGCAction(cfg),
+			},
+			{
+				Name:   "copy",
+				Usage:  "copy all objects to another storage",
+				Flags:  append(storageCopyFlags(cfg), uploadKeyFlags(cfg)...),
+				Action: storageCopyAction(cfg),
+			},
+			{
+				Name:   "rotate",
+				Usage:  "re-encrypt all objects of the file driver by the upload key",
+				Flags:  append(storageRotateFlags(cfg), uploadKeyFlags(cfg)...),
+				Action: storageRotateAction(cfg),
+			},
+			{
+				Name:   "verify",
+				Usage:  "compare all referenced objects with the stored checksums",
+				Flags:  append(storageVerifyFlags(cfg), uploadKeyFlags(cfg)...),
+				Action: storageVerifyAction(cfg),
+			},
+		},
+	}
+}
+
+func storageFlags(cfg *config.Config) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:        "db-dsn",
+			Value:       "boltdb://kleister.db",
+			Usage:       "database dsn",
+			EnvVars:     []string{"KLEISTER_API_DB_DSN"},
+			Destination: &cfg.Database.DSN,
+		},
+		&cli.StringFlag{
+			Name:        "upload-dsn",
+			Value:       "file://storage/",
+			Usage:       "uploads dsn",
+			EnvVars:     []string{"KLEISTER_API_UPLOAD_DSN"},
+			Destination: &cfg.Upload.DSN,
+		},
+	}
+}
+
+func storageGCFlags(cfg *config.Config) []cli.Flag {
+	return append(
+		storageFlags(cfg),
+		&cli.DurationFlag{
+			Name:        "grace",
+			Value:       24 * time.Hour,
+			Usage:       "keep orphans which have been modified within this period",
+			EnvVars:     []string{"KLEISTER_API_UPLOAD_GC_GRACE"},
+			Destination: &cfg.Upload.GCGrace,
+		},
+		&cli.BoolFlag{
+			Name:  "apply",
+			Value: false,
+			Usage: "delete the expired orphans instead of only reporting them",
+		},
+	)
+}
+
+func storageVerifyFlags(cfg *config.Config) []cli.Flag {
+	return append(
+		storageFlags(cfg),
+		&cli.BoolFlag{
+			Name:  "mark",
+			Value: false,
+			Usage: "mark the versions of damaged objects as broken",
+		},
+	)
+}
+
+func storageRotateFlags(cfg *config.Config) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:        "upload-dsn",
+			Value:       "file://storage/",
+			Usage:       "uploads dsn",
+			EnvVars:     []string{"KLEISTER_API_UPLOAD_DSN"},
+			Destination: &cfg.Upload.DSN,
+		},
+	}
+}
+
+func uploadKeyFlags(cfg *config.Config) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:        "upload-key",
+			Value:       "",
+			Usage:       "base64 encoded key to encrypt uploads of the file driver",
+			EnvVars:     []string{"KLEISTER_API_UPLOAD_KEY"},
+			Destination: &cfg.Upload.Key,
+		},
+		&cli.StringFlag{
+			Name:        "upload-key-file",
+			Value:       "",
+			Usage:       "path to the file containing the upload key",
+			EnvVars:     []string{"KLEISTER_API_UPLOAD_KEY_FILE"},
+			Destination: &cfg.Upload.KeyFile,
+		},
+		&cli.StringFlag{
+			Name:        "upload-previous-key",
+			Value:       "",
+			Usage:       "base64 encoded key to decrypt uploads until they are rotated",
+			EnvVars:     []string{"KLEISTER_API_UPLOAD_PREVIOUS_KEY"},
+			Destination: &cfg.Upload.PreviousKey,
+		},
+		&cli.StringFlag{
+			Name:        "upload-previous-key-file",
+			Value:       "",
+			Usage:       "path to the file containing the previous upload key",
+			EnvVars:     []string{"KLEISTER_API_UPLOAD_PREVIOUS_KEY_FILE"},
+			Destination: &cfg.Upload.PreviousKeyFile,
+		},
+	}
+}
+
+func storageCopyFlags(cfg *config.Config) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "from",
+			Value: "",
+			Usage: "uploads dsn to copy from",
+		},
+		&cli.StringFlag{
+			Name:  "to",
+			Value: "",
+			Usage: "uploads dsn to copy to",
+		},
+		&cli.IntFlag{
+			Name:  "concurrency",
+			Value: 4,
+			Usage: "number of objects to copy in parallel",
+		},
+	}
+}
+
+func storageBefore(cfg *config.Config) cli.BeforeFunc {
+	return func(c *cli.Context) error {
+		setupLogger(cfg)
+		return nil
+	}
+}
+
+func storageGCAction(cfg *config.Config) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		ctx := context.Background()
+
+		storage, err := setupStorage(cfg)
+
+		if err != nil {
+			log.Error().
+				Err(err).
+				Msg("failed to setup database")
+
+			return err
+		}
+
+		defer storage.Close()
+
+		if pending, err := store.Pending(ctx, storage); err != nil || pending > 0 {
+			if err == nil {
+				err = errors.Errorf("database has %d pending migrations, run the migrate command", pending)
+			}
+
+			log.Error().
+				Err(err).
+				Msg("failed to check database")
+
+			return err
+		}
+
+		uploads, err := setupUploads(cfg)
+
+		if err != nil {
+			log.Error().
+				Err(err).
+				Msg("failed to setup uploads")
+
+			return err
+		}
+
+		defer uploads.Close()
+
+		report, err := gc.Collect(ctx, storage, uploads, cfg.Upload.GCGrace, c.Bool("apply"))
+
+		if report != nil {
+			if err := printOrphans(report); err != nil {
+				return err
+			}
+		}
+
+		if err != nil {
+			log.Error().
+				Err(err).
+				Msg("failed to collect orphans")
+
+			return err
+		}
+
+		if !c.Bool("apply") {
+			log.Info().
+				Int("orphans", len(report.Orphans)).
+				Int64("size", report.Size).
+				Msg("found orphans, run it with --apply to delete the expired ones")
+
+			return nil
+		}
+
+		log.Info().
+			Int("orphans", len(report.Orphans)).
+			Int64("size", report.Size).
+			Int("deleted", report.Deleted).
+			Int64("freed", report.Freed).
+			Msg("deleted expired orphans")
+
+		return nil
+	}
+}
+
+func storageCopyAction(cfg *config.Config) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		ctx := context.Background()
+
+		if c.String("from") == "" || c.String("to") == "" {
+			return errors.New("both --from and --to are required")
+		}
+
+		if c.String("from") == c.String("to") {
+			return errors.New("source and target must differ")
+		}
+
+		from, err := openUploads(cfg, c.String("from"))
+
+		if err != nil {
+			log.Error().
+				Err(err).
+				Msg("failed to setup source uploads")
+
+			return err
+		}
+
+		defer from.Close()
+
+		to, err := openUploads(cfg, c.String("to"))
+
+		if err != nil {
+			log.Error().
+				Err(err).
+				Msg("failed to setup target uploads")
+
+			return err
+		}
+
+		defer to.Close()
+
+		log.Info().
+			Str("from", from.Info()).
+			Str("to", to.Info()).
+			Msg("copying objects")
+
+		report, err := transfer.Copy(ctx, from, to, c.Int("concurrency"), printTransfer)
+
+		if err != nil {
+			log.Error().
+				Err(err).
+				Msg("failed to copy objects, run it again to resume")
+
+			return err
+		}
+
+		log.Info().
+			Int("total", report.Total).
+			Int("copied", report.Copied).
+			Int("skipped", report.Skipped).
+			Int64("size", report.Size).
+			Msg("copied objects")
+
+		return nil
+	}
+}
+
+func storageRotateAction(cfg *config.Config) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		ctx := context.Background()
+
+		uploads, err := setupUploads(cfg)
+
+		if err != nil {
+			log.Error().
+				Err(err).
+				Msg("failed to setup uploads")
+
+			return err
+		}
+
+		defer uploads.Close()
+
+		objects, err := uploads.List(ctx, "")
+
+		if err != nil {
+			log.Error().
+				Err(err).
+				Msg("failed to list uploads")
+
+			return err
+		}
+
+		rotated := 0
+
+		for i, obj := range objects {
+			changed, err := file.Rotate(ctx, uploads, obj.Key)
+
+			if err != nil {
+				log.Error().
+					Err(err).
+					Str("key", obj.Key).
+					Msg("failed to rotate object, run it again to resume")
+
+				return err
+			}
+
+			state := "unchanged"
+
+			if changed {
+				state = "rotated"
+				rotated++
+			}
+
+			fmt.Fprintf(os.Stdout, "[%d/%d] %s %s\n", i+1, len(objects), state, obj.Key)
+		}
+
+		log.Info().
+			Int("total", len(objects)).
+			Int("rotated", rotated).
+			Msg("rotated objects")
+
+		return nil
+	}
+}
+
+func storageVerifyAction(cfg *config.Config) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		ctx := context.Background()
+
+		storage, err := setupStorage(cfg)
+
+		if err != nil {
+			log.Error().
+				Err(err).
+				Msg("failed to setup database")
+
+			return err
+		}
+
+		defer storage.Close()
+
+		if pending, err := store.Pending(ctx, storage); err != nil || pending > 0 {
+			if err == nil {
+				err = errors.Errorf("database has %d pending migrations, run the migrate command", pending)
+			}
+
+			log.Error().
+				Err(err).
+				Msg("failed to check database")
+
+			return err
+		}
+
+		uploads, err := setupUploads(cfg)
+
+		if err != nil {
+			log.Error().
+				Err(err).
+				Msg("failed to setup uploads")
+
+			return err
+		}
+
+		defer uploads.Close()
+
+		report, err := verify.Verify(ctx, storage, uploads, c.Bool("mark"), printVerify)
+
+		if err != nil {
+			log.Error().
+				Err(err).
+				Msg("failed to verify objects")
+
+			return err
+		}
+
+		log.Info().
+			Int("total", report.Total).
+			Int("ok", report.OK).
+			Int("missing", report.Missing).
+			Int("truncated", report.Truncated).
+			Int("altered", report.Altered).
+			Int("failed", report.Failed).
+			Int("marked", report.Marked).
+			Msg("verified objects")
+
+		if report.Damaged() > 0 || report.Failed > 0 {
+			return errors.Errorf("found %d damaged and %d unreadable objects", report.Damaged(), report.Failed)
+		}
+
+		return nil
+	}
+}
+
+func printTransfer(entry *transfer.Entry) {
+	if entry.Err != nil {
+		fmt.Fprintf(os.Stdout, "[%d/%d] %s %s: %s\n", entry.Position, entry.Total, entry.State, entry.Key, entry.Err)
+		return
+	}
+
+	fmt.Fprintf(os.Stdout, "[%d/%d] %s %s (%d bytes)\n", entry.Position, entry.Total, entry.State, entry.Key, entry.Size)
+}
+
+func printVerify(result *verify.Result) {
+	state := result.State
+
+	if result.Marked {
+		state = state + ", marked"
+	}
+
+	switch {
+	case result.Err != nil:
+		fmt.Fprintf(os.Stdout, "[%d/%d] %s %s %s (%s): %s\n", result.Position, result.Total, state, result.Kind, result.Owner, result.Key, result.Err)
+	case result.State == verify.StateMissing:
+		fmt.Fprintf(os.Stdout, "[%d/%d] %s %s %s (%s)\n", result.Position, result.Total, state, result.Kind, result.Owner, result.Key)
+	case result.Expected < 0:
+		fmt.Fprintf(os.Stdout, "[%d/%d] %s %s %s (%s, %d bytes)\n", result.Position, result.Total, state, result.Kind, result.Owner, result.Key, result.Size)
+	default:
+		fmt.Fprintf(os.Stdout, "[%d/%d] %s %s %s (%s, %d of %d bytes)\n", result.Position, result.Total, state, result.Kind, result.Owner, result.Key, result.Size, result.Expected)
+	}
+}
+
+func printOrphans(report *gc.Report) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KIND\tKEY\tSIZE\tMODIFIED\tSTATE")
+
+	for _, orphan := range report.Orphans {
+		state := "pending"
+
+		switch {
+		case orphan.Deleted:
+			state = "deleted"
+		case orphan.Expired:
+			state = "expired"
+		}
+
+		fmt.Fprintf(
+			w,
+			"%s\t%s\t%d\t%s\t%s\n",
+			orphan.Kind,
+			orphan.Key,
+			orphan.Size,
+			orphan.ModifiedAt.Format(time.RFC3339),
+			state,
+		)
+	}
+
+	fmt.Fprintf(w, "TOTAL\t\t%d\t\t\n", report.Size)
+	return w.Flush()
+}
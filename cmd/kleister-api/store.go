@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"strings"
+
+	"github.com/kleister/kleister-api/pkg/config"
+	"github.com/kleister/kleister-api/pkg/store"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/urfave/cli.v2"
+)
+
+// Store provides the sub-command to manage the stored data.
+func Store(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:   "store",
+		Usage:  "manage the stored data",
+		Before: storeBefore(cfg),
+		Subcommands: []*cli.Command{
+			{
+				Name:   "copy",
+				Usage:  "copy all records between databases",
+				Flags:  storeCopyFlags(cfg),
+				Action: storeCopyAction(cfg),
+			},
+		},
+	}
+}
+
+func storeCopyFlags(cfg *config.Config) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "from",
+			Value: "",
+			Usage: "dsn of the source database",
+		},
+		&cli.StringFlag{
+			Name:  "to",
+			Value: "",
+			Usage: "dsn of the target database",
+		},
+		&cli.BoolFlag{
+			Name:  "migrate",
+			Value: false,
+			Usage: "apply pending migrations on the target",
+		},
+	}
+}
+
+func storeBefore(cfg *config.Config) cli.BeforeFunc {
+	return func(c *cli.Context) error {
+		setupLogger(cfg)
+		return nil
+	}
+}
+
+func storeCopyAction(cfg *config.Config) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		ctx := context.Background()
+
+		if c.String("from") == "" || c.String("to") == "" {
+			err := errors.New("both --from and --to are required")
+
+			log.Error().
+				Err(err).
+				Msg("missing database dsn")
+
+			return err
+		}
+
+		src, err := openStorage(c.String("from"))
+
+		if err != nil {
+			log.Error().
+				Err(err).
+				Msg("failed to setup source database")
+
+			return err
+		}
+
+		defer src.Close()
+
+		dst, err := openStorage(c.String("to"))
+
+		if err != nil {
+			log.Error().
+				Err(err).
+				Msg("failed to setup target database")
+
+			return err
+		}
+
+		defer dst.Close()
+
+		if pending, err := store.Pending(ctx, src); err != nil || pending > 0 {
+			if err == nil {
+				err = errors.Errorf("source database has %d pending migrations", pending)
+			}
+
+			log.Error().
+				Err(err).
+				Msg("failed to check source database")
+
+			return err
+		}
+
+		if pending, err := store.Pending(ctx, dst); err != nil || pending > 0 {
+			if err == nil && !c.Bool("migrate") {
+				err = errors.Errorf("target database has %d pending migrations, run the migrate command or enable --migrate", pending)
+			}
+
+			if err == nil {
+				var records []*store.Migration
+
+				records, err = dst.Migrations().Up(ctx, false)
+				logMigrations(records, false, "applied migration")
+			}
+
+			if err != nil {
+				log.Error().
+					Err(err).
+					Msg("failed to migrate target database")
+
+				return err
+			}
+		}
+
+		expected, err := store.Copy(ctx, src, dst, func(name string, copied, skipped int) {
+			log.Info().
+				Str("name", name).
+				Int("copied", copied).
+				Int("skipped", skipped).
+				Msg("copied records")
+		})
+
+		if err != nil {
+			log.Error().
+				Err(err).
+				Msg("failed to copy records, simply run it again to resume")
+
+			return err
+		}
+
+		actual, err := store.Count(ctx, dst)
+
+		if err != nil {
+			log.Error().
+				Err(err).
+				Msg("failed to count target records")
+
+			return err
+		}
+
+		if diff := expected.Diff(actual); len(diff) > 0 {
+			err := errors.Errorf("record counts differ for %s", strings.Join(diff, ", "))
+
+			log.Error().
+				Err(err).
+				Msg("failed to verify copy")
+
+			return err
+		}
+
+		log.Info().
+			Msg("successfully copied all records")
+
+		return nil
+	}
+}
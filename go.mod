@@ -4,6 +4,7 @@ go 1.12
 
 require (
 	github.com/BurntSushi/toml v0.3.1 // indirect
+	github.com/aws/aws-sdk-go v1.19.45
 	github.com/dgrijalva/jwt-go v3.2.0+incompatible
 	github.com/elazarl/go-bindata-assetfs v1.0.0 // indirect
 	github.com/go-chi/chi v4.0.2+incompatible
@@ -15,16 +16,21 @@ require (
 	github.com/go-openapi/strfmt v0.19.0
 	github.com/go-openapi/swag v0.19.0
 	github.com/go-openapi/validate v0.19.0
+	github.com/go-sql-driver/mysql v1.5.0
 	github.com/go-swagger/go-swagger v0.19.0 // indirect
+	github.com/google/uuid v1.1.1
 	github.com/gorilla/handlers v1.4.0 // indirect
 	github.com/haya14busa/goverage v0.0.0-20180129164344-eec3514a20b5 // indirect
 	github.com/jessevdk/go-flags v1.4.0
 	github.com/joho/godotenv v1.3.0
+	github.com/lib/pq v1.1.1
 	github.com/mitchellh/gox v1.0.1 // indirect
 	github.com/oklog/oklog v0.3.2
 	github.com/oklog/run v1.0.0 // indirect
 	github.com/opentracing/opentracing-go v1.1.0 // indirect
+	github.com/pelletier/go-toml v1.2.0
 	github.com/pkg/errors v0.8.1
+	github.com/pkg/sftp v1.11.0
 	github.com/prometheus/client_golang v0.9.3
 	github.com/rs/zerolog v1.14.3
 	github.com/spf13/viper v1.3.2 // indirect
@@ -32,6 +38,9 @@ require (
 	github.com/uber/jaeger-client-go v2.16.0+incompatible
 	github.com/uber/jaeger-lib v2.0.0+incompatible // indirect
 	github.com/utahta/swagger-doc v0.0.1
+	go.etcd.io/bbolt v1.3.3
+	golang.org/x/crypto v0.0.0-20190820162420-60c769a6c586
+	golang.org/x/image v0.0.0-20190802002840-cff245a6509b
 	golang.org/x/lint v0.0.0-20190409202823-959b441ac422 // indirect
 	golang.org/x/net v0.0.0-20190520210107-018c4d40a106
 	gopkg.in/urfave/cli.v2 v2.0.0-20180128182452-d3ae77c26ac8
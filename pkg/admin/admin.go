@@ -0,0 +1,22 @@
+// Package admin verifies the admin token which grants access to the
+// administrative endpoints like backups and quotas.
+package admin
+
+import (
+	"crypto/subtle"
+	"net/http"
+)
+
+// Authorized checks if the request provides the admin token as bearer
+// token, the comparison takes constant time. Nothing is authorized if the
+// admin token is empty.
+func Authorized(token string, r *http.Request) bool {
+	if token == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare(
+		[]byte(r.Header.Get("Authorization")),
+		[]byte("Bearer "+token),
+	) == 1
+}
@@ -0,0 +1,31 @@
+package admin
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthorized(t *testing.T) {
+	for _, c := range []struct {
+		token    string
+		header   string
+		expected bool
+	}{
+		{"secret", "Bearer secret", true},
+		{"secret", "Bearer other", false},
+		{"secret", "secret", false},
+		{"secret", "", false},
+		{"", "Bearer ", false},
+		{"", "", false},
+	} {
+		req := httptest.NewRequest("GET", "/", nil)
+
+		if c.header != "" {
+			req.Header.Set("Authorization", c.header)
+		}
+
+		if actual := Authorized(c.token, req); actual != c.expected {
+			t.Errorf("expected %q to be authorized by %q: %v, got %v", c.header, c.token, c.expected, actual)
+		}
+	}
+}
@@ -7,6 +7,9 @@ import (
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/kleister/kleister-api/pkg/api/v1/restapi"
 	"github.com/kleister/kleister-api/pkg/api/v1/restapi/operations"
+	"github.com/kleister/kleister-api/pkg/config"
+	"github.com/kleister/kleister-api/pkg/store"
+	"github.com/kleister/kleister-api/pkg/upload"
 	"github.com/rs/zerolog/log"
 )
 
@@ -18,7 +21,7 @@ type API struct {
 }
 
 // New creates a new API that adds the custom Handler implementations.
-func New() *API {
+func New(cfg *config.Config, storage store.Store, uploads upload.Upload) *API {
 	spec, err := loads.Analyzed(restapi.SwaggerJSON, "")
 
 	if err != nil {
@@ -30,6 +33,14 @@ func New() *API {
 	}
 
 	api := operations.NewKleisterAPI(spec)
+	api.ModUploadVersionFileHandler = uploadVersionFileHandler(cfg, storage, uploads)
+	api.PackUploadPackLogoHandler = uploadPackLogoHandler(cfg, storage, uploads)
+	api.PackUploadPackIconHandler = uploadPackIconHandler(cfg, storage, uploads)
+	api.PackUploadPackBackgroundHandler = uploadPackBackgroundHandler(cfg, storage, uploads)
+	api.UserShowUserQuotaHandler = showUserQuotaHandler(cfg, storage)
+	api.UserUpdateUserQuotaHandler = updateUserQuotaHandler(cfg, storage)
+	api.TeamShowTeamQuotaHandler = showTeamQuotaHandler(cfg, storage)
+	api.TeamUpdateTeamQuotaHandler = updateTeamQuotaHandler(cfg, storage)
 
 	api.Middleware = func(b middleware.Builder) http.Handler {
 		return middleware.Spec("", nil, api.Context().RoutesHandler(b))
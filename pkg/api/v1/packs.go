@@ -0,0 +1,240 @@
+package v1
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/kleister/kleister-api/pkg/api/v1/models"
+	"github.com/kleister/kleister-api/pkg/api/v1/restapi/operations/pack"
+	"github.com/kleister/kleister-api/pkg/config"
+	"github.com/kleister/kleister-api/pkg/download"
+	"github.com/kleister/kleister-api/pkg/images"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/quota"
+	"github.com/kleister/kleister-api/pkg/store"
+	"github.com/kleister/kleister-api/pkg/upload"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// uploadPackLogoHandler stores the uploaded logo of a pack, the image gets
+// validated and resized to the dimensions of the launcher.
+func uploadPackLogoHandler(cfg *config.Config, storage store.Store, uploads upload.Upload) pack.UploadPackLogoHandlerFunc {
+	return func(params pack.UploadPackLogoParams) middleware.Responder {
+		return uploadPackImage(cfg, storage, uploads, images.Logo, params.HTTPRequest, params.PackID, params.File, packImageResponders{
+			ok: func(payload *models.Pack) middleware.Responder {
+				return pack.NewUploadPackLogoOK().WithPayload(payload)
+			},
+			notFound: func(payload *models.GeneralError) middleware.Responder {
+				return pack.NewUploadPackLogoNotFound().WithPayload(payload)
+			},
+			tooLarge: func(payload *models.GeneralError) middleware.Responder {
+				return pack.NewUploadPackLogoRequestEntityTooLarge().WithPayload(payload)
+			},
+			invalid: func(payload *models.ValidationError) middleware.Responder {
+				return pack.NewUploadPackLogoUnprocessableEntity().WithPayload(payload)
+			},
+			failed: func(payload *models.GeneralError) middleware.Responder {
+				return pack.NewUploadPackLogoDefault(http.StatusInternalServerError).WithPayload(payload)
+			},
+		})
+	}
+}
+
+// uploadPackIconHandler stores the uploaded icon of a pack, the image gets
+// validated and resized to the dimensions of the launcher.
+func uploadPackIconHandler(cfg *config.Config, storage store.Store, uploads upload.Upload) pack.UploadPackIconHandlerFunc {
+	return func(params pack.UploadPackIconParams) middleware.Responder {
+		return uploadPackImage(cfg, storage, uploads, images.Icon, params.HTTPRequest, params.PackID, params.File, packImageResponders{
+			ok: func(payload *models.Pack) middleware.Responder {
+				return pack.NewUploadPackIconOK().WithPayload(payload)
+			},
+			notFound: func(payload *models.GeneralError) middleware.Responder {
+				return pack.NewUploadPackIconNotFound().WithPayload(payload)
+			},
+			tooLarge: func(payload *models.GeneralError) middleware.Responder {
+				return pack.NewUploadPackIconRequestEntityTooLarge().WithPayload(payload)
+			},
+			invalid: func(payload *models.ValidationError) middleware.Responder {
+				return pack.NewUploadPackIconUnprocessableEntity().WithPayload(payload)
+			},
+			failed: func(payload *models.GeneralError) middleware.Responder {
+				return pack.NewUploadPackIconDefault(http.StatusInternalServerError).WithPayload(payload)
+			},
+		})
+	}
+}
+
+// uploadPackBackgroundHandler stores the uploaded background of a pack, the
+// image gets validated and resized to the dimensions of the launcher.
+func uploadPackBackgroundHandler(cfg *config.Config, storage store.Store, uploads upload.Upload) pack.UploadPackBackgroundHandlerFunc {
+	return func(params pack.UploadPackBackgroundParams) middleware.Responder {
+		return uploadPackImage(cfg, storage, uploads, images.Background, params.HTTPRequest, params.PackID, params.File, packImageResponders{
+			ok: func(payload *models.Pack) middleware.Responder {
+				return pack.NewUploadPackBackgroundOK().WithPayload(payload)
+			},
+			notFound: func(payload *models.GeneralError) middleware.Responder {
+				return pack.NewUploadPackBackgroundNotFound().WithPayload(payload)
+			},
+			tooLarge: func(payload *models.GeneralError) middleware.Responder {
+				return pack.NewUploadPackBackgroundRequestEntityTooLarge().WithPayload(payload)
+			},
+			invalid: func(payload *models.ValidationError) middleware.Responder {
+				return pack.NewUploadPackBackgroundUnprocessableEntity().WithPayload(payload)
+			},
+			failed: func(payload *models.GeneralError) middleware.Responder {
+				return pack.NewUploadPackBackgroundDefault(http.StatusInternalServerError).WithPayload(payload)
+			},
+		})
+	}
+}
+
+// packImageResponders maps the results of an image upload to the generated
+// responses of the operation for the specific kind of image.
+type packImageResponders struct {
+	ok       func(*models.Pack) middleware.Responder
+	notFound func(*models.GeneralError) middleware.Responder
+	tooLarge func(*models.GeneralError) middleware.Responder
+	invalid  func(*models.ValidationError) middleware.Responder
+	failed   func(*models.GeneralError) middleware.Responder
+}
+
+// uploadPackImage stores an uploaded image of a pack and responds through
+// the responders of the operation for the kind of image.
+func uploadPackImage(cfg *config.Config, storage store.Store, uploads upload.Upload, kind *images.Kind, req *http.Request, packID string, file io.ReadCloser, responders packImageResponders) middleware.Responder {
+	defer file.Close()
+
+	record, err := images.Attach(
+		req.Context(),
+		storage,
+		uploads,
+		packID,
+		kind,
+		file,
+		limits(cfg),
+	)
+
+	switch cause := errors.Cause(err); {
+	case err == nil:
+	case cause == store.ErrNotFound:
+		return responders.notFound(&models.GeneralError{
+			Status:  swag.Int64(http.StatusNotFound),
+			Message: swag.String("pack not found"),
+		})
+	case cause == images.ErrTooLarge || cause == quota.ErrExceeded:
+		return responders.tooLarge(&models.GeneralError{
+			Status:  swag.Int64(http.StatusRequestEntityTooLarge),
+			Message: swag.String(err.Error()),
+		})
+	case cause == images.ErrInvalidImage || cause == images.ErrTooSmall:
+		return responders.invalid(&models.ValidationError{
+			Status:  swag.Int64(http.StatusUnprocessableEntity),
+			Message: swag.String(err.Error()),
+		})
+	case record != nil:
+		log.Warn().
+			Err(err).
+			Str("pack", record.ID).
+			Msg("failed to remove previous pack " + kind.Name)
+	default:
+		log.Error().
+			Err(err).
+			Str("pack", packID).
+			Msg("failed to upload pack " + kind.Name)
+
+		return responders.failed(&models.GeneralError{
+			Status:  swag.Int64(http.StatusInternalServerError),
+			Message: swag.String("failed to upload pack " + kind.Name),
+		})
+	}
+
+	result, err := convertPack(cfg, record)
+
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("pack", record.ID).
+			Msg("failed to sign pack " + kind.Name)
+
+		return responders.failed(&models.GeneralError{
+			Status:  swag.Int64(http.StatusInternalServerError),
+			Message: swag.String("failed to sign pack " + kind.Name),
+		})
+	}
+
+	return responders.ok(result)
+}
+
+// convertPack converts a pack record into the API model.
+func convertPack(cfg *config.Config, record *model.Pack) (*models.Pack, error) {
+	result := &models.Pack{
+		ID:            strfmt.UUID(record.ID),
+		RecommendedID: strfmt.UUID(record.RecommendedID),
+		LatestID:      strfmt.UUID(record.LatestID),
+		Slug:          record.Slug,
+		Name:          swag.String(record.Name),
+		Website:       record.Website,
+		Published:     record.Published,
+		Hidden:        record.Hidden,
+		Private:       record.Private,
+		Public:        record.Public,
+		CreatedAt:     strfmt.DateTime(record.CreatedAt),
+		UpdatedAt:     strfmt.DateTime(record.UpdatedAt),
+	}
+
+	for target, kind := range map[**models.PackImage]*images.Kind{
+		&result.Logo:       images.Logo,
+		&result.Icon:       images.Icon,
+		&result.Background: images.Background,
+	} {
+		image, err := convertPackImage(cfg, record, kind)
+
+		if err != nil {
+			return nil, err
+		}
+
+		*target = image
+	}
+
+	return result, nil
+}
+
+// convertPackImage converts an image of a pack into the API model, the
+// URLs of images of private or hidden packs get signed like their builds.
+func convertPackImage(cfg *config.Config, record *model.Pack, kind *images.Kind) (*models.PackImage, error) {
+	key, md5 := kind.Get(record)
+
+	if key == "" {
+		return nil, nil
+	}
+
+	result := &models.PackImage{
+		Md5: md5,
+	}
+
+	for target, variant := range map[*string]string{
+		&result.URL:         images.VariantLauncher,
+		&result.SmallURL:    images.VariantSmall,
+		&result.OriginalURL: images.VariantOriginal,
+	} {
+		key := images.Variant(key, variant)
+		*target = storageURL(cfg, key)
+
+		if !record.Private && !record.Hidden {
+			continue
+		}
+
+		signed, err := download.Sign(cfg.Token.Secret, *target, key, cfg.Upload.Expire)
+
+		if err != nil {
+			return nil, err
+		}
+
+		*target = signed
+	}
+
+	return result, nil
+}
@@ -0,0 +1,142 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/kleister/kleister-api/pkg/api/v1/restapi/operations/pack"
+	"github.com/kleister/kleister-api/pkg/config"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store/memory"
+	"github.com/kleister/kleister-api/pkg/token"
+	"github.com/kleister/kleister-api/pkg/upload/file"
+)
+
+func TestUploadPackLogo(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kleister-api")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	uploads := file.Must(&url.URL{Scheme: "file", Path: dir})
+	s := memory.Must(&url.URL{Scheme: "memory"})
+
+	cfg := &config.Config{}
+	cfg.Server.Host = "http://localhost"
+
+	record, err := s.Packs().Create(ctx, &model.Pack{Name: "Hexxit"})
+	must(t, err)
+
+	handler := uploadPackLogoHandler(cfg, s, uploads)
+	logo := encodePNG(t, 370, 220)
+
+	ok, valid := handler(logoParams(record.Slug, logo)).(*pack.UploadPackLogoOK)
+
+	if !valid {
+		t.Fatalf("expected logo to be uploaded")
+	}
+
+	if ok.Payload.Logo == nil || !strings.HasSuffix(ok.Payload.Logo.URL, "/launcher.png") {
+		t.Fatalf("expected logo to be returned")
+	}
+
+	if _, valid := handler(logoParams("missing", logo)).(*pack.UploadPackLogoNotFound); !valid {
+		t.Fatalf("expected missing pack to be rejected")
+	}
+
+	invalid, valid := handler(logoParams(record.Slug, encodePNG(t, 100, 50))).(*pack.UploadPackLogoUnprocessableEntity)
+
+	if !valid || *invalid.Payload.Status != http.StatusUnprocessableEntity {
+		t.Fatalf("expected small image to be rejected")
+	}
+
+	current, err := s.Packs().Show(ctx, record.ID)
+	must(t, err)
+
+	user, err := s.Users().Create(ctx, &model.User{Username: "alice", Quota: current.LogoSize})
+	must(t, err)
+
+	must(t, s.UserPacks().Append(ctx, &model.UserPack{UserID: user.ID, PackID: record.ID, Perm: model.PermOwner}))
+
+	if _, valid := handler(logoParams(record.Slug, encodePNG(t, 740, 440))).(*pack.UploadPackLogoRequestEntityTooLarge); !valid {
+		t.Fatalf("expected exceeded quota to be rejected")
+	}
+}
+
+func TestConvertPack(t *testing.T) {
+	secret, err := token.Generate()
+	must(t, err)
+
+	cfg := &config.Config{}
+	cfg.Server.Host = "http://localhost"
+	cfg.Token.Secret = secret
+
+	for _, record := range []*model.Pack{
+		{Name: "Public", Public: true},
+		{Name: "Private", Private: true},
+		{Name: "Hidden", Hidden: true, Public: true},
+	} {
+		record.ID = strings.ToLower(record.Name)
+		record.LogoKey = "packs/" + record.ID + "/logo/0f8fad5b-d9cb-469f-a165-70867728950e/launcher.png"
+
+		result, err := convertPack(cfg, record)
+		must(t, err)
+
+		if result.Icon != nil || result.Background != nil {
+			t.Fatalf("expected missing images to be omitted for %s", record.Name)
+		}
+
+		signed := record.Private || record.Hidden
+
+		for _, url := range []string{result.Logo.URL, result.Logo.SmallURL, result.Logo.OriginalURL} {
+			if strings.Contains(url, "?token=") != signed {
+				t.Errorf("expected signature of %s to be %v, got %s", record.Name, signed, url)
+			}
+		}
+
+		if !strings.HasSuffix(strings.Split(result.Logo.SmallURL, "?")[0], "/small.png") {
+			t.Errorf("expected small variant, got %s", result.Logo.SmallURL)
+		}
+	}
+}
+
+// logoParams builds the parameters of a logo upload.
+func logoParams(packID string, content []byte) pack.UploadPackLogoParams {
+	return pack.UploadPackLogoParams{
+		HTTPRequest: httptest.NewRequest("POST", "/api/v1/packs/"+packID+"/logo", nil),
+		File:        ioutil.NopCloser(bytes.NewReader(content)),
+		PackID:      packID,
+	}
+}
+
+// encodePNG encodes a gradient image of the given dimensions.
+func encodePNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Pix[img.PixOffset(x, y)] = uint8(x + y)
+			img.Pix[img.PixOffset(x, y)+3] = 255
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	must(t, png.Encode(buf, img))
+
+	return buf.Bytes()
+}
@@ -0,0 +1,216 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/swag"
+	"github.com/kleister/kleister-api/pkg/admin"
+	"github.com/kleister/kleister-api/pkg/api/v1/models"
+	"github.com/kleister/kleister-api/pkg/api/v1/restapi/operations/team"
+	"github.com/kleister/kleister-api/pkg/api/v1/restapi/operations/user"
+	"github.com/kleister/kleister-api/pkg/config"
+	"github.com/kleister/kleister-api/pkg/quota"
+	"github.com/kleister/kleister-api/pkg/store"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// showUserQuotaHandler shows the storage quota and usage of a user.
+func showUserQuotaHandler(cfg *config.Config, storage store.Store) user.ShowUserQuotaHandlerFunc {
+	return func(params user.ShowUserQuotaParams) middleware.Responder {
+		if !admin.Authorized(cfg.Admin.Token, params.HTTPRequest) {
+			return user.NewShowUserQuotaForbidden().WithPayload(&models.GeneralError{
+				Status:  swag.Int64(http.StatusForbidden),
+				Message: swag.String("only admins can access quotas"),
+			})
+		}
+
+		result, err := quota.User(params.HTTPRequest.Context(), storage, limits(cfg), params.UserID)
+
+		switch cause := errors.Cause(err); {
+		case err == nil:
+		case cause == store.ErrNotFound:
+			return user.NewShowUserQuotaNotFound().WithPayload(&models.GeneralError{
+				Status:  swag.Int64(http.StatusNotFound),
+				Message: swag.String("user not found"),
+			})
+		default:
+			log.Error().
+				Err(err).
+				Str("user", params.UserID).
+				Msg("failed to calculate user quota")
+
+			return user.NewShowUserQuotaDefault(http.StatusInternalServerError).WithPayload(&models.GeneralError{
+				Status:  swag.Int64(http.StatusInternalServerError),
+				Message: swag.String("failed to calculate user quota"),
+			})
+		}
+
+		return user.NewShowUserQuotaOK().WithPayload(convertQuota(result))
+	}
+}
+
+// updateUserQuotaHandler replaces the storage limit of a user.
+func updateUserQuotaHandler(cfg *config.Config, storage store.Store) user.UpdateUserQuotaHandlerFunc {
+	return func(params user.UpdateUserQuotaParams) middleware.Responder {
+		if !admin.Authorized(cfg.Admin.Token, params.HTTPRequest) {
+			return user.NewUpdateUserQuotaForbidden().WithPayload(&models.GeneralError{
+				Status:  swag.Int64(http.StatusForbidden),
+				Message: swag.String("only admins can update quotas"),
+			})
+		}
+
+		ctx := params.HTTPRequest.Context()
+
+		err := storage.Transaction(ctx, func(tx store.Repositories) error {
+			record, err := tx.Users().Show(ctx, params.UserID)
+
+			if err != nil {
+				return err
+			}
+
+			record.Quota = swag.Int64Value(params.Quota.Limit)
+
+			_, err = tx.Users().Update(ctx, record)
+			return err
+		})
+
+		var (
+			result *quota.Usage
+		)
+
+		if err == nil {
+			result, err = quota.User(ctx, storage, limits(cfg), params.UserID)
+		}
+
+		switch cause := errors.Cause(err); {
+		case err == nil:
+		case cause == store.ErrNotFound:
+			return user.NewUpdateUserQuotaNotFound().WithPayload(&models.GeneralError{
+				Status:  swag.Int64(http.StatusNotFound),
+				Message: swag.String("user not found"),
+			})
+		default:
+			log.Error().
+				Err(err).
+				Str("user", params.UserID).
+				Msg("failed to update user quota")
+
+			return user.NewUpdateUserQuotaDefault(http.StatusInternalServerError).WithPayload(&models.GeneralError{
+				Status:  swag.Int64(http.StatusInternalServerError),
+				Message: swag.String("failed to update user quota"),
+			})
+		}
+
+		return user.NewUpdateUserQuotaOK().WithPayload(convertQuota(result))
+	}
+}
+
+// showTeamQuotaHandler shows the storage quota and usage of a team.
+func showTeamQuotaHandler(cfg *config.Config, storage store.Store) team.ShowTeamQuotaHandlerFunc {
+	return func(params team.ShowTeamQuotaParams) middleware.Responder {
+		if !admin.Authorized(cfg.Admin.Token, params.HTTPRequest) {
+			return team.NewShowTeamQuotaForbidden().WithPayload(&models.GeneralError{
+				Status:  swag.Int64(http.StatusForbidden),
+				Message: swag.String("only admins can access quotas"),
+			})
+		}
+
+		result, err := quota.Team(params.HTTPRequest.Context(), storage, limits(cfg), params.TeamID)
+
+		switch cause := errors.Cause(err); {
+		case err == nil:
+		case cause == store.ErrNotFound:
+			return team.NewShowTeamQuotaNotFound().WithPayload(&models.GeneralError{
+				Status:  swag.Int64(http.StatusNotFound),
+				Message: swag.String("team not found"),
+			})
+		default:
+			log.Error().
+				Err(err).
+				Str("team", params.TeamID).
+				Msg("failed to calculate team quota")
+
+			return team.NewShowTeamQuotaDefault(http.StatusInternalServerError).WithPayload(&models.GeneralError{
+				Status:  swag.Int64(http.StatusInternalServerError),
+				Message: swag.String("failed to calculate team quota"),
+			})
+		}
+
+		return team.NewShowTeamQuotaOK().WithPayload(convertQuota(result))
+	}
+}
+
+// updateTeamQuotaHandler replaces the storage limit of a team.
+func updateTeamQuotaHandler(cfg *config.Config, storage store.Store) team.UpdateTeamQuotaHandlerFunc {
+	return func(params team.UpdateTeamQuotaParams) middleware.Responder {
+		if !admin.Authorized(cfg.Admin.Token, params.HTTPRequest) {
+			return team.NewUpdateTeamQuotaForbidden().WithPayload(&models.GeneralError{
+				Status:  swag.Int64(http.StatusForbidden),
+				Message: swag.String("only admins can update quotas"),
+			})
+		}
+
+		ctx := params.HTTPRequest.Context()
+
+		err := storage.Transaction(ctx, func(tx store.Repositories) error {
+			record, err := tx.Teams().Show(ctx, params.TeamID)
+
+			if err != nil {
+				return err
+			}
+
+			record.Quota = swag.Int64Value(params.Quota.Limit)
+
+			_, err = tx.Teams().Update(ctx, record)
+			return err
+		})
+
+		var (
+			result *quota.Usage
+		)
+
+		if err == nil {
+			result, err = quota.Team(ctx, storage, limits(cfg), params.TeamID)
+		}
+
+		switch cause := errors.Cause(err); {
+		case err == nil:
+		case cause == store.ErrNotFound:
+			return team.NewUpdateTeamQuotaNotFound().WithPayload(&models.GeneralError{
+				Status:  swag.Int64(http.StatusNotFound),
+				Message: swag.String("team not found"),
+			})
+		default:
+			log.Error().
+				Err(err).
+				Str("team", params.TeamID).
+				Msg("failed to update team quota")
+
+			return team.NewUpdateTeamQuotaDefault(http.StatusInternalServerError).WithPayload(&models.GeneralError{
+				Status:  swag.Int64(http.StatusInternalServerError),
+				Message: swag.String("failed to update team quota"),
+			})
+		}
+
+		return team.NewUpdateTeamQuotaOK().WithPayload(convertQuota(result))
+	}
+}
+
+// convertQuota converts a calculated usage into the API model.
+func convertQuota(record *quota.Usage) *models.Quota {
+	return &models.Quota{
+		Limit:     swag.Int64(record.Quota),
+		Effective: record.Limit,
+		Usage:     record.Used,
+	}
+}
+
+// limits builds the default quota limits from the config.
+func limits(cfg *config.Config) quota.Limits {
+	return quota.Limits{
+		User: cfg.Quota.User,
+		Team: cfg.Quota.Team,
+	}
+}
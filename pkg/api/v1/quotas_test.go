@@ -0,0 +1,77 @@
+package v1
+
+import (
+	"context"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/kleister/kleister-api/pkg/api/v1/restapi/operations/user"
+	"github.com/kleister/kleister-api/pkg/config"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+	"github.com/kleister/kleister-api/pkg/store/memory"
+	"github.com/pkg/errors"
+)
+
+// wrapping wraps the errors of the users like the SQL drivers.
+type wrapping struct {
+	store.Store
+}
+
+// Users returns the users with wrapped errors.
+func (w *wrapping) Users() store.Users {
+	return &wrappingUsers{w.Store.Users()}
+}
+
+type wrappingUsers struct {
+	store.Users
+}
+
+// Show wraps the error of the wrapped users.
+func (u *wrappingUsers) Show(ctx context.Context, id string) (*model.User, error) {
+	record, err := u.Users.Show(ctx, id)
+	return record, errors.Wrap(err, "failed to fetch user")
+}
+
+func TestShowUserQuota(t *testing.T) {
+	ctx := context.Background()
+	s := &wrapping{memory.Must(&url.URL{Scheme: "memory"})}
+
+	cfg := &config.Config{}
+	cfg.Admin.Token = "secret"
+	cfg.Quota.User = 1000
+
+	record, err := s.Users().Create(ctx, &model.User{Username: "alice"})
+	must(t, err)
+
+	handler := showUserQuotaHandler(cfg, s)
+
+	if _, valid := handler(quotaParams(record.Slug, "")).(*user.ShowUserQuotaForbidden); !valid {
+		t.Fatalf("expected missing token to be rejected")
+	}
+
+	ok, valid := handler(quotaParams(record.Slug, "secret")).(*user.ShowUserQuotaOK)
+
+	if !valid || ok.Payload.Effective != 1000 || ok.Payload.Usage != 0 {
+		t.Fatalf("expected the default quota to be returned")
+	}
+
+	if _, valid := handler(quotaParams("missing", "secret")).(*user.ShowUserQuotaNotFound); !valid {
+		t.Fatalf("expected wrapped not found to be rejected as missing user")
+	}
+}
+
+// quotaParams builds the parameters to show the quota of a user.
+func quotaParams(userID, token string) user.ShowUserQuotaParams {
+	req := httptest.NewRequest("GET", "/api/v1/users/"+userID+"/quota", nil)
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return user.ShowUserQuotaParams{
+		HTTPRequest: req,
+		UserID:      userID,
+	}
+}
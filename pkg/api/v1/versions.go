@@ -0,0 +1,236 @@
+package v1
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/kleister/kleister-api/pkg/api/v1/models"
+	"github.com/kleister/kleister-api/pkg/api/v1/restapi/operations/mod"
+	"github.com/kleister/kleister-api/pkg/config"
+	"github.com/kleister/kleister-api/pkg/download"
+	"github.com/kleister/kleister-api/pkg/files"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/modinfo"
+	"github.com/kleister/kleister-api/pkg/quota"
+	"github.com/kleister/kleister-api/pkg/store"
+	"github.com/kleister/kleister-api/pkg/upload"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// uploadVersionFileHandler stores the uploaded file of a version, the file
+// gets streamed into the uploads while all checksums are calculated. Files
+// exceeding the quota of any assigned user or team get rejected upfront if
+// the size is known, the streamed size gets checked again when the file is
+// attached. The metadata of mod archives gets applied to the mod and version.
+func uploadVersionFileHandler(cfg *config.Config, storage store.Store, uploads upload.Upload) mod.UploadVersionFileHandlerFunc {
+	return func(params mod.UploadVersionFileParams) middleware.Responder {
+		defer params.File.Close()
+
+		var (
+			name        string
+			contentType string
+			size        int64 = -1
+		)
+
+		if file, ok := params.File.(*runtime.File); ok {
+			name = file.Header.Filename
+			size = file.Header.Size
+
+			if val := file.Header.Header.Get("Content-Type"); val != "application/octet-stream" {
+				contentType = val
+			}
+		}
+
+		if size >= 0 {
+			err := quota.Check(params.HTTPRequest.Context(), storage, limits(cfg), params.ModID, params.VersionID, size)
+
+			switch cause := errors.Cause(err); {
+			case err == nil:
+			case cause == store.ErrNotFound:
+				return mod.NewUploadVersionFileNotFound().WithPayload(&models.GeneralError{
+					Status:  swag.Int64(http.StatusNotFound),
+					Message: swag.String("mod or version not found"),
+				})
+			case cause == quota.ErrExceeded:
+				return mod.NewUploadVersionFileRequestEntityTooLarge().WithPayload(&models.GeneralError{
+					Status:  swag.Int64(http.StatusRequestEntityTooLarge),
+					Message: swag.String(err.Error()),
+				})
+			default:
+				log.Error().
+					Err(err).
+					Str("mod", params.ModID).
+					Str("version", params.VersionID).
+					Msg("failed to check storage quota")
+
+				return mod.NewUploadVersionFileDefault(http.StatusInternalServerError).WithPayload(&models.GeneralError{
+					Status:  swag.Int64(http.StatusInternalServerError),
+					Message: swag.String("failed to check storage quota"),
+				})
+			}
+		}
+
+		var (
+			info     *modinfo.Info
+			warnings []string
+		)
+
+		if file, ok := params.File.(*runtime.File); ok && size >= 0 && modinfo.Supported(name) {
+			parsed, err := modinfo.Parse(file.Data, size)
+
+			switch cause := errors.Cause(err); {
+			case err == nil:
+				info = parsed
+			case cause == modinfo.ErrInvalidArchive:
+				return mod.NewUploadVersionFileUnprocessableEntity().WithPayload(&models.ValidationError{
+					Status:  swag.Int64(http.StatusUnprocessableEntity),
+					Message: swag.String(cause.Error()),
+				})
+			default:
+				warnings = append(warnings, err.Error())
+			}
+
+			if _, err := file.Data.Seek(0, io.SeekStart); err != nil {
+				log.Error().
+					Err(err).
+					Str("mod", params.ModID).
+					Str("version", params.VersionID).
+					Msg("failed to rewind version file")
+
+				return mod.NewUploadVersionFileDefault(http.StatusInternalServerError).WithPayload(&models.GeneralError{
+					Status:  swag.Int64(http.StatusInternalServerError),
+					Message: swag.String("failed to upload version file"),
+				})
+			}
+		}
+
+		record, applied, err := files.Attach(
+			params.HTTPRequest.Context(),
+			storage,
+			uploads,
+			params.ModID,
+			params.VersionID,
+			name,
+			params.File,
+			size,
+			contentType,
+			info,
+			limits(cfg),
+		)
+
+		switch cause := errors.Cause(err); {
+		case err == nil:
+		case cause == store.ErrNotFound:
+			return mod.NewUploadVersionFileNotFound().WithPayload(&models.GeneralError{
+				Status:  swag.Int64(http.StatusNotFound),
+				Message: swag.String("mod or version not found"),
+			})
+		case cause == files.ErrInvalidName || cause == upload.ErrSizeMismatch:
+			return mod.NewUploadVersionFileUnprocessableEntity().WithPayload(&models.ValidationError{
+				Status:  swag.Int64(http.StatusUnprocessableEntity),
+				Message: swag.String(cause.Error()),
+			})
+		case cause == quota.ErrExceeded:
+			return mod.NewUploadVersionFileRequestEntityTooLarge().WithPayload(&models.GeneralError{
+				Status:  swag.Int64(http.StatusRequestEntityTooLarge),
+				Message: swag.String(err.Error()),
+			})
+		case record != nil:
+			log.Warn().
+				Err(err).
+				Str("version", record.ID).
+				Msg("failed to remove previous version file")
+		default:
+			log.Error().
+				Err(err).
+				Str("mod", params.ModID).
+				Str("version", params.VersionID).
+				Msg("failed to upload version file")
+
+			return mod.NewUploadVersionFileDefault(http.StatusInternalServerError).WithPayload(&models.GeneralError{
+				Status:  swag.Int64(http.StatusInternalServerError),
+				Message: swag.String("failed to upload version file"),
+			})
+		}
+
+		result := convertVersion(cfg, record)
+
+		if result.File != nil {
+			if result.File.URL, err = downloadURL(params.HTTPRequest.Context(), cfg, storage, record.FileKey); err != nil {
+				log.Error().
+					Err(err).
+					Str("version", record.ID).
+					Msg("failed to sign version file")
+
+				return mod.NewUploadVersionFileDefault(http.StatusInternalServerError).WithPayload(&models.GeneralError{
+					Status:  swag.Int64(http.StatusInternalServerError),
+					Message: swag.String("failed to sign version file"),
+				})
+			}
+		}
+
+		result.Warnings = append(append(make([]string, 0), warnings...), applied...)
+
+		return mod.NewUploadVersionFileOK().WithPayload(result)
+	}
+}
+
+// convertVersion converts a version record into the API model.
+func convertVersion(cfg *config.Config, record *model.Version) *models.Version {
+	result := &models.Version{
+		ID:        strfmt.UUID(record.ID),
+		ModID:     strfmt.UUID(record.ModID),
+		Slug:      record.Slug,
+		Name:      swag.String(record.Name),
+		CreatedAt: strfmt.DateTime(record.CreatedAt),
+		UpdatedAt: strfmt.DateTime(record.UpdatedAt),
+	}
+
+	if record.FileKey != "" {
+		result.File = &models.VersionFile{
+			Name:      record.FileName,
+			Size:      record.FileSize,
+			Md5:       record.FileMD5,
+			Sha1:      record.FileSHA1,
+			Sha256:    record.FileSHA256,
+			Loader:    record.FileLoader,
+			Minecraft: record.FileMinecraft,
+			Broken:    record.FileBroken,
+			URL:       storageURL(cfg, record.FileKey),
+		}
+	}
+
+	return result
+}
+
+// downloadURL builds the external URL to download an object, objects of
+// private and hidden packs get a signed URL which expires.
+func downloadURL(ctx context.Context, cfg *config.Config, storage store.Store, key string) (string, error) {
+	result := storageURL(cfg, key)
+	protected, err := download.Protected(ctx, storage, key)
+
+	if err != nil || !protected {
+		return result, err
+	}
+
+	return download.Sign(cfg.Token.Secret, result, key, cfg.Upload.Expire)
+}
+
+// storageURL builds the external URL to download an object.
+func storageURL(cfg *config.Config, key string) string {
+	return strings.TrimSuffix(cfg.Server.Host, "/") + path.Join(
+		"/",
+		cfg.Server.Root,
+		"api",
+		"storage",
+		key,
+	)
+}
@@ -0,0 +1,135 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/go-openapi/runtime"
+	"github.com/kleister/kleister-api/pkg/api/v1/restapi/operations/mod"
+	"github.com/kleister/kleister-api/pkg/config"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store/memory"
+	"github.com/kleister/kleister-api/pkg/upload/file"
+)
+
+func TestUploadVersionFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kleister-api")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	uploads := file.Must(&url.URL{Scheme: "file", Path: dir})
+	s := memory.Must(&url.URL{Scheme: "memory"})
+
+	cfg := &config.Config{}
+	cfg.Server.Host = "http://localhost"
+
+	record, err := s.Mods().Create(ctx, &model.Mod{Name: "Foo"})
+	must(t, err)
+
+	version, err := s.Versions().Create(ctx, &model.Version{ModID: record.ID, Name: "1.0"})
+	must(t, err)
+
+	handler := uploadVersionFileHandler(cfg, s, uploads)
+
+	ok, valid := handler(params(t, record.Slug, version.Slug, "foo.txt", "content", 0)).(*mod.UploadVersionFileOK)
+
+	if !valid {
+		t.Fatalf("expected file to be uploaded")
+	}
+
+	result := ok.Payload.File
+
+	if result.Name != "foo.txt" || result.Size != 7 {
+		t.Fatalf("expected name and size to be recorded, got %s of %d bytes", result.Name, result.Size)
+	}
+
+	for expected, actual := range map[string]string{
+		"9a0364b9e99bb480dd25e1f0284c8555":                                 result.Md5,
+		"040f06fd774092478d450774f5ba30c5da78acc8":                         result.Sha1,
+		"ed7002b439e9ac845f22357d822bac1444730fbdb6016d3ec9432297b9ec9f73": result.Sha256,
+	} {
+		if actual != expected {
+			t.Errorf("expected checksum %s to be recorded, got %s", expected, actual)
+		}
+	}
+
+	mismatch, valid := handler(params(t, record.Slug, version.Slug, "foo.txt", "other", 5)).(*mod.UploadVersionFileUnprocessableEntity)
+
+	if !valid || *mismatch.Payload.Status != http.StatusUnprocessableEntity {
+		t.Fatalf("expected size mismatch to be rejected")
+	}
+
+	if _, valid := handler(params(t, record.Slug, "missing", "foo.txt", "content", 0)).(*mod.UploadVersionFileNotFound); !valid {
+		t.Fatalf("expected missing version to be rejected")
+	}
+
+	user, err := s.Users().Create(ctx, &model.User{Username: "alice", Quota: 10})
+	must(t, err)
+
+	must(t, s.UserMods().Append(ctx, &model.UserMod{UserID: user.ID, ModID: record.ID, Perm: model.PermOwner}))
+
+	if _, valid := handler(params(t, record.Slug, version.Slug, "foo.txt", "exceeding content", 0)).(*mod.UploadVersionFileRequestEntityTooLarge); !valid {
+		t.Fatalf("expected exceeded quota to be rejected")
+	}
+
+	current, err := s.Versions().Show(ctx, record.ID, version.ID)
+	must(t, err)
+
+	if current.FileMD5 != result.Md5 {
+		t.Fatalf("expected rejected uploads to keep the previous file")
+	}
+}
+
+// params builds the parameters of a multipart upload, a positive offset
+// falsifies the size of the file header.
+func params(t *testing.T, modID, versionID, name, content string, offset int64) mod.UploadVersionFileParams {
+	t.Helper()
+
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+
+	part, err := w.CreateFormFile("file", name)
+	must(t, err)
+
+	_, err = part.Write([]byte(content))
+	must(t, err)
+	must(t, w.Close())
+
+	req := httptest.NewRequest("POST", "/api/v1/mods/"+modID+"/versions/"+versionID+"/file", body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+
+	must(t, req.ParseMultipartForm(1<<20))
+
+	data, header, err := req.FormFile("file")
+	must(t, err)
+
+	header.Size += offset
+
+	return mod.UploadVersionFileParams{
+		HTTPRequest: req,
+		File:        &runtime.File{Data: data, Header: header},
+		ModID:       modID,
+		VersionID:   versionID,
+	}
+}
+
+// must fails the test on any unexpected error.
+func must(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
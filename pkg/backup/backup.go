@@ -0,0 +1,194 @@
+// Package backup writes and restores archives of a whole instance, they
+// contain a dump of the database, optionally the uploaded objects and a
+// manifest with the checksums of all files.
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"path"
+	"time"
+
+	"github.com/kleister/kleister-api/pkg/store"
+	"github.com/kleister/kleister-api/pkg/upload"
+	"github.com/pkg/errors"
+)
+
+const (
+	// Version defines the format version of written archives.
+	Version = 1
+
+	// manifestName defines the name of the manifest within the archive.
+	manifestName = "manifest.json"
+
+	// databaseName defines the name of the database dump within the archive.
+	databaseName = "database.json"
+
+	// uploadsPrefix defines the directory of the uploads within the archive.
+	uploadsPrefix = "uploads"
+)
+
+// Manifest describes the content of an archive.
+type Manifest struct {
+	Version   int          `json:"version"`
+	CreatedAt time.Time    `json:"created_at"`
+	Counts    store.Counts `json:"counts"`
+	Files     []*File      `json:"files"`
+}
+
+// File describes a single file within an archive.
+type File struct {
+	Name        string `json:"name"`
+	Size        int64  `json:"size"`
+	SHA256      string `json:"sha256"`
+	ContentType string `json:"content_type,omitempty"`
+}
+
+// Uploads checks if the archive contains any uploaded objects.
+func (m *Manifest) Uploads() bool {
+	for _, file := range m.Files {
+		if file.Name != databaseName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Write writes a gzip compressed tar archive of the store to w. The
+// records are read from a snapshot while the uploaded objects get included
+// if uploads is not nil. The manifest gets written as the last file, this
+// way the archive can be streamed without buffering the uploads.
+func Write(ctx context.Context, w io.Writer, s store.Store, uploads upload.Upload) (*Manifest, error) {
+	var (
+		d *store.Dump
+	)
+
+	if err := s.Snapshot(ctx, func(tx store.Repositories) error {
+		var err error
+		d, err = store.Export(ctx, tx)
+		return err
+	}); err != nil {
+		return nil, errors.Wrap(err, "failed to export database")
+	}
+
+	content, err := json.Marshal(d)
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode database")
+	}
+
+	m := &Manifest{
+		Version:   Version,
+		CreatedAt: time.Now().UTC(),
+		Counts:    d.Counts(),
+		Files:     make([]*File, 0),
+	}
+
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	if err := m.add(tw, databaseName, int64(len(content)), "", bytes.NewReader(content)); err != nil {
+		return nil, err
+	}
+
+	if uploads != nil {
+		if err := m.uploads(ctx, tw, uploads); err != nil {
+			return nil, errors.Wrap(err, "failed to archive uploads")
+		}
+	}
+
+	manifest, err := json.MarshalIndent(m, "", "  ")
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode manifest")
+	}
+
+	if err := tw.WriteHeader(m.header(manifestName, int64(len(manifest)))); err != nil {
+		return nil, err
+	}
+
+	if _, err := tw.Write(manifest); err != nil {
+		return nil, err
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// uploads writes all uploaded objects to the archive.
+func (m *Manifest) uploads(ctx context.Context, tw *tar.Writer, uploads upload.Upload) error {
+	objects, err := uploads.List(ctx, "")
+
+	if err != nil {
+		return err
+	}
+
+	for _, obj := range objects {
+		r, current, err := uploads.Get(ctx, obj.Key)
+
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %s", obj.Key)
+		}
+
+		err = m.add(tw, path.Join(uploadsPrefix, obj.Key), current.Size, current.ContentType, r)
+		r.Close()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// add writes a file to the archive and records its checksum.
+func (m *Manifest) add(tw *tar.Writer, name string, size int64, contentType string, r io.Reader) error {
+	if err := tw.WriteHeader(m.header(name, size)); err != nil {
+		return errors.Wrapf(err, "failed to archive %s", name)
+	}
+
+	hash := sha256.New()
+	written, err := io.Copy(tw, io.TeeReader(r, hash))
+
+	if err != nil {
+		return errors.Wrapf(err, "failed to archive %s", name)
+	}
+
+	if written != size {
+		return errors.Errorf("failed to archive %s, size changed while reading", name)
+	}
+
+	m.Files = append(m.Files, &File{
+		Name:        name,
+		Size:        size,
+		SHA256:      hex.EncodeToString(hash.Sum(nil)),
+		ContentType: contentType,
+	})
+
+	return nil
+}
+
+// header prepares the tar header for a file of the archive.
+func (m *Manifest) header(name string, size int64) *tar.Header {
+	return &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     size,
+		Mode:     0644,
+		ModTime:  m.CreatedAt,
+	}
+}
@@ -0,0 +1,393 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kleister/kleister-api/pkg/files"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+	"github.com/kleister/kleister-api/pkg/store/boltdb"
+	"github.com/kleister/kleister-api/pkg/store/memory"
+	"github.com/kleister/kleister-api/pkg/upload"
+	"github.com/kleister/kleister-api/pkg/upload/file"
+	"github.com/pkg/errors"
+)
+
+func TestRoundTrip(t *testing.T) {
+	dir := tempdir(t)
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	src, uploads := seed(t, filepath.Join(dir, "src"))
+
+	buf := &bytes.Buffer{}
+	written, err := Write(ctx, buf, src, uploads)
+	must(t, err)
+
+	if len(written.Files) != 2 || !written.Uploads() {
+		t.Fatalf("expected database and one upload to be archived, got %d files", len(written.Files))
+	}
+
+	verified, err := Verify(bytes.NewReader(buf.Bytes()))
+	must(t, err)
+
+	if diff := verified.Counts.Diff(written.Counts); len(diff) > 0 {
+		t.Fatalf("expected verified counts to match, got differences for %v", diff)
+	}
+
+	dst := memory.Must(&url.URL{Scheme: "memory"})
+	restored := file.Must(&url.URL{Scheme: "file", Path: filepath.Join(dir, "dst")})
+
+	_, err = Restore(ctx, bytes.NewReader(buf.Bytes()), dst, restored, false)
+	must(t, err)
+
+	compare(t, src, dst)
+
+	r, obj, err := restored.Get(ctx, "mods/jei-1.0.jar")
+	must(t, err)
+
+	content, err := ioutil.ReadAll(r)
+	r.Close()
+	must(t, err)
+
+	if string(content) != "jei" || obj.ContentType != "application/java-archive" {
+		t.Fatalf("expected upload to be restored, got %q of %s", content, obj.ContentType)
+	}
+}
+
+func TestVerifyTampered(t *testing.T) {
+	dir := tempdir(t)
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	src, uploads := seed(t, dir)
+
+	buf := &bytes.Buffer{}
+	_, err := Write(ctx, buf, src, uploads)
+	must(t, err)
+
+	for name, tc := range map[string]struct {
+		tamper   func([]*entry) []*entry
+		expected string
+	}{
+		"altered": {
+			tamper: func(entries []*entry) []*entry {
+				for _, e := range entries {
+					if e.name == "uploads/mods/jei-1.0.jar" {
+						e.content = []byte("JEI")
+					}
+				}
+
+				return entries
+			},
+			expected: "checksum mismatch for uploads/mods/jei-1.0.jar",
+		},
+		"unlisted": {
+			tamper: func(entries []*entry) []*entry {
+				return append(entries, &entry{name: "uploads/extra", content: []byte("extra")})
+			},
+			expected: "archive contains unlisted file uploads/extra",
+		},
+		"traversal": {
+			tamper: func(entries []*entry) []*entry {
+				return append(entries, &entry{name: "uploads/../escape", content: []byte("escape")})
+			},
+			expected: "archive contains invalid file uploads/../escape",
+		},
+		"missing": {
+			tamper: func(entries []*entry) []*entry {
+				result := make([]*entry, 0, len(entries))
+
+				for _, e := range entries {
+					if e.name != "uploads/mods/jei-1.0.jar" {
+						result = append(result, e)
+					}
+				}
+
+				return result
+			},
+			expected: "archive is missing uploads/mods/jei-1.0.jar",
+		},
+	} {
+		archive := rewrite(t, buf.Bytes(), tc.tamper)
+
+		if _, err := Verify(bytes.NewReader(archive)); err == nil || err.Error() != tc.expected {
+			t.Errorf("expected %s archive to fail with %q, got %v", name, tc.expected, err)
+		}
+
+		dst := memory.Must(&url.URL{Scheme: "memory"})
+
+		if _, err := Restore(ctx, bytes.NewReader(archive), dst, nil, false); err == nil {
+			t.Errorf("expected %s archive to be refused by restore", name)
+		}
+
+		counts, err := store.Count(ctx, dst)
+		must(t, err)
+
+		if len(counts.Diff(store.Counts{})) > 0 {
+			t.Errorf("expected nothing to be restored from %s archive", name)
+		}
+	}
+}
+
+func TestRestoreNotEmpty(t *testing.T) {
+	dir := tempdir(t)
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	src, uploads := seed(t, filepath.Join(dir, "src"))
+
+	buf := &bytes.Buffer{}
+	_, err := Write(ctx, buf, src, uploads)
+	must(t, err)
+
+	dst := memory.Must(&url.URL{Scheme: "memory"})
+
+	_, err = dst.Users().Create(ctx, &model.User{Username: "Carol"})
+	must(t, err)
+
+	if _, err := Restore(ctx, bytes.NewReader(buf.Bytes()), dst, nil, false); errors.Cause(err) != ErrNotEmpty {
+		t.Fatalf("expected non-empty database to be refused, got %v", err)
+	}
+
+	restored := file.Must(&url.URL{Scheme: "file", Path: filepath.Join(dir, "dst")})
+	_, err = files.Put(ctx, restored, "other.txt", strings.NewReader("other"), -1, "")
+	must(t, err)
+
+	empty := memory.Must(&url.URL{Scheme: "memory"})
+
+	if _, err := Restore(ctx, bytes.NewReader(buf.Bytes()), empty, restored, false); errors.Cause(err) != ErrNotEmpty {
+		t.Fatalf("expected non-empty uploads to be refused, got %v", err)
+	}
+
+	if _, err := dst.Users().Show(ctx, "carol"); err != nil {
+		t.Fatalf("expected refused restore to keep existing records, got %v", err)
+	}
+
+	if _, err := Restore(ctx, bytes.NewReader(buf.Bytes()), dst, nil, true); err == nil || !strings.Contains(err.Error(), "kept records for users") {
+		t.Fatalf("expected forced restore to fail for a store without reversible migrations")
+	}
+}
+
+func TestRestoreForce(t *testing.T) {
+	dir := tempdir(t)
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	src, uploads := seed(t, filepath.Join(dir, "src"))
+
+	buf := &bytes.Buffer{}
+	_, err := Write(ctx, buf, src, uploads)
+	must(t, err)
+
+	dst, err := boltdb.New(&url.URL{Scheme: "boltdb", Path: filepath.Join(dir, "dst.db")})
+	must(t, err)
+
+	_, err = dst.Migrations().Up(ctx, false)
+	must(t, err)
+
+	_, err = dst.Users().Create(ctx, &model.User{Username: "Carol"})
+	must(t, err)
+
+	_, err = dst.Mods().Create(ctx, &model.Mod{Name: "JEI"})
+	must(t, err)
+
+	restored := file.Must(&url.URL{Scheme: "file", Path: filepath.Join(dir, "dst")})
+	_, err = files.Put(ctx, restored, "mods/jei-1.0.jar", strings.NewReader("stale"), -1, "")
+	must(t, err)
+
+	_, err = Restore(ctx, bytes.NewReader(buf.Bytes()), dst, restored, true)
+	must(t, err)
+
+	compare(t, src, dst)
+
+	if _, err := dst.Users().Show(ctx, "carol"); err != store.ErrNotFound {
+		t.Fatalf("expected previous records to be removed, got %v", err)
+	}
+
+	r, _, err := restored.Get(ctx, "mods/jei-1.0.jar")
+	must(t, err)
+
+	content, err := ioutil.ReadAll(r)
+	r.Close()
+	must(t, err)
+
+	if string(content) != "jei" {
+		t.Fatalf("expected upload to be overwritten, got %q", content)
+	}
+}
+
+func TestRestoreCounts(t *testing.T) {
+	dir := tempdir(t)
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	src, uploads := seed(t, dir)
+
+	buf := &bytes.Buffer{}
+	_, err := Write(ctx, buf, src, uploads)
+	must(t, err)
+
+	archive := rewrite(t, buf.Bytes(), func(entries []*entry) []*entry {
+		for _, e := range entries {
+			if e.name != manifestName {
+				continue
+			}
+
+			m := &Manifest{}
+			must(t, json.Unmarshal(e.content, m))
+
+			m.Counts["users"]++
+
+			content, err := json.Marshal(m)
+			must(t, err)
+
+			e.content = content
+		}
+
+		return entries
+	})
+
+	dst := memory.Must(&url.URL{Scheme: "memory"})
+
+	if _, err := Restore(ctx, bytes.NewReader(archive), dst, nil, false); err == nil || err.Error() != "record counts differ for users" {
+		t.Fatalf("expected differing counts to be detected, got %v", err)
+	}
+}
+
+// entry defines a file of a rewritten archive.
+type entry struct {
+	name    string
+	content []byte
+}
+
+// rewrite reads all files of the archive and writes them again after the
+// tamper function has been applied.
+func rewrite(t *testing.T, archive []byte, tamper func([]*entry) []*entry) []byte {
+	t.Helper()
+
+	gr, err := gzip.NewReader(bytes.NewReader(archive))
+	must(t, err)
+
+	var (
+		entries []*entry
+		tr      = tar.NewReader(gr)
+	)
+
+	for {
+		header, err := tr.Next()
+
+		if err != nil {
+			break
+		}
+
+		content, err := ioutil.ReadAll(tr)
+		must(t, err)
+
+		entries = append(entries, &entry{name: header.Name, content: content})
+	}
+
+	buf := &bytes.Buffer{}
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+
+	for _, e := range tamper(entries) {
+		must(t, tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     e.name,
+			Size:     int64(len(e.content)),
+			Mode:     0644,
+		}))
+
+		_, err := tw.Write(e.content)
+		must(t, err)
+	}
+
+	must(t, tw.Close())
+	must(t, gw.Close())
+
+	return buf.Bytes()
+}
+
+// seed creates a store with a mod, a version and its uploaded file.
+func seed(t *testing.T, dir string) (store.Store, upload.Upload) {
+	t.Helper()
+
+	ctx := context.Background()
+	s := memory.Must(&url.URL{Scheme: "memory"})
+	uploads := file.Must(&url.URL{Scheme: "file", Path: dir})
+
+	_, err := s.Users().Create(ctx, &model.User{Username: "Alice"})
+	must(t, err)
+
+	mod, err := s.Mods().Create(ctx, &model.Mod{Name: "JEI"})
+	must(t, err)
+
+	digest, err := files.Put(ctx, uploads, "mods/jei-1.0.jar", strings.NewReader("jei"), -1, "application/java-archive")
+	must(t, err)
+
+	version := &model.Version{ModID: mod.ID, Name: "1.0"}
+	files.Assign(version, "mods/jei-1.0.jar", "jei.jar", digest)
+
+	version, err = s.Versions().Create(ctx, version)
+	must(t, err)
+
+	pack, err := s.Packs().Create(ctx, &model.Pack{Name: "Hexxit"})
+	must(t, err)
+
+	build, err := s.Builds().Create(ctx, &model.Build{PackID: pack.ID, Name: "1.0.0"})
+	must(t, err)
+
+	must(t, s.BuildVersions().Append(ctx, &model.BuildVersion{BuildID: build.ID, VersionID: version.ID}))
+
+	return s, uploads
+}
+
+// compare checks that both stores contain the same number of records.
+func compare(t *testing.T, src, dst store.Store) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	expected, err := store.Count(ctx, src)
+	must(t, err)
+
+	got, err := store.Count(ctx, dst)
+	must(t, err)
+
+	if diff := expected.Diff(got); len(diff) != 0 {
+		t.Fatalf("expected equal counts, got differences for %v", diff)
+	}
+}
+
+// tempdir creates a temporary directory for the uploads.
+func tempdir(t *testing.T) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "kleister-backup")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return dir
+}
+
+// must fails the test on any unexpected error.
+func must(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package backup
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kleister/kleister-api/pkg/admin"
+	"github.com/kleister/kleister-api/pkg/store"
+	"github.com/kleister/kleister-api/pkg/upload"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/hlog"
+)
+
+var (
+	// ErrInvalidToken is returned when the request token is invalid.
+	ErrInvalidToken = errors.New("invalid or missing token")
+)
+
+// Handler streams a backup archive of the store, the uploads get included
+// if they have been requested by the uploads query parameter. The request
+// has to provide the admin token as bearer token.
+func Handler(token string, s store.Store, uploads upload.Upload) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !admin.Authorized(token, r) {
+			http.Error(w, ErrInvalidToken.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		var (
+			included upload.Upload
+		)
+
+		if val := r.URL.Query().Get("uploads"); val != "" {
+			enabled, err := strconv.ParseBool(val)
+
+			if err != nil {
+				http.Error(w, "invalid uploads parameter", http.StatusBadRequest)
+				return
+			}
+
+			if enabled {
+				included = uploads
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/gzip")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(
+			"attachment; filename=\"kleister-%s.tar.gz\"",
+			time.Now().UTC().Format("20060102-150405"),
+		))
+
+		out := &response{ResponseWriter: w}
+		m, err := Write(r.Context(), out, s, included)
+
+		if err != nil {
+			hlog.FromRequest(r).Error().
+				Err(err).
+				Msg("failed to write backup")
+
+			// After the first written bytes the status has already been sent,
+			// the client detects the failure by the truncated archive.
+			if !out.started {
+				w.Header().Del("Content-Disposition")
+				http.Error(w, "failed to write backup", http.StatusInternalServerError)
+			}
+
+			return
+		}
+
+		hlog.FromRequest(r).Info().
+			Int("files", len(m.Files)).
+			Msg("wrote backup")
+	}
+}
+
+// response records if any content has been written to the client.
+type response struct {
+	http.ResponseWriter
+	started bool
+}
+
+// Write implements the io.Writer interface.
+func (r *response) Write(p []byte) (int, error) {
+	r.started = true
+	return r.ResponseWriter.Write(p)
+}
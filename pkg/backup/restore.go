@@ -0,0 +1,272 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/kleister/kleister-api/pkg/store"
+	"github.com/kleister/kleister-api/pkg/upload"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrNotEmpty defines a named error if the restore target has content.
+	ErrNotEmpty = errors.New("restore target is not empty")
+)
+
+// Verify reads the whole archive and checks all files against the checksums
+// of the manifest, files which are missing within the manifest are invalid.
+func Verify(r io.Reader) (*Manifest, error) {
+	var (
+		m     *Manifest
+		found = make(map[string]*File)
+	)
+
+	if err := walk(r, func(name string, content io.Reader) error {
+		if name == manifestName {
+			m = &Manifest{}
+
+			if err := json.NewDecoder(content).Decode(m); err != nil {
+				return errors.Wrap(err, "failed to parse manifest")
+			}
+
+			return nil
+		}
+
+		hash := sha256.New()
+		size, err := io.Copy(hash, content)
+
+		if err != nil {
+			return err
+		}
+
+		found[name] = &File{
+			Name:   name,
+			Size:   size,
+			SHA256: hex.EncodeToString(hash.Sum(nil)),
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if m == nil {
+		return nil, errors.New("archive doesn't contain a manifest")
+	}
+
+	if m.Version != Version {
+		return nil, errors.Errorf("unsupported archive version %d", m.Version)
+	}
+
+	if _, ok := found[databaseName]; !ok {
+		return nil, errors.New("archive doesn't contain a database")
+	}
+
+	for _, expected := range m.Files {
+		actual, ok := found[expected.Name]
+
+		if !ok {
+			return nil, errors.Errorf("archive is missing %s", expected.Name)
+		}
+
+		if actual.Size != expected.Size || actual.SHA256 != expected.SHA256 {
+			return nil, errors.Errorf("checksum mismatch for %s", expected.Name)
+		}
+
+		delete(found, expected.Name)
+	}
+
+	for name := range found {
+		return nil, errors.Errorf("archive contains unlisted file %s", name)
+	}
+
+	return m, nil
+}
+
+// Restore verifies the archive and imports the records into the store, the
+// uploaded objects get restored if uploads is not nil. It refuses to restore
+// into a store or uploads with content unless force is set, the schema of a
+// forced store gets recreated by reverting and applying the migrations.
+func Restore(ctx context.Context, r io.ReadSeeker, s store.Store, uploads upload.Upload, force bool) (*Manifest, error) {
+	m, err := Verify(r)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	counts, err := store.Count(ctx, s)
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to count records")
+	}
+
+	if len(counts.Diff(store.Counts{})) > 0 {
+		if !force {
+			return nil, errors.Wrap(ErrNotEmpty, "database has records")
+		}
+
+		if err := reset(ctx, s); err != nil {
+			return nil, errors.Wrap(err, "failed to reset database")
+		}
+	}
+
+	if uploads != nil && m.Uploads() && !force {
+		objects, err := uploads.List(ctx, "")
+
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list uploads")
+		}
+
+		if len(objects) > 0 {
+			return nil, errors.Wrap(ErrNotEmpty, "uploads have objects")
+		}
+	}
+
+	files := make(map[string]*File, len(m.Files))
+
+	for _, file := range m.Files {
+		files[file.Name] = file
+	}
+
+	if err := walk(r, func(name string, content io.Reader) error {
+		switch {
+		case name == databaseName:
+			d := &store.Dump{}
+
+			if err := json.NewDecoder(content).Decode(d); err != nil {
+				return errors.Wrap(err, "failed to parse database")
+			}
+
+			if err := s.Transaction(ctx, func(tx store.Repositories) error {
+				return store.Import(ctx, d, tx)
+			}); err != nil {
+				return errors.Wrap(err, "failed to import database")
+			}
+		case uploads != nil && strings.HasPrefix(name, uploadsPrefix+"/"):
+			if _, err := uploads.Put(
+				ctx,
+				strings.TrimPrefix(name, uploadsPrefix+"/"),
+				content,
+				files[name].Size,
+				files[name].ContentType,
+			); err != nil {
+				return errors.Wrapf(err, "failed to restore %s", name)
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	counts, err = store.Count(ctx, s)
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to count records")
+	}
+
+	if diff := m.Counts.Diff(counts); len(diff) > 0 {
+		return nil, errors.Errorf("record counts differ for %s", strings.Join(diff, ", "))
+	}
+
+	return m, nil
+}
+
+// walk calls the function for every file of the archive, it rejects any
+// file which is not expected within an archive.
+func walk(r io.Reader, fn func(string, io.Reader) error) error {
+	gr, err := gzip.NewReader(r)
+
+	if err != nil {
+		return errors.Wrap(err, "failed to open archive")
+	}
+
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+
+	for {
+		header, err := tr.Next()
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return errors.Wrap(err, "failed to read archive")
+		}
+
+		if header.Typeflag != tar.TypeReg || !valid(header.Name) {
+			return errors.Errorf("archive contains invalid file %s", header.Name)
+		}
+
+		if err := fn(header.Name, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// valid checks if the name is allowed within an archive.
+func valid(name string) bool {
+	switch name {
+	case manifestName, databaseName:
+		return true
+	}
+
+	if !strings.HasPrefix(name, uploadsPrefix+"/") || path.Clean(name) != name {
+		return false
+	}
+
+	for _, part := range strings.Split(name, "/") {
+		if part == ".." {
+			return false
+		}
+	}
+
+	return true
+}
+
+// reset reverts all applied migrations and applies them again to get a
+// store with empty tables. Stores without reversible migrations like the
+// memory store keep their records, they can't be reset.
+func reset(ctx context.Context, s store.Store) error {
+	for {
+		records, err := s.Migrations().Down(ctx, false)
+
+		if err != nil {
+			return err
+		}
+
+		if len(records) == 0 {
+			break
+		}
+	}
+
+	if _, err := s.Migrations().Up(ctx, false); err != nil {
+		return err
+	}
+
+	counts, err := store.Count(ctx, s)
+
+	if err != nil {
+		return err
+	}
+
+	if diff := counts.Diff(store.Counts{}); len(diff) > 0 {
+		return errors.Errorf("reverting the migrations kept records for %s", strings.Join(diff, ", "))
+	}
+
+	return nil
+}
@@ -1,13 +1,36 @@
 package config
 
+import (
+	"time"
+)
+
 // Database defines the database configuration.
 type Database struct {
-	DSN string
+	DSN     string
+	Migrate bool
 }
 
 // Upload defines the asset upload configuration.
 type Upload struct {
-	DSN string
+	DSN             string
+	GCInterval      time.Duration
+	GCGrace         time.Duration
+	Expire          time.Duration
+	Key             string
+	KeyFile         string
+	PreviousKey     string
+	PreviousKeyFile string
+}
+
+// Quota defines the default storage quotas in bytes.
+type Quota struct {
+	User int64
+	Team int64
+}
+
+// Token defines the secret to sign tokens and downloads.
+type Token struct {
+	Secret string
 }
 
 // Server defines the webserver configuration.
@@ -31,6 +54,7 @@ type Admin struct {
 	Username string
 	Password string
 	Email    string
+	Token    string
 }
 
 // Logs defines the level and color for log configuration.
@@ -50,6 +74,8 @@ type Tracing struct {
 type Config struct {
 	Database Database
 	Upload   Upload
+	Quota    Quota
+	Token    Token
 	Server   Server
 	Metrics  Metrics
 	Admin    Admin
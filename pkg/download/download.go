@@ -0,0 +1,192 @@
+// Package download protects the uploads of private and hidden packs, these
+// can only be downloaded by URLs which are signed with the token secret and
+// expire after some time. It also defines how long downloads get cached.
+package download
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kleister/kleister-api/pkg/files"
+	"github.com/kleister/kleister-api/pkg/images"
+	"github.com/kleister/kleister-api/pkg/store"
+	"github.com/kleister/kleister-api/pkg/token"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/hlog"
+)
+
+const (
+	// Param defines the query parameter which contains the signature.
+	Param = "token"
+
+	// maxAge defines the lifetime of cached immutable objects in seconds.
+	maxAge = 365 * 24 * 60 * 60
+)
+
+var (
+	// ErrInvalidSignature defines a named error for signatures which don't
+	// match the requested object or which are expired.
+	ErrInvalidSignature = errors.New("invalid signature")
+)
+
+// Protected checks if an uploaded object is only used by protected builds.
+// Builds are protected if they are private or hidden or if they belong to a
+// private or hidden pack, objects which aren't used by builds are public.
+// Images are protected if their pack is private or hidden.
+func Protected(ctx context.Context, s store.Repositories, key string) (bool, error) {
+	if id := images.Pack(key); id != "" {
+		pack, err := s.Packs().Show(ctx, id)
+
+		if err == store.ErrNotFound {
+			return false, nil
+		}
+
+		if err != nil {
+			return false, err
+		}
+
+		return pack.Private || pack.Hidden, nil
+	}
+
+	versions, err := s.Versions().ListByFile(ctx, key)
+
+	if err != nil {
+		return false, err
+	}
+
+	if len(versions) == 0 {
+		return false, nil
+	}
+
+	packs := make(map[string]bool)
+
+	for _, version := range versions {
+		assigned, err := s.BuildVersions().ListByVersion(ctx, version.ID)
+
+		if err != nil {
+			return false, err
+		}
+
+		if len(assigned) == 0 {
+			return false, nil
+		}
+
+		for _, a := range assigned {
+			if a.Build.Private || a.Build.Hidden {
+				continue
+			}
+
+			private, ok := packs[a.Build.PackID]
+
+			if !ok {
+				pack, err := s.Packs().Show(ctx, a.Build.PackID)
+
+				if err != nil {
+					return false, err
+				}
+
+				private = pack.Private || pack.Hidden
+				packs[a.Build.PackID] = private
+			}
+
+			if !private {
+				return false, nil
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// Sign appends a signature for the object key to the URL which expires
+// after the given duration.
+func Sign(secret, rawurl, key string, expire time.Duration) (string, error) {
+	result, err := token.New(token.DownloadToken, key).SignExpiring(secret, expire)
+
+	if err != nil {
+		return "", errors.Wrap(err, "failed to sign download")
+	}
+
+	u, err := url.Parse(rawurl)
+
+	if err != nil {
+		return "", err
+	}
+
+	query := u.Query()
+	query.Set(Param, result.Token)
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+// Verify checks if the signature belongs to the object key and if it is
+// not expired yet.
+func Verify(secret, key, signature string) error {
+	parsed, err := token.Direct(signature, func(t *token.Token) ([]byte, error) {
+		return token.Key(secret)
+	})
+
+	if err != nil || parsed.Kind != token.DownloadToken || parsed.Text != key {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// Handler wraps the handler of the storage, requests for protected objects
+// get refused without a valid signature.
+func Handler(secret, root string, s store.Store, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.URL.Path, root+"/")
+
+		if signature := r.URL.Query().Get(Param); signature != "" {
+			if err := Verify(secret, key, signature); err == nil {
+				w.Header().Set("Cache-Control", CacheControl(key, true))
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		protected, err := Protected(r.Context(), s, key)
+
+		if err != nil {
+			hlog.FromRequest(r).Error().
+				Err(err).
+				Str("key", key).
+				Msg("failed to check protection of download")
+
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		if protected {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+
+		w.Header().Set("Cache-Control", CacheControl(key, false))
+		next.ServeHTTP(w, r)
+	})
+}
+
+// CacheControl defines the caching of an object. Objects of single uploads
+// never change and get cached for a year, others have to be revalidated by
+// their ETag. Protected objects must not be stored by shared caches.
+func CacheControl(key string, protected bool) string {
+	scope := "public"
+
+	if protected {
+		scope = "private"
+	}
+
+	if !files.Immutable(key) {
+		return scope + ", no-cache"
+	}
+
+	return fmt.Sprintf("%s, max-age=%d, immutable", scope, maxAge)
+}
@@ -0,0 +1,122 @@
+package download
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store/memory"
+	"github.com/kleister/kleister-api/pkg/token"
+)
+
+func TestHandler(t *testing.T) {
+	ctx := context.Background()
+	s := memory.Must(&url.URL{Scheme: "memory"})
+
+	secret, err := token.Generate()
+	must(t, err)
+
+	public, err := s.Packs().Create(ctx, &model.Pack{Name: "Public"})
+	must(t, err)
+
+	private, err := s.Packs().Create(ctx, &model.Pack{Name: "Private", Private: true})
+	must(t, err)
+
+	mod, err := s.Mods().Create(ctx, &model.Mod{Name: "Foo"})
+	must(t, err)
+
+	builds := map[string]*model.Build{
+		"hidden":  {PackID: public.ID, Name: "1.0", Hidden: true},
+		"private": {PackID: private.ID, Name: "1.0"},
+		"public":  {PackID: public.ID, Name: "2.0"},
+	}
+
+	for name, build := range builds {
+		record, err := s.Builds().Create(ctx, build)
+		must(t, err)
+
+		builds[name] = record
+	}
+
+	for key, assigned := range map[string][]string{
+		"mods/hidden.jar": {"hidden"},
+		"mods/secret.jar": {"hidden", "private"},
+		"mods/shared.jar": {"private", "public"},
+		"mods/loose.jar":  {},
+	} {
+		version, err := s.Versions().Create(ctx, &model.Version{ModID: mod.ID, Name: key, FileKey: key})
+		must(t, err)
+
+		for _, name := range assigned {
+			must(t, s.BuildVersions().Append(ctx, &model.BuildVersion{BuildID: builds[name].ID, VersionID: version.ID}))
+		}
+	}
+
+	handler := Handler(secret, "/storage", s, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	signed, err := Sign(secret, "/storage/mods/secret.jar", "mods/secret.jar", time.Hour)
+	must(t, err)
+
+	other, err := Sign(secret, "/storage/mods/hidden.jar", "mods/secret.jar", time.Hour)
+	must(t, err)
+
+	expired := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"type": token.DownloadToken,
+		"text": "mods/secret.jar",
+		"exp":  time.Now().Add(-time.Minute).Unix(),
+	})
+
+	key, err := token.Key(secret)
+	must(t, err)
+
+	raw, err := expired.SignedString(key)
+	must(t, err)
+
+	var (
+		image         = "/logo/" + uuid.New().String() + "/launcher.png"
+		privateImage  = "packs/" + private.ID + image
+		publicImage   = "packs/" + public.ID + image
+		orphanedImage = "packs/" + uuid.New().String() + image
+	)
+
+	signedImage, err := Sign(secret, "/storage/"+privateImage, privateImage, time.Hour)
+	must(t, err)
+
+	for target, status := range map[string]int{
+		"/storage/" + privateImage:              http.StatusForbidden,
+		"/storage/" + publicImage:               http.StatusOK,
+		"/storage/" + orphanedImage:             http.StatusOK,
+		signedImage:                             http.StatusOK,
+		"/storage/mods/loose.jar":               http.StatusOK,
+		"/storage/mods/shared.jar":              http.StatusOK,
+		"/storage/mods/unknown.jar":             http.StatusOK,
+		"/storage/mods/hidden.jar":              http.StatusForbidden,
+		"/storage/mods/secret.jar":              http.StatusForbidden,
+		"/storage/mods/secret.jar?token=" + raw: http.StatusForbidden,
+		other:                                   http.StatusForbidden,
+		signed:                                  http.StatusOK,
+	} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+
+		if w.Code != status {
+			t.Errorf("expected status %d for %s, got %d", status, target, w.Code)
+		}
+	}
+}
+
+func must(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,185 @@
+// Package files stores the files of mod versions within the uploads and
+// records their name, size and checksums on the versions.
+package files
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/modinfo"
+	"github.com/kleister/kleister-api/pkg/quota"
+	"github.com/kleister/kleister-api/pkg/store"
+	"github.com/kleister/kleister-api/pkg/upload"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrInvalidName defines a named error for file names which can't be stored.
+	ErrInvalidName = errors.New("invalid file name")
+)
+
+// Digest describes the size and checksums of a stored file.
+type Digest struct {
+	Size   int64
+	MD5    string
+	SHA1   string
+	SHA256 string
+}
+
+// Name strips any directory from the name of an uploaded file, names which
+// are empty or hidden get rejected.
+func Name(name string) (string, error) {
+	name = path.Base(strings.Replace(name, "\\", "/", -1))
+
+	if name == "" || name == "/" || strings.HasPrefix(name, ".") {
+		return "", ErrInvalidName
+	}
+
+	return name, nil
+}
+
+// Immutable checks if the key has been generated for a single upload, these
+// keys contain a random UUID as parent directory of the file and never get
+// replaced.
+func Immutable(key string) bool {
+	_, err := uuid.Parse(path.Base(path.Dir(key)))
+	return err == nil
+}
+
+// Put streams the content into the uploads and calculates all checksums
+// while writing.
+func Put(ctx context.Context, uploads upload.Upload, key string, r io.Reader, size int64, contentType string) (*Digest, error) {
+	var (
+		md5sum    = md5.New()
+		sha1sum   = sha1.New()
+		sha256sum = sha256.New()
+	)
+
+	obj, err := uploads.Put(
+		ctx,
+		key,
+		io.TeeReader(r, io.MultiWriter(md5sum, sha1sum, sha256sum)),
+		size,
+		contentType,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Digest{
+		Size:   obj.Size,
+		MD5:    hex.EncodeToString(md5sum.Sum(nil)),
+		SHA1:   hex.EncodeToString(sha1sum.Sum(nil)),
+		SHA256: hex.EncodeToString(sha256sum.Sum(nil)),
+	}, nil
+}
+
+// Assign records the stored file on the version, the metadata of a previous
+// file gets cleared.
+func Assign(record *model.Version, key, name string, digest *Digest) {
+	record.FileKey = key
+	record.FileName = name
+	record.FileSize = digest.Size
+	record.FileMD5 = digest.MD5
+	record.FileSHA1 = digest.SHA1
+	record.FileSHA256 = digest.SHA256
+	record.FileLoader = ""
+	record.FileMinecraft = ""
+	record.FileBroken = false
+}
+
+// Attach stores the file of a version and replaces a previous file. The
+// content gets written below a new key, this way the previous file stays
+// intact until the version has been updated. The quotas get checked against
+// the streamed size within the same transaction, this way uploads of unknown
+// size and concurrent uploads can't exceed them. If metadata of the file is
+// given it gets applied to the mod and version, mismatches are returned as
+// warnings. The updated version is also returned if only the removal of the
+// previous file failed.
+func Attach(ctx context.Context, s store.Store, uploads upload.Upload, modID, versionID, name string, r io.Reader, size int64, contentType string, info *modinfo.Info, limits quota.Limits) (*model.Version, []string, error) {
+	name, err := Name(name)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mod, err := s.Mods().Show(ctx, modID)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	version, err := s.Versions().Show(ctx, mod.ID, versionID)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key := path.Join("mods", mod.ID, version.ID, uuid.New().String(), name)
+	digest, err := Put(ctx, uploads, key, r, size, contentType)
+
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to store file")
+	}
+
+	var (
+		record   *model.Version
+		warnings []string
+		previous string
+	)
+
+	if err := s.Transaction(ctx, func(tx store.Repositories) error {
+		current, err := tx.Versions().Show(ctx, mod.ID, version.ID)
+
+		if err != nil {
+			return err
+		}
+
+		if err := quota.Check(ctx, tx, limits, mod.ID, version.ID, digest.Size); err != nil {
+			return err
+		}
+
+		previous = current.FileKey
+		Assign(current, key, name, digest)
+
+		if info != nil {
+			parent, err := tx.Mods().Show(ctx, mod.ID)
+
+			if err != nil {
+				return err
+			}
+
+			changed, messages := info.Apply(parent, current)
+			warnings = messages
+
+			if changed {
+				if _, err := tx.Mods().Update(ctx, parent); err != nil {
+					return err
+				}
+			}
+		}
+
+		record, err = tx.Versions().Update(ctx, current)
+		return err
+	}); err != nil {
+		uploads.Delete(ctx, key)
+		return nil, nil, err
+	}
+
+	if previous != "" && previous != key {
+		if err := uploads.Delete(ctx, previous); err != nil && err != upload.ErrNotFound {
+			return record, warnings, errors.Wrap(err, "failed to remove previous file")
+		}
+	}
+
+	return record, warnings, nil
+}
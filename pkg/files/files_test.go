@@ -0,0 +1,168 @@
+package files
+
+import (
+	"context"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/quota"
+	"github.com/kleister/kleister-api/pkg/store"
+	"github.com/kleister/kleister-api/pkg/store/memory"
+	"github.com/kleister/kleister-api/pkg/upload"
+	"github.com/kleister/kleister-api/pkg/upload/file"
+	"github.com/pkg/errors"
+)
+
+var (
+	errCommit = errors.New("commit failed")
+)
+
+// hooked wraps a store to inspect or fail the transactions of attach.
+type hooked struct {
+	store.Store
+
+	before func() error
+	after  func() error
+}
+
+// Transaction calls the hooks around the function within the transaction.
+func (h *hooked) Transaction(ctx context.Context, fn func(store.Repositories) error) error {
+	return h.Store.Transaction(ctx, func(tx store.Repositories) error {
+		if h.before != nil {
+			if err := h.before(); err != nil {
+				return err
+			}
+		}
+
+		if err := fn(tx); err != nil {
+			return err
+		}
+
+		if h.after != nil {
+			return h.after()
+		}
+
+		return nil
+	})
+}
+
+func TestAttach(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kleister-files")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	uploads := file.Must(&url.URL{Scheme: "file", Path: dir})
+	s := memory.Must(&url.URL{Scheme: "memory"})
+
+	mod, err := s.Mods().Create(ctx, &model.Mod{Name: "Foo"})
+	must(t, err)
+
+	version, err := s.Versions().Create(ctx, &model.Version{ModID: mod.ID, Name: "1.0"})
+	must(t, err)
+
+	first, _, err := Attach(ctx, s, uploads, mod.Slug, version.Slug, "dir/foo.jar", strings.NewReader("first"), 5, "", nil, quota.Limits{})
+	must(t, err)
+
+	for expected, actual := range map[string]string{
+		"foo.jar":                                  first.FileName,
+		"8b04d5e3775d298e78455efc5ca404d5":         first.FileMD5,
+		"e0996a37c13d44c3b06074939d43fa3759bd32c1": first.FileSHA1,
+		"a7937b64b8caa58f03721bb6bacf5c78cb235febe0e70b1b84cd99541461a08e": first.FileSHA256,
+	} {
+		if actual != expected {
+			t.Errorf("expected %s to be recorded, got %s", expected, actual)
+		}
+	}
+
+	if first.FileSize != 5 || !Immutable(first.FileKey) {
+		t.Fatalf("expected an immutable key with the size, got %s of %d bytes", first.FileKey, first.FileSize)
+	}
+
+	failing := &hooked{
+		Store: s,
+		after: func() error {
+			return errCommit
+		},
+	}
+
+	if _, _, err := Attach(ctx, failing, uploads, mod.ID, version.ID, "foo.jar", strings.NewReader("second"), 6, "", nil, quota.Limits{}); err != errCommit {
+		t.Fatalf("expected failed transaction to be returned, got %v", err)
+	}
+
+	objects, err := uploads.List(ctx, "mods/")
+	must(t, err)
+
+	if len(objects) != 1 || objects[0].Key != first.FileKey {
+		t.Fatalf("expected only the previous file to be kept, got %d objects", len(objects))
+	}
+
+	current, err := s.Versions().Show(ctx, mod.ID, version.ID)
+	must(t, err)
+
+	if current.FileKey != first.FileKey || current.FileMD5 != first.FileMD5 {
+		t.Fatalf("expected version to keep the previous file")
+	}
+
+	committing := &hooked{
+		Store: s,
+		before: func() error {
+			_, err := uploads.Stat(ctx, first.FileKey)
+			return err
+		},
+	}
+
+	third, _, err := Attach(ctx, committing, uploads, mod.ID, version.ID, "foo.jar", strings.NewReader("third"), -1, "", nil, quota.Limits{})
+	must(t, err)
+
+	if third.FileKey == first.FileKey || third.FileSize != 5 {
+		t.Fatalf("expected a new key with the streamed size, got %s of %d bytes", third.FileKey, third.FileSize)
+	}
+
+	if _, err := uploads.Stat(ctx, first.FileKey); err != upload.ErrNotFound {
+		t.Fatalf("expected previous file to be removed after the commit, got %v", err)
+	}
+
+	if _, _, err := Attach(ctx, s, uploads, mod.ID, version.ID, "foo.jar", strings.NewReader("short"), 10, "", nil, quota.Limits{}); errors.Cause(err) != upload.ErrSizeMismatch {
+		t.Fatalf("expected size mismatch, got %v", err)
+	}
+
+	user, err := s.Users().Create(ctx, &model.User{Username: "alice", Quota: 8})
+	must(t, err)
+
+	must(t, s.UserMods().Append(ctx, &model.UserMod{UserID: user.ID, ModID: mod.ID, Perm: model.PermOwner}))
+
+	if _, _, err := Attach(ctx, s, uploads, mod.ID, version.ID, "foo.jar", strings.NewReader("exceeding"), -1, "", nil, quota.Limits{}); errors.Cause(err) != quota.ErrExceeded {
+		t.Fatalf("expected streamed size to exceed the quota, got %v", err)
+	}
+
+	for _, name := range []string{"", ".hidden", "dir/.."} {
+		if _, _, err := Attach(ctx, s, uploads, mod.ID, version.ID, name, strings.NewReader("x"), 1, "", nil, quota.Limits{}); err != ErrInvalidName {
+			t.Errorf("expected %q to be an invalid name, got %v", name, err)
+		}
+	}
+
+	objects, err = uploads.List(ctx, "mods/")
+	must(t, err)
+
+	if len(objects) != 1 || objects[0].Key != third.FileKey {
+		t.Fatalf("expected only the current file to be kept, got %d objects", len(objects))
+	}
+}
+
+// must fails the test on any unexpected error.
+func must(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,303 @@
+// Package gc detects uploaded objects which are not referenced by the store
+// anymore and removes them after a grace period.
+package gc
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kleister/kleister-api/pkg/images"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+	"github.com/kleister/kleister-api/pkg/upload"
+	"github.com/kleister/kleister-api/pkg/upload/dedup"
+	"github.com/pkg/errors"
+)
+
+const (
+	// KindObject marks an object which has been stored without a mapping.
+	KindObject = "object"
+
+	// KindLink marks a logical key mapped to a blob.
+	KindLink = "link"
+
+	// KindBlob marks the deduplicated content of one or more links.
+	KindBlob = "blob"
+)
+
+// Orphan describes an unreferenced object, it only gets deleted if it is
+// expired, which means it hasn't been modified within the grace period.
+type Orphan struct {
+	Kind       string
+	Key        string
+	Size       int64
+	ModifiedAt time.Time
+	Expired    bool
+	Deleted    bool
+}
+
+// Report lists all orphans, the size sums up the stored bytes of the
+// orphaned objects and blobs while the links don't occupy any storage.
+type Report struct {
+	Orphans []*Orphan
+	Size    int64
+	Deleted int
+	Freed   int64
+}
+
+// Collect compares the objects of the plain uploads with the references of
+// the store. Orphans get only deleted if apply is set, a blob is orphaned
+// if all of its links are orphaned.
+func Collect(ctx context.Context, s store.Store, uploads upload.Upload, grace time.Duration, apply bool) (*Report, error) {
+	var (
+		refs  = make(map[string]bool)
+		links []*model.Link
+		blobs []*model.Blob
+	)
+
+	if err := s.Snapshot(ctx, func(tx store.Repositories) error {
+		var err error
+
+		if err = references(ctx, tx, refs); err != nil {
+			return err
+		}
+
+		if links, err = tx.Links().List(ctx); err != nil {
+			return errors.Wrap(err, "failed to list links")
+		}
+
+		if blobs, err = tx.Blobs().List(ctx); err != nil {
+			return errors.Wrap(err, "failed to list blobs")
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	objects, err := uploads.List(ctx, "")
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list uploads")
+	}
+
+	var (
+		deadline = time.Now().UTC().Add(-grace)
+		report   = &Report{Orphans: make([]*Orphan, 0)}
+		linked   = make(map[string]bool, len(links))
+		orphaned = make(map[string][]*Orphan)
+		stored   = make(map[string]*upload.Object)
+	)
+
+	for _, link := range links {
+		linked[link.Path] = true
+
+		if refs[link.Path] {
+			continue
+		}
+
+		orphaned[link.Digest] = append(orphaned[link.Digest], &Orphan{
+			Kind:       KindLink,
+			Key:        link.Path,
+			ModifiedAt: link.UpdatedAt,
+			Expired:    !link.UpdatedAt.After(deadline),
+		})
+	}
+
+	for _, obj := range objects {
+		if strings.HasPrefix(obj.Key, dedup.BlobPrefix) {
+			stored[path.Base(obj.Key)] = obj
+			continue
+		}
+
+		if refs[obj.Key] || linked[obj.Key] {
+			continue
+		}
+
+		report.add(&Orphan{
+			Kind:       KindObject,
+			Key:        obj.Key,
+			Size:       obj.Size,
+			ModifiedAt: obj.ModifiedAt,
+			Expired:    !obj.ModifiedAt.After(deadline),
+		}, true)
+	}
+
+	for _, blob := range blobs {
+		obj := stored[blob.Digest]
+		delete(stored, blob.Digest)
+
+		candidates := orphaned[blob.Digest]
+
+		for _, orphan := range candidates {
+			orphan.Size = blob.Size
+			report.add(orphan, false)
+		}
+
+		if len(candidates) < blob.References {
+			continue
+		}
+
+		orphan := &Orphan{
+			Kind:       KindBlob,
+			Key:        dedup.Key(blob.Digest),
+			Size:       blob.Size,
+			ModifiedAt: blob.UpdatedAt,
+			Expired:    !blob.UpdatedAt.After(deadline),
+		}
+
+		if obj == nil {
+			orphan.Size = 0
+		} else if obj.ModifiedAt.After(orphan.ModifiedAt) {
+			orphan.ModifiedAt = obj.ModifiedAt
+			orphan.Expired = !obj.ModifiedAt.After(deadline)
+		}
+
+		for _, candidate := range candidates {
+			orphan.Expired = orphan.Expired && candidate.Expired
+		}
+
+		report.add(orphan, true)
+	}
+
+	for _, obj := range stored {
+		report.add(&Orphan{
+			Kind:       KindBlob,
+			Key:        obj.Key,
+			Size:       obj.Size,
+			ModifiedAt: obj.ModifiedAt,
+			Expired:    !obj.ModifiedAt.After(deadline),
+		}, true)
+	}
+
+	sort.SliceStable(report.Orphans, func(i, j int) bool {
+		return report.Orphans[i].Key < report.Orphans[j].Key
+	})
+
+	if apply {
+		if err := report.delete(ctx, s, uploads); err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+// add appends the orphan to the report, the size only gets counted if the
+// orphan occupies storage.
+func (r *Report) add(orphan *Orphan, stored bool) {
+	r.Orphans = append(r.Orphans, orphan)
+
+	if stored {
+		r.Size += orphan.Size
+	}
+}
+
+// delete removes all expired orphans, the links get removed first as the
+// blobs can only be removed without any references.
+func (r *Report) delete(ctx context.Context, s store.Store, uploads upload.Upload) error {
+	for _, kind := range []string{KindLink, KindObject, KindBlob} {
+		for _, orphan := range r.Orphans {
+			if orphan.Kind != kind || !orphan.Expired {
+				continue
+			}
+
+			var err error
+
+			switch kind {
+			case KindLink:
+				err = s.Links().Delete(ctx, orphan.Key)
+			case KindObject:
+				err = uploads.Delete(ctx, orphan.Key)
+			case KindBlob:
+				err = blob(ctx, s, uploads, orphan.Key)
+
+				// A conflict means the content has been linked again in the
+				// meantime, it must be kept.
+				if err == store.ErrConflict {
+					continue
+				}
+			}
+
+			if err != nil && err != store.ErrNotFound && err != upload.ErrNotFound {
+				return errors.Wrapf(err, "failed to delete %s", orphan.Key)
+			}
+
+			orphan.Deleted = true
+			r.Deleted++
+
+			if kind != KindLink {
+				r.Freed += orphan.Size
+			}
+		}
+	}
+
+	return nil
+}
+
+// blob removes the record and the content of a blob within a transaction.
+// Deleting the record checks the references right before the content gets
+// removed and keeps the record locked, this way a concurrent upload can't
+// link the content until it is gone and stores it again.
+func blob(ctx context.Context, s store.Store, uploads upload.Upload, key string) error {
+	return s.Transaction(ctx, func(tx store.Repositories) error {
+		if err := tx.Blobs().Delete(ctx, path.Base(key)); err != nil && err != store.ErrNotFound {
+			return err
+		}
+
+		if err := uploads.Delete(ctx, key); err != nil && err != upload.ErrNotFound {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// references collects the keys of all uploads which are referenced by
+// records of the store.
+func references(ctx context.Context, s store.Repositories, refs map[string]bool) error {
+	mods, err := s.Mods().List(ctx)
+
+	if err != nil {
+		return errors.Wrap(err, "failed to list mods")
+	}
+
+	for _, mod := range mods {
+		versions, err := s.Versions().List(ctx, mod.ID)
+
+		if err != nil {
+			return errors.Wrap(err, "failed to list versions")
+		}
+
+		for _, version := range versions {
+			if version.FileKey != "" {
+				refs[version.FileKey] = true
+			}
+		}
+	}
+
+	packs, err := s.Packs().List(ctx)
+
+	if err != nil {
+		return errors.Wrap(err, "failed to list packs")
+	}
+
+	for _, pack := range packs {
+		for _, kind := range images.Kinds {
+			key, _ := kind.Get(pack)
+
+			if key == "" {
+				continue
+			}
+
+			for _, variant := range images.Keys(key) {
+				refs[variant] = true
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,236 @@
+package gc
+
+import (
+	"context"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kleister/kleister-api/pkg/images"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store/memory"
+	"github.com/kleister/kleister-api/pkg/upload"
+	"github.com/kleister/kleister-api/pkg/upload/dedup"
+	"github.com/kleister/kleister-api/pkg/upload/file"
+)
+
+func TestCollect(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kleister-gc")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	raw := file.Must(&url.URL{Scheme: "file", Path: dir})
+	s := memory.Must(&url.URL{Scheme: "memory"})
+	content := dedup.New(raw, s)
+
+	mod, err := s.Mods().Create(ctx, &model.Mod{Name: "Foo"})
+	must(t, err)
+
+	for name, key := range map[string]string{"1.0": "mods/foo-1.0.jar", "2.0": "mods/legacy-2.0.jar"} {
+		_, err := s.Versions().Create(ctx, &model.Version{ModID: mod.ID, Name: name, FileKey: key})
+		must(t, err)
+	}
+
+	for key, body := range map[string]string{
+		"mods/foo-1.0.jar":  "shared",
+		"mods/copy-1.0.jar": "shared",
+		"mods/bar-1.0.jar":  "orphaned",
+	} {
+		_, err := content.Put(ctx, key, strings.NewReader(body), -1, "")
+		must(t, err)
+	}
+
+	pack, err := s.Packs().Create(ctx, &model.Pack{Name: "Hexxit", LogoKey: "packs/hexxit/logo/launcher.png"})
+	must(t, err)
+
+	for _, key := range append(images.Keys(pack.LogoKey), "mods/legacy-1.0.jar", "mods/legacy-2.0.jar") {
+		_, err := raw.Put(ctx, key, strings.NewReader("legacy"), -1, "")
+		must(t, err)
+	}
+
+	report, err := Collect(ctx, s, raw, time.Hour, true)
+	must(t, err)
+
+	if len(report.Orphans) != 4 || report.Deleted != 0 {
+		t.Fatalf("expected 4 pending orphans, got %d with %d deleted", len(report.Orphans), report.Deleted)
+	}
+
+	if report.Size != int64(len("orphaned")+len("legacy")) {
+		t.Fatalf("expected size of the blob and the object, got %d", report.Size)
+	}
+
+	report, err = Collect(ctx, s, raw, 0, true)
+	must(t, err)
+
+	if report.Deleted != 4 || report.Freed != report.Size {
+		t.Fatalf("expected all orphans to be deleted, got %d freeing %d", report.Deleted, report.Freed)
+	}
+
+	objects, err := content.List(ctx, "")
+	must(t, err)
+
+	if len(objects) != 5 || objects[0].Key != "mods/foo-1.0.jar" || objects[1].Key != "mods/legacy-2.0.jar" {
+		t.Fatalf("expected only referenced objects to be kept, got %d objects", len(objects))
+	}
+
+	blobs, err := s.Blobs().List(ctx)
+	must(t, err)
+
+	if len(blobs) != 1 || blobs[0].References != 1 {
+		t.Fatalf("expected a single blob with one reference, got %d blobs", len(blobs))
+	}
+
+	report, err = Collect(ctx, s, raw, 0, true)
+	must(t, err)
+
+	if len(report.Orphans) != 0 {
+		t.Fatalf("expected no orphans after collection, got %d", len(report.Orphans))
+	}
+}
+
+func TestCollectConcurrent(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kleister-gc")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	var (
+		ctx     = context.Background()
+		raw     = file.Must(&url.URL{Scheme: "file", Path: dir})
+		s       = memory.Must(&url.URL{Scheme: "memory"})
+		paused  = &paused{Upload: raw}
+		content = dedup.New(paused, s)
+	)
+
+	mod, err := s.Mods().Create(ctx, &model.Mod{Name: "Foo"})
+	must(t, err)
+
+	_, err = s.Versions().Create(ctx, &model.Version{ModID: mod.ID, Name: "1.0", FileKey: "mods/foo-1.0.jar"})
+	must(t, err)
+
+	_, err = content.Put(ctx, "mods/orphan.jar", strings.NewReader("shared"), -1, "")
+	must(t, err)
+
+	var (
+		stat    = make(chan struct{})
+		done    = make(chan struct{})
+		put     = make(chan error)
+		stopped sync.Once
+	)
+
+	// The upload pauses after checking the blob, the collection runs in
+	// between and must not remove the content it's going to link.
+	paused.stat = func(key string) {
+		if !strings.HasPrefix(key, dedup.BlobPrefix) {
+			return
+		}
+
+		stopped.Do(func() {
+			close(stat)
+
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+			}
+		})
+	}
+
+	go func() {
+		_, err := content.Put(ctx, "mods/foo-1.0.jar", strings.NewReader("shared"), -1, "")
+		put <- err
+	}()
+
+	<-stat
+
+	_, err = Collect(ctx, s, raw, 0, true)
+	must(t, err)
+
+	close(done)
+	must(t, <-put)
+
+	r, _, err := content.Get(ctx, "mods/foo-1.0.jar")
+
+	if err != nil {
+		t.Fatalf("expected linked content to be kept, got %v", err)
+	}
+
+	body, err := ioutil.ReadAll(r)
+	r.Close()
+	must(t, err)
+
+	if string(body) != "shared" {
+		t.Fatalf("expected linked content to be intact, got %q", body)
+	}
+
+	must(t, content.Delete(ctx, "mods/foo-1.0.jar"))
+
+	// The content gets linked again after the collection has listed the
+	// orphans, the references must be checked again before removing it.
+	paused.list = func() {
+		_, err := content.Put(ctx, "mods/foo-1.0.jar", strings.NewReader("shared"), -1, "")
+		must(t, err)
+	}
+
+	report, err := Collect(ctx, s, paused, 0, true)
+	must(t, err)
+
+	if len(report.Orphans) != 1 || report.Deleted != 0 {
+		t.Fatalf("expected the relinked blob to be kept, got %d deleted", report.Deleted)
+	}
+
+	if _, err := raw.Stat(ctx, report.Orphans[0].Key); err != nil {
+		t.Fatalf("expected relinked content to be kept, got %v", err)
+	}
+}
+
+// paused wraps the uploads to call hooks after objects have been checked
+// or listed.
+type paused struct {
+	upload.Upload
+
+	stat func(string)
+	list func()
+}
+
+// Stat calls the hook after the object has been checked.
+func (p *paused) Stat(ctx context.Context, key string) (*upload.Object, error) {
+	obj, err := p.Upload.Stat(ctx, key)
+
+	if p.stat != nil {
+		p.stat(key)
+	}
+
+	return obj, err
+}
+
+// List calls the hook after the objects have been listed.
+func (p *paused) List(ctx context.Context, prefix string) ([]*upload.Object, error) {
+	objects, err := p.Upload.List(ctx, prefix)
+
+	if p.list != nil {
+		p.list()
+	}
+
+	return objects, err
+}
+
+// must fails the test on any unexpected error.
+func must(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,334 @@
+// Package images stores the logo, icon and background of packs within the
+// uploads. Uploaded images get validated against the dimensions expected by
+// the launcher and resized variants are stored next to the original.
+package images
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/kleister/kleister-api/pkg/files"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/quota"
+	"github.com/kleister/kleister-api/pkg/store"
+	"github.com/kleister/kleister-api/pkg/upload"
+	"github.com/pkg/errors"
+	"golang.org/x/image/draw"
+)
+
+const (
+	// VariantOriginal names the uploaded image without any changes.
+	VariantOriginal = "original"
+
+	// VariantLauncher names the image resized to the launcher dimensions.
+	VariantLauncher = "launcher"
+
+	// VariantSmall names the image resized to half the launcher dimensions.
+	VariantSmall = "small"
+
+	// MaxSize defines the maximum size of an uploaded image in bytes.
+	MaxSize = 10 << 20
+
+	// maxDimension limits the width and height of an uploaded image, this
+	// way decoding can't exhaust the memory.
+	maxDimension = 8192
+
+	// jpegQuality defines the quality of resized JPEG variants.
+	jpegQuality = 90
+
+	// prefix defines the directory of pack images within the uploads.
+	prefix = "packs"
+)
+
+var (
+	// ErrInvalidImage defines a named error for uploads which are no PNG or
+	// JPEG images.
+	ErrInvalidImage = errors.New("invalid image, only png and jpeg are supported")
+
+	// ErrTooSmall defines a named error for images which are smaller than
+	// the dimensions expected by the launcher.
+	ErrTooSmall = errors.New("image is smaller than expected")
+
+	// ErrTooLarge defines a named error for images exceeding the maximum
+	// size or dimensions.
+	ErrTooLarge = errors.New("image is too large")
+)
+
+// Kind defines an image of a pack together with the dimensions expected by
+// the launcher.
+type Kind struct {
+	Name   string
+	Width  int
+	Height int
+}
+
+var (
+	// Logo defines the logo which gets shown within the pack list.
+	Logo = &Kind{Name: "logo", Width: 370, Height: 220}
+
+	// Icon defines the icon which gets shown next to the pack name.
+	Icon = &Kind{Name: "icon", Width: 50, Height: 50}
+
+	// Background defines the background of the pack details.
+	Background = &Kind{Name: "background", Width: 900, Height: 600}
+
+	// Kinds lists all images of a pack.
+	Kinds = []*Kind{Logo, Icon, Background}
+)
+
+// Get returns the recorded key and checksum of the image on the pack.
+func (k *Kind) Get(record *model.Pack) (string, string) {
+	key, md5, _ := k.fields(record)
+	return *key, *md5
+}
+
+// Size returns the recorded size of all variants of the image on the pack.
+func (k *Kind) Size(record *model.Pack) int64 {
+	_, _, size := k.fields(record)
+	return *size
+}
+
+// Set records the key, checksum and size of the image on the pack.
+func (k *Kind) Set(record *model.Pack, key, md5 string, size int64) {
+	recordKey, recordMD5, recordSize := k.fields(record)
+
+	*recordKey = key
+	*recordMD5 = md5
+	*recordSize = size
+}
+
+// fields maps the kind to the fields of the pack.
+func (k *Kind) fields(record *model.Pack) (*string, *string, *int64) {
+	switch k.Name {
+	case Logo.Name:
+		return &record.LogoKey, &record.LogoMD5, &record.LogoSize
+	case Icon.Name:
+		return &record.IconKey, &record.IconMD5, &record.IconSize
+	case Background.Name:
+		return &record.BackgroundKey, &record.BackgroundMD5, &record.BackgroundSize
+	}
+
+	panic("unknown image kind " + k.Name)
+}
+
+// Variant returns the key of another variant stored next to the key.
+func Variant(key, name string) string {
+	return path.Join(path.Dir(key), name+path.Ext(key))
+}
+
+// Keys returns the keys of all variants stored next to the key.
+func Keys(key string) []string {
+	return []string{
+		Variant(key, VariantOriginal),
+		Variant(key, VariantLauncher),
+		Variant(key, VariantSmall),
+	}
+}
+
+// Pack returns the ID of the pack an image belongs to, the result is empty
+// if the key doesn't belong to a pack image.
+func Pack(key string) string {
+	parts := strings.Split(key, "/")
+
+	if len(parts) != 5 || parts[0] != prefix {
+		return ""
+	}
+
+	return parts[1]
+}
+
+// Store validates the image and writes the original together with the
+// resized variants below a new key. It returns the key of the launcher
+// variant together with its checksum and the size of all variants.
+func Store(ctx context.Context, uploads upload.Upload, prefix string, kind *Kind, r io.Reader) (string, string, int64, error) {
+	content, err := ioutil.ReadAll(io.LimitReader(r, MaxSize+1))
+
+	if err != nil {
+		return "", "", 0, err
+	}
+
+	if len(content) > MaxSize {
+		return "", "", 0, ErrTooLarge
+	}
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(content))
+
+	if err != nil || (format != "png" && format != "jpeg") {
+		return "", "", 0, ErrInvalidImage
+	}
+
+	if cfg.Width > maxDimension || cfg.Height > maxDimension {
+		return "", "", 0, ErrTooLarge
+	}
+
+	if cfg.Width < kind.Width || cfg.Height < kind.Height {
+		return "", "", 0, errors.Wrapf(ErrTooSmall, "%s requires at least %dx%d pixels", kind.Name, kind.Width, kind.Height)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(content))
+
+	if err != nil {
+		return "", "", 0, ErrInvalidImage
+	}
+
+	var (
+		ext         = "." + format
+		contentType = "image/" + format
+		base        = path.Join(prefix, uuid.New().String())
+		stored      []string
+	)
+
+	if format == "jpeg" {
+		ext = ".jpg"
+	}
+
+	launcher, err := encode(resize(src, kind.Width, kind.Height), format)
+
+	if err != nil {
+		return "", "", 0, errors.Wrap(err, "failed to encode launcher variant")
+	}
+
+	small, err := encode(resize(src, (kind.Width+1)/2, (kind.Height+1)/2), format)
+
+	if err != nil {
+		return "", "", 0, errors.Wrap(err, "failed to encode small variant")
+	}
+
+	var (
+		checksum string
+		size     int64
+	)
+
+	for _, v := range []struct {
+		name    string
+		content []byte
+	}{
+		{VariantOriginal, content},
+		{VariantLauncher, launcher},
+		{VariantSmall, small},
+	} {
+		key := path.Join(base, v.name+ext)
+		digest, err := files.Put(ctx, uploads, key, bytes.NewReader(v.content), int64(len(v.content)), contentType)
+
+		if err != nil {
+			for _, key := range stored {
+				uploads.Delete(ctx, key)
+			}
+
+			return "", "", 0, errors.Wrap(err, "failed to store image")
+		}
+
+		stored = append(stored, key)
+		size += digest.Size
+
+		if v.name == VariantLauncher {
+			checksum = digest.MD5
+		}
+	}
+
+	return path.Join(base, VariantLauncher+ext), checksum, size, nil
+}
+
+// Attach stores the image of a pack and replaces a previous image. All
+// variants get written below a new key, this way the previous image stays
+// intact until the pack has been updated. The size of all variants counts
+// against the quotas of the users and teams the pack is assigned to. The
+// updated pack is also returned if only the removal of the previous image
+// failed.
+func Attach(ctx context.Context, s store.Store, uploads upload.Upload, packID string, kind *Kind, r io.Reader, limits quota.Limits) (*model.Pack, error) {
+	pack, err := s.Packs().Show(ctx, packID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	key, md5, size, err := Store(ctx, uploads, path.Join(prefix, pack.ID, kind.Name), kind, r)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		record   *model.Pack
+		previous string
+	)
+
+	if err := s.Transaction(ctx, func(tx store.Repositories) error {
+		current, err := tx.Packs().Show(ctx, pack.ID)
+
+		if err != nil {
+			return err
+		}
+
+		if err := quota.CheckPack(ctx, tx, limits, current.ID, size-kind.Size(current)); err != nil {
+			return err
+		}
+
+		previous, _ = kind.Get(current)
+		kind.Set(current, key, md5, size)
+
+		record, err = tx.Packs().Update(ctx, current)
+		return err
+	}); err != nil {
+		for _, variant := range Keys(key) {
+			uploads.Delete(ctx, variant)
+		}
+
+		return nil, err
+	}
+
+	if previous != "" && previous != key {
+		for _, variant := range Keys(previous) {
+			if err := uploads.Delete(ctx, variant); err != nil && err != upload.ErrNotFound {
+				return record, errors.Wrap(err, "failed to remove previous image")
+			}
+		}
+	}
+
+	return record, nil
+}
+
+// resize scales the image to cover the dimensions and crops the overlapping
+// parts around the center.
+func resize(src image.Image, width, height int) image.Image {
+	bounds := src.Bounds()
+	crop := bounds
+
+	if bounds.Dx()*height > bounds.Dy()*width {
+		w := bounds.Dy() * width / height
+		crop.Min.X += (bounds.Dx() - w) / 2
+		crop.Max.X = crop.Min.X + w
+	} else {
+		h := bounds.Dx() * height / width
+		crop.Min.Y += (bounds.Dy() - h) / 2
+		crop.Max.Y = crop.Min.Y + h
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, crop, draw.Src, nil)
+
+	return dst
+}
+
+// encode writes the image in the given format.
+func encode(img image.Image, format string) ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	var err error
+
+	if format == "jpeg" {
+		err = jpeg.Encode(buf, img, &jpeg.Options{Quality: jpegQuality})
+	} else {
+		err = png.Encode(buf, img)
+	}
+
+	return buf.Bytes(), err
+}
@@ -0,0 +1,183 @@
+package images
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/quota"
+	"github.com/kleister/kleister-api/pkg/store"
+	"github.com/kleister/kleister-api/pkg/store/memory"
+	"github.com/kleister/kleister-api/pkg/upload"
+	"github.com/kleister/kleister-api/pkg/upload/file"
+	"github.com/pkg/errors"
+)
+
+func TestAttach(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kleister-images")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	uploads := file.Must(&url.URL{Scheme: "file", Path: dir})
+	s := memory.Must(&url.URL{Scheme: "memory"})
+
+	pack, err := s.Packs().Create(ctx, &model.Pack{Name: "Hexxit"})
+	must(t, err)
+
+	for name, content := range map[string][]byte{
+		"text":  []byte("no image"),
+		"gif":   []byte("GIF89a\x01\x00\x01\x00\x00\x00\x00;"),
+		"small": generate(t, "png", 100, 50),
+	} {
+		_, err := Attach(ctx, s, uploads, pack.ID, Logo, bytes.NewReader(content), quota.Limits{})
+
+		if name == "small" && errors.Cause(err) != ErrTooSmall {
+			t.Errorf("expected %s to be too small, got %v", name, err)
+		}
+
+		if name != "small" && errors.Cause(err) != ErrInvalidImage {
+			t.Errorf("expected %s to be invalid, got %v", name, err)
+		}
+	}
+
+	_, err = Attach(ctx, s, uploads, "missing", Logo, bytes.NewReader(generate(t, "png", 370, 220)), quota.Limits{})
+
+	if err != store.ErrNotFound {
+		t.Fatalf("expected missing pack to fail, got %v", err)
+	}
+
+	first, err := Attach(ctx, s, uploads, pack.Slug, Logo, bytes.NewReader(generate(t, "png", 740, 300)), quota.Limits{})
+	must(t, err)
+
+	if !strings.HasSuffix(first.LogoKey, "/launcher.png") || first.IconKey != "" {
+		t.Fatalf("expected the logo to be recorded, got %q", first.LogoKey)
+	}
+
+	var size int64
+
+	for variant, dimensions := range map[string][2]int{
+		VariantOriginal: {740, 300},
+		VariantLauncher: {370, 220},
+		VariantSmall:    {185, 110},
+	} {
+		r, obj, err := uploads.Get(ctx, Variant(first.LogoKey, variant))
+		must(t, err)
+
+		content, err := ioutil.ReadAll(r)
+		r.Close()
+		must(t, err)
+
+		cfg, format, err := image.DecodeConfig(bytes.NewReader(content))
+		must(t, err)
+
+		if format != "png" || obj.ContentType != "image/png" || cfg.Width != dimensions[0] || cfg.Height != dimensions[1] {
+			t.Errorf("expected %s to be a png of %dx%d, got %s of %dx%d", variant, dimensions[0], dimensions[1], format, cfg.Width, cfg.Height)
+		}
+
+		size += obj.Size
+
+		if sum := md5.Sum(content); variant == VariantLauncher && hex.EncodeToString(sum[:]) != first.LogoMD5 {
+			t.Errorf("expected checksum of the launcher variant to be recorded")
+		}
+	}
+
+	if first.LogoSize != size {
+		t.Fatalf("expected the size of all variants to be recorded, got %d of %d bytes", first.LogoSize, size)
+	}
+
+	second, err := Attach(ctx, s, uploads, pack.ID, Background, bytes.NewReader(generate(t, "jpeg", 900, 600)), quota.Limits{})
+	must(t, err)
+
+	if !strings.HasSuffix(second.BackgroundKey, "/launcher.jpg") || second.LogoKey != first.LogoKey {
+		t.Fatalf("expected the background to be added, got %q", second.BackgroundKey)
+	}
+
+	third, err := Attach(ctx, s, uploads, pack.ID, Logo, bytes.NewReader(generate(t, "jpeg", 370, 220)), quota.Limits{})
+	must(t, err)
+
+	if third.LogoKey == first.LogoKey {
+		t.Fatalf("expected the logo to be replaced by a new key")
+	}
+
+	for _, key := range Keys(first.LogoKey) {
+		if _, err := uploads.Stat(ctx, key); err != upload.ErrNotFound {
+			t.Errorf("expected previous variant %s to be removed, got %v", key, err)
+		}
+	}
+
+	for _, key := range Keys(third.LogoKey) {
+		_, err := uploads.Stat(ctx, key)
+		must(t, err)
+	}
+
+	user, err := s.Users().Create(ctx, &model.User{Username: "alice", Quota: third.LogoSize + second.BackgroundSize})
+	must(t, err)
+
+	must(t, s.UserPacks().Append(ctx, &model.UserPack{UserID: user.ID, PackID: pack.ID, Perm: model.PermOwner}))
+
+	if _, err := Attach(ctx, s, uploads, pack.ID, Icon, bytes.NewReader(generate(t, "png", 50, 50)), quota.Limits{}); errors.Cause(err) != quota.ErrExceeded {
+		t.Fatalf("expected images to count against the quota, got %v", err)
+	}
+
+	current, err := s.Packs().Show(ctx, pack.ID)
+	must(t, err)
+
+	if current.IconKey != "" || current.IconSize != 0 {
+		t.Fatalf("expected rejected image to be discarded, got %q", current.IconKey)
+	}
+
+	objects, err := uploads.List(ctx, "packs/")
+	must(t, err)
+
+	if len(objects) != 6 {
+		t.Fatalf("expected only the variants of logo and background to be kept, got %d objects", len(objects))
+	}
+}
+
+// generate encodes a gradient image of the given dimensions.
+func generate(t *testing.T, format string, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{uint8(x), uint8(y), 128, 255})
+		}
+	}
+
+	buf := &bytes.Buffer{}
+
+	if format == "jpeg" {
+		must(t, jpeg.Encode(buf, img, nil))
+	} else {
+		must(t, png.Encode(buf, img))
+	}
+
+	return buf.Bytes()
+}
+
+// must fails the test on any unexpected error.
+func must(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,16 @@
+package model
+
+import (
+	"time"
+)
+
+// Blob represents deduplicated content of the uploads, it is addressed by
+// the SHA-256 of the content and counts the links pointing to it.
+type Blob struct {
+	Digest     string    `json:"digest"`
+	Size       int64     `json:"size"`
+	MD5        string    `json:"md5"`
+	References int       `json:"references"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+)
+
+// Build represents a build of a pack within the store.
+type Build struct {
+	ID          string    `json:"id"`
+	PackID      string    `json:"pack_id"`
+	MinecraftID string    `json:"minecraft_id"`
+	ForgeID     string    `json:"forge_id"`
+	Slug        string    `json:"slug"`
+	Name        string    `json:"name"`
+	MinJava     string    `json:"min_java"`
+	MinMemory   string    `json:"min_memory"`
+	Published   bool      `json:"published"`
+	Hidden      bool      `json:"hidden"`
+	Private     bool      `json:"private"`
+	Public      bool      `json:"public"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
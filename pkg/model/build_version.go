@@ -0,0 +1,15 @@
+package model
+
+import (
+	"time"
+)
+
+// BuildVersion represents the assignment of a mod version to a build.
+type BuildVersion struct {
+	BuildID   string    `json:"build_id"`
+	Build     *Build    `json:"-"`
+	VersionID string    `json:"version_id"`
+	Version   *Version  `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
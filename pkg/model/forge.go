@@ -0,0 +1,15 @@
+package model
+
+import (
+	"time"
+)
+
+// Forge represents a Forge version within the store.
+type Forge struct {
+	ID        string    `json:"id"`
+	Slug      string    `json:"slug"`
+	Name      string    `json:"name"`
+	Minecraft string    `json:"minecraft"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,14 @@
+package model
+
+import (
+	"time"
+)
+
+// Link represents a logical path of the uploads pointing to a blob.
+type Link struct {
+	Path        string    `json:"path"`
+	Digest      string    `json:"digest"`
+	ContentType string    `json:"content_type"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
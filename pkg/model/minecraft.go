@@ -0,0 +1,15 @@
+package model
+
+import (
+	"time"
+)
+
+// Minecraft represents a Minecraft version within the store.
+type Minecraft struct {
+	ID        string    `json:"id"`
+	Slug      string    `json:"slug"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+)
+
+// Mod represents a mod within the store.
+type Mod struct {
+	ID          string    `json:"id"`
+	Slug        string    `json:"slug"`
+	Name        string    `json:"name"`
+	Side        string    `json:"side"`
+	Description string    `json:"description"`
+	Author      string    `json:"author"`
+	Website     string    `json:"website"`
+	Donate      string    `json:"donate"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
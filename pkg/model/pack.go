@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+)
+
+// Pack represents a mod pack within the store.
+type Pack struct {
+	ID             string    `json:"id"`
+	RecommendedID  string    `json:"recommended_id"`
+	LatestID       string    `json:"latest_id"`
+	Slug           string    `json:"slug"`
+	Name           string    `json:"name"`
+	Website        string    `json:"website"`
+	Published      bool      `json:"published"`
+	Hidden         bool      `json:"hidden"`
+	Private        bool      `json:"private"`
+	Public         bool      `json:"public"`
+	LogoKey        string    `json:"logo_key"`
+	LogoMD5        string    `json:"logo_md5"`
+	LogoSize       int64     `json:"logo_size"`
+	IconKey        string    `json:"icon_key"`
+	IconMD5        string    `json:"icon_md5"`
+	IconSize       int64     `json:"icon_size"`
+	BackgroundKey  string    `json:"background_key"`
+	BackgroundMD5  string    `json:"background_md5"`
+	BackgroundSize int64     `json:"background_size"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
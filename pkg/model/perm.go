@@ -0,0 +1,12 @@
+package model
+
+const (
+	// PermUser grants read access to the related record.
+	PermUser = "user"
+
+	// PermAdmin grants write access to the related record.
+	PermAdmin = "admin"
+
+	// PermOwner grants full access to the related record.
+	PermOwner = "owner"
+)
@@ -0,0 +1,15 @@
+package model
+
+import (
+	"time"
+)
+
+// Team represents a team within the store.
+type Team struct {
+	ID        string    `json:"id"`
+	Slug      string    `json:"slug"`
+	Name      string    `json:"name"`
+	Quota     int64     `json:"quota"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,16 @@
+package model
+
+import (
+	"time"
+)
+
+// TeamMod represents the assignment of a mod to a team.
+type TeamMod struct {
+	TeamID    string    `json:"team_id"`
+	Team      *Team     `json:"-"`
+	ModID     string    `json:"mod_id"`
+	Mod       *Mod      `json:"-"`
+	Perm      string    `json:"perm"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
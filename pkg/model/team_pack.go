@@ -0,0 +1,16 @@
+package model
+
+import (
+	"time"
+)
+
+// TeamPack represents the assignment of a pack to a team.
+type TeamPack struct {
+	TeamID    string    `json:"team_id"`
+	Team      *Team     `json:"-"`
+	PackID    string    `json:"pack_id"`
+	Pack      *Pack     `json:"-"`
+	Perm      string    `json:"perm"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,16 @@
+package model
+
+import (
+	"time"
+)
+
+// TeamUser represents the assignment of a user to a team.
+type TeamUser struct {
+	TeamID    string    `json:"team_id"`
+	Team      *Team     `json:"-"`
+	UserID    string    `json:"user_id"`
+	User      *User     `json:"-"`
+	Perm      string    `json:"perm"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
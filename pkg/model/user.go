@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+)
+
+// User represents a user within the store.
+type User struct {
+	ID        string    `json:"id"`
+	Slug      string    `json:"slug"`
+	Username  string    `json:"username"`
+	Password  string    `json:"password"`
+	Email     string    `json:"email"`
+	Admin     bool      `json:"admin"`
+	Active    bool      `json:"active"`
+	Quota     int64     `json:"quota"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
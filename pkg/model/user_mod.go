@@ -0,0 +1,16 @@
+package model
+
+import (
+	"time"
+)
+
+// UserMod represents the assignment of a mod to a user.
+type UserMod struct {
+	UserID    string    `json:"user_id"`
+	User      *User     `json:"-"`
+	ModID     string    `json:"mod_id"`
+	Mod       *Mod      `json:"-"`
+	Perm      string    `json:"perm"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,16 @@
+package model
+
+import (
+	"time"
+)
+
+// UserPack represents the assignment of a pack to a user.
+type UserPack struct {
+	UserID    string    `json:"user_id"`
+	User      *User     `json:"-"`
+	PackID    string    `json:"pack_id"`
+	Pack      *Pack     `json:"-"`
+	Perm      string    `json:"perm"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
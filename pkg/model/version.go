@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+)
+
+// Version represents a version of a mod within the store.
+type Version struct {
+	ID            string    `json:"id"`
+	ModID         string    `json:"mod_id"`
+	Slug          string    `json:"slug"`
+	Name          string    `json:"name"`
+	FileKey       string    `json:"file_key"`
+	FileName      string    `json:"file_name"`
+	FileSize      int64     `json:"file_size"`
+	FileMD5       string    `json:"file_md5"`
+	FileSHA1      string    `json:"file_sha1"`
+	FileSHA256    string    `json:"file_sha256"`
+	FileLoader    string    `json:"file_loader"`
+	FileMinecraft string    `json:"file_minecraft"`
+	FileBroken    bool      `json:"file_broken"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
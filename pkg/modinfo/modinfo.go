@@ -0,0 +1,437 @@
+// Package modinfo validates uploaded mod archives and extracts the metadata
+// which is declared for the different mod loaders.
+package modinfo
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/pelletier/go-toml"
+	"github.com/pkg/errors"
+)
+
+const (
+	// LoaderForge defines the loader of mods declared by mods.toml or mcmod.info.
+	LoaderForge = "forge"
+
+	// LoaderNeoForge defines the loader of mods.toml depending on NeoForge.
+	LoaderNeoForge = "neoforge"
+
+	// LoaderFabric defines the loader of mods declared by fabric.mod.json.
+	LoaderFabric = "fabric"
+
+	// LoaderQuilt defines the loader of mods declared by quilt.mod.json.
+	LoaderQuilt = "quilt"
+)
+
+var (
+	// ErrInvalidArchive defines a named error for files which are no zip archive.
+	ErrInvalidArchive = errors.New("invalid mod archive")
+
+	// ErrMissingMetadata defines a named error for archives without metadata.
+	ErrMissingMetadata = errors.New("missing mod metadata")
+
+	// ErrInvalidMetadata defines a named error for metadata which can't be parsed.
+	ErrInvalidMetadata = errors.New("invalid mod metadata")
+)
+
+// Info describes the metadata of a mod archive, the Minecraft versions are
+// the declared requirements and any of them is sufficient.
+type Info struct {
+	ID          string
+	Name        string
+	Version     string
+	Description string
+	Author      string
+	Website     string
+	Loader      string
+	Minecraft   []string
+}
+
+// Supported checks if the metadata of a file can be parsed by its name.
+func Supported(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".jar", ".zip":
+		return true
+	}
+
+	return false
+}
+
+// Parse opens the archive and reads the first available metadata, the
+// loaders get checked in the order Quilt, Fabric, Forge and legacy Forge.
+func Parse(r io.ReaderAt, size int64) (*Info, error) {
+	archive, err := zip.NewReader(r, size)
+
+	if err != nil {
+		return nil, ErrInvalidArchive
+	}
+
+	entries := make(map[string]*zip.File, len(archive.File))
+
+	for _, f := range archive.File {
+		entries[f.Name] = f
+	}
+
+	for _, parser := range []struct {
+		name  string
+		parse func([]byte) (*Info, error)
+	}{
+		{"quilt.mod.json", parseQuilt},
+		{"fabric.mod.json", parseFabric},
+		{"META-INF/mods.toml", parseForge},
+		{"mcmod.info", parseLegacy},
+	} {
+		f, ok := entries[parser.name]
+
+		if !ok {
+			continue
+		}
+
+		content, err := read(f)
+
+		if err != nil {
+			return nil, ErrInvalidArchive
+		}
+
+		info, err := parser.parse(content)
+
+		if err != nil {
+			return nil, errors.Wrapf(ErrInvalidMetadata, "failed to parse %s: %s", parser.name, err)
+		}
+
+		// Forge replaces placeholders like ${file.jarVersion} by the
+		// implementation version of the manifest.
+		if strings.HasPrefix(info.Version, "${") {
+			info.Version = ""
+
+			if f, ok := entries["META-INF/MANIFEST.MF"]; ok {
+				info.Version = attribute(f, "Implementation-Version")
+			}
+		}
+
+		return info, nil
+	}
+
+	return nil, ErrMissingMetadata
+}
+
+// Apply pre-fills the empty details of the mod and records the loader and
+// Minecraft versions on the version. Details which don't match the metadata
+// are reported as warnings, the mod has to be updated if changed is set.
+func (i *Info) Apply(mod *model.Mod, version *model.Version) (changed bool, warnings []string) {
+	if i.ID != "" && normalize(i.ID) != normalize(mod.Slug) {
+		warnings = append(warnings, fmt.Sprintf("mod id %q doesn't match the mod slug %q", i.ID, mod.Slug))
+	}
+
+	if i.Name != "" && !strings.EqualFold(i.Name, mod.Name) {
+		warnings = append(warnings, fmt.Sprintf("mod name %q doesn't match the mod name %q", i.Name, mod.Name))
+	}
+
+	if i.Version != "" && i.Version != version.Name {
+		warnings = append(warnings, fmt.Sprintf("mod version %q doesn't match the version name %q", i.Version, version.Name))
+	}
+
+	for _, field := range []struct {
+		target *string
+		value  string
+	}{
+		{&mod.Description, i.Description},
+		{&mod.Author, i.Author},
+		{&mod.Website, i.Website},
+	} {
+		if *field.target == "" && field.value != "" {
+			*field.target = field.value
+			changed = true
+		}
+	}
+
+	version.FileLoader = i.Loader
+	version.FileMinecraft = strings.Join(i.Minecraft, " || ")
+
+	return changed, warnings
+}
+
+// parseQuilt parses the quilt.mod.json used by Quilt.
+func parseQuilt(content []byte) (*Info, error) {
+	manifest := struct {
+		Loader struct {
+			ID       string `json:"id"`
+			Version  string `json:"version"`
+			Metadata struct {
+				Name         string            `json:"name"`
+				Description  string            `json:"description"`
+				Contributors map[string]string `json:"contributors"`
+				Contact      map[string]string `json:"contact"`
+			} `json:"metadata"`
+			Depends []json.RawMessage `json:"depends"`
+		} `json:"quilt_loader"`
+	}{}
+
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, err
+	}
+
+	info := &Info{
+		ID:          manifest.Loader.ID,
+		Name:        manifest.Loader.Metadata.Name,
+		Version:     manifest.Loader.Version,
+		Description: manifest.Loader.Metadata.Description,
+		Website:     manifest.Loader.Metadata.Contact["homepage"],
+		Loader:      LoaderQuilt,
+	}
+
+	contributors := make([]string, 0, len(manifest.Loader.Metadata.Contributors))
+
+	for name := range manifest.Loader.Metadata.Contributors {
+		contributors = append(contributors, name)
+	}
+
+	sort.Strings(contributors)
+	info.Author = strings.Join(contributors, ", ")
+
+	for _, raw := range manifest.Loader.Depends {
+		dependency := struct {
+			ID       string          `json:"id"`
+			Versions json.RawMessage `json:"versions"`
+		}{}
+
+		// Dependencies without any version are declared as plain strings.
+		if err := json.Unmarshal(raw, &dependency); err != nil || dependency.ID != "minecraft" {
+			continue
+		}
+
+		info.Minecraft = values(dependency.Versions)
+	}
+
+	return info, nil
+}
+
+// parseFabric parses the fabric.mod.json used by Fabric.
+func parseFabric(content []byte) (*Info, error) {
+	manifest := struct {
+		ID          string                     `json:"id"`
+		Version     string                     `json:"version"`
+		Name        string                     `json:"name"`
+		Description string                     `json:"description"`
+		Authors     json.RawMessage            `json:"authors"`
+		Contact     map[string]string          `json:"contact"`
+		Depends     map[string]json.RawMessage `json:"depends"`
+	}{}
+
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, err
+	}
+
+	return &Info{
+		ID:          manifest.ID,
+		Name:        manifest.Name,
+		Version:     manifest.Version,
+		Description: manifest.Description,
+		Author:      strings.Join(values(manifest.Authors), ", "),
+		Website:     manifest.Contact["homepage"],
+		Loader:      LoaderFabric,
+		Minecraft:   values(manifest.Depends["minecraft"]),
+	}, nil
+}
+
+// parseForge parses the META-INF/mods.toml used by Forge and NeoForge.
+func parseForge(content []byte) (*Info, error) {
+	tree, err := toml.LoadBytes(content)
+
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := tree.ToMap()
+	mods, _ := manifest["mods"].([]interface{})
+
+	if len(mods) == 0 {
+		return nil, errors.New("no mods declared")
+	}
+
+	mod, _ := mods[0].(map[string]interface{})
+
+	info := &Info{
+		ID:          text(mod["modId"]),
+		Name:        text(mod["displayName"]),
+		Version:     text(mod["version"]),
+		Description: strings.TrimSpace(text(mod["description"])),
+		Author:      text(mod["authors"]),
+		Website:     text(mod["displayURL"]),
+		Loader:      LoaderForge,
+	}
+
+	if dependencies, ok := manifest["dependencies"].(map[string]interface{}); ok {
+		list, _ := dependencies[info.ID].([]interface{})
+
+		for _, item := range list {
+			dependency, _ := item.(map[string]interface{})
+
+			switch text(dependency["modId"]) {
+			case "minecraft":
+				if val := text(dependency["versionRange"]); val != "" {
+					info.Minecraft = []string{val}
+				}
+			case "neoforge":
+				info.Loader = LoaderNeoForge
+			}
+		}
+	}
+
+	return info, nil
+}
+
+// parseLegacy parses the mcmod.info used by Forge before Minecraft 1.13,
+// it contains either a list of mods or a versioned object with the list.
+func parseLegacy(content []byte) (*Info, error) {
+	type entry struct {
+		ModID       string   `json:"modid"`
+		Name        string   `json:"name"`
+		Description string   `json:"description"`
+		Version     string   `json:"version"`
+		MCVersion   string   `json:"mcversion"`
+		URL         string   `json:"url"`
+		AuthorList  []string `json:"authorList"`
+		Authors     []string `json:"authors"`
+	}
+
+	var (
+		entries []entry
+	)
+
+	if err := json.Unmarshal(content, &entries); err != nil {
+		versioned := struct {
+			ModList []entry `json:"modList"`
+		}{}
+
+		if err := json.Unmarshal(content, &versioned); err != nil {
+			return nil, err
+		}
+
+		entries = versioned.ModList
+	}
+
+	if len(entries) == 0 {
+		return nil, errors.New("no mods declared")
+	}
+
+	authors := entries[0].AuthorList
+
+	if len(authors) == 0 {
+		authors = entries[0].Authors
+	}
+
+	info := &Info{
+		ID:          entries[0].ModID,
+		Name:        entries[0].Name,
+		Version:     entries[0].Version,
+		Description: strings.TrimSpace(entries[0].Description),
+		Author:      strings.Join(authors, ", "),
+		Website:     entries[0].URL,
+		Loader:      LoaderForge,
+	}
+
+	if entries[0].MCVersion != "" && !strings.HasPrefix(entries[0].MCVersion, "${") {
+		info.Minecraft = []string{entries[0].MCVersion}
+	}
+
+	return info, nil
+}
+
+// values decodes a JSON value which is either a string or a list of strings
+// or objects with a name.
+func values(raw json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	var (
+		single string
+		list   []json.RawMessage
+	)
+
+	if err := json.Unmarshal(raw, &single); err == nil {
+		return []string{single}
+	}
+
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil
+	}
+
+	result := make([]string, 0, len(list))
+
+	for _, item := range list {
+		named := struct {
+			Name string `json:"name"`
+		}{}
+
+		if err := json.Unmarshal(item, &single); err == nil {
+			result = append(result, single)
+		} else if err := json.Unmarshal(item, &named); err == nil && named.Name != "" {
+			result = append(result, named.Name)
+		}
+	}
+
+	return result
+}
+
+// text converts a TOML value into a string, missing values are empty.
+func text(val interface{}) string {
+	if s, ok := val.(string); ok {
+		return s
+	}
+
+	return ""
+}
+
+// normalize unifies a mod id or slug for comparison.
+func normalize(val string) string {
+	return strings.Replace(strings.ToLower(val), "_", "-", -1)
+}
+
+// read fetches the content of an entry within the archive.
+func read(f *zip.File) ([]byte, error) {
+	r, err := f.Open()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Close()
+	return ioutil.ReadAll(io.LimitReader(r, 1<<20))
+}
+
+// attribute reads an attribute from the main section of the JAR manifest.
+func attribute(f *zip.File, name string) string {
+	r, err := f.Open()
+
+	if err != nil {
+		return ""
+	}
+
+	defer r.Close()
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		if line == "" {
+			break
+		}
+
+		if strings.HasPrefix(line, name+":") {
+			return strings.TrimSpace(strings.TrimPrefix(line, name+":"))
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,213 @@
+package modinfo
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/pkg/errors"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		expect  *Info
+		failure error
+	}{
+		{
+			name: "quilt",
+			files: map[string]string{
+				"quilt.mod.json": `{
+					"quilt_loader": {
+						"id": "example_mod",
+						"version": "1.0.0",
+						"metadata": {
+							"name": "Example",
+							"description": "An example",
+							"contributors": {"Bob": "Owner", "Alice": "Artist"},
+							"contact": {"homepage": "https://example.com"}
+						},
+						"depends": ["quilt_loader", {"id": "minecraft", "versions": ">=1.20"}]
+					}
+				}`,
+			},
+			expect: &Info{
+				ID:          "example_mod",
+				Name:        "Example",
+				Version:     "1.0.0",
+				Description: "An example",
+				Author:      "Alice, Bob",
+				Website:     "https://example.com",
+				Loader:      LoaderQuilt,
+				Minecraft:   []string{">=1.20"},
+			},
+		},
+		{
+			name: "fabric",
+			files: map[string]string{
+				"fabric.mod.json": `{
+					"id": "example",
+					"version": "2.0.0",
+					"name": "Example",
+					"authors": ["Alice", {"name": "Bob"}],
+					"contact": {"homepage": "https://example.com"},
+					"depends": {"fabricloader": ">=0.14", "minecraft": ["1.19.4", "1.20.x"]}
+				}`,
+			},
+			expect: &Info{
+				ID:        "example",
+				Name:      "Example",
+				Version:   "2.0.0",
+				Author:    "Alice, Bob",
+				Website:   "https://example.com",
+				Loader:    LoaderFabric,
+				Minecraft: []string{"1.19.4", "1.20.x"},
+			},
+		},
+		{
+			name: "forge",
+			files: map[string]string{
+				"META-INF/MANIFEST.MF": "Manifest-Version: 1.0\r\nImplementation-Version: 3.1.0\r\n",
+				"META-INF/mods.toml": `
+modLoader="javafml"
+loaderVersion="[47,)"
+
+[[mods]]
+modId="example"
+version="${file.jarVersion}"
+displayName="Example"
+authors="Alice"
+displayURL="https://example.com"
+description='''
+An example
+'''
+
+[[dependencies.example]]
+modId="neoforge"
+versionRange="[20.4,)"
+
+[[dependencies.example]]
+modId="minecraft"
+versionRange="[1.20.4,1.21)"
+`,
+			},
+			expect: &Info{
+				ID:          "example",
+				Name:        "Example",
+				Version:     "3.1.0",
+				Description: "An example",
+				Author:      "Alice",
+				Website:     "https://example.com",
+				Loader:      LoaderNeoForge,
+				Minecraft:   []string{"[1.20.4,1.21)"},
+			},
+		},
+		{
+			name: "legacy",
+			files: map[string]string{
+				"mcmod.info": `{"modListVersion": 2, "modList": [{
+					"modid": "example",
+					"name": "Example",
+					"version": "1.7.10-1.0",
+					"mcversion": "1.7.10",
+					"authorList": ["Alice", "Bob"]
+				}]}`,
+			},
+			expect: &Info{
+				ID:        "example",
+				Name:      "Example",
+				Version:   "1.7.10-1.0",
+				Author:    "Alice, Bob",
+				Loader:    LoaderForge,
+				Minecraft: []string{"1.7.10"},
+			},
+		},
+		{
+			name:    "missing",
+			files:   map[string]string{"example/Example.class": ""},
+			failure: ErrMissingMetadata,
+		},
+		{
+			name:    "broken",
+			files:   map[string]string{"fabric.mod.json": "{"},
+			failure: ErrInvalidMetadata,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := archive(t, tt.files)
+			info, err := Parse(bytes.NewReader(content), int64(len(content)))
+
+			if errors.Cause(err) != tt.failure {
+				t.Fatalf("expected error %v, got %v", tt.failure, err)
+			}
+
+			if !reflect.DeepEqual(info, tt.expect) {
+				t.Fatalf("expected %+v, got %+v", tt.expect, info)
+			}
+		})
+	}
+
+	if _, err := Parse(strings.NewReader("plain"), 5); err != ErrInvalidArchive {
+		t.Fatalf("expected invalid archive, got %v", err)
+	}
+}
+
+func TestApply(t *testing.T) {
+	mod := &model.Mod{Slug: "example", Name: "Example", Author: "Someone"}
+	version := &model.Version{Name: "1.0.0"}
+
+	info := &Info{
+		ID:          "Example_Mod",
+		Name:        "example",
+		Version:     "1.0.0",
+		Description: "An example",
+		Author:      "Alice",
+		Loader:      LoaderFabric,
+		Minecraft:   []string{"1.19.4", "1.20.x"},
+	}
+
+	changed, warnings := info.Apply(mod, version)
+
+	if !changed || mod.Description != "An example" || mod.Author != "Someone" {
+		t.Fatalf("expected only the empty details to be filled, got %+v", mod)
+	}
+
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "mod slug") {
+		t.Fatalf("expected a slug warning, got %v", warnings)
+	}
+
+	if version.FileLoader != LoaderFabric || version.FileMinecraft != "1.19.4 || 1.20.x" {
+		t.Fatalf("expected loader and minecraft versions, got %+v", version)
+	}
+}
+
+func archive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	w := zip.NewWriter(buf)
+
+	for name, content := range files {
+		f, err := w.Create(name)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
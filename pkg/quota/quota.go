@@ -0,0 +1,263 @@
+// Package quota calculates the storage used by users and teams and enforces
+// their limits. The usage is the sum of the file sizes of all versions of
+// the mods and the image sizes of the packs assigned to a user or team.
+package quota
+
+import (
+	"context"
+
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrExceeded defines a named error if an upload exceeds a quota.
+	ErrExceeded = errors.New("storage quota exceeded")
+)
+
+// Limits defines the default limits in bytes, zero disables the quota.
+type Limits struct {
+	User int64
+	Team int64
+}
+
+// Usage describes the used storage together with the limit in bytes, the
+// limit is the stored limit of the record or the default if it is zero.
+// A limit of zero or below means that the storage is unlimited.
+type Usage struct {
+	Quota int64
+	Limit int64
+	Used  int64
+}
+
+// Exceeds checks if additional bytes would exceed the limit.
+func (u *Usage) Exceeds(size int64) bool {
+	return u.Limit > 0 && u.Used+size > u.Limit
+}
+
+// User calculates the storage used by a user.
+func User(ctx context.Context, s store.Repositories, limits Limits, id string) (*Usage, error) {
+	record, err := s.Users().Show(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	assignedMods, err := s.UserMods().ListByUser(ctx, record.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	mods := make([]string, 0, len(assignedMods))
+
+	for _, a := range assignedMods {
+		mods = append(mods, a.ModID)
+	}
+
+	assignedPacks, err := s.UserPacks().ListByUser(ctx, record.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	packs := make([]string, 0, len(assignedPacks))
+
+	for _, a := range assignedPacks {
+		packs = append(packs, a.PackID)
+	}
+
+	return usage(ctx, s, mods, packs, record.Quota, limits.User)
+}
+
+// Team calculates the storage used by a team.
+func Team(ctx context.Context, s store.Repositories, limits Limits, id string) (*Usage, error) {
+	record, err := s.Teams().Show(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	assignedMods, err := s.TeamMods().ListByTeam(ctx, record.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	mods := make([]string, 0, len(assignedMods))
+
+	for _, a := range assignedMods {
+		mods = append(mods, a.ModID)
+	}
+
+	assignedPacks, err := s.TeamPacks().ListByTeam(ctx, record.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	packs := make([]string, 0, len(assignedPacks))
+
+	for _, a := range assignedPacks {
+		packs = append(packs, a.PackID)
+	}
+
+	return usage(ctx, s, mods, packs, record.Quota, limits.Team)
+}
+
+// Check verifies that replacing the file of a version by a file of the
+// given size doesn't exceed the quota of any user or team the mod is
+// assigned to.
+func Check(ctx context.Context, s store.Repositories, limits Limits, modID, versionID string, size int64) error {
+	mod, err := s.Mods().Show(ctx, modID)
+
+	if err != nil {
+		return err
+	}
+
+	version, err := s.Versions().Show(ctx, mod.ID, versionID)
+
+	if err != nil {
+		return err
+	}
+
+	added := size - version.FileSize
+
+	if added <= 0 {
+		return nil
+	}
+
+	assignedUsers, err := s.UserMods().ListByMod(ctx, mod.ID)
+
+	if err != nil {
+		return err
+	}
+
+	users := make([]*model.User, 0, len(assignedUsers))
+
+	for _, a := range assignedUsers {
+		users = append(users, a.User)
+	}
+
+	assignedTeams, err := s.TeamMods().ListByMod(ctx, mod.ID)
+
+	if err != nil {
+		return err
+	}
+
+	teams := make([]*model.Team, 0, len(assignedTeams))
+
+	for _, a := range assignedTeams {
+		teams = append(teams, a.Team)
+	}
+
+	return exceeds(ctx, s, limits, users, teams, added)
+}
+
+// CheckPack verifies that adding the given bytes to the images of a pack
+// doesn't exceed the quota of any user or team the pack is assigned to.
+func CheckPack(ctx context.Context, s store.Repositories, limits Limits, packID string, added int64) error {
+	pack, err := s.Packs().Show(ctx, packID)
+
+	if err != nil {
+		return err
+	}
+
+	if added <= 0 {
+		return nil
+	}
+
+	assignedUsers, err := s.UserPacks().ListByPack(ctx, pack.ID)
+
+	if err != nil {
+		return err
+	}
+
+	users := make([]*model.User, 0, len(assignedUsers))
+
+	for _, a := range assignedUsers {
+		users = append(users, a.User)
+	}
+
+	assignedTeams, err := s.TeamPacks().ListByPack(ctx, pack.ID)
+
+	if err != nil {
+		return err
+	}
+
+	teams := make([]*model.Team, 0, len(assignedTeams))
+
+	for _, a := range assignedTeams {
+		teams = append(teams, a.Team)
+	}
+
+	return exceeds(ctx, s, limits, users, teams, added)
+}
+
+// exceeds checks the additional bytes against the quotas of the users and
+// teams.
+func exceeds(ctx context.Context, s store.Repositories, limits Limits, users []*model.User, teams []*model.Team, added int64) error {
+	for _, user := range users {
+		result, err := User(ctx, s, limits, user.ID)
+
+		if err != nil {
+			return err
+		}
+
+		if result.Exceeds(added) {
+			return errors.Wrapf(ErrExceeded, "user %s uses %d of %d bytes", user.Username, result.Used, result.Limit)
+		}
+	}
+
+	for _, team := range teams {
+		result, err := Team(ctx, s, limits, team.ID)
+
+		if err != nil {
+			return err
+		}
+
+		if result.Exceeds(added) {
+			return errors.Wrapf(ErrExceeded, "team %s uses %d of %d bytes", team.Name, result.Used, result.Limit)
+		}
+	}
+
+	return nil
+}
+
+// usage sums up the file sizes of the versions of all mods and the image
+// sizes of all packs.
+func usage(ctx context.Context, s store.Repositories, mods, packs []string, quota, fallback int64) (*Usage, error) {
+	result := &Usage{
+		Quota: quota,
+		Limit: quota,
+	}
+
+	if result.Limit == 0 {
+		result.Limit = fallback
+	}
+
+	for _, id := range mods {
+		versions, err := s.Versions().List(ctx, id)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, version := range versions {
+			result.Used += version.FileSize
+		}
+	}
+
+	for _, id := range packs {
+		pack, err := s.Packs().Show(ctx, id)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result.Used += pack.LogoSize + pack.IconSize + pack.BackgroundSize
+	}
+
+	return result, nil
+}
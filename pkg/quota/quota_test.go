@@ -0,0 +1,125 @@
+package quota
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store/memory"
+	"github.com/pkg/errors"
+)
+
+func TestCheck(t *testing.T) {
+	ctx := context.Background()
+	s := memory.Must(&url.URL{Scheme: "memory"})
+
+	user, err := s.Users().Create(ctx, &model.User{Username: "alice"})
+	must(t, err)
+
+	team, err := s.Teams().Create(ctx, &model.Team{Name: "Core", Quota: 2000})
+	must(t, err)
+
+	mod, err := s.Mods().Create(ctx, &model.Mod{Name: "Foo"})
+	must(t, err)
+
+	version, err := s.Versions().Create(ctx, &model.Version{ModID: mod.ID, Name: "1.0", FileKey: "mods/foo-1.0.jar", FileSize: 600})
+	must(t, err)
+
+	must(t, s.UserMods().Append(ctx, &model.UserMod{UserID: user.ID, ModID: mod.ID, Perm: model.PermOwner}))
+	must(t, s.TeamMods().Append(ctx, &model.TeamMod{TeamID: team.ID, ModID: mod.ID, Perm: model.PermOwner}))
+
+	limits := Limits{User: 1000, Team: 100}
+
+	usage, err := User(ctx, s, limits, user.ID)
+	must(t, err)
+
+	if usage.Used != 600 || usage.Limit != 1000 {
+		t.Fatalf("expected 600 of 1000 bytes, got %d of %d", usage.Used, usage.Limit)
+	}
+
+	usage, err = Team(ctx, s, limits, team.ID)
+	must(t, err)
+
+	if usage.Used != 600 || usage.Limit != 2000 {
+		t.Fatalf("expected 600 of 2000 bytes, got %d of %d", usage.Used, usage.Limit)
+	}
+
+	must(t, Check(ctx, s, limits, mod.ID, version.ID, 1000))
+
+	if err := Check(ctx, s, limits, mod.ID, version.ID, 1001); errors.Cause(err) != ErrExceeded {
+		t.Fatalf("expected exceeded quota, got %v", err)
+	}
+
+	user.Quota = -1
+	_, err = s.Users().Update(ctx, user)
+	must(t, err)
+
+	must(t, Check(ctx, s, limits, mod.ID, version.ID, 2000))
+
+	if err := Check(ctx, s, limits, mod.ID, version.ID, 2001); errors.Cause(err) != ErrExceeded {
+		t.Fatalf("expected exceeded quota, got %v", err)
+	}
+}
+
+func TestCheckPack(t *testing.T) {
+	ctx := context.Background()
+	s := memory.Must(&url.URL{Scheme: "memory"})
+
+	user, err := s.Users().Create(ctx, &model.User{Username: "alice", Quota: 1000})
+	must(t, err)
+
+	team, err := s.Teams().Create(ctx, &model.Team{Name: "Core"})
+	must(t, err)
+
+	mod, err := s.Mods().Create(ctx, &model.Mod{Name: "Foo"})
+	must(t, err)
+
+	_, err = s.Versions().Create(ctx, &model.Version{ModID: mod.ID, Name: "1.0", FileKey: "mods/foo-1.0.jar", FileSize: 300})
+	must(t, err)
+
+	pack, err := s.Packs().Create(ctx, &model.Pack{Name: "Hexxit", LogoKey: "packs/hexxit/logo/1/launcher.png", LogoSize: 200, IconSize: 100})
+	must(t, err)
+
+	must(t, s.UserMods().Append(ctx, &model.UserMod{UserID: user.ID, ModID: mod.ID, Perm: model.PermOwner}))
+	must(t, s.UserPacks().Append(ctx, &model.UserPack{UserID: user.ID, PackID: pack.ID, Perm: model.PermOwner}))
+	must(t, s.TeamPacks().Append(ctx, &model.TeamPack{TeamID: team.ID, PackID: pack.ID, Perm: model.PermOwner}))
+
+	limits := Limits{Team: 500}
+
+	usage, err := User(ctx, s, limits, user.ID)
+	must(t, err)
+
+	if usage.Used != 600 {
+		t.Fatalf("expected versions and images to be used, got %d bytes", usage.Used)
+	}
+
+	usage, err = Team(ctx, s, limits, team.ID)
+	must(t, err)
+
+	if usage.Used != 300 || usage.Limit != 500 {
+		t.Fatalf("expected 300 of 500 bytes, got %d of %d", usage.Used, usage.Limit)
+	}
+
+	must(t, CheckPack(ctx, s, limits, pack.ID, 200))
+
+	if err := CheckPack(ctx, s, limits, pack.ID, 201); errors.Cause(err) != ErrExceeded {
+		t.Fatalf("expected exceeded team quota, got %v", err)
+	}
+
+	limits.Team = -1
+
+	if err := CheckPack(ctx, s, limits, pack.ID, 401); errors.Cause(err) != ErrExceeded {
+		t.Fatalf("expected exceeded user quota, got %v", err)
+	}
+
+	must(t, CheckPack(ctx, s, limits, pack.ID, -300))
+}
+
+func must(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
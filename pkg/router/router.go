@@ -8,11 +8,14 @@ import (
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
+	"github.com/kleister/kleister-api/pkg/backup"
 	"github.com/kleister/kleister-api/pkg/config"
+	"github.com/kleister/kleister-api/pkg/download"
 	"github.com/kleister/kleister-api/pkg/middleware/header"
 	"github.com/kleister/kleister-api/pkg/middleware/prometheus"
 	"github.com/kleister/kleister-api/pkg/store"
 	"github.com/kleister/kleister-api/pkg/upload"
+	"github.com/kleister/kleister-api/pkg/upload/dedup"
 	"github.com/rs/zerolog/hlog"
 	"github.com/rs/zerolog/log"
 	"github.com/utahta/swagger-doc"
@@ -21,9 +24,13 @@ import (
 	restapiv1 "github.com/kleister/kleister-api/pkg/api/v1/restapi"
 )
 
-// Server initializes the routing of the server.
+// Server initializes the routing of the server. The API and the storage
+// use deduplicated uploads while the backups archive the plain uploads.
+// Objects of private and hidden packs require signed download URLs and the
+// storage is the only route which allows caching.
 func Server(cfg *config.Config, storage store.Store, uploads upload.Upload) http.Handler {
 	mux := chi.NewRouter()
+	content := dedup.New(uploads, storage)
 
 	mux.Use(hlog.NewHandler(log.Logger))
 	mux.Use(hlog.RemoteAddrHandler("ip"))
@@ -45,13 +52,15 @@ func Server(cfg *config.Config, storage store.Store, uploads upload.Upload) http
 	mux.Use(middleware.RealIP)
 
 	mux.Use(header.Version)
-	mux.Use(header.Cache)
 	mux.Use(header.Secure)
 	mux.Use(header.Options)
 
 	mux.Route(cfg.Server.Root, func(root chi.Router) {
 		root.Route("/api", func(base chi.Router) {
-			base.Route("/v1", func(v1 chi.Router) {
+			// The storage defines the caching of the objects on its own.
+			uncached := base.With(header.Cache)
+
+			uncached.Route("/v1", func(v1 chi.Router) {
 				if cfg.Server.Docs {
 					v1.Get("/swagger", func(w http.ResponseWriter, r *http.Request) {
 						w.Header().Set("Content-Type", "application/json")
@@ -71,21 +80,30 @@ func Server(cfg *config.Config, storage store.Store, uploads upload.Upload) http
 					))
 				}
 
-				if api := apiv1.New(); api != nil {
+				if api := apiv1.New(cfg, storage, content); api != nil {
 					v1.Mount("/", middleware.NoCache(api.Handler))
 				}
 			})
 
 			if cfg.Server.Pprof {
-				base.Mount("/debug", middleware.Profiler())
+				uncached.Mount("/debug", middleware.Profiler())
+			}
+
+			if cfg.Admin.Token != "" {
+				uncached.Get("/admin/backup", backup.Handler(cfg.Admin.Token, storage, uploads))
 			}
 
-			base.Handle("/storage/*", uploads.Handler(
-				path.Join(
-					cfg.Server.Root,
-					"api",
-					"storage",
-				),
+			storageRoot := path.Join(
+				cfg.Server.Root,
+				"api",
+				"storage",
+			)
+
+			base.Handle("/storage/*", download.Handler(
+				cfg.Token.Secret,
+				storageRoot,
+				storage,
+				content.Handler(storageRoot),
 			))
 		})
 	})
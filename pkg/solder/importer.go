@@ -0,0 +1,467 @@
+package solder
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+	"github.com/pkg/errors"
+)
+
+// Entry describes a single record which gets created by an import.
+type Entry struct {
+	Kind string
+	Name string
+	Slug string
+	Note string
+}
+
+// Report describes the records which get created by an import and the
+// rows which can't be imported.
+type Report struct {
+	Counts  store.Counts
+	Entries []*Entry
+	Skipped []string
+}
+
+// Import maps the source onto the store and creates all records within a
+// single transaction. For a dry run the records are only planned based on a
+// snapshot of the store.
+func Import(ctx context.Context, src *Source, s store.Store, dry bool) (*Report, error) {
+	var (
+		report *Report
+	)
+
+	fn := func(tx store.Repositories) error {
+		d, r, err := Plan(ctx, src, tx)
+
+		if err != nil {
+			return err
+		}
+
+		report = r
+
+		if dry {
+			return nil
+		}
+
+		return store.Import(ctx, d, tx)
+	}
+
+	if dry {
+		if err := s.Snapshot(ctx, fn); err != nil {
+			return nil, err
+		}
+
+		return report, nil
+	}
+
+	if err := s.Transaction(ctx, fn); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// Plan maps the source onto a dump which can be imported into the store.
+// Slugs which are already taken within the store or the source get a
+// numeric suffix, Minecraft and Forge versions are matched by name.
+func Plan(ctx context.Context, src *Source, s store.Repositories) (*store.Dump, *Report, error) {
+	p := &planner{
+		src:  src,
+		dump: &store.Dump{},
+		report: &Report{
+			Entries: make([]*Entry, 0),
+			Skipped: make([]string, 0),
+		},
+		modSlugs:       slugs{},
+		packSlugs:      slugs{},
+		minecraftSlugs: slugs{},
+		forgeSlugs:     slugs{},
+		minecrafts:     make(map[string]string),
+		forges:         make(map[string]string),
+		mods:           make(map[int64]*model.Mod),
+		versions:       make(map[int64]*model.Version),
+		packs:          make(map[int64]*model.Pack),
+		builds:         make(map[int64]*model.Build),
+	}
+
+	if err := p.existing(ctx, s); err != nil {
+		return nil, nil, err
+	}
+
+	p.planMods()
+	p.planVersions()
+	p.planPacks()
+	p.planBuilds()
+	p.planMarkers()
+	p.planBuildVersions()
+
+	if len(src.Clients) > 0 {
+		p.skip("%d clients have no counterpart and are not imported", len(src.Clients))
+	}
+
+	if len(src.Keys) > 0 {
+		p.skip("%d api keys have no counterpart and are not imported", len(src.Keys))
+	}
+
+	p.report.Counts = p.dump.Counts()
+	return p.dump, p.report, nil
+}
+
+// slugs tracks the taken slugs of a scope to resolve conflicts.
+type slugs map[string]bool
+
+// claim takes the slug or the first free one with a numeric suffix.
+func (s slugs) claim(slug string) string {
+	result := slug
+
+	for i := 2; s[result]; i++ {
+		result = fmt.Sprintf("%s-%d", slug, i)
+	}
+
+	s[result] = true
+	return result
+}
+
+// planner maps the rows of the source onto the records of a dump.
+type planner struct {
+	src    *Source
+	dump   *store.Dump
+	report *Report
+
+	modSlugs       slugs
+	packSlugs      slugs
+	minecraftSlugs slugs
+	forgeSlugs     slugs
+
+	minecrafts map[string]string
+	forges     map[string]string
+	mods       map[int64]*model.Mod
+	versions   map[int64]*model.Version
+	packs      map[int64]*model.Pack
+	builds     map[int64]*model.Build
+}
+
+// existing loads the taken slugs and the known Minecraft and Forge versions.
+func (p *planner) existing(ctx context.Context, s store.Repositories) error {
+	mods, err := s.Mods().List(ctx)
+
+	if err != nil {
+		return errors.Wrap(err, "failed to list mods")
+	}
+
+	for _, record := range mods {
+		p.modSlugs[record.Slug] = true
+	}
+
+	packs, err := s.Packs().List(ctx)
+
+	if err != nil {
+		return errors.Wrap(err, "failed to list packs")
+	}
+
+	for _, record := range packs {
+		p.packSlugs[record.Slug] = true
+	}
+
+	minecrafts, err := s.Minecrafts().List(ctx)
+
+	if err != nil {
+		return errors.Wrap(err, "failed to list minecrafts")
+	}
+
+	for _, record := range minecrafts {
+		p.minecraftSlugs[record.Slug] = true
+		p.minecrafts[record.Name] = record.ID
+	}
+
+	forges, err := s.Forges().List(ctx)
+
+	if err != nil {
+		return errors.Wrap(err, "failed to list forges")
+	}
+
+	for _, record := range forges {
+		p.forgeSlugs[record.Slug] = true
+		p.forges[record.Name] = record.ID
+	}
+
+	return nil
+}
+
+// entry adds an entry to the report and notes a resolved slug conflict.
+func (p *planner) entry(kind, name, wanted, slug string) {
+	e := &Entry{
+		Kind: kind,
+		Name: name,
+		Slug: slug,
+	}
+
+	if wanted != slug {
+		e.Note = fmt.Sprintf("slug %s is taken", wanted)
+	}
+
+	p.report.Entries = append(p.report.Entries, e)
+}
+
+// skip adds a message about a row which can't be imported.
+func (p *planner) skip(format string, args ...interface{}) {
+	p.report.Skipped = append(p.report.Skipped, fmt.Sprintf(format, args...))
+}
+
+func (p *planner) planMods() {
+	for _, row := range p.src.Mods {
+		record := &model.Mod{
+			ID:          uuid.New().String(),
+			Name:        row.PrettyName,
+			Description: row.Description,
+			Author:      row.Author,
+			Website:     row.Link,
+			Donate:      row.Donate,
+			CreatedAt:   row.CreatedAt,
+			UpdatedAt:   row.UpdatedAt,
+		}
+
+		if record.Name == "" {
+			record.Name = row.Name
+		}
+
+		wanted := store.SlugOrDefault(store.Slugify(row.Name), record.Name, record.ID)
+		record.Slug = p.modSlugs.claim(wanted)
+
+		p.mods[row.ID] = record
+		p.dump.Mods = append(p.dump.Mods, record)
+		p.entry("mod", record.Name, wanted, record.Slug)
+	}
+}
+
+func (p *planner) planVersions() {
+	scopes := make(map[int64]slugs)
+
+	for _, row := range p.src.ModVersions {
+		mod, ok := p.mods[row.ModID]
+
+		if !ok {
+			p.skip("version %s references unknown mod %d", row.Version, row.ModID)
+			continue
+		}
+
+		if _, ok := scopes[row.ModID]; !ok {
+			scopes[row.ModID] = slugs{}
+		}
+
+		record := &model.Version{
+			ID:        uuid.New().String(),
+			ModID:     mod.ID,
+			Name:      row.Version,
+			FileSize:  row.Filesize,
+			FileMD5:   row.MD5,
+			CreatedAt: row.CreatedAt,
+			UpdatedAt: row.UpdatedAt,
+		}
+
+		wanted := store.SlugOrDefault("", record.Name, record.ID)
+		record.Slug = scopes[row.ModID].claim(wanted)
+
+		p.versions[row.ID] = record
+		p.dump.Versions = append(p.dump.Versions, record)
+		p.entry("version", mod.Slug+"/"+record.Name, wanted, record.Slug)
+	}
+}
+
+// planPacks maps the modpacks, Solder shows every pack which is not hidden
+// within the launcher and only private packs require an authorized client.
+func (p *planner) planPacks() {
+	for _, row := range p.src.Modpacks {
+		record := &model.Pack{
+			ID:        uuid.New().String(),
+			Name:      row.Name,
+			Website:   row.URL,
+			Published: true,
+			Hidden:    row.Hidden,
+			Private:   row.Private,
+			Public:    !row.Private,
+			CreatedAt: row.CreatedAt,
+			UpdatedAt: row.UpdatedAt,
+		}
+
+		wanted := store.SlugOrDefault(store.Slugify(row.Slug), record.Name, record.ID)
+		record.Slug = p.packSlugs.claim(wanted)
+
+		p.packs[row.ID] = record
+		p.dump.Packs = append(p.dump.Packs, record)
+		p.entry("pack", record.Name, wanted, record.Slug)
+	}
+}
+
+func (p *planner) planBuilds() {
+	scopes := make(map[int64]slugs)
+
+	for _, row := range p.src.Builds {
+		pack, ok := p.packs[row.ModpackID]
+
+		if !ok {
+			p.skip("build %s references unknown modpack %d", row.Version, row.ModpackID)
+			continue
+		}
+
+		if _, ok := scopes[row.ModpackID]; !ok {
+			scopes[row.ModpackID] = slugs{}
+		}
+
+		record := &model.Build{
+			ID:          uuid.New().String(),
+			PackID:      pack.ID,
+			MinecraftID: p.minecraft(row.Minecraft),
+			ForgeID:     p.forge(row.Forge, row.Minecraft),
+			Name:        row.Version,
+			MinJava:     row.MinJava,
+			Published:   row.Published,
+			Private:     row.Private,
+			Public:      !row.Private,
+			CreatedAt:   row.CreatedAt,
+			UpdatedAt:   row.UpdatedAt,
+		}
+
+		if row.MinMemory > 0 {
+			record.MinMemory = strconv.FormatInt(row.MinMemory, 10)
+		}
+
+		wanted := store.SlugOrDefault("", record.Name, record.ID)
+		record.Slug = scopes[row.ModpackID].claim(wanted)
+
+		p.builds[row.ID] = record
+		p.dump.Builds = append(p.dump.Builds, record)
+		p.entry("build", pack.Slug+"/"+record.Name, wanted, record.Slug)
+	}
+}
+
+// planMarkers maps the recommended and latest build versions of Solder onto
+// the IDs of the planned builds.
+func (p *planner) planMarkers() {
+	for _, row := range p.src.Modpacks {
+		pack := p.packs[row.ID]
+
+		for _, marker := range []struct {
+			name    string
+			version string
+			target  *string
+		}{
+			{"recommended", row.Recommended, &pack.RecommendedID},
+			{"latest", row.Latest, &pack.LatestID},
+		} {
+			if marker.version == "" {
+				continue
+			}
+
+			if id := p.build(pack.ID, marker.version); id != "" {
+				*marker.target = id
+				continue
+			}
+
+			p.skip("%s build %s of pack %s doesn't exist", marker.name, marker.version, pack.Slug)
+		}
+	}
+}
+
+func (p *planner) planBuildVersions() {
+	assigned := make(map[[2]int64]bool)
+
+	for _, row := range p.src.BuildModVersions {
+		build, ok := p.builds[row.BuildID]
+
+		if !ok {
+			p.skip("assignment references unknown build %d", row.BuildID)
+			continue
+		}
+
+		version, ok := p.versions[row.ModVersionID]
+
+		if !ok {
+			p.skip("assignment references unknown mod version %d", row.ModVersionID)
+			continue
+		}
+
+		key := [2]int64{row.BuildID, row.ModVersionID}
+
+		if assigned[key] {
+			continue
+		}
+
+		assigned[key] = true
+
+		p.dump.BuildVersions = append(p.dump.BuildVersions, &model.BuildVersion{
+			BuildID:   build.ID,
+			VersionID: version.ID,
+			CreatedAt: row.CreatedAt,
+			UpdatedAt: row.UpdatedAt,
+		})
+	}
+}
+
+// build finds the ID of a planned build by its name within a pack.
+func (p *planner) build(packID, name string) string {
+	for _, record := range p.dump.Builds {
+		if record.PackID == packID && record.Name == name {
+			return record.ID
+		}
+	}
+
+	return ""
+}
+
+// minecraft finds or plans the Minecraft version of the name.
+func (p *planner) minecraft(name string) string {
+	if name == "" {
+		return ""
+	}
+
+	if id, ok := p.minecrafts[name]; ok {
+		return id
+	}
+
+	record := &model.Minecraft{
+		ID:   uuid.New().String(),
+		Name: name,
+		Type: "release",
+	}
+
+	wanted := store.SlugOrDefault("", record.Name, record.ID)
+	record.Slug = p.minecraftSlugs.claim(wanted)
+
+	p.minecrafts[name] = record.ID
+	p.dump.Minecrafts = append(p.dump.Minecrafts, record)
+	p.entry("minecraft", record.Name, wanted, record.Slug)
+
+	return record.ID
+}
+
+// forge finds or plans the Forge version of the name.
+func (p *planner) forge(name, minecraft string) string {
+	if name == "" {
+		return ""
+	}
+
+	if id, ok := p.forges[name]; ok {
+		return id
+	}
+
+	record := &model.Forge{
+		ID:        uuid.New().String(),
+		Name:      name,
+		Minecraft: minecraft,
+	}
+
+	wanted := store.SlugOrDefault("", record.Name, record.ID)
+	record.Slug = p.forgeSlugs.claim(wanted)
+
+	p.forges[name] = record.ID
+	p.dump.Forges = append(p.dump.Forges, record)
+	p.entry("forge", record.Name, wanted, record.Slug)
+
+	return record.ID
+}
@@ -0,0 +1,213 @@
+package solder
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+	"github.com/kleister/kleister-api/pkg/store/memory"
+)
+
+func TestPlan(t *testing.T) {
+	ctx := context.Background()
+	s := existing(t)
+
+	d, report, err := Plan(ctx, source(), s)
+	must(t, err)
+
+	for name, expected := range map[string]int{
+		"mods":           3,
+		"versions":       2,
+		"packs":          1,
+		"builds":         1,
+		"minecrafts":     0,
+		"forges":         1,
+		"build_versions": 1,
+	} {
+		if report.Counts[name] != expected {
+			t.Errorf("expected %d planned %s, got %d", expected, name, report.Counts[name])
+		}
+	}
+
+	notes := make(map[string]string)
+
+	for _, entry := range report.Entries {
+		notes[entry.Kind+":"+entry.Slug] = entry.Note
+	}
+
+	for key, note := range map[string]string{
+		"mod:botania":     "",
+		"mod:botania-2":   "slug botania is taken",
+		"mod:jei-2":       "slug jei is taken",
+		"version:1.0":     "",
+		"version:1.0-2":   "slug 1.0 is taken",
+		"pack:hexxit-2":   "slug hexxit is taken",
+		"build:1.0.0":     "",
+		"forge:14.23.5.2": "",
+	} {
+		if actual, ok := notes[key]; !ok || actual != note {
+			t.Errorf("expected %s to be planned with note %q, got %q", key, note, actual)
+		}
+	}
+
+	minecraft, err := s.Minecrafts().Show(ctx, "1.12.2")
+	must(t, err)
+
+	if len(d.Builds) != 1 || d.Builds[0].MinecraftID != minecraft.ID || d.Builds[0].ForgeID != d.Forges[0].ID {
+		t.Fatalf("expected build to reference existing minecraft and planned forge")
+	}
+
+	if pack := d.Packs[0]; pack.RecommendedID != d.Builds[0].ID || pack.LatestID != "" {
+		t.Fatalf("expected only the recommended build to be assigned, got %q and %q", pack.RecommendedID, pack.LatestID)
+	}
+
+	skipped := make(map[string]bool)
+
+	for _, message := range report.Skipped {
+		skipped[message] = true
+	}
+
+	for _, message := range []string{
+		"version 3.0 references unknown mod 99",
+		"build 9.9.9 references unknown modpack 99",
+		"latest build 2.0.0 of pack hexxit-2 doesn't exist",
+		"assignment references unknown build 99",
+		"assignment references unknown mod version 99",
+		"1 clients have no counterpart and are not imported",
+	} {
+		if !skipped[message] {
+			t.Errorf("expected %q to be skipped, got %v", message, report.Skipped)
+		}
+	}
+
+	if len(report.Skipped) != 6 {
+		t.Errorf("expected 6 skipped rows, got %v", report.Skipped)
+	}
+}
+
+func TestImport(t *testing.T) {
+	ctx := context.Background()
+	s := existing(t)
+
+	before, err := store.Count(ctx, s)
+	must(t, err)
+
+	report, err := Import(ctx, source(), s, true)
+	must(t, err)
+
+	if report.Counts["mods"] != 3 {
+		t.Fatalf("expected dry run to plan 3 mods, got %d", report.Counts["mods"])
+	}
+
+	after, err := store.Count(ctx, s)
+	must(t, err)
+
+	if diff := before.Diff(after); len(diff) > 0 {
+		t.Fatalf("expected dry run to keep the store, got differences for %v", diff)
+	}
+
+	report, err = Import(ctx, source(), s, false)
+	must(t, err)
+
+	after, err = store.Count(ctx, s)
+	must(t, err)
+
+	for name, planned := range report.Counts {
+		if after[name] != before[name]+planned {
+			t.Errorf("expected %d %s to be imported, got %d", planned, name, after[name]-before[name])
+		}
+	}
+
+	pack, err := s.Packs().Show(ctx, "hexxit-2")
+	must(t, err)
+
+	build, err := s.Builds().Show(ctx, pack.ID, "1.0.0")
+	must(t, err)
+
+	if pack.RecommendedID != build.ID || pack.LatestID != "" {
+		t.Fatalf("expected recommended build to be imported, got %q", pack.RecommendedID)
+	}
+
+	mod, err := s.Mods().Show(ctx, "jei-2")
+	must(t, err)
+
+	if mod.Name != "Just Enough Items" {
+		t.Fatalf("expected pretty name to be imported, got %q", mod.Name)
+	}
+
+	report, err = Import(ctx, source(), s, false)
+	must(t, err)
+
+	notes := make(map[string]string)
+
+	for _, entry := range report.Entries {
+		notes[entry.Kind+":"+entry.Name] = entry.Slug
+	}
+
+	if notes["mod:Just Enough Items"] != "jei-3" || notes["pack:Hexxit"] != "hexxit-3" {
+		t.Fatalf("expected a repeated import to claim new slugs, got %v", notes)
+	}
+}
+
+// existing creates a store with records conflicting with the source.
+func existing(t *testing.T) store.Store {
+	t.Helper()
+
+	ctx := context.Background()
+	s := memory.Must(&url.URL{Scheme: "memory"})
+
+	_, err := s.Mods().Create(ctx, &model.Mod{Name: "JEI", Slug: "jei"})
+	must(t, err)
+
+	_, err = s.Packs().Create(ctx, &model.Pack{Name: "Hexxit", Slug: "hexxit"})
+	must(t, err)
+
+	_, err = s.Minecrafts().Create(ctx, &model.Minecraft{Name: "1.12.2", Type: "release"})
+	must(t, err)
+
+	return s
+}
+
+// source builds the rows of a Solder database with conflicting slugs and
+// orphaned references.
+func source() *Source {
+	return &Source{
+		Modpacks: []*Modpack{
+			{ID: 1, Name: "Hexxit", Slug: "hexxit", Recommended: "1.0.0", Latest: "2.0.0"},
+		},
+		Builds: []*Build{
+			{ID: 1, ModpackID: 1, Version: "1.0.0", Minecraft: "1.12.2", Forge: "14.23.5.2", MinMemory: 2048},
+			{ID: 2, ModpackID: 99, Version: "9.9.9"},
+		},
+		Mods: []*Mod{
+			{ID: 1, Name: "botania", PrettyName: "Botania"},
+			{ID: 2, Name: "Botania"},
+			{ID: 3, Name: "jei", PrettyName: "Just Enough Items"},
+		},
+		ModVersions: []*ModVersion{
+			{ID: 1, ModID: 1, Version: "1.0"},
+			{ID: 2, ModID: 1, Version: "1.0"},
+			{ID: 3, ModID: 99, Version: "3.0"},
+		},
+		BuildModVersions: []*BuildModVersion{
+			{BuildID: 1, ModVersionID: 1},
+			{BuildID: 1, ModVersionID: 1},
+			{BuildID: 99, ModVersionID: 1},
+			{BuildID: 1, ModVersionID: 99},
+		},
+		Clients: []*Client{
+			{ID: 1, Name: "launcher"},
+		},
+	}
+}
+
+// must fails the test on any unexpected error.
+func must(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
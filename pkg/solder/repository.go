@@ -0,0 +1,214 @@
+package solder
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/kleister/kleister-api/pkg/files"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+	"github.com/kleister/kleister-api/pkg/upload"
+	"github.com/pkg/errors"
+)
+
+// Unmatched describes a file of the repository which has not been linked.
+type Unmatched struct {
+	Path   string
+	Reason string
+}
+
+// RepositoryReport describes the result of a repository import.
+type RepositoryReport struct {
+	Mods      int
+	Versions  int
+	Files     int
+	Unmatched []*Unmatched
+}
+
+// ImportRepository walks the mods directory of a Solder repository where
+// the archives are laid out as mods/<slug>/<slug>-<version>.zip. Every
+// archive gets stored and its size and checksums are recorded on the matching
+// version, missing mods and versions get created. Files which don't follow
+// the layout or whose version is not used by any build are reported.
+func ImportRepository(ctx context.Context, root string, s store.Store, uploads upload.Upload) (*RepositoryReport, error) {
+	report := &RepositoryReport{
+		Unmatched: make([]*Unmatched, 0),
+	}
+
+	base := filepath.Join(root, "mods")
+
+	if _, err := os.Stat(base); err != nil {
+		return nil, errors.Wrap(err, "failed to find mods directory")
+	}
+
+	err := filepath.Walk(base, func(name string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, name)
+
+		if err != nil {
+			return err
+		}
+
+		rel = filepath.ToSlash(rel)
+		parts := strings.Split(rel, "/")
+
+		if len(parts) != 3 {
+			report.unmatched(rel, "unexpected location")
+			return nil
+		}
+
+		slug, file := parts[1], parts[2]
+
+		if !strings.HasPrefix(file, slug+"-") || path.Ext(file) != ".zip" {
+			report.unmatched(rel, "name doesn't match mod slug")
+			return nil
+		}
+
+		version := strings.TrimSuffix(strings.TrimPrefix(file, slug+"-"), ".zip")
+
+		if version == "" {
+			report.unmatched(rel, "name doesn't contain a version")
+			return nil
+		}
+
+		used, err := report.archive(ctx, s, uploads, name, slug, version)
+
+		if err != nil {
+			return errors.Wrapf(err, "failed to import %s", rel)
+		}
+
+		if !used {
+			report.unmatched(rel, "version is not used by any build")
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// unmatched adds a file which has not been linked to the report.
+func (r *RepositoryReport) unmatched(name, reason string) {
+	r.Unmatched = append(r.Unmatched, &Unmatched{
+		Path:   name,
+		Reason: reason,
+	})
+}
+
+// archive stores the archive and records it on the version of the mod, it
+// returns if the version is assigned to any build.
+func (r *RepositoryReport) archive(ctx context.Context, s store.Store, uploads upload.Upload, name, slug, version string) (bool, error) {
+	f, err := os.Open(name)
+
+	if err != nil {
+		return false, err
+	}
+
+	defer f.Close()
+
+	info, err := f.Stat()
+
+	if err != nil {
+		return false, err
+	}
+
+	key := path.Join("mods", slug, filepath.Base(name))
+	digest, err := files.Put(ctx, uploads, key, f, info.Size(), "application/zip")
+
+	if err != nil {
+		return false, errors.Wrap(err, "failed to store archive")
+	}
+
+	var (
+		used           bool
+		createdMod     bool
+		createdVersion bool
+	)
+
+	err = s.Transaction(ctx, func(tx store.Repositories) error {
+		mod, err := tx.Mods().Show(ctx, store.Slugify(slug))
+
+		if err == store.ErrNotFound {
+			mod, err = tx.Mods().Create(ctx, &model.Mod{Name: slug, Slug: store.Slugify(slug)})
+			createdMod = err == nil
+		}
+
+		if err != nil {
+			return errors.Wrap(err, "failed to find mod")
+		}
+
+		records, err := tx.Versions().List(ctx, mod.ID)
+
+		if err != nil {
+			return errors.Wrap(err, "failed to list versions")
+		}
+
+		var record *model.Version
+
+		for _, existing := range records {
+			if existing.Name == version {
+				record = existing
+				break
+			}
+		}
+
+		if record == nil {
+			record = &model.Version{ModID: mod.ID, Name: version}
+			record, err = tx.Versions().Create(ctx, record)
+
+			if err != nil {
+				return errors.Wrap(err, "failed to create version")
+			}
+
+			createdVersion = true
+		}
+
+		files.Assign(record, key, filepath.Base(name), digest)
+
+		if _, err := tx.Versions().Update(ctx, record); err != nil {
+			return errors.Wrap(err, "failed to update version")
+		}
+
+		builds, err := tx.BuildVersions().ListByVersion(ctx, record.ID)
+
+		if err != nil {
+			return errors.Wrap(err, "failed to list builds")
+		}
+
+		used = len(builds) > 0
+		return nil
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	if createdMod {
+		r.Mods++
+	}
+
+	if createdVersion {
+		r.Versions++
+	}
+
+	r.Files++
+	return used, nil
+}
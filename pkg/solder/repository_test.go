@@ -0,0 +1,125 @@
+package solder
+
+import (
+	"context"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store/memory"
+	"github.com/kleister/kleister-api/pkg/upload/file"
+)
+
+func TestImportRepository(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kleister-solder")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	var (
+		ctx     = context.Background()
+		root    = filepath.Join(dir, "repo")
+		s       = memory.Must(&url.URL{Scheme: "memory"})
+		uploads = file.Must(&url.URL{Scheme: "file", Path: filepath.Join(dir, "uploads")})
+	)
+
+	for name, content := range map[string]string{
+		"mods/IC2/IC2-1.0.zip": "ic2 1.0",
+		"mods/IC2/IC2-2.0.zip": "ic2 2.0",
+		"mods/jei/jei-1.0.zip": "jei 1.0",
+		"mods/jei/jei-.zip":    "empty",
+		"mods/jei/other.zip":   "other",
+		"mods/readme.txt":      "readme",
+	} {
+		name = filepath.Join(root, filepath.FromSlash(name))
+
+		must(t, os.MkdirAll(filepath.Dir(name), 0755))
+		must(t, ioutil.WriteFile(name, []byte(content), 0644))
+	}
+
+	mod, err := s.Mods().Create(ctx, &model.Mod{Name: "JEI", Slug: "jei"})
+	must(t, err)
+
+	version, err := s.Versions().Create(ctx, &model.Version{ModID: mod.ID, Name: "1.0"})
+	must(t, err)
+
+	pack, err := s.Packs().Create(ctx, &model.Pack{Name: "Hexxit"})
+	must(t, err)
+
+	build, err := s.Builds().Create(ctx, &model.Build{PackID: pack.ID, Name: "1.0.0"})
+	must(t, err)
+
+	must(t, s.BuildVersions().Append(ctx, &model.BuildVersion{BuildID: build.ID, VersionID: version.ID}))
+
+	report, err := ImportRepository(ctx, root, s, uploads)
+	must(t, err)
+
+	if report.Mods != 1 || report.Versions != 2 || report.Files != 3 {
+		t.Fatalf("expected 1 mod, 2 versions and 3 files, got %d, %d and %d", report.Mods, report.Versions, report.Files)
+	}
+
+	reasons := make(map[string]string)
+
+	for _, unmatched := range report.Unmatched {
+		reasons[unmatched.Path] = unmatched.Reason
+	}
+
+	expected := map[string]string{
+		"mods/IC2/IC2-1.0.zip": "version is not used by any build",
+		"mods/IC2/IC2-2.0.zip": "version is not used by any build",
+		"mods/jei/jei-.zip":    "name doesn't contain a version",
+		"mods/jei/other.zip":   "name doesn't match mod slug",
+		"mods/readme.txt":      "unexpected location",
+	}
+
+	if len(reasons) != len(expected) {
+		t.Errorf("expected %d unmatched files, got %v", len(expected), reasons)
+	}
+
+	for name, reason := range expected {
+		if reasons[name] != reason {
+			t.Errorf("expected %s to be unmatched by %q, got %q", name, reason, reasons[name])
+		}
+	}
+
+	ic2, err := s.Mods().Show(ctx, "ic2")
+	must(t, err)
+
+	versions, err := s.Versions().List(ctx, ic2.ID)
+	must(t, err)
+
+	if len(versions) != 2 {
+		t.Fatalf("expected both archives to be linked to the same mod, got %d versions", len(versions))
+	}
+
+	version, err = s.Versions().Show(ctx, mod.ID, version.ID)
+	must(t, err)
+
+	if version.FileKey != "mods/jei/jei-1.0.zip" || version.FileSize != 7 || version.FileMD5 == "" || version.FileSHA256 == "" {
+		t.Fatalf("expected archive to be recorded on the existing version, got %q of %d bytes", version.FileKey, version.FileSize)
+	}
+
+	r, _, err := uploads.Get(ctx, version.FileKey)
+	must(t, err)
+
+	content, err := ioutil.ReadAll(r)
+	r.Close()
+	must(t, err)
+
+	if string(content) != "jei 1.0" {
+		t.Fatalf("expected archive to be stored, got %q", content)
+	}
+
+	report, err = ImportRepository(ctx, root, s, uploads)
+	must(t, err)
+
+	if report.Mods != 0 || report.Versions != 0 || report.Files != 3 {
+		t.Fatalf("expected a repeated import to only update the files, got %d mods and %d versions", report.Mods, report.Versions)
+	}
+}
@@ -0,0 +1,92 @@
+// Package solder imports the data of existing TechnicSolder installations,
+// it reads the MySQL schema of Solder and maps it onto the store.
+package solder
+
+import (
+	"time"
+)
+
+// Modpack represents a row of the modpacks table.
+type Modpack struct {
+	ID          int64
+	Name        string
+	Slug        string
+	Recommended string
+	Latest      string
+	URL         string
+	Hidden      bool
+	Private     bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Build represents a row of the builds table.
+type Build struct {
+	ID        int64
+	ModpackID int64
+	Version   string
+	Minecraft string
+	Forge     string
+	Published bool
+	Private   bool
+	MinJava   string
+	MinMemory int64
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Mod represents a row of the mods table.
+type Mod struct {
+	ID          int64
+	Name        string
+	PrettyName  string
+	Description string
+	Author      string
+	Link        string
+	Donate      string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// ModVersion represents a row of the modversions table.
+type ModVersion struct {
+	ID        int64
+	ModID     int64
+	Version   string
+	MD5       string
+	Filesize  int64
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// BuildModVersion represents a row of the build_modversion pivot table.
+type BuildModVersion struct {
+	BuildID      int64
+	ModVersionID int64
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// Client represents a row of the clients table.
+type Client struct {
+	ID   int64
+	Name string
+	UUID string
+}
+
+// Key represents a row of the keys table.
+type Key struct {
+	ID   int64
+	Name string
+}
+
+// Source holds all rows read from a Solder database.
+type Source struct {
+	Modpacks         []*Modpack
+	Builds           []*Build
+	Mods             []*Mod
+	ModVersions      []*ModVersion
+	BuildModVersions []*BuildModVersion
+	Clients          []*Client
+	Keys             []*Key
+}
@@ -0,0 +1,23 @@
+package store
+
+import (
+	"context"
+
+	"github.com/kleister/kleister-api/pkg/model"
+)
+
+// Blobs provides the repository interface for deduplicated upload content.
+type Blobs interface {
+	// List returns all blobs ordered by digest.
+	List(context.Context) ([]*model.Blob, error)
+
+	// Show returns a single blob by its digest.
+	Show(context.Context, string) (*model.Blob, error)
+
+	// Create persists a new blob without any references.
+	Create(context.Context, *model.Blob) (*model.Blob, error)
+
+	// Delete removes a blob by its digest, fails with ErrConflict if it is
+	// still referenced by any link.
+	Delete(context.Context, string) error
+}
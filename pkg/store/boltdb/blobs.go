@@ -0,0 +1,120 @@
+package boltdb
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+	bolt "go.etcd.io/bbolt"
+)
+
+type blobs struct {
+	s *boltdb
+}
+
+// List implements the store.Blobs interface.
+func (r *blobs) List(ctx context.Context) ([]*model.Blob, error) {
+	records := make([]*model.Blob, 0)
+
+	err := r.s.view(func(tx *bolt.Tx) error {
+		return blobEntity.each(tx, func(val []byte) error {
+			record := &model.Blob{}
+
+			if err := json.Unmarshal(val, record); err != nil {
+				return err
+			}
+
+			records = append(records, record)
+			return nil
+		})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Digest < records[j].Digest
+	})
+
+	return records, nil
+}
+
+// Show implements the store.Blobs interface.
+func (r *blobs) Show(ctx context.Context, digest string) (*model.Blob, error) {
+	record := &model.Blob{}
+
+	err := r.s.view(func(tx *bolt.Tx) error {
+		return blobEntity.get(tx, digest, record)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// Create implements the store.Blobs interface.
+func (r *blobs) Create(ctx context.Context, blob *model.Blob) (*model.Blob, error) {
+	record := *blob
+	now := time.Now().UTC()
+
+	record.References = 0
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = now
+	}
+
+	err := r.s.update(func(tx *bolt.Tx) error {
+		if blobEntity.exists(tx, record.Digest) {
+			return store.ErrConflict
+		}
+
+		return blobEntity.put(tx, record.Digest, record)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Delete implements the store.Blobs interface.
+func (r *blobs) Delete(ctx context.Context, digest string) error {
+	return r.s.update(func(tx *bolt.Tx) error {
+		record := &model.Blob{}
+
+		if err := blobEntity.get(tx, digest, record); err != nil {
+			return err
+		}
+
+		if record.References > 0 {
+			return store.ErrConflict
+		}
+
+		return tx.Bucket(blobsBucket).Delete([]byte(digest))
+	})
+}
+
+// referenceBlob adjusts the reference counter of a blob.
+func referenceBlob(tx *bolt.Tx, digest string, delta int) error {
+	record := &model.Blob{}
+
+	if err := blobEntity.get(tx, digest, record); err != nil {
+		return err
+	}
+
+	record.References += delta
+	record.UpdatedAt = time.Now().UTC()
+
+	return blobEntity.put(tx, digest, record)
+}
@@ -1,25 +1,223 @@
 package boltdb
 
 import (
+	"context"
 	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"time"
 
 	"github.com/kleister/kleister-api/pkg/store"
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
 )
 
 type boltdb struct {
-	dsn *url.URL
+	dsn    *url.URL
+	handle *bolt.DB
+	tx     *bolt.Tx
+}
+
+// Users returns the repository for users.
+func (s *boltdb) Users() store.Users {
+	return &users{s: s}
+}
+
+// Teams returns the repository for teams.
+func (s *boltdb) Teams() store.Teams {
+	return &teams{s: s}
+}
+
+// Packs returns the repository for packs.
+func (s *boltdb) Packs() store.Packs {
+	return &packs{s: s}
+}
+
+// Builds returns the repository for builds.
+func (s *boltdb) Builds() store.Builds {
+	return &builds{s: s}
+}
+
+// Mods returns the repository for mods.
+func (s *boltdb) Mods() store.Mods {
+	return &mods{s: s}
+}
+
+// Versions returns the repository for versions.
+func (s *boltdb) Versions() store.Versions {
+	return &versions{s: s}
+}
+
+// Minecrafts returns the repository for Minecraft versions.
+func (s *boltdb) Minecrafts() store.Minecrafts {
+	return &minecrafts{s: s}
+}
+
+// Forges returns the repository for Forge versions.
+func (s *boltdb) Forges() store.Forges {
+	return &forges{s: s}
+}
+
+// TeamUsers returns the repository for users assigned to teams.
+func (s *boltdb) TeamUsers() store.TeamUsers {
+	return &teamUsers{s: s}
+}
+
+// TeamPacks returns the repository for packs assigned to teams.
+func (s *boltdb) TeamPacks() store.TeamPacks {
+	return &teamPacks{s: s}
+}
+
+// TeamMods returns the repository for mods assigned to teams.
+func (s *boltdb) TeamMods() store.TeamMods {
+	return &teamMods{s: s}
+}
+
+// UserPacks returns the repository for packs assigned to users.
+func (s *boltdb) UserPacks() store.UserPacks {
+	return &userPacks{s: s}
+}
+
+// UserMods returns the repository for mods assigned to users.
+func (s *boltdb) UserMods() store.UserMods {
+	return &userMods{s: s}
+}
+
+// BuildVersions returns the repository for versions assigned to builds.
+func (s *boltdb) BuildVersions() store.BuildVersions {
+	return &buildVersions{s: s}
+}
+
+// Blobs returns the repository for deduplicated upload content.
+func (s *boltdb) Blobs() store.Blobs {
+	return &blobs{s: s}
+}
+
+// Links returns the repository for logical upload paths.
+func (s *boltdb) Links() store.Links {
+	return &links{s: s}
+}
+
+// Migrations returns the repository for schema migrations.
+func (s *boltdb) Migrations() store.Migrations {
+	return &migrations{s: s}
+}
+
+// Transaction implements the store.Store interface.
+func (s *boltdb) Transaction(ctx context.Context, fn func(store.Repositories) error) error {
+	if s.tx != nil {
+		return fn(s)
+	}
+
+	return s.handle.Update(func(tx *bolt.Tx) error {
+		return fn(&boltdb{dsn: s.dsn, handle: s.handle, tx: tx})
+	})
+}
+
+// Snapshot implements the store.Store interface with a read transaction.
+func (s *boltdb) Snapshot(ctx context.Context, fn func(store.Repositories) error) error {
+	if s.tx != nil {
+		return fn(s)
+	}
+
+	return s.handle.View(func(tx *bolt.Tx) error {
+		return fn(&boltdb{dsn: s.dsn, handle: s.handle, tx: tx})
+	})
 }
 
 // Close simply closes the BoltDB connection.
 func (s *boltdb) Close() error {
-	return nil
+	return s.handle.Close()
+}
+
+// view executes a read-only function within a transaction, it reuses the
+// current transaction if the store is already transactional.
+func (s *boltdb) view(fn func(*bolt.Tx) error) error {
+	if s.tx != nil {
+		return fn(s.tx)
+	}
+
+	return s.handle.View(fn)
+}
+
+// update executes a read-write function within a transaction, it reuses the
+// current transaction if the store is already transactional.
+func (s *boltdb) update(fn func(*bolt.Tx) error) error {
+	if s.tx != nil {
+		return fn(s.tx)
+	}
+
+	return s.handle.Update(fn)
+}
+
+// perms retrieves the file perms from dsn or fallback.
+func (s *boltdb) perms() os.FileMode {
+	if val := s.dsn.Query().Get("perms"); val != "" {
+		u, err := strconv.ParseUint(val, 8, 32)
+
+		if err != nil {
+			return 0600
+		}
+
+		return os.FileMode(u)
+	}
+
+	return 0600
+}
+
+// timeout retrieves the lock timeout from dsn or fallback.
+func (s *boltdb) timeout() time.Duration {
+	if val := s.dsn.Query().Get("timeout"); val != "" {
+		d, err := time.ParseDuration(val)
+
+		if err != nil {
+			return 1 * time.Second
+		}
+
+		return d
+	}
+
+	return 1 * time.Second
+}
+
+// path cleans the dsn and returns a valid path.
+func (s *boltdb) path() string {
+	return path.Join(
+		s.dsn.Host,
+		s.dsn.EscapedPath(),
+	)
 }
 
 // New initializes a new BoltDB connection.
 func New(dsn *url.URL) (store.Store, error) {
-	return &boltdb{
+	s := &boltdb{
 		dsn: dsn,
-	}, nil
+	}
+
+	handle, err := bolt.Open(
+		s.path(),
+		s.perms(),
+		&bolt.Options{
+			Timeout: s.timeout(),
+		},
+	)
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open database")
+	}
+
+	s.handle = handle
+
+	if err := s.update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(migrationsBucket)
+		return err
+	}); err != nil {
+		handle.Close()
+		return nil, errors.Wrap(err, "failed to create migrations bucket")
+	}
+
+	return s, nil
 }
 
 // Must simply calls New and panics on an error.
@@ -0,0 +1,40 @@
+package boltdb
+
+import (
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/kleister/kleister-api/pkg/store"
+	"github.com/kleister/kleister-api/pkg/store/storetest"
+)
+
+func TestConformance(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kleister-boltdb")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+	counter := 0
+
+	storetest.Run(t, func(t *testing.T) store.Store {
+		counter++
+
+		s, err := New(&url.URL{
+			Scheme: "boltdb",
+			Path:   filepath.Join(dir, strconv.Itoa(counter)+".db"),
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		storetest.Reset(t, s)
+		return s
+	})
+}
@@ -0,0 +1,379 @@
+package boltdb
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/kleister/kleister-api/pkg/store"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	usersBucket      = []byte("users")
+	teamsBucket      = []byte("teams")
+	packsBucket      = []byte("packs")
+	buildsBucket     = []byte("builds")
+	modsBucket       = []byte("mods")
+	versionsBucket   = []byte("versions")
+	minecraftsBucket = []byte("minecrafts")
+	forgesBucket     = []byte("forges")
+
+	usersSlugIndex      = []byte("users_slug")
+	teamsSlugIndex      = []byte("teams_slug")
+	packsSlugIndex      = []byte("packs_slug")
+	buildsSlugIndex     = []byte("builds_slug")
+	modsSlugIndex       = []byte("mods_slug")
+	versionsSlugIndex   = []byte("versions_slug")
+	minecraftsSlugIndex = []byte("minecrafts_slug")
+	forgesSlugIndex     = []byte("forges_slug")
+
+	packBuildsIndex      = []byte("pack_builds")
+	modVersionsIndex     = []byte("mod_versions")
+	minecraftBuildsIndex = []byte("minecraft_builds")
+	forgeBuildsIndex     = []byte("forge_builds")
+
+	teamUsersBucket     = []byte("team_users")
+	userTeamsIndex      = []byte("user_teams")
+	teamPacksBucket     = []byte("team_packs")
+	packTeamsIndex      = []byte("pack_teams")
+	teamModsBucket      = []byte("team_mods")
+	modTeamsIndex       = []byte("mod_teams")
+	userPacksBucket     = []byte("user_packs")
+	packUsersIndex      = []byte("pack_users")
+	userModsBucket      = []byte("user_mods")
+	modUsersIndex       = []byte("mod_users")
+	buildVersionsBucket = []byte("build_versions")
+	versionBuildsIndex  = []byte("version_builds")
+
+	blobsBucket = []byte("blobs")
+	linksBucket = []byte("links")
+
+	migrationsBucket = []byte("migrations")
+)
+
+var (
+	userEntity      = entity{records: usersBucket, slugs: usersSlugIndex}
+	teamEntity      = entity{records: teamsBucket, slugs: teamsSlugIndex}
+	packEntity      = entity{records: packsBucket, slugs: packsSlugIndex}
+	buildEntity     = entity{records: buildsBucket, slugs: buildsSlugIndex}
+	modEntity       = entity{records: modsBucket, slugs: modsSlugIndex}
+	versionEntity   = entity{records: versionsBucket, slugs: versionsSlugIndex}
+	minecraftEntity = entity{records: minecraftsBucket, slugs: minecraftsSlugIndex}
+	forgeEntity     = entity{records: forgesBucket, slugs: forgesSlugIndex}
+
+	packBuilds      = index{bucket: packBuildsIndex}
+	modVersions     = index{bucket: modVersionsIndex}
+	minecraftBuilds = index{bucket: minecraftBuildsIndex}
+	forgeBuilds     = index{bucket: forgeBuildsIndex}
+
+	teamUserRelation     = relation{records: teamUsersBucket, reverse: index{bucket: userTeamsIndex}}
+	teamPackRelation     = relation{records: teamPacksBucket, reverse: index{bucket: packTeamsIndex}}
+	teamModRelation      = relation{records: teamModsBucket, reverse: index{bucket: modTeamsIndex}}
+	userPackRelation     = relation{records: userPacksBucket, reverse: index{bucket: packUsersIndex}}
+	userModRelation      = relation{records: userModsBucket, reverse: index{bucket: modUsersIndex}}
+	buildVersionRelation = relation{records: buildVersionsBucket, reverse: index{bucket: versionBuildsIndex}}
+
+	blobEntity = entity{records: blobsBucket}
+	linkEntity = entity{records: linksBucket}
+)
+
+var schemaBuckets = [][]byte{
+	usersBucket,
+	teamsBucket,
+	packsBucket,
+	buildsBucket,
+	modsBucket,
+	versionsBucket,
+	minecraftsBucket,
+	forgesBucket,
+	usersSlugIndex,
+	teamsSlugIndex,
+	packsSlugIndex,
+	buildsSlugIndex,
+	modsSlugIndex,
+	versionsSlugIndex,
+	minecraftsSlugIndex,
+	forgesSlugIndex,
+	packBuildsIndex,
+	modVersionsIndex,
+	minecraftBuildsIndex,
+	forgeBuildsIndex,
+	teamUsersBucket,
+	userTeamsIndex,
+	teamPacksBucket,
+	packTeamsIndex,
+	teamModsBucket,
+	modTeamsIndex,
+	userPacksBucket,
+	packUsersIndex,
+	userModsBucket,
+	modUsersIndex,
+	buildVersionsBucket,
+	versionBuildsIndex,
+}
+
+var uploadBuckets = [][]byte{
+	blobsBucket,
+	linksBucket,
+}
+
+// createBuckets makes sure that all required buckets exist.
+func createBuckets(tx *bolt.Tx) error {
+	return create(tx, schemaBuckets)
+}
+
+// dropBuckets removes all buckets including the stored records.
+func dropBuckets(tx *bolt.Tx) error {
+	return drop(tx, schemaBuckets)
+}
+
+// createUploadBuckets makes sure that the buckets for the uploads exist.
+func createUploadBuckets(tx *bolt.Tx) error {
+	return create(tx, uploadBuckets)
+}
+
+// dropUploadBuckets removes the buckets for the uploads.
+func dropUploadBuckets(tx *bolt.Tx) error {
+	return drop(tx, uploadBuckets)
+}
+
+// create makes sure that the given buckets exist.
+func create(tx *bolt.Tx, names [][]byte) error {
+	for _, name := range names {
+		if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// drop removes the given buckets including the stored records.
+func drop(tx *bolt.Tx, names [][]byte) error {
+	for _, name := range names {
+		if err := tx.DeleteBucket(name); err != nil && err != bolt.ErrBucketNotFound {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// key builds a composite key out of the given parts.
+func key(parts ...string) []byte {
+	return []byte(strings.Join(parts, "/"))
+}
+
+// entity bundles the record bucket with its slug index.
+type entity struct {
+	records []byte
+	slugs   []byte
+}
+
+// resolve looks up the ID for an ID or a slug within an optional scope.
+// Values shaped like a UUID only match the ID like within the SQL drivers,
+// this way a slug which looks like a UUID can't resolve differently.
+func (e entity) resolve(tx *bolt.Tx, scope, id string) (string, error) {
+	if tx.Bucket(e.records).Get([]byte(id)) != nil {
+		return id, nil
+	}
+
+	if _, err := uuid.Parse(id); err == nil {
+		return "", store.ErrNotFound
+	}
+
+	if val := tx.Bucket(e.slugs).Get(e.slug(scope, id)); val != nil {
+		return string(val), nil
+	}
+
+	return "", store.ErrNotFound
+}
+
+// get unmarshals the record with the given ID.
+func (e entity) get(tx *bolt.Tx, id string, record interface{}) error {
+	val := tx.Bucket(e.records).Get([]byte(id))
+
+	if val == nil {
+		return store.ErrNotFound
+	}
+
+	return json.Unmarshal(val, record)
+}
+
+// put marshals the record and stores it with the given ID.
+func (e entity) put(tx *bolt.Tx, id string, record interface{}) error {
+	val, err := json.Marshal(record)
+
+	if err != nil {
+		return err
+	}
+
+	return tx.Bucket(e.records).Put([]byte(id), val)
+}
+
+// each iterates over all stored records.
+func (e entity) each(tx *bolt.Tx, fn func([]byte) error) error {
+	return tx.Bucket(e.records).ForEach(func(_, v []byte) error {
+		return fn(v)
+	})
+}
+
+// exists checks if the record with the given ID exists.
+func (e entity) exists(tx *bolt.Tx, id string) bool {
+	return id != "" && tx.Bucket(e.records).Get([]byte(id)) != nil
+}
+
+// claim updates the slug index and fails if the slug is already taken.
+func (e entity) claim(tx *bolt.Tx, scope, id, before, after string) error {
+	bucket := tx.Bucket(e.slugs)
+
+	if before == after && before != "" {
+		return nil
+	}
+
+	if val := bucket.Get(e.slug(scope, after)); val != nil && string(val) != id {
+		return store.ErrConflict
+	}
+
+	if before != "" {
+		if err := bucket.Delete(e.slug(scope, before)); err != nil {
+			return err
+		}
+	}
+
+	return bucket.Put(e.slug(scope, after), []byte(id))
+}
+
+// remove deletes the record and its slug from the index.
+func (e entity) remove(tx *bolt.Tx, scope, id, slug string) error {
+	if err := tx.Bucket(e.slugs).Delete(e.slug(scope, slug)); err != nil {
+		return err
+	}
+
+	return tx.Bucket(e.records).Delete([]byte(id))
+}
+
+// slug generates the key for the slug index.
+func (e entity) slug(scope, slug string) []byte {
+	if scope == "" {
+		return []byte(slug)
+	}
+
+	return key(scope, slug)
+}
+
+// index maps parent IDs to child IDs by composite keys.
+type index struct {
+	bucket []byte
+}
+
+// add stores the link between a parent and a child.
+func (i index) add(tx *bolt.Tx, parent, child string) error {
+	if parent == "" {
+		return nil
+	}
+
+	return tx.Bucket(i.bucket).Put(key(parent, child), []byte{})
+}
+
+// remove deletes the link between a parent and a child.
+func (i index) remove(tx *bolt.Tx, parent, child string) error {
+	if parent == "" {
+		return nil
+	}
+
+	return tx.Bucket(i.bucket).Delete(key(parent, child))
+}
+
+// children lists the IDs of all children linked to a parent.
+func (i index) children(tx *bolt.Tx, parent string) []string {
+	result := make([]string, 0)
+	prefix := key(parent, "")
+	c := tx.Bucket(i.bucket).Cursor()
+
+	for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+		result = append(result, string(k[len(prefix):]))
+	}
+
+	return result
+}
+
+// relation stores assignments keyed by left and right IDs.
+type relation struct {
+	records []byte
+	reverse index
+}
+
+// get unmarshals the assignment between left and right.
+func (r relation) get(tx *bolt.Tx, left, right string, record interface{}) error {
+	val := tx.Bucket(r.records).Get(key(left, right))
+
+	if val == nil {
+		return store.ErrNotFound
+	}
+
+	return json.Unmarshal(val, record)
+}
+
+// exists checks if an assignment between left and right exists.
+func (r relation) exists(tx *bolt.Tx, left, right string) bool {
+	return tx.Bucket(r.records).Get(key(left, right)) != nil
+}
+
+// put marshals and stores the assignment between left and right.
+func (r relation) put(tx *bolt.Tx, left, right string, record interface{}) error {
+	val, err := json.Marshal(record)
+
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Bucket(r.records).Put(key(left, right), val); err != nil {
+		return err
+	}
+
+	return r.reverse.add(tx, right, left)
+}
+
+// drop deletes the assignment between left and right.
+func (r relation) drop(tx *bolt.Tx, left, right string) error {
+	if err := tx.Bucket(r.records).Delete(key(left, right)); err != nil {
+		return err
+	}
+
+	return r.reverse.remove(tx, right, left)
+}
+
+// rights lists all right IDs assigned to the left ID.
+func (r relation) rights(tx *bolt.Tx, left string) []string {
+	return index{bucket: r.records}.children(tx, left)
+}
+
+// lefts lists all left IDs assigned to the right ID.
+func (r relation) lefts(tx *bolt.Tx, right string) []string {
+	return r.reverse.children(tx, right)
+}
+
+// dropLeft deletes all assignments of the left ID.
+func (r relation) dropLeft(tx *bolt.Tx, left string) error {
+	for _, right := range r.rights(tx, left) {
+		if err := r.drop(tx, left, right); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dropRight deletes all assignments of the right ID.
+func (r relation) dropRight(tx *bolt.Tx, right string) error {
+	for _, left := range r.lefts(tx, right) {
+		if err := r.drop(tx, left, right); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
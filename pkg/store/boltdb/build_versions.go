@@ -0,0 +1,143 @@
+package boltdb
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+	bolt "go.etcd.io/bbolt"
+)
+
+type buildVersions struct {
+	s *boltdb
+}
+
+// ListByBuild implements the store.BuildVersions interface.
+func (r *buildVersions) ListByBuild(ctx context.Context, buildID string) ([]*model.BuildVersion, error) {
+	records := make([]*model.BuildVersion, 0)
+
+	err := r.s.view(func(tx *bolt.Tx) error {
+		if !buildEntity.exists(tx, buildID) {
+			return store.ErrNotFound
+		}
+
+		for _, versionID := range buildVersionRelation.rights(tx, buildID) {
+			record, err := loadBuildVersion(tx, buildID, versionID)
+
+			if err != nil {
+				return err
+			}
+
+			records = append(records, record)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Version.Name < records[j].Version.Name
+	})
+
+	return records, nil
+}
+
+// ListByVersion implements the store.BuildVersions interface.
+func (r *buildVersions) ListByVersion(ctx context.Context, versionID string) ([]*model.BuildVersion, error) {
+	records := make([]*model.BuildVersion, 0)
+
+	err := r.s.view(func(tx *bolt.Tx) error {
+		if !versionEntity.exists(tx, versionID) {
+			return store.ErrNotFound
+		}
+
+		for _, buildID := range buildVersionRelation.lefts(tx, versionID) {
+			record, err := loadBuildVersion(tx, buildID, versionID)
+
+			if err != nil {
+				return err
+			}
+
+			records = append(records, record)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Build.Name < records[j].Build.Name
+	})
+
+	return records, nil
+}
+
+// Append implements the store.BuildVersions interface.
+func (r *buildVersions) Append(ctx context.Context, buildVersion *model.BuildVersion) error {
+	record := *buildVersion
+	now := time.Now().UTC()
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = now
+	}
+
+	return r.s.update(func(tx *bolt.Tx) error {
+		if !buildEntity.exists(tx, record.BuildID) || !versionEntity.exists(tx, record.VersionID) {
+			return store.ErrNotFound
+		}
+
+		if buildVersionRelation.exists(tx, record.BuildID, record.VersionID) {
+			return store.ErrConflict
+		}
+
+		record.Build = nil
+		record.Version = nil
+
+		return buildVersionRelation.put(tx, record.BuildID, record.VersionID, record)
+	})
+}
+
+// Drop implements the store.BuildVersions interface.
+func (r *buildVersions) Drop(ctx context.Context, buildID, versionID string) error {
+	return r.s.update(func(tx *bolt.Tx) error {
+		if !buildVersionRelation.exists(tx, buildID, versionID) {
+			return store.ErrNotFound
+		}
+
+		return buildVersionRelation.drop(tx, buildID, versionID)
+	})
+}
+
+// loadBuildVersion unmarshals an assignment including both sides.
+func loadBuildVersion(tx *bolt.Tx, buildID, versionID string) (*model.BuildVersion, error) {
+	record := &model.BuildVersion{
+		Build:   &model.Build{},
+		Version: &model.Version{},
+	}
+
+	if err := buildVersionRelation.get(tx, buildID, versionID, record); err != nil {
+		return nil, err
+	}
+
+	if err := buildEntity.get(tx, buildID, record.Build); err != nil {
+		return nil, err
+	}
+
+	if err := versionEntity.get(tx, versionID, record.Version); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
@@ -0,0 +1,262 @@
+package boltdb
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+	bolt "go.etcd.io/bbolt"
+)
+
+type builds struct {
+	s *boltdb
+}
+
+// List implements the store.Builds interface.
+func (r *builds) List(ctx context.Context, packID string) ([]*model.Build, error) {
+	records := make([]*model.Build, 0)
+
+	err := r.s.view(func(tx *bolt.Tx) error {
+		if !packEntity.exists(tx, packID) {
+			return store.ErrNotFound
+		}
+
+		for _, id := range packBuilds.children(tx, packID) {
+			record := &model.Build{}
+
+			if err := buildEntity.get(tx, id, record); err != nil {
+				return err
+			}
+
+			records = append(records, record)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Name < records[j].Name
+	})
+
+	return records, nil
+}
+
+// Show implements the store.Builds interface.
+func (r *builds) Show(ctx context.Context, packID, id string) (*model.Build, error) {
+	record := &model.Build{}
+
+	err := r.s.view(func(tx *bolt.Tx) error {
+		return findBuild(tx, packID, id, record)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// Create implements the store.Builds interface.
+func (r *builds) Create(ctx context.Context, build *model.Build) (*model.Build, error) {
+	record := *build
+	now := time.Now().UTC()
+
+	if record.ID == "" {
+		record.ID = uuid.New().String()
+	}
+
+	record.Slug = store.SlugOrDefault(record.Slug, record.Name, record.ID)
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = now
+	}
+
+	err := r.s.update(func(tx *bolt.Tx) error {
+		if buildEntity.exists(tx, record.ID) {
+			return store.ErrConflict
+		}
+
+		if err := checkBuild(tx, &record); err != nil {
+			return err
+		}
+
+		if err := buildEntity.claim(tx, record.PackID, record.ID, "", record.Slug); err != nil {
+			return err
+		}
+
+		if err := packBuilds.add(tx, record.PackID, record.ID); err != nil {
+			return err
+		}
+
+		if err := minecraftBuilds.add(tx, record.MinecraftID, record.ID); err != nil {
+			return err
+		}
+
+		if err := forgeBuilds.add(tx, record.ForgeID, record.ID); err != nil {
+			return err
+		}
+
+		return buildEntity.put(tx, record.ID, record)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Update implements the store.Builds interface.
+func (r *builds) Update(ctx context.Context, build *model.Build) (*model.Build, error) {
+	record := *build
+
+	err := r.s.update(func(tx *bolt.Tx) error {
+		current := &model.Build{}
+
+		if err := buildEntity.get(tx, record.ID, current); err != nil {
+			return err
+		}
+
+		if current.PackID != record.PackID {
+			return store.ErrNotFound
+		}
+
+		record.Slug = store.SlugOrDefault(record.Slug, record.Name, current.Slug)
+		record.CreatedAt = current.CreatedAt
+		record.UpdatedAt = time.Now().UTC()
+
+		if err := checkBuild(tx, &record); err != nil {
+			return err
+		}
+
+		if err := buildEntity.claim(tx, record.PackID, record.ID, current.Slug, record.Slug); err != nil {
+			return err
+		}
+
+		if err := minecraftBuilds.remove(tx, current.MinecraftID, record.ID); err != nil {
+			return err
+		}
+
+		if err := minecraftBuilds.add(tx, record.MinecraftID, record.ID); err != nil {
+			return err
+		}
+
+		if err := forgeBuilds.remove(tx, current.ForgeID, record.ID); err != nil {
+			return err
+		}
+
+		if err := forgeBuilds.add(tx, record.ForgeID, record.ID); err != nil {
+			return err
+		}
+
+		return buildEntity.put(tx, record.ID, record)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Delete implements the store.Builds interface.
+func (r *builds) Delete(ctx context.Context, packID, id string) error {
+	return r.s.update(func(tx *bolt.Tx) error {
+		record := &model.Build{}
+
+		if err := findBuild(tx, packID, id, record); err != nil {
+			return err
+		}
+
+		return deleteBuild(tx, record)
+	})
+}
+
+// findBuild resolves and unmarshals a build of a pack by ID or slug.
+func findBuild(tx *bolt.Tx, packID, id string, record *model.Build) error {
+	resolved, err := buildEntity.resolve(tx, packID, id)
+
+	if err != nil {
+		return err
+	}
+
+	if err := buildEntity.get(tx, resolved, record); err != nil {
+		return err
+	}
+
+	if record.PackID != packID {
+		return store.ErrNotFound
+	}
+
+	return nil
+}
+
+// checkBuild verifies that all references of the build exist.
+func checkBuild(tx *bolt.Tx, record *model.Build) error {
+	if !packEntity.exists(tx, record.PackID) {
+		return store.ErrNotFound
+	}
+
+	if record.MinecraftID != "" && !minecraftEntity.exists(tx, record.MinecraftID) {
+		return store.ErrNotFound
+	}
+
+	if record.ForgeID != "" && !forgeEntity.exists(tx, record.ForgeID) {
+		return store.ErrNotFound
+	}
+
+	return nil
+}
+
+// deleteBuild removes a build including its assignments and detaches it
+// from the recommended and latest markers of the pack.
+func deleteBuild(tx *bolt.Tx, record *model.Build) error {
+	if err := buildVersionRelation.dropLeft(tx, record.ID); err != nil {
+		return err
+	}
+
+	pack := &model.Pack{}
+
+	if err := packEntity.get(tx, record.PackID, pack); err != nil {
+		return err
+	}
+
+	if pack.RecommendedID == record.ID || pack.LatestID == record.ID {
+		if pack.RecommendedID == record.ID {
+			pack.RecommendedID = ""
+		}
+
+		if pack.LatestID == record.ID {
+			pack.LatestID = ""
+		}
+
+		if err := packEntity.put(tx, pack.ID, pack); err != nil {
+			return err
+		}
+	}
+
+	if err := packBuilds.remove(tx, record.PackID, record.ID); err != nil {
+		return err
+	}
+
+	if err := minecraftBuilds.remove(tx, record.MinecraftID, record.ID); err != nil {
+		return err
+	}
+
+	if err := forgeBuilds.remove(tx, record.ForgeID, record.ID); err != nil {
+		return err
+	}
+
+	return buildEntity.remove(tx, record.PackID, record.ID, record.Slug)
+}
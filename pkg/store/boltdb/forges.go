@@ -0,0 +1,174 @@
+package boltdb
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+	bolt "go.etcd.io/bbolt"
+)
+
+type forges struct {
+	s *boltdb
+}
+
+// List implements the store.Forges interface.
+func (r *forges) List(ctx context.Context) ([]*model.Forge, error) {
+	records := make([]*model.Forge, 0)
+
+	err := r.s.view(func(tx *bolt.Tx) error {
+		return forgeEntity.each(tx, func(val []byte) error {
+			record := &model.Forge{}
+
+			if err := json.Unmarshal(val, record); err != nil {
+				return err
+			}
+
+			records = append(records, record)
+			return nil
+		})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Name < records[j].Name
+	})
+
+	return records, nil
+}
+
+// Show implements the store.Forges interface.
+func (r *forges) Show(ctx context.Context, id string) (*model.Forge, error) {
+	record := &model.Forge{}
+
+	err := r.s.view(func(tx *bolt.Tx) error {
+		return findForge(tx, id, record)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// Create implements the store.Forges interface.
+func (r *forges) Create(ctx context.Context, forge *model.Forge) (*model.Forge, error) {
+	record := *forge
+	now := time.Now().UTC()
+
+	if record.ID == "" {
+		record.ID = uuid.New().String()
+	}
+
+	record.Slug = store.SlugOrDefault(record.Slug, record.Name, record.ID)
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = now
+	}
+
+	err := r.s.update(func(tx *bolt.Tx) error {
+		if forgeEntity.exists(tx, record.ID) {
+			return store.ErrConflict
+		}
+
+		if err := forgeEntity.claim(tx, "", record.ID, "", record.Slug); err != nil {
+			return err
+		}
+
+		return forgeEntity.put(tx, record.ID, record)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Update implements the store.Forges interface.
+func (r *forges) Update(ctx context.Context, forge *model.Forge) (*model.Forge, error) {
+	record := *forge
+
+	err := r.s.update(func(tx *bolt.Tx) error {
+		current := &model.Forge{}
+
+		if err := forgeEntity.get(tx, record.ID, current); err != nil {
+			return err
+		}
+
+		record.Slug = store.SlugOrDefault(record.Slug, record.Name, current.Slug)
+		record.CreatedAt = current.CreatedAt
+		record.UpdatedAt = time.Now().UTC()
+
+		if err := forgeEntity.claim(tx, "", record.ID, current.Slug, record.Slug); err != nil {
+			return err
+		}
+
+		return forgeEntity.put(tx, record.ID, record)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Delete implements the store.Forges interface.
+func (r *forges) Delete(ctx context.Context, id string) error {
+	return r.s.update(func(tx *bolt.Tx) error {
+		record := &model.Forge{}
+
+		if err := findForge(tx, id, record); err != nil {
+			return err
+		}
+
+		return deleteForge(tx, record)
+	})
+}
+
+// findForge resolves and unmarshals a forge by ID or slug.
+func findForge(tx *bolt.Tx, id string, record *model.Forge) error {
+	resolved, err := forgeEntity.resolve(tx, "", id)
+
+	if err != nil {
+		return err
+	}
+
+	return forgeEntity.get(tx, resolved, record)
+}
+
+// deleteForge removes a Forge version and detaches it from builds.
+func deleteForge(tx *bolt.Tx, record *model.Forge) error {
+	for _, id := range forgeBuilds.children(tx, record.ID) {
+		build := &model.Build{}
+
+		if err := buildEntity.get(tx, id, build); err != nil {
+			return err
+		}
+
+		build.ForgeID = ""
+
+		if err := buildEntity.put(tx, build.ID, build); err != nil {
+			return err
+		}
+
+		if err := forgeBuilds.remove(tx, record.ID, build.ID); err != nil {
+			return err
+		}
+	}
+
+	return forgeEntity.remove(tx, "", record.ID, record.Slug)
+}
@@ -0,0 +1,108 @@
+package boltdb
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+	bolt "go.etcd.io/bbolt"
+)
+
+type links struct {
+	s *boltdb
+}
+
+// List implements the store.Links interface.
+func (r *links) List(ctx context.Context) ([]*model.Link, error) {
+	records := make([]*model.Link, 0)
+
+	err := r.s.view(func(tx *bolt.Tx) error {
+		return linkEntity.each(tx, func(val []byte) error {
+			record := &model.Link{}
+
+			if err := json.Unmarshal(val, record); err != nil {
+				return err
+			}
+
+			records = append(records, record)
+			return nil
+		})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Path < records[j].Path
+	})
+
+	return records, nil
+}
+
+// Show implements the store.Links interface.
+func (r *links) Show(ctx context.Context, path string) (*model.Link, error) {
+	record := &model.Link{}
+
+	err := r.s.view(func(tx *bolt.Tx) error {
+		return linkEntity.get(tx, path, record)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// Create implements the store.Links interface.
+func (r *links) Create(ctx context.Context, link *model.Link) (*model.Link, error) {
+	record := *link
+	now := time.Now().UTC()
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = now
+	}
+
+	err := r.s.update(func(tx *bolt.Tx) error {
+		if linkEntity.exists(tx, record.Path) {
+			return store.ErrConflict
+		}
+
+		if err := referenceBlob(tx, record.Digest, 1); err != nil {
+			return err
+		}
+
+		return linkEntity.put(tx, record.Path, record)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Delete implements the store.Links interface.
+func (r *links) Delete(ctx context.Context, path string) error {
+	return r.s.update(func(tx *bolt.Tx) error {
+		record := &model.Link{}
+
+		if err := linkEntity.get(tx, path, record); err != nil {
+			return err
+		}
+
+		if err := referenceBlob(tx, record.Digest, -1); err != nil {
+			return err
+		}
+
+		return tx.Bucket(linksBucket).Delete([]byte(path))
+	})
+}
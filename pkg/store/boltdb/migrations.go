@@ -0,0 +1,203 @@
+package boltdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/kleister/kleister-api/pkg/store"
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+// migration defines a versioned change of the bucket layout.
+type migration struct {
+	Version int
+	Name    string
+	Up      func(*bolt.Tx) error
+	Down    func(*bolt.Tx) error
+}
+
+// applied defines the record stored for every applied migration.
+type applied struct {
+	Name      string    `json:"name"`
+	AppliedAt time.Time `json:"applied_at"`
+}
+
+var migrationList = []migration{
+	{
+		Version: 1,
+		Name:    "create_buckets",
+		Up:      createBuckets,
+		Down:    dropBuckets,
+	},
+	{
+		Version: 2,
+		Name:    "create_upload_buckets",
+		Up:      createUploadBuckets,
+		Down:    dropUploadBuckets,
+	},
+}
+
+type migrations struct {
+	s *boltdb
+}
+
+// Status implements the store.Migrations interface.
+func (r *migrations) Status(ctx context.Context) ([]*store.Migration, error) {
+	records := make([]*store.Migration, 0)
+
+	err := r.s.view(func(tx *bolt.Tx) error {
+		for _, m := range migrationList {
+			record := &store.Migration{
+				Version: m.Version,
+				Name:    m.Name,
+			}
+
+			a, err := r.get(tx, m.Version)
+
+			if err != nil {
+				return err
+			}
+
+			if a != nil {
+				record.AppliedAt = a.AppliedAt
+			}
+
+			records = append(records, record)
+		}
+
+		return nil
+	})
+
+	return records, err
+}
+
+// Up implements the store.Migrations interface.
+func (r *migrations) Up(ctx context.Context, dry bool) ([]*store.Migration, error) {
+	records := make([]*store.Migration, 0)
+
+	for _, m := range migrationList {
+		var done bool
+
+		if err := r.s.view(func(tx *bolt.Tx) error {
+			a, err := r.get(tx, m.Version)
+			done = a != nil
+			return err
+		}); err != nil {
+			return records, err
+		}
+
+		if done {
+			continue
+		}
+
+		record := &store.Migration{
+			Version: m.Version,
+			Name:    m.Name,
+		}
+
+		if !dry {
+			record.AppliedAt = time.Now().UTC()
+
+			if err := r.s.update(func(tx *bolt.Tx) error {
+				if err := m.Up(tx); err != nil {
+					return err
+				}
+
+				return r.put(tx, m.Version, &applied{
+					Name:      m.Name,
+					AppliedAt: record.AppliedAt,
+				})
+			}); err != nil {
+				return records, errors.Wrapf(err, "failed to apply migration %d", m.Version)
+			}
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// Down implements the store.Migrations interface.
+func (r *migrations) Down(ctx context.Context, dry bool) ([]*store.Migration, error) {
+	records := make([]*store.Migration, 0)
+
+	for i := len(migrationList) - 1; i >= 0; i-- {
+		m := migrationList[i]
+
+		var current *applied
+
+		if err := r.s.view(func(tx *bolt.Tx) error {
+			a, err := r.get(tx, m.Version)
+			current = a
+			return err
+		}); err != nil {
+			return records, err
+		}
+
+		if current == nil {
+			continue
+		}
+
+		record := &store.Migration{
+			Version:   m.Version,
+			Name:      m.Name,
+			AppliedAt: current.AppliedAt,
+		}
+
+		if !dry {
+			if err := r.s.update(func(tx *bolt.Tx) error {
+				if err := m.Down(tx); err != nil {
+					return err
+				}
+
+				return tx.Bucket(migrationsBucket).Delete(migrationKey(m.Version))
+			}); err != nil {
+				return records, errors.Wrapf(err, "failed to revert migration %d", m.Version)
+			}
+
+			record.AppliedAt = time.Time{}
+		}
+
+		records = append(records, record)
+		break
+	}
+
+	return records, nil
+}
+
+// get fetches the applied record of a migration, nil if it is pending.
+func (r *migrations) get(tx *bolt.Tx, version int) (*applied, error) {
+	raw := tx.Bucket(migrationsBucket).Get(migrationKey(version))
+
+	if raw == nil {
+		return nil, nil
+	}
+
+	record := &applied{}
+
+	if err := json.Unmarshal(raw, record); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// put stores the applied record of a migration.
+func (r *migrations) put(tx *bolt.Tx, version int, record *applied) error {
+	raw, err := json.Marshal(record)
+
+	if err != nil {
+		return err
+	}
+
+	return tx.Bucket(migrationsBucket).Put(migrationKey(version), raw)
+}
+
+// migrationKey pads the version to keep the keys sorted.
+func migrationKey(version int) []byte {
+	return []byte(fmt.Sprintf("%08d", version))
+}
@@ -0,0 +1,174 @@
+package boltdb
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+	bolt "go.etcd.io/bbolt"
+)
+
+type minecrafts struct {
+	s *boltdb
+}
+
+// List implements the store.Minecrafts interface.
+func (r *minecrafts) List(ctx context.Context) ([]*model.Minecraft, error) {
+	records := make([]*model.Minecraft, 0)
+
+	err := r.s.view(func(tx *bolt.Tx) error {
+		return minecraftEntity.each(tx, func(val []byte) error {
+			record := &model.Minecraft{}
+
+			if err := json.Unmarshal(val, record); err != nil {
+				return err
+			}
+
+			records = append(records, record)
+			return nil
+		})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Name < records[j].Name
+	})
+
+	return records, nil
+}
+
+// Show implements the store.Minecrafts interface.
+func (r *minecrafts) Show(ctx context.Context, id string) (*model.Minecraft, error) {
+	record := &model.Minecraft{}
+
+	err := r.s.view(func(tx *bolt.Tx) error {
+		return findMinecraft(tx, id, record)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// Create implements the store.Minecrafts interface.
+func (r *minecrafts) Create(ctx context.Context, minecraft *model.Minecraft) (*model.Minecraft, error) {
+	record := *minecraft
+	now := time.Now().UTC()
+
+	if record.ID == "" {
+		record.ID = uuid.New().String()
+	}
+
+	record.Slug = store.SlugOrDefault(record.Slug, record.Name, record.ID)
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = now
+	}
+
+	err := r.s.update(func(tx *bolt.Tx) error {
+		if minecraftEntity.exists(tx, record.ID) {
+			return store.ErrConflict
+		}
+
+		if err := minecraftEntity.claim(tx, "", record.ID, "", record.Slug); err != nil {
+			return err
+		}
+
+		return minecraftEntity.put(tx, record.ID, record)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Update implements the store.Minecrafts interface.
+func (r *minecrafts) Update(ctx context.Context, minecraft *model.Minecraft) (*model.Minecraft, error) {
+	record := *minecraft
+
+	err := r.s.update(func(tx *bolt.Tx) error {
+		current := &model.Minecraft{}
+
+		if err := minecraftEntity.get(tx, record.ID, current); err != nil {
+			return err
+		}
+
+		record.Slug = store.SlugOrDefault(record.Slug, record.Name, current.Slug)
+		record.CreatedAt = current.CreatedAt
+		record.UpdatedAt = time.Now().UTC()
+
+		if err := minecraftEntity.claim(tx, "", record.ID, current.Slug, record.Slug); err != nil {
+			return err
+		}
+
+		return minecraftEntity.put(tx, record.ID, record)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Delete implements the store.Minecrafts interface.
+func (r *minecrafts) Delete(ctx context.Context, id string) error {
+	return r.s.update(func(tx *bolt.Tx) error {
+		record := &model.Minecraft{}
+
+		if err := findMinecraft(tx, id, record); err != nil {
+			return err
+		}
+
+		return deleteMinecraft(tx, record)
+	})
+}
+
+// findMinecraft resolves and unmarshals a minecraft by ID or slug.
+func findMinecraft(tx *bolt.Tx, id string, record *model.Minecraft) error {
+	resolved, err := minecraftEntity.resolve(tx, "", id)
+
+	if err != nil {
+		return err
+	}
+
+	return minecraftEntity.get(tx, resolved, record)
+}
+
+// deleteMinecraft removes a Minecraft version and detaches it from builds.
+func deleteMinecraft(tx *bolt.Tx, record *model.Minecraft) error {
+	for _, id := range minecraftBuilds.children(tx, record.ID) {
+		build := &model.Build{}
+
+		if err := buildEntity.get(tx, id, build); err != nil {
+			return err
+		}
+
+		build.MinecraftID = ""
+
+		if err := buildEntity.put(tx, build.ID, build); err != nil {
+			return err
+		}
+
+		if err := minecraftBuilds.remove(tx, record.ID, build.ID); err != nil {
+			return err
+		}
+	}
+
+	return minecraftEntity.remove(tx, "", record.ID, record.Slug)
+}
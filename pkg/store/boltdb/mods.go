@@ -0,0 +1,176 @@
+package boltdb
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+	bolt "go.etcd.io/bbolt"
+)
+
+type mods struct {
+	s *boltdb
+}
+
+// List implements the store.Mods interface.
+func (r *mods) List(ctx context.Context) ([]*model.Mod, error) {
+	records := make([]*model.Mod, 0)
+
+	err := r.s.view(func(tx *bolt.Tx) error {
+		return modEntity.each(tx, func(val []byte) error {
+			record := &model.Mod{}
+
+			if err := json.Unmarshal(val, record); err != nil {
+				return err
+			}
+
+			records = append(records, record)
+			return nil
+		})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Name < records[j].Name
+	})
+
+	return records, nil
+}
+
+// Show implements the store.Mods interface.
+func (r *mods) Show(ctx context.Context, id string) (*model.Mod, error) {
+	record := &model.Mod{}
+
+	err := r.s.view(func(tx *bolt.Tx) error {
+		return findMod(tx, id, record)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// Create implements the store.Mods interface.
+func (r *mods) Create(ctx context.Context, mod *model.Mod) (*model.Mod, error) {
+	record := *mod
+	now := time.Now().UTC()
+
+	if record.ID == "" {
+		record.ID = uuid.New().String()
+	}
+
+	record.Slug = store.SlugOrDefault(record.Slug, record.Name, record.ID)
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = now
+	}
+
+	err := r.s.update(func(tx *bolt.Tx) error {
+		if modEntity.exists(tx, record.ID) {
+			return store.ErrConflict
+		}
+
+		if err := modEntity.claim(tx, "", record.ID, "", record.Slug); err != nil {
+			return err
+		}
+
+		return modEntity.put(tx, record.ID, record)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Update implements the store.Mods interface.
+func (r *mods) Update(ctx context.Context, mod *model.Mod) (*model.Mod, error) {
+	record := *mod
+
+	err := r.s.update(func(tx *bolt.Tx) error {
+		current := &model.Mod{}
+
+		if err := modEntity.get(tx, record.ID, current); err != nil {
+			return err
+		}
+
+		record.Slug = store.SlugOrDefault(record.Slug, record.Name, current.Slug)
+		record.CreatedAt = current.CreatedAt
+		record.UpdatedAt = time.Now().UTC()
+
+		if err := modEntity.claim(tx, "", record.ID, current.Slug, record.Slug); err != nil {
+			return err
+		}
+
+		return modEntity.put(tx, record.ID, record)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Delete implements the store.Mods interface.
+func (r *mods) Delete(ctx context.Context, id string) error {
+	return r.s.update(func(tx *bolt.Tx) error {
+		record := &model.Mod{}
+
+		if err := findMod(tx, id, record); err != nil {
+			return err
+		}
+
+		return deleteMod(tx, record)
+	})
+}
+
+// findMod resolves and unmarshals a mod by ID or slug.
+func findMod(tx *bolt.Tx, id string, record *model.Mod) error {
+	resolved, err := modEntity.resolve(tx, "", id)
+
+	if err != nil {
+		return err
+	}
+
+	return modEntity.get(tx, resolved, record)
+}
+
+// deleteMod removes a mod including its versions and assignments.
+func deleteMod(tx *bolt.Tx, record *model.Mod) error {
+	for _, id := range modVersions.children(tx, record.ID) {
+		version := &model.Version{}
+
+		if err := versionEntity.get(tx, id, version); err != nil {
+			return err
+		}
+
+		if err := deleteVersion(tx, version); err != nil {
+			return err
+		}
+	}
+
+	if err := teamModRelation.dropRight(tx, record.ID); err != nil {
+		return err
+	}
+
+	if err := userModRelation.dropRight(tx, record.ID); err != nil {
+		return err
+	}
+
+	return modEntity.remove(tx, "", record.ID, record.Slug)
+}
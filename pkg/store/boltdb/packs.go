@@ -0,0 +1,225 @@
+package boltdb
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+	bolt "go.etcd.io/bbolt"
+)
+
+type packs struct {
+	s *boltdb
+}
+
+// List implements the store.Packs interface.
+func (r *packs) List(ctx context.Context) ([]*model.Pack, error) {
+	records := make([]*model.Pack, 0)
+
+	err := r.s.view(func(tx *bolt.Tx) error {
+		return packEntity.each(tx, func(val []byte) error {
+			record := &model.Pack{}
+
+			if err := json.Unmarshal(val, record); err != nil {
+				return err
+			}
+
+			records = append(records, record)
+			return nil
+		})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Name < records[j].Name
+	})
+
+	return records, nil
+}
+
+// Show implements the store.Packs interface.
+func (r *packs) Show(ctx context.Context, id string) (*model.Pack, error) {
+	record := &model.Pack{}
+
+	err := r.s.view(func(tx *bolt.Tx) error {
+		return findPack(tx, id, record)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// Create implements the store.Packs interface.
+func (r *packs) Create(ctx context.Context, pack *model.Pack) (*model.Pack, error) {
+	record := *pack
+	now := time.Now().UTC()
+
+	if record.ID == "" {
+		record.ID = uuid.New().String()
+	}
+
+	record.Slug = store.SlugOrDefault(record.Slug, record.Name, record.ID)
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = now
+	}
+
+	err := r.s.update(func(tx *bolt.Tx) error {
+		if packEntity.exists(tx, record.ID) {
+			return store.ErrConflict
+		}
+
+		if err := checkPack(tx, &record); err != nil {
+			return err
+		}
+
+		if err := packEntity.claim(tx, "", record.ID, "", record.Slug); err != nil {
+			return err
+		}
+
+		return packEntity.put(tx, record.ID, record)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Update implements the store.Packs interface.
+func (r *packs) Update(ctx context.Context, pack *model.Pack) (*model.Pack, error) {
+	record := *pack
+
+	err := r.s.update(func(tx *bolt.Tx) error {
+		current := &model.Pack{}
+
+		if err := packEntity.get(tx, record.ID, current); err != nil {
+			return err
+		}
+
+		record.Slug = store.SlugOrDefault(record.Slug, record.Name, current.Slug)
+		record.CreatedAt = current.CreatedAt
+		record.UpdatedAt = time.Now().UTC()
+
+		if err := checkPack(tx, &record); err != nil {
+			return err
+		}
+
+		if err := packEntity.claim(tx, "", record.ID, current.Slug, record.Slug); err != nil {
+			return err
+		}
+
+		return packEntity.put(tx, record.ID, record)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Delete implements the store.Packs interface.
+func (r *packs) Delete(ctx context.Context, id string) error {
+	return r.s.update(func(tx *bolt.Tx) error {
+		record := &model.Pack{}
+
+		if err := findPack(tx, id, record); err != nil {
+			return err
+		}
+
+		return deletePack(tx, record)
+	})
+}
+
+// Assign implements the store.Packs interface.
+func (r *packs) Assign(ctx context.Context, pack *model.Pack) error {
+	return r.s.update(func(tx *bolt.Tx) error {
+		record := &model.Pack{}
+
+		if err := packEntity.get(tx, pack.ID, record); err != nil {
+			return err
+		}
+
+		record.RecommendedID = pack.RecommendedID
+		record.LatestID = pack.LatestID
+
+		if err := checkPack(tx, record); err != nil {
+			return err
+		}
+
+		return packEntity.put(tx, record.ID, record)
+	})
+}
+
+// findPack resolves and unmarshals a pack by ID or slug.
+func findPack(tx *bolt.Tx, id string, record *model.Pack) error {
+	resolved, err := packEntity.resolve(tx, "", id)
+
+	if err != nil {
+		return err
+	}
+
+	return packEntity.get(tx, resolved, record)
+}
+
+// checkPack verifies that the referenced builds belong to the pack.
+func checkPack(tx *bolt.Tx, record *model.Pack) error {
+	for _, id := range []string{record.RecommendedID, record.LatestID} {
+		if id == "" {
+			continue
+		}
+
+		build := &model.Build{}
+
+		if err := buildEntity.get(tx, id, build); err != nil {
+			return err
+		}
+
+		if build.PackID != record.ID {
+			return store.ErrNotFound
+		}
+	}
+
+	return nil
+}
+
+// deletePack removes a pack including its builds and assignments.
+func deletePack(tx *bolt.Tx, record *model.Pack) error {
+	for _, id := range packBuilds.children(tx, record.ID) {
+		build := &model.Build{}
+
+		if err := buildEntity.get(tx, id, build); err != nil {
+			return err
+		}
+
+		if err := deleteBuild(tx, build); err != nil {
+			return err
+		}
+	}
+
+	if err := teamPackRelation.dropRight(tx, record.ID); err != nil {
+		return err
+	}
+
+	if err := userPackRelation.dropRight(tx, record.ID); err != nil {
+		return err
+	}
+
+	return packEntity.remove(tx, "", record.ID, record.Slug)
+}
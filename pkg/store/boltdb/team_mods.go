@@ -0,0 +1,163 @@
+package boltdb
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+	bolt "go.etcd.io/bbolt"
+)
+
+type teamMods struct {
+	s *boltdb
+}
+
+// ListByTeam implements the store.TeamMods interface.
+func (r *teamMods) ListByTeam(ctx context.Context, teamID string) ([]*model.TeamMod, error) {
+	records := make([]*model.TeamMod, 0)
+
+	err := r.s.view(func(tx *bolt.Tx) error {
+		if !teamEntity.exists(tx, teamID) {
+			return store.ErrNotFound
+		}
+
+		for _, modID := range teamModRelation.rights(tx, teamID) {
+			record, err := loadTeamMod(tx, teamID, modID)
+
+			if err != nil {
+				return err
+			}
+
+			records = append(records, record)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Mod.Name < records[j].Mod.Name
+	})
+
+	return records, nil
+}
+
+// ListByMod implements the store.TeamMods interface.
+func (r *teamMods) ListByMod(ctx context.Context, modID string) ([]*model.TeamMod, error) {
+	records := make([]*model.TeamMod, 0)
+
+	err := r.s.view(func(tx *bolt.Tx) error {
+		if !modEntity.exists(tx, modID) {
+			return store.ErrNotFound
+		}
+
+		for _, teamID := range teamModRelation.lefts(tx, modID) {
+			record, err := loadTeamMod(tx, teamID, modID)
+
+			if err != nil {
+				return err
+			}
+
+			records = append(records, record)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Team.Name < records[j].Team.Name
+	})
+
+	return records, nil
+}
+
+// Append implements the store.TeamMods interface.
+func (r *teamMods) Append(ctx context.Context, teamMod *model.TeamMod) error {
+	record := *teamMod
+	now := time.Now().UTC()
+
+	if record.Perm == "" {
+		record.Perm = model.PermUser
+	}
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = now
+	}
+
+	return r.s.update(func(tx *bolt.Tx) error {
+		if !teamEntity.exists(tx, record.TeamID) || !modEntity.exists(tx, record.ModID) {
+			return store.ErrNotFound
+		}
+
+		if teamModRelation.exists(tx, record.TeamID, record.ModID) {
+			return store.ErrConflict
+		}
+
+		record.Team = nil
+		record.Mod = nil
+
+		return teamModRelation.put(tx, record.TeamID, record.ModID, record)
+	})
+}
+
+// Permit implements the store.TeamMods interface.
+func (r *teamMods) Permit(ctx context.Context, teamMod *model.TeamMod) error {
+	return r.s.update(func(tx *bolt.Tx) error {
+		record := &model.TeamMod{}
+
+		if err := teamModRelation.get(tx, teamMod.TeamID, teamMod.ModID, record); err != nil {
+			return err
+		}
+
+		record.Perm = teamMod.Perm
+		record.UpdatedAt = time.Now().UTC()
+
+		return teamModRelation.put(tx, record.TeamID, record.ModID, record)
+	})
+}
+
+// Drop implements the store.TeamMods interface.
+func (r *teamMods) Drop(ctx context.Context, teamID, modID string) error {
+	return r.s.update(func(tx *bolt.Tx) error {
+		if !teamModRelation.exists(tx, teamID, modID) {
+			return store.ErrNotFound
+		}
+
+		return teamModRelation.drop(tx, teamID, modID)
+	})
+}
+
+// loadTeamMod unmarshals an assignment including both sides.
+func loadTeamMod(tx *bolt.Tx, teamID, modID string) (*model.TeamMod, error) {
+	record := &model.TeamMod{
+		Team: &model.Team{},
+		Mod:  &model.Mod{},
+	}
+
+	if err := teamModRelation.get(tx, teamID, modID, record); err != nil {
+		return nil, err
+	}
+
+	if err := teamEntity.get(tx, teamID, record.Team); err != nil {
+		return nil, err
+	}
+
+	if err := modEntity.get(tx, modID, record.Mod); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
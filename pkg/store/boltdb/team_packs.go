@@ -0,0 +1,163 @@
+package boltdb
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+	bolt "go.etcd.io/bbolt"
+)
+
+type teamPacks struct {
+	s *boltdb
+}
+
+// ListByTeam implements the store.TeamPacks interface.
+func (r *teamPacks) ListByTeam(ctx context.Context, teamID string) ([]*model.TeamPack, error) {
+	records := make([]*model.TeamPack, 0)
+
+	err := r.s.view(func(tx *bolt.Tx) error {
+		if !teamEntity.exists(tx, teamID) {
+			return store.ErrNotFound
+		}
+
+		for _, packID := range teamPackRelation.rights(tx, teamID) {
+			record, err := loadTeamPack(tx, teamID, packID)
+
+			if err != nil {
+				return err
+			}
+
+			records = append(records, record)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Pack.Name < records[j].Pack.Name
+	})
+
+	return records, nil
+}
+
+// ListByPack implements the store.TeamPacks interface.
+func (r *teamPacks) ListByPack(ctx context.Context, packID string) ([]*model.TeamPack, error) {
+	records := make([]*model.TeamPack, 0)
+
+	err := r.s.view(func(tx *bolt.Tx) error {
+		if !packEntity.exists(tx, packID) {
+			return store.ErrNotFound
+		}
+
+		for _, teamID := range teamPackRelation.lefts(tx, packID) {
+			record, err := loadTeamPack(tx, teamID, packID)
+
+			if err != nil {
+				return err
+			}
+
+			records = append(records, record)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Team.Name < records[j].Team.Name
+	})
+
+	return records, nil
+}
+
+// Append implements the store.TeamPacks interface.
+func (r *teamPacks) Append(ctx context.Context, teamPack *model.TeamPack) error {
+	record := *teamPack
+	now := time.Now().UTC()
+
+	if record.Perm == "" {
+		record.Perm = model.PermUser
+	}
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = now
+	}
+
+	return r.s.update(func(tx *bolt.Tx) error {
+		if !teamEntity.exists(tx, record.TeamID) || !packEntity.exists(tx, record.PackID) {
+			return store.ErrNotFound
+		}
+
+		if teamPackRelation.exists(tx, record.TeamID, record.PackID) {
+			return store.ErrConflict
+		}
+
+		record.Team = nil
+		record.Pack = nil
+
+		return teamPackRelation.put(tx, record.TeamID, record.PackID, record)
+	})
+}
+
+// Permit implements the store.TeamPacks interface.
+func (r *teamPacks) Permit(ctx context.Context, teamPack *model.TeamPack) error {
+	return r.s.update(func(tx *bolt.Tx) error {
+		record := &model.TeamPack{}
+
+		if err := teamPackRelation.get(tx, teamPack.TeamID, teamPack.PackID, record); err != nil {
+			return err
+		}
+
+		record.Perm = teamPack.Perm
+		record.UpdatedAt = time.Now().UTC()
+
+		return teamPackRelation.put(tx, record.TeamID, record.PackID, record)
+	})
+}
+
+// Drop implements the store.TeamPacks interface.
+func (r *teamPacks) Drop(ctx context.Context, teamID, packID string) error {
+	return r.s.update(func(tx *bolt.Tx) error {
+		if !teamPackRelation.exists(tx, teamID, packID) {
+			return store.ErrNotFound
+		}
+
+		return teamPackRelation.drop(tx, teamID, packID)
+	})
+}
+
+// loadTeamPack unmarshals an assignment including both sides.
+func loadTeamPack(tx *bolt.Tx, teamID, packID string) (*model.TeamPack, error) {
+	record := &model.TeamPack{
+		Team: &model.Team{},
+		Pack: &model.Pack{},
+	}
+
+	if err := teamPackRelation.get(tx, teamID, packID, record); err != nil {
+		return nil, err
+	}
+
+	if err := teamEntity.get(tx, teamID, record.Team); err != nil {
+		return nil, err
+	}
+
+	if err := packEntity.get(tx, packID, record.Pack); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
@@ -0,0 +1,163 @@
+package boltdb
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+	bolt "go.etcd.io/bbolt"
+)
+
+type teamUsers struct {
+	s *boltdb
+}
+
+// ListByTeam implements the store.TeamUsers interface.
+func (r *teamUsers) ListByTeam(ctx context.Context, teamID string) ([]*model.TeamUser, error) {
+	records := make([]*model.TeamUser, 0)
+
+	err := r.s.view(func(tx *bolt.Tx) error {
+		if !teamEntity.exists(tx, teamID) {
+			return store.ErrNotFound
+		}
+
+		for _, userID := range teamUserRelation.rights(tx, teamID) {
+			record, err := loadTeamUser(tx, teamID, userID)
+
+			if err != nil {
+				return err
+			}
+
+			records = append(records, record)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].User.Username < records[j].User.Username
+	})
+
+	return records, nil
+}
+
+// ListByUser implements the store.TeamUsers interface.
+func (r *teamUsers) ListByUser(ctx context.Context, userID string) ([]*model.TeamUser, error) {
+	records := make([]*model.TeamUser, 0)
+
+	err := r.s.view(func(tx *bolt.Tx) error {
+		if !userEntity.exists(tx, userID) {
+			return store.ErrNotFound
+		}
+
+		for _, teamID := range teamUserRelation.lefts(tx, userID) {
+			record, err := loadTeamUser(tx, teamID, userID)
+
+			if err != nil {
+				return err
+			}
+
+			records = append(records, record)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Team.Name < records[j].Team.Name
+	})
+
+	return records, nil
+}
+
+// Append implements the store.TeamUsers interface.
+func (r *teamUsers) Append(ctx context.Context, teamUser *model.TeamUser) error {
+	record := *teamUser
+	now := time.Now().UTC()
+
+	if record.Perm == "" {
+		record.Perm = model.PermUser
+	}
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = now
+	}
+
+	return r.s.update(func(tx *bolt.Tx) error {
+		if !teamEntity.exists(tx, record.TeamID) || !userEntity.exists(tx, record.UserID) {
+			return store.ErrNotFound
+		}
+
+		if teamUserRelation.exists(tx, record.TeamID, record.UserID) {
+			return store.ErrConflict
+		}
+
+		record.Team = nil
+		record.User = nil
+
+		return teamUserRelation.put(tx, record.TeamID, record.UserID, record)
+	})
+}
+
+// Permit implements the store.TeamUsers interface.
+func (r *teamUsers) Permit(ctx context.Context, teamUser *model.TeamUser) error {
+	return r.s.update(func(tx *bolt.Tx) error {
+		record := &model.TeamUser{}
+
+		if err := teamUserRelation.get(tx, teamUser.TeamID, teamUser.UserID, record); err != nil {
+			return err
+		}
+
+		record.Perm = teamUser.Perm
+		record.UpdatedAt = time.Now().UTC()
+
+		return teamUserRelation.put(tx, record.TeamID, record.UserID, record)
+	})
+}
+
+// Drop implements the store.TeamUsers interface.
+func (r *teamUsers) Drop(ctx context.Context, teamID, userID string) error {
+	return r.s.update(func(tx *bolt.Tx) error {
+		if !teamUserRelation.exists(tx, teamID, userID) {
+			return store.ErrNotFound
+		}
+
+		return teamUserRelation.drop(tx, teamID, userID)
+	})
+}
+
+// loadTeamUser unmarshals an assignment including both sides.
+func loadTeamUser(tx *bolt.Tx, teamID, userID string) (*model.TeamUser, error) {
+	record := &model.TeamUser{
+		Team: &model.Team{},
+		User: &model.User{},
+	}
+
+	if err := teamUserRelation.get(tx, teamID, userID, record); err != nil {
+		return nil, err
+	}
+
+	if err := teamEntity.get(tx, teamID, record.Team); err != nil {
+		return nil, err
+	}
+
+	if err := userEntity.get(tx, userID, record.User); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
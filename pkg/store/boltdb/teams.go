@@ -0,0 +1,168 @@
+package boltdb
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+	bolt "go.etcd.io/bbolt"
+)
+
+type teams struct {
+	s *boltdb
+}
+
+// List implements the store.Teams interface.
+func (r *teams) List(ctx context.Context) ([]*model.Team, error) {
+	records := make([]*model.Team, 0)
+
+	err := r.s.view(func(tx *bolt.Tx) error {
+		return teamEntity.each(tx, func(val []byte) error {
+			record := &model.Team{}
+
+			if err := json.Unmarshal(val, record); err != nil {
+				return err
+			}
+
+			records = append(records, record)
+			return nil
+		})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Name < records[j].Name
+	})
+
+	return records, nil
+}
+
+// Show implements the store.Teams interface.
+func (r *teams) Show(ctx context.Context, id string) (*model.Team, error) {
+	record := &model.Team{}
+
+	err := r.s.view(func(tx *bolt.Tx) error {
+		return findTeam(tx, id, record)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// Create implements the store.Teams interface.
+func (r *teams) Create(ctx context.Context, team *model.Team) (*model.Team, error) {
+	record := *team
+	now := time.Now().UTC()
+
+	if record.ID == "" {
+		record.ID = uuid.New().String()
+	}
+
+	record.Slug = store.SlugOrDefault(record.Slug, record.Name, record.ID)
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = now
+	}
+
+	err := r.s.update(func(tx *bolt.Tx) error {
+		if teamEntity.exists(tx, record.ID) {
+			return store.ErrConflict
+		}
+
+		if err := teamEntity.claim(tx, "", record.ID, "", record.Slug); err != nil {
+			return err
+		}
+
+		return teamEntity.put(tx, record.ID, record)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Update implements the store.Teams interface.
+func (r *teams) Update(ctx context.Context, team *model.Team) (*model.Team, error) {
+	record := *team
+
+	err := r.s.update(func(tx *bolt.Tx) error {
+		current := &model.Team{}
+
+		if err := teamEntity.get(tx, record.ID, current); err != nil {
+			return err
+		}
+
+		record.Slug = store.SlugOrDefault(record.Slug, record.Name, current.Slug)
+		record.CreatedAt = current.CreatedAt
+		record.UpdatedAt = time.Now().UTC()
+
+		if err := teamEntity.claim(tx, "", record.ID, current.Slug, record.Slug); err != nil {
+			return err
+		}
+
+		return teamEntity.put(tx, record.ID, record)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Delete implements the store.Teams interface.
+func (r *teams) Delete(ctx context.Context, id string) error {
+	return r.s.update(func(tx *bolt.Tx) error {
+		record := &model.Team{}
+
+		if err := findTeam(tx, id, record); err != nil {
+			return err
+		}
+
+		return deleteTeam(tx, record)
+	})
+}
+
+// findTeam resolves and unmarshals a team by ID or slug.
+func findTeam(tx *bolt.Tx, id string, record *model.Team) error {
+	resolved, err := teamEntity.resolve(tx, "", id)
+
+	if err != nil {
+		return err
+	}
+
+	return teamEntity.get(tx, resolved, record)
+}
+
+// deleteTeam removes a team including all of its assignments.
+func deleteTeam(tx *bolt.Tx, record *model.Team) error {
+	if err := teamUserRelation.dropLeft(tx, record.ID); err != nil {
+		return err
+	}
+
+	if err := teamPackRelation.dropLeft(tx, record.ID); err != nil {
+		return err
+	}
+
+	if err := teamModRelation.dropLeft(tx, record.ID); err != nil {
+		return err
+	}
+
+	return teamEntity.remove(tx, "", record.ID, record.Slug)
+}
@@ -0,0 +1,163 @@
+package boltdb
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+	bolt "go.etcd.io/bbolt"
+)
+
+type userMods struct {
+	s *boltdb
+}
+
+// ListByUser implements the store.UserMods interface.
+func (r *userMods) ListByUser(ctx context.Context, userID string) ([]*model.UserMod, error) {
+	records := make([]*model.UserMod, 0)
+
+	err := r.s.view(func(tx *bolt.Tx) error {
+		if !userEntity.exists(tx, userID) {
+			return store.ErrNotFound
+		}
+
+		for _, modID := range userModRelation.rights(tx, userID) {
+			record, err := loadUserMod(tx, userID, modID)
+
+			if err != nil {
+				return err
+			}
+
+			records = append(records, record)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Mod.Name < records[j].Mod.Name
+	})
+
+	return records, nil
+}
+
+// ListByMod implements the store.UserMods interface.
+func (r *userMods) ListByMod(ctx context.Context, modID string) ([]*model.UserMod, error) {
+	records := make([]*model.UserMod, 0)
+
+	err := r.s.view(func(tx *bolt.Tx) error {
+		if !modEntity.exists(tx, modID) {
+			return store.ErrNotFound
+		}
+
+		for _, userID := range userModRelation.lefts(tx, modID) {
+			record, err := loadUserMod(tx, userID, modID)
+
+			if err != nil {
+				return err
+			}
+
+			records = append(records, record)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].User.Username < records[j].User.Username
+	})
+
+	return records, nil
+}
+
+// Append implements the store.UserMods interface.
+func (r *userMods) Append(ctx context.Context, userMod *model.UserMod) error {
+	record := *userMod
+	now := time.Now().UTC()
+
+	if record.Perm == "" {
+		record.Perm = model.PermUser
+	}
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = now
+	}
+
+	return r.s.update(func(tx *bolt.Tx) error {
+		if !userEntity.exists(tx, record.UserID) || !modEntity.exists(tx, record.ModID) {
+			return store.ErrNotFound
+		}
+
+		if userModRelation.exists(tx, record.UserID, record.ModID) {
+			return store.ErrConflict
+		}
+
+		record.User = nil
+		record.Mod = nil
+
+		return userModRelation.put(tx, record.UserID, record.ModID, record)
+	})
+}
+
+// Permit implements the store.UserMods interface.
+func (r *userMods) Permit(ctx context.Context, userMod *model.UserMod) error {
+	return r.s.update(func(tx *bolt.Tx) error {
+		record := &model.UserMod{}
+
+		if err := userModRelation.get(tx, userMod.UserID, userMod.ModID, record); err != nil {
+			return err
+		}
+
+		record.Perm = userMod.Perm
+		record.UpdatedAt = time.Now().UTC()
+
+		return userModRelation.put(tx, record.UserID, record.ModID, record)
+	})
+}
+
+// Drop implements the store.UserMods interface.
+func (r *userMods) Drop(ctx context.Context, userID, modID string) error {
+	return r.s.update(func(tx *bolt.Tx) error {
+		if !userModRelation.exists(tx, userID, modID) {
+			return store.ErrNotFound
+		}
+
+		return userModRelation.drop(tx, userID, modID)
+	})
+}
+
+// loadUserMod unmarshals an assignment including both sides.
+func loadUserMod(tx *bolt.Tx, userID, modID string) (*model.UserMod, error) {
+	record := &model.UserMod{
+		User: &model.User{},
+		Mod:  &model.Mod{},
+	}
+
+	if err := userModRelation.get(tx, userID, modID, record); err != nil {
+		return nil, err
+	}
+
+	if err := userEntity.get(tx, userID, record.User); err != nil {
+		return nil, err
+	}
+
+	if err := modEntity.get(tx, modID, record.Mod); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
@@ -0,0 +1,163 @@
+package boltdb
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+	bolt "go.etcd.io/bbolt"
+)
+
+type userPacks struct {
+	s *boltdb
+}
+
+// ListByUser implements the store.UserPacks interface.
+func (r *userPacks) ListByUser(ctx context.Context, userID string) ([]*model.UserPack, error) {
+	records := make([]*model.UserPack, 0)
+
+	err := r.s.view(func(tx *bolt.Tx) error {
+		if !userEntity.exists(tx, userID) {
+			return store.ErrNotFound
+		}
+
+		for _, packID := range userPackRelation.rights(tx, userID) {
+			record, err := loadUserPack(tx, userID, packID)
+
+			if err != nil {
+				return err
+			}
+
+			records = append(records, record)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Pack.Name < records[j].Pack.Name
+	})
+
+	return records, nil
+}
+
+// ListByPack implements the store.UserPacks interface.
+func (r *userPacks) ListByPack(ctx context.Context, packID string) ([]*model.UserPack, error) {
+	records := make([]*model.UserPack, 0)
+
+	err := r.s.view(func(tx *bolt.Tx) error {
+		if !packEntity.exists(tx, packID) {
+			return store.ErrNotFound
+		}
+
+		for _, userID := range userPackRelation.lefts(tx, packID) {
+			record, err := loadUserPack(tx, userID, packID)
+
+			if err != nil {
+				return err
+			}
+
+			records = append(records, record)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].User.Username < records[j].User.Username
+	})
+
+	return records, nil
+}
+
+// Append implements the store.UserPacks interface.
+func (r *userPacks) Append(ctx context.Context, userPack *model.UserPack) error {
+	record := *userPack
+	now := time.Now().UTC()
+
+	if record.Perm == "" {
+		record.Perm = model.PermUser
+	}
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = now
+	}
+
+	return r.s.update(func(tx *bolt.Tx) error {
+		if !userEntity.exists(tx, record.UserID) || !packEntity.exists(tx, record.PackID) {
+			return store.ErrNotFound
+		}
+
+		if userPackRelation.exists(tx, record.UserID, record.PackID) {
+			return store.ErrConflict
+		}
+
+		record.User = nil
+		record.Pack = nil
+
+		return userPackRelation.put(tx, record.UserID, record.PackID, record)
+	})
+}
+
+// Permit implements the store.UserPacks interface.
+func (r *userPacks) Permit(ctx context.Context, userPack *model.UserPack) error {
+	return r.s.update(func(tx *bolt.Tx) error {
+		record := &model.UserPack{}
+
+		if err := userPackRelation.get(tx, userPack.UserID, userPack.PackID, record); err != nil {
+			return err
+		}
+
+		record.Perm = userPack.Perm
+		record.UpdatedAt = time.Now().UTC()
+
+		return userPackRelation.put(tx, record.UserID, record.PackID, record)
+	})
+}
+
+// Drop implements the store.UserPacks interface.
+func (r *userPacks) Drop(ctx context.Context, userID, packID string) error {
+	return r.s.update(func(tx *bolt.Tx) error {
+		if !userPackRelation.exists(tx, userID, packID) {
+			return store.ErrNotFound
+		}
+
+		return userPackRelation.drop(tx, userID, packID)
+	})
+}
+
+// loadUserPack unmarshals an assignment including both sides.
+func loadUserPack(tx *bolt.Tx, userID, packID string) (*model.UserPack, error) {
+	record := &model.UserPack{
+		User: &model.User{},
+		Pack: &model.Pack{},
+	}
+
+	if err := userPackRelation.get(tx, userID, packID, record); err != nil {
+		return nil, err
+	}
+
+	if err := userEntity.get(tx, userID, record.User); err != nil {
+		return nil, err
+	}
+
+	if err := packEntity.get(tx, packID, record.Pack); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
@@ -0,0 +1,168 @@
+package boltdb
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+	bolt "go.etcd.io/bbolt"
+)
+
+type users struct {
+	s *boltdb
+}
+
+// List implements the store.Users interface.
+func (r *users) List(ctx context.Context) ([]*model.User, error) {
+	records := make([]*model.User, 0)
+
+	err := r.s.view(func(tx *bolt.Tx) error {
+		return userEntity.each(tx, func(val []byte) error {
+			record := &model.User{}
+
+			if err := json.Unmarshal(val, record); err != nil {
+				return err
+			}
+
+			records = append(records, record)
+			return nil
+		})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Username < records[j].Username
+	})
+
+	return records, nil
+}
+
+// Show implements the store.Users interface.
+func (r *users) Show(ctx context.Context, id string) (*model.User, error) {
+	record := &model.User{}
+
+	err := r.s.view(func(tx *bolt.Tx) error {
+		return findUser(tx, id, record)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// Create implements the store.Users interface.
+func (r *users) Create(ctx context.Context, user *model.User) (*model.User, error) {
+	record := *user
+	now := time.Now().UTC()
+
+	if record.ID == "" {
+		record.ID = uuid.New().String()
+	}
+
+	record.Slug = store.SlugOrDefault(record.Slug, record.Username, record.ID)
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = now
+	}
+
+	err := r.s.update(func(tx *bolt.Tx) error {
+		if userEntity.exists(tx, record.ID) {
+			return store.ErrConflict
+		}
+
+		if err := userEntity.claim(tx, "", record.ID, "", record.Slug); err != nil {
+			return err
+		}
+
+		return userEntity.put(tx, record.ID, record)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Update implements the store.Users interface.
+func (r *users) Update(ctx context.Context, user *model.User) (*model.User, error) {
+	record := *user
+
+	err := r.s.update(func(tx *bolt.Tx) error {
+		current := &model.User{}
+
+		if err := userEntity.get(tx, record.ID, current); err != nil {
+			return err
+		}
+
+		record.Slug = store.SlugOrDefault(record.Slug, record.Username, current.Slug)
+		record.CreatedAt = current.CreatedAt
+		record.UpdatedAt = time.Now().UTC()
+
+		if err := userEntity.claim(tx, "", record.ID, current.Slug, record.Slug); err != nil {
+			return err
+		}
+
+		return userEntity.put(tx, record.ID, record)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Delete implements the store.Users interface.
+func (r *users) Delete(ctx context.Context, id string) error {
+	return r.s.update(func(tx *bolt.Tx) error {
+		record := &model.User{}
+
+		if err := findUser(tx, id, record); err != nil {
+			return err
+		}
+
+		return deleteUser(tx, record)
+	})
+}
+
+// findUser resolves and unmarshals a user by ID or slug.
+func findUser(tx *bolt.Tx, id string, record *model.User) error {
+	resolved, err := userEntity.resolve(tx, "", id)
+
+	if err != nil {
+		return err
+	}
+
+	return userEntity.get(tx, resolved, record)
+}
+
+// deleteUser removes a user including all of its assignments.
+func deleteUser(tx *bolt.Tx, record *model.User) error {
+	if err := teamUserRelation.dropRight(tx, record.ID); err != nil {
+		return err
+	}
+
+	if err := userPackRelation.dropLeft(tx, record.ID); err != nil {
+		return err
+	}
+
+	if err := userModRelation.dropLeft(tx, record.ID); err != nil {
+		return err
+	}
+
+	return userEntity.remove(tx, "", record.ID, record.Slug)
+}
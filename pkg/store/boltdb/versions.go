@@ -0,0 +1,224 @@
+package boltdb
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+	bolt "go.etcd.io/bbolt"
+)
+
+type versions struct {
+	s *boltdb
+}
+
+// List implements the store.Versions interface.
+func (r *versions) List(ctx context.Context, modID string) ([]*model.Version, error) {
+	records := make([]*model.Version, 0)
+
+	err := r.s.view(func(tx *bolt.Tx) error {
+		if !modEntity.exists(tx, modID) {
+			return store.ErrNotFound
+		}
+
+		for _, id := range modVersions.children(tx, modID) {
+			record := &model.Version{}
+
+			if err := versionEntity.get(tx, id, record); err != nil {
+				return err
+			}
+
+			records = append(records, record)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Name < records[j].Name
+	})
+
+	return records, nil
+}
+
+// ListByFile implements the store.Versions interface.
+func (r *versions) ListByFile(ctx context.Context, key string) ([]*model.Version, error) {
+	records := make([]*model.Version, 0)
+
+	if key == "" {
+		return records, nil
+	}
+
+	err := r.s.view(func(tx *bolt.Tx) error {
+		return versionEntity.each(tx, func(val []byte) error {
+			record := &model.Version{}
+
+			if err := json.Unmarshal(val, record); err != nil {
+				return err
+			}
+
+			if record.FileKey == key {
+				records = append(records, record)
+			}
+
+			return nil
+		})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Name < records[j].Name
+	})
+
+	return records, nil
+}
+
+// Show implements the store.Versions interface.
+func (r *versions) Show(ctx context.Context, modID, id string) (*model.Version, error) {
+	record := &model.Version{}
+
+	err := r.s.view(func(tx *bolt.Tx) error {
+		return findVersion(tx, modID, id, record)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// Create implements the store.Versions interface.
+func (r *versions) Create(ctx context.Context, version *model.Version) (*model.Version, error) {
+	record := *version
+	now := time.Now().UTC()
+
+	if record.ID == "" {
+		record.ID = uuid.New().String()
+	}
+
+	record.Slug = store.SlugOrDefault(record.Slug, record.Name, record.ID)
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = now
+	}
+
+	err := r.s.update(func(tx *bolt.Tx) error {
+		if versionEntity.exists(tx, record.ID) {
+			return store.ErrConflict
+		}
+
+		if !modEntity.exists(tx, record.ModID) {
+			return store.ErrNotFound
+		}
+
+		if err := versionEntity.claim(tx, record.ModID, record.ID, "", record.Slug); err != nil {
+			return err
+		}
+
+		if err := modVersions.add(tx, record.ModID, record.ID); err != nil {
+			return err
+		}
+
+		return versionEntity.put(tx, record.ID, record)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Update implements the store.Versions interface.
+func (r *versions) Update(ctx context.Context, version *model.Version) (*model.Version, error) {
+	record := *version
+
+	err := r.s.update(func(tx *bolt.Tx) error {
+		current := &model.Version{}
+
+		if err := versionEntity.get(tx, record.ID, current); err != nil {
+			return err
+		}
+
+		if current.ModID != record.ModID {
+			return store.ErrNotFound
+		}
+
+		record.Slug = store.SlugOrDefault(record.Slug, record.Name, current.Slug)
+		record.CreatedAt = current.CreatedAt
+		record.UpdatedAt = time.Now().UTC()
+
+		if err := versionEntity.claim(tx, record.ModID, record.ID, current.Slug, record.Slug); err != nil {
+			return err
+		}
+
+		return versionEntity.put(tx, record.ID, record)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Delete implements the store.Versions interface.
+func (r *versions) Delete(ctx context.Context, modID, id string) error {
+	return r.s.update(func(tx *bolt.Tx) error {
+		record := &model.Version{}
+
+		if err := findVersion(tx, modID, id, record); err != nil {
+			return err
+		}
+
+		return deleteVersion(tx, record)
+	})
+}
+
+// findVersion resolves and unmarshals a version of a mod by ID or slug.
+func findVersion(tx *bolt.Tx, modID, id string, record *model.Version) error {
+	resolved, err := versionEntity.resolve(tx, modID, id)
+
+	if err != nil {
+		return err
+	}
+
+	if err := versionEntity.get(tx, resolved, record); err != nil {
+		return err
+	}
+
+	if record.ModID != modID {
+		return store.ErrNotFound
+	}
+
+	return nil
+}
+
+// deleteVersion removes a version including its build assignments.
+func deleteVersion(tx *bolt.Tx, record *model.Version) error {
+	if err := buildVersionRelation.dropRight(tx, record.ID); err != nil {
+		return err
+	}
+
+	if err := modVersions.remove(tx, record.ModID, record.ID); err != nil {
+		return err
+	}
+
+	return versionEntity.remove(tx, record.ModID, record.ID, record.Slug)
+}
@@ -0,0 +1,22 @@
+package store
+
+import (
+	"context"
+
+	"github.com/kleister/kleister-api/pkg/model"
+)
+
+// BuildVersions provides the repository interface for versions assigned to builds.
+type BuildVersions interface {
+	// ListByBuild returns all assignments of a build ordered by version name.
+	ListByBuild(context.Context, string) ([]*model.BuildVersion, error)
+
+	// ListByVersion returns all assignments of a version ordered by build name.
+	ListByVersion(context.Context, string) ([]*model.BuildVersion, error)
+
+	// Append assigns a version to a build, fails with ErrConflict if assigned.
+	Append(context.Context, *model.BuildVersion) error
+
+	// Drop removes the assignment between a build and a version.
+	Drop(context.Context, string, string) error
+}
@@ -0,0 +1,25 @@
+package store
+
+import (
+	"context"
+
+	"github.com/kleister/kleister-api/pkg/model"
+)
+
+// Builds provides the repository interface for builds of a pack.
+type Builds interface {
+	// List returns all builds of the pack ordered by name.
+	List(context.Context, string) ([]*model.Build, error)
+
+	// Show returns a single build of the pack by its ID or slug.
+	Show(context.Context, string, string) (*model.Build, error)
+
+	// Create persists a new build, an ID and slug get generated if empty.
+	Create(context.Context, *model.Build) (*model.Build, error)
+
+	// Update persists the changes of an existing build.
+	Update(context.Context, *model.Build) (*model.Build, error)
+
+	// Delete removes a build of the pack by its ID or slug.
+	Delete(context.Context, string, string) error
+}
@@ -0,0 +1,701 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Counts maps the names of entities and relations to the number of records.
+type Counts map[string]int
+
+// Diff lists the names of all entities and relations with differing counts.
+func (c Counts) Diff(other Counts) []string {
+	result := make([]string, 0)
+
+	for name, count := range c {
+		if other[name] != count {
+			result = append(result, name)
+		}
+	}
+
+	for name, count := range other {
+		if _, ok := c[name]; !ok && count != 0 {
+			result = append(result, name)
+		}
+	}
+
+	sort.Strings(result)
+	return result
+}
+
+// Progress gets called after every entity or relation has been copied.
+type Progress func(name string, copied, skipped int)
+
+// copier bundles the repositories and the progress reporting of a copy.
+type copier struct {
+	src      Repositories
+	dst      Repositories
+	progress Progress
+}
+
+// Copy transfers all records from src to dst while keeping the IDs, slugs
+// and timestamps. Records which already exist within dst get skipped, this
+// way an interrupted copy can simply be executed again to resume it. All
+// records get read from a consistent snapshot of src, the returned counts
+// of this snapshot can be used to verify the copy.
+func Copy(ctx context.Context, src Store, dst Repositories, progress Progress) (Counts, error) {
+	var (
+		counts Counts
+	)
+
+	err := src.Snapshot(ctx, func(tx Repositories) error {
+		c := &copier{
+			src:      tx,
+			dst:      dst,
+			progress: progress,
+		}
+
+		if err := c.copy(ctx); err != nil {
+			return err
+		}
+
+		var err error
+		counts, err = Count(ctx, tx)
+
+		return errors.Wrap(err, "failed to count source records")
+	})
+
+	return counts, err
+}
+
+// copy transfers the entities and relations in the order of their
+// dependencies.
+func (c *copier) copy(ctx context.Context) error {
+	for _, step := range []struct {
+		name string
+		fn   func(context.Context) (int, int, error)
+	}{
+		{"users", c.users},
+		{"teams", c.teams},
+		{"minecrafts", c.minecrafts},
+		{"forges", c.forges},
+		{"mods", c.mods},
+		{"versions", c.versions},
+		{"packs", c.packs},
+		{"builds", c.builds},
+		{"team_users", c.teamUsers},
+		{"team_packs", c.teamPacks},
+		{"team_mods", c.teamMods},
+		{"user_packs", c.userPacks},
+		{"user_mods", c.userMods},
+		{"build_versions", c.buildVersions},
+		{"blobs", c.blobs},
+		{"links", c.links},
+	} {
+		copied, skipped, err := step.fn(ctx)
+
+		if err != nil {
+			return errors.Wrapf(err, "failed to copy %s", strings.Replace(step.name, "_", " ", -1))
+		}
+
+		if c.progress != nil {
+			c.progress(step.name, copied, skipped)
+		}
+	}
+
+	return nil
+}
+
+// Count counts the records of all entities and relations.
+func Count(ctx context.Context, s Repositories) (Counts, error) {
+	d, err := Export(ctx, s)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return d.Counts(), nil
+}
+
+// skip checks the result of a lookup within the destination, any other
+// error than ErrNotFound aborts the copy.
+func skip(err error) (bool, error) {
+	switch err {
+	case nil:
+		return true, nil
+	case ErrNotFound:
+		return false, nil
+	}
+
+	return false, err
+}
+
+// appended checks the result of an assignment, a conflict means that the
+// assignment has already been copied before.
+func appended(err error) (bool, error) {
+	switch err {
+	case nil:
+		return true, nil
+	case ErrConflict:
+		return false, nil
+	}
+
+	return false, err
+}
+
+func (c *copier) users(ctx context.Context) (int, int, error) {
+	records, err := c.src.Users().List(ctx)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	copied := 0
+
+	for _, record := range records {
+		_, err := c.dst.Users().Show(ctx, record.ID)
+		exists, err := skip(err)
+
+		if err != nil {
+			return copied, 0, err
+		}
+
+		if exists {
+			continue
+		}
+
+		if _, err := c.dst.Users().Create(ctx, record); err != nil {
+			return copied, 0, errors.Wrapf(err, "user %s", record.ID)
+		}
+
+		copied++
+	}
+
+	return copied, len(records) - copied, nil
+}
+
+func (c *copier) teams(ctx context.Context) (int, int, error) {
+	records, err := c.src.Teams().List(ctx)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	copied := 0
+
+	for _, record := range records {
+		_, err := c.dst.Teams().Show(ctx, record.ID)
+		exists, err := skip(err)
+
+		if err != nil {
+			return copied, 0, err
+		}
+
+		if exists {
+			continue
+		}
+
+		if _, err := c.dst.Teams().Create(ctx, record); err != nil {
+			return copied, 0, errors.Wrapf(err, "team %s", record.ID)
+		}
+
+		copied++
+	}
+
+	return copied, len(records) - copied, nil
+}
+
+func (c *copier) minecrafts(ctx context.Context) (int, int, error) {
+	records, err := c.src.Minecrafts().List(ctx)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	copied := 0
+
+	for _, record := range records {
+		_, err := c.dst.Minecrafts().Show(ctx, record.ID)
+		exists, err := skip(err)
+
+		if err != nil {
+			return copied, 0, err
+		}
+
+		if exists {
+			continue
+		}
+
+		if _, err := c.dst.Minecrafts().Create(ctx, record); err != nil {
+			return copied, 0, errors.Wrapf(err, "minecraft %s", record.ID)
+		}
+
+		copied++
+	}
+
+	return copied, len(records) - copied, nil
+}
+
+func (c *copier) forges(ctx context.Context) (int, int, error) {
+	records, err := c.src.Forges().List(ctx)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	copied := 0
+
+	for _, record := range records {
+		_, err := c.dst.Forges().Show(ctx, record.ID)
+		exists, err := skip(err)
+
+		if err != nil {
+			return copied, 0, err
+		}
+
+		if exists {
+			continue
+		}
+
+		if _, err := c.dst.Forges().Create(ctx, record); err != nil {
+			return copied, 0, errors.Wrapf(err, "forge %s", record.ID)
+		}
+
+		copied++
+	}
+
+	return copied, len(records) - copied, nil
+}
+
+func (c *copier) mods(ctx context.Context) (int, int, error) {
+	records, err := c.src.Mods().List(ctx)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	copied := 0
+
+	for _, record := range records {
+		_, err := c.dst.Mods().Show(ctx, record.ID)
+		exists, err := skip(err)
+
+		if err != nil {
+			return copied, 0, err
+		}
+
+		if exists {
+			continue
+		}
+
+		if _, err := c.dst.Mods().Create(ctx, record); err != nil {
+			return copied, 0, errors.Wrapf(err, "mod %s", record.ID)
+		}
+
+		copied++
+	}
+
+	return copied, len(records) - copied, nil
+}
+
+func (c *copier) versions(ctx context.Context) (int, int, error) {
+	mods, err := c.src.Mods().List(ctx)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	copied, total := 0, 0
+
+	for _, mod := range mods {
+		records, err := c.src.Versions().List(ctx, mod.ID)
+
+		if err != nil {
+			return copied, 0, err
+		}
+
+		total += len(records)
+
+		for _, record := range records {
+			_, err := c.dst.Versions().Show(ctx, mod.ID, record.ID)
+			exists, err := skip(err)
+
+			if err != nil {
+				return copied, 0, err
+			}
+
+			if exists {
+				continue
+			}
+
+			if _, err := c.dst.Versions().Create(ctx, record); err != nil {
+				return copied, 0, errors.Wrapf(err, "version %s", record.ID)
+			}
+
+			copied++
+		}
+	}
+
+	return copied, total - copied, nil
+}
+
+// packs copies the packs without the recommended and latest builds, they
+// get assigned by builds as they have to be copied before.
+func (c *copier) packs(ctx context.Context) (int, int, error) {
+	records, err := c.src.Packs().List(ctx)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	copied := 0
+
+	for _, record := range records {
+		_, err := c.dst.Packs().Show(ctx, record.ID)
+		exists, err := skip(err)
+
+		if err != nil {
+			return copied, 0, err
+		}
+
+		if exists {
+			continue
+		}
+
+		pack := *record
+		pack.RecommendedID = ""
+		pack.LatestID = ""
+
+		if _, err := c.dst.Packs().Create(ctx, &pack); err != nil {
+			return copied, 0, errors.Wrapf(err, "pack %s", record.ID)
+		}
+
+		copied++
+	}
+
+	return copied, len(records) - copied, nil
+}
+
+func (c *copier) builds(ctx context.Context) (int, int, error) {
+	packs, err := c.src.Packs().List(ctx)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	copied, total := 0, 0
+
+	for _, pack := range packs {
+		records, err := c.src.Builds().List(ctx, pack.ID)
+
+		if err != nil {
+			return copied, 0, err
+		}
+
+		total += len(records)
+
+		for _, record := range records {
+			_, err := c.dst.Builds().Show(ctx, pack.ID, record.ID)
+			exists, err := skip(err)
+
+			if err != nil {
+				return copied, 0, err
+			}
+
+			if exists {
+				continue
+			}
+
+			if _, err := c.dst.Builds().Create(ctx, record); err != nil {
+				return copied, 0, errors.Wrapf(err, "build %s", record.ID)
+			}
+
+			copied++
+		}
+
+		if pack.RecommendedID != "" || pack.LatestID != "" {
+			if err := c.dst.Packs().Assign(ctx, pack); err != nil {
+				return copied, 0, errors.Wrapf(err, "pack %s", pack.ID)
+			}
+		}
+	}
+
+	return copied, total - copied, nil
+}
+
+func (c *copier) teamUsers(ctx context.Context) (int, int, error) {
+	teams, err := c.src.Teams().List(ctx)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	copied, total := 0, 0
+
+	for _, team := range teams {
+		records, err := c.src.TeamUsers().ListByTeam(ctx, team.ID)
+
+		if err != nil {
+			return copied, 0, err
+		}
+
+		total += len(records)
+
+		for _, record := range records {
+			ok, err := appended(c.dst.TeamUsers().Append(ctx, record))
+
+			if err != nil {
+				return copied, 0, errors.Wrapf(err, "user %s of team %s", record.UserID, record.TeamID)
+			}
+
+			if ok {
+				copied++
+			}
+		}
+	}
+
+	return copied, total - copied, nil
+}
+
+func (c *copier) teamPacks(ctx context.Context) (int, int, error) {
+	teams, err := c.src.Teams().List(ctx)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	copied, total := 0, 0
+
+	for _, team := range teams {
+		records, err := c.src.TeamPacks().ListByTeam(ctx, team.ID)
+
+		if err != nil {
+			return copied, 0, err
+		}
+
+		total += len(records)
+
+		for _, record := range records {
+			ok, err := appended(c.dst.TeamPacks().Append(ctx, record))
+
+			if err != nil {
+				return copied, 0, errors.Wrapf(err, "pack %s of team %s", record.PackID, record.TeamID)
+			}
+
+			if ok {
+				copied++
+			}
+		}
+	}
+
+	return copied, total - copied, nil
+}
+
+func (c *copier) teamMods(ctx context.Context) (int, int, error) {
+	teams, err := c.src.Teams().List(ctx)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	copied, total := 0, 0
+
+	for _, team := range teams {
+		records, err := c.src.TeamMods().ListByTeam(ctx, team.ID)
+
+		if err != nil {
+			return copied, 0, err
+		}
+
+		total += len(records)
+
+		for _, record := range records {
+			ok, err := appended(c.dst.TeamMods().Append(ctx, record))
+
+			if err != nil {
+				return copied, 0, errors.Wrapf(err, "mod %s of team %s", record.ModID, record.TeamID)
+			}
+
+			if ok {
+				copied++
+			}
+		}
+	}
+
+	return copied, total - copied, nil
+}
+
+func (c *copier) userPacks(ctx context.Context) (int, int, error) {
+	users, err := c.src.Users().List(ctx)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	copied, total := 0, 0
+
+	for _, user := range users {
+		records, err := c.src.UserPacks().ListByUser(ctx, user.ID)
+
+		if err != nil {
+			return copied, 0, err
+		}
+
+		total += len(records)
+
+		for _, record := range records {
+			ok, err := appended(c.dst.UserPacks().Append(ctx, record))
+
+			if err != nil {
+				return copied, 0, errors.Wrapf(err, "pack %s of user %s", record.PackID, record.UserID)
+			}
+
+			if ok {
+				copied++
+			}
+		}
+	}
+
+	return copied, total - copied, nil
+}
+
+func (c *copier) userMods(ctx context.Context) (int, int, error) {
+	users, err := c.src.Users().List(ctx)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	copied, total := 0, 0
+
+	for _, user := range users {
+		records, err := c.src.UserMods().ListByUser(ctx, user.ID)
+
+		if err != nil {
+			return copied, 0, err
+		}
+
+		total += len(records)
+
+		for _, record := range records {
+			ok, err := appended(c.dst.UserMods().Append(ctx, record))
+
+			if err != nil {
+				return copied, 0, errors.Wrapf(err, "mod %s of user %s", record.ModID, record.UserID)
+			}
+
+			if ok {
+				copied++
+			}
+		}
+	}
+
+	return copied, total - copied, nil
+}
+
+func (c *copier) buildVersions(ctx context.Context) (int, int, error) {
+	packs, err := c.src.Packs().List(ctx)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	copied, total := 0, 0
+
+	for _, pack := range packs {
+		builds, err := c.src.Builds().List(ctx, pack.ID)
+
+		if err != nil {
+			return copied, 0, err
+		}
+
+		for _, build := range builds {
+			records, err := c.src.BuildVersions().ListByBuild(ctx, build.ID)
+
+			if err != nil {
+				return copied, 0, err
+			}
+
+			total += len(records)
+
+			for _, record := range records {
+				ok, err := appended(c.dst.BuildVersions().Append(ctx, record))
+
+				if err != nil {
+					return copied, 0, errors.Wrapf(err, "version %s of build %s", record.VersionID, record.BuildID)
+				}
+
+				if ok {
+					copied++
+				}
+			}
+		}
+	}
+
+	return copied, total - copied, nil
+}
+
+func (c *copier) blobs(ctx context.Context) (int, int, error) {
+	records, err := c.src.Blobs().List(ctx)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	copied := 0
+
+	for _, record := range records {
+		_, err := c.dst.Blobs().Show(ctx, record.Digest)
+		exists, err := skip(err)
+
+		if err != nil {
+			return copied, 0, err
+		}
+
+		if exists {
+			continue
+		}
+
+		if _, err := c.dst.Blobs().Create(ctx, record); err != nil {
+			return copied, 0, errors.Wrapf(err, "blob %s", record.Digest)
+		}
+
+		copied++
+	}
+
+	return copied, len(records) - copied, nil
+}
+
+func (c *copier) links(ctx context.Context) (int, int, error) {
+	records, err := c.src.Links().List(ctx)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	copied := 0
+
+	for _, record := range records {
+		_, err := c.dst.Links().Show(ctx, record.Path)
+		exists, err := skip(err)
+
+		if err != nil {
+			return copied, 0, err
+		}
+
+		if exists {
+			continue
+		}
+
+		if _, err := c.dst.Links().Create(ctx, record); err != nil {
+			return copied, 0, errors.Wrapf(err, "link %s", record.Path)
+		}
+
+		copied++
+	}
+
+	return copied, len(records) - copied, nil
+}
@@ -0,0 +1,269 @@
+package store_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+	"github.com/kleister/kleister-api/pkg/store/memory"
+)
+
+func TestCopy(t *testing.T) {
+	ctx := context.Background()
+	src := seed(t)
+	dst := memory.Must(&url.URL{Scheme: "memory"})
+
+	counts, err := store.Copy(ctx, src, dst, nil)
+	must(t, err)
+
+	compare(t, src, dst)
+
+	expected, err := store.Count(ctx, src)
+	must(t, err)
+
+	if diff := expected.Diff(counts); len(diff) > 0 {
+		t.Errorf("expected counts of the source to be returned, got differences for %v", diff)
+	}
+
+	mods, err := src.Mods().List(ctx)
+	must(t, err)
+
+	for _, mod := range mods {
+		copied, err := dst.Mods().Show(ctx, mod.ID)
+		must(t, err)
+
+		if copied.Slug != mod.Slug || !copied.CreatedAt.Equal(mod.CreatedAt) || !copied.UpdatedAt.Equal(mod.UpdatedAt) {
+			t.Errorf("expected mod %s to keep slug and timestamps", mod.Slug)
+		}
+
+		versions, err := src.Versions().List(ctx, mod.ID)
+		must(t, err)
+
+		for _, version := range versions {
+			copied, err := dst.Versions().Show(ctx, mod.ID, version.ID)
+			must(t, err)
+
+			if copied.Slug != version.Slug || !copied.CreatedAt.Equal(version.CreatedAt) || copied.FileKey != version.FileKey {
+				t.Errorf("expected version %s to keep slug, timestamps and file", version.Slug)
+			}
+		}
+	}
+
+	pack, err := src.Packs().Show(ctx, "hexxit")
+	must(t, err)
+
+	copied, err := dst.Packs().Show(ctx, pack.ID)
+	must(t, err)
+
+	if copied.Slug != pack.Slug || !copied.CreatedAt.Equal(pack.CreatedAt) || !copied.UpdatedAt.Equal(pack.UpdatedAt) {
+		t.Errorf("expected pack to keep slug and timestamps")
+	}
+
+	if copied.RecommendedID != pack.RecommendedID || copied.LatestID != pack.LatestID || copied.RecommendedID == copied.LatestID {
+		t.Errorf("expected recommended and latest builds to be kept, got %q and %q", copied.RecommendedID, copied.LatestID)
+	}
+
+	blob, err := dst.Blobs().Show(ctx, "ca978112ca1bbdcafac231b39a23dc4da786eff8147c4e72b9807785afee48bb")
+	must(t, err)
+
+	if blob.References != 1 {
+		t.Errorf("expected blob to be referenced once, got %d", blob.References)
+	}
+}
+
+func TestCopyResume(t *testing.T) {
+	ctx := context.Background()
+	src := seed(t)
+	dst := memory.Must(&url.URL{Scheme: "memory"})
+
+	users, err := src.Users().List(ctx)
+	must(t, err)
+
+	_, err = dst.Users().Create(ctx, users[0])
+	must(t, err)
+
+	mod, err := src.Mods().Show(ctx, "jei")
+	must(t, err)
+
+	_, err = dst.Mods().Create(ctx, mod)
+	must(t, err)
+
+	versions, err := src.Versions().List(ctx, mod.ID)
+	must(t, err)
+
+	_, err = dst.Versions().Create(ctx, versions[0])
+	must(t, err)
+
+	var (
+		copied  = make(map[string]int)
+		skipped = make(map[string]int)
+	)
+
+	_, err = store.Copy(ctx, src, dst, func(name string, c, s int) {
+		copied[name] = c
+		skipped[name] = s
+	})
+	must(t, err)
+
+	for name, expected := range map[string][2]int{
+		"users":          {1, 1},
+		"mods":           {1, 1},
+		"versions":       {2, 1},
+		"packs":          {1, 0},
+		"builds":         {2, 0},
+		"team_users":     {1, 0},
+		"build_versions": {1, 0},
+		"links":          {1, 0},
+	} {
+		if copied[name] != expected[0] || skipped[name] != expected[1] {
+			t.Errorf("expected %s to copy %d and skip %d, got %d and %d", name, expected[0], expected[1], copied[name], skipped[name])
+		}
+	}
+
+	compare(t, src, dst)
+
+	_, err = store.Copy(ctx, src, dst, func(name string, c, s int) {
+		if c != 0 {
+			t.Errorf("expected nothing to be copied again, got %d %s", c, name)
+		}
+	})
+	must(t, err)
+
+	compare(t, src, dst)
+}
+
+func TestCopySnapshot(t *testing.T) {
+	ctx := context.Background()
+	live := seed(t)
+	dst := memory.Must(&url.URL{Scheme: "memory"})
+
+	d, err := store.Export(ctx, live)
+	must(t, err)
+
+	frozen := memory.Must(&url.URL{Scheme: "memory"})
+	must(t, store.Import(ctx, d, frozen))
+
+	_, err = live.Mods().Create(ctx, &model.Mod{Name: "Thaumcraft"})
+	must(t, err)
+
+	counts, err := store.Copy(ctx, &snapshotted{Store: live, snapshot: frozen}, dst, nil)
+	must(t, err)
+
+	compare(t, frozen, dst)
+
+	actual, err := store.Count(ctx, dst)
+	must(t, err)
+
+	if diff := counts.Diff(actual); len(diff) > 0 {
+		t.Fatalf("expected counts of the snapshot, got differences for %v", diff)
+	}
+}
+
+// snapshotted serves snapshots from another store, this way records which
+// are not read from the snapshot stand out.
+type snapshotted struct {
+	store.Store
+
+	snapshot store.Store
+}
+
+// Snapshot executes the function on the snapshot store.
+func (s *snapshotted) Snapshot(ctx context.Context, fn func(store.Repositories) error) error {
+	return s.snapshot.Snapshot(ctx, fn)
+}
+
+// seed creates a store with records of all entities and relations.
+func seed(t *testing.T) store.Store {
+	t.Helper()
+
+	ctx := context.Background()
+	s := memory.Must(&url.URL{Scheme: "memory"})
+	created := time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	alice, err := s.Users().Create(ctx, &model.User{Username: "Alice", CreatedAt: created})
+	must(t, err)
+
+	_, err = s.Users().Create(ctx, &model.User{Username: "Bob"})
+	must(t, err)
+
+	team, err := s.Teams().Create(ctx, &model.Team{Name: "Core"})
+	must(t, err)
+
+	must(t, s.TeamUsers().Append(ctx, &model.TeamUser{TeamID: team.ID, UserID: alice.ID, Perm: "owner"}))
+
+	jei, err := s.Mods().Create(ctx, &model.Mod{Name: "JEI", CreatedAt: created, UpdatedAt: created})
+	must(t, err)
+
+	_, err = s.Mods().Create(ctx, &model.Mod{Name: "Botania", Slug: "botania-mod"})
+	must(t, err)
+
+	var latest *model.Version
+
+	for _, name := range []string{"1.0", "2.0", "3.0"} {
+		latest, err = s.Versions().Create(ctx, &model.Version{
+			ModID:     jei.ID,
+			Name:      name,
+			FileKey:   "mods/jei-" + name + ".jar",
+			CreatedAt: created,
+		})
+		must(t, err)
+	}
+
+	_, err = s.Blobs().Create(ctx, &model.Blob{
+		Digest: "ca978112ca1bbdcafac231b39a23dc4da786eff8147c4e72b9807785afee48bb",
+		Size:   1,
+	})
+	must(t, err)
+
+	_, err = s.Links().Create(ctx, &model.Link{
+		Path:   latest.FileKey,
+		Digest: "ca978112ca1bbdcafac231b39a23dc4da786eff8147c4e72b9807785afee48bb",
+	})
+	must(t, err)
+
+	pack, err := s.Packs().Create(ctx, &model.Pack{Name: "Hexxit", CreatedAt: created, UpdatedAt: created})
+	must(t, err)
+
+	stable, err := s.Builds().Create(ctx, &model.Build{PackID: pack.ID, Name: "1.0.0"})
+	must(t, err)
+
+	beta, err := s.Builds().Create(ctx, &model.Build{PackID: pack.ID, Name: "1.1.0"})
+	must(t, err)
+
+	must(t, s.BuildVersions().Append(ctx, &model.BuildVersion{BuildID: stable.ID, VersionID: latest.ID}))
+
+	pack.RecommendedID = stable.ID
+	pack.LatestID = beta.ID
+	must(t, s.Packs().Assign(ctx, pack))
+
+	return s
+}
+
+// compare checks that both stores contain the same number of records.
+func compare(t *testing.T, src, dst store.Store) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	expected, err := store.Count(ctx, src)
+	must(t, err)
+
+	got, err := store.Count(ctx, dst)
+	must(t, err)
+
+	if diff := expected.Diff(got); len(diff) != 0 {
+		t.Fatalf("expected equal counts, got differences for %v", diff)
+	}
+}
+
+// must fails the test on any unexpected error.
+func must(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
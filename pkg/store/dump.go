@@ -0,0 +1,288 @@
+package store
+
+import (
+	"context"
+
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/pkg/errors"
+)
+
+// Dump holds all records of a store in a driver independent format, it gets
+// used for backups and fixtures. The records keep their IDs, slugs and
+// timestamps if they are provided.
+type Dump struct {
+	Users      []*model.User      `json:"users"`
+	Teams      []*model.Team      `json:"teams"`
+	Minecrafts []*model.Minecraft `json:"minecrafts"`
+	Forges     []*model.Forge     `json:"forges"`
+	Mods       []*model.Mod       `json:"mods"`
+	Versions   []*model.Version   `json:"versions"`
+	Packs      []*model.Pack      `json:"packs"`
+	Builds     []*model.Build     `json:"builds"`
+
+	TeamUsers     []*model.TeamUser     `json:"team_users"`
+	TeamPacks     []*model.TeamPack     `json:"team_packs"`
+	TeamMods      []*model.TeamMod      `json:"team_mods"`
+	UserPacks     []*model.UserPack     `json:"user_packs"`
+	UserMods      []*model.UserMod      `json:"user_mods"`
+	BuildVersions []*model.BuildVersion `json:"build_versions"`
+
+	Blobs []*model.Blob `json:"blobs"`
+	Links []*model.Link `json:"links"`
+}
+
+// Counts counts the records of all entities and relations within the dump.
+func (d *Dump) Counts() Counts {
+	return Counts{
+		"users":          len(d.Users),
+		"teams":          len(d.Teams),
+		"minecrafts":     len(d.Minecrafts),
+		"forges":         len(d.Forges),
+		"mods":           len(d.Mods),
+		"versions":       len(d.Versions),
+		"packs":          len(d.Packs),
+		"builds":         len(d.Builds),
+		"team_users":     len(d.TeamUsers),
+		"team_packs":     len(d.TeamPacks),
+		"team_mods":      len(d.TeamMods),
+		"user_packs":     len(d.UserPacks),
+		"user_mods":      len(d.UserMods),
+		"build_versions": len(d.BuildVersions),
+		"blobs":          len(d.Blobs),
+		"links":          len(d.Links),
+	}
+}
+
+// Export reads all records of the repositories into a dump, it should be
+// executed within a snapshot to get a consistent result.
+func Export(ctx context.Context, s Repositories) (*Dump, error) {
+	var (
+		d   = &Dump{}
+		err error
+	)
+
+	if d.Users, err = s.Users().List(ctx); err != nil {
+		return nil, errors.Wrap(err, "failed to list users")
+	}
+
+	for _, user := range d.Users {
+		packs, err := s.UserPacks().ListByUser(ctx, user.ID)
+
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list user packs")
+		}
+
+		mods, err := s.UserMods().ListByUser(ctx, user.ID)
+
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list user mods")
+		}
+
+		d.UserPacks = append(d.UserPacks, packs...)
+		d.UserMods = append(d.UserMods, mods...)
+	}
+
+	if d.Teams, err = s.Teams().List(ctx); err != nil {
+		return nil, errors.Wrap(err, "failed to list teams")
+	}
+
+	for _, team := range d.Teams {
+		users, err := s.TeamUsers().ListByTeam(ctx, team.ID)
+
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list team users")
+		}
+
+		packs, err := s.TeamPacks().ListByTeam(ctx, team.ID)
+
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list team packs")
+		}
+
+		mods, err := s.TeamMods().ListByTeam(ctx, team.ID)
+
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list team mods")
+		}
+
+		d.TeamUsers = append(d.TeamUsers, users...)
+		d.TeamPacks = append(d.TeamPacks, packs...)
+		d.TeamMods = append(d.TeamMods, mods...)
+	}
+
+	if d.Minecrafts, err = s.Minecrafts().List(ctx); err != nil {
+		return nil, errors.Wrap(err, "failed to list minecrafts")
+	}
+
+	if d.Forges, err = s.Forges().List(ctx); err != nil {
+		return nil, errors.Wrap(err, "failed to list forges")
+	}
+
+	if d.Mods, err = s.Mods().List(ctx); err != nil {
+		return nil, errors.Wrap(err, "failed to list mods")
+	}
+
+	for _, mod := range d.Mods {
+		versions, err := s.Versions().List(ctx, mod.ID)
+
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list versions")
+		}
+
+		d.Versions = append(d.Versions, versions...)
+	}
+
+	if d.Packs, err = s.Packs().List(ctx); err != nil {
+		return nil, errors.Wrap(err, "failed to list packs")
+	}
+
+	for _, pack := range d.Packs {
+		builds, err := s.Builds().List(ctx, pack.ID)
+
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list builds")
+		}
+
+		for _, build := range builds {
+			versions, err := s.BuildVersions().ListByBuild(ctx, build.ID)
+
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to list build versions")
+			}
+
+			d.BuildVersions = append(d.BuildVersions, versions...)
+		}
+
+		d.Builds = append(d.Builds, builds...)
+	}
+
+	if d.Blobs, err = s.Blobs().List(ctx); err != nil {
+		return nil, errors.Wrap(err, "failed to list blobs")
+	}
+
+	if d.Links, err = s.Links().List(ctx); err != nil {
+		return nil, errors.Wrap(err, "failed to list links")
+	}
+
+	return d, nil
+}
+
+// Import creates all records of the dump within the repositories, it uses
+// the repositories to apply the same validations like for regular requests.
+func Import(ctx context.Context, d *Dump, s Repositories) error {
+	for _, record := range d.Users {
+		if _, err := s.Users().Create(ctx, record); err != nil {
+			return errors.Wrapf(err, "failed to create user %s", record.Username)
+		}
+	}
+
+	for _, record := range d.Teams {
+		if _, err := s.Teams().Create(ctx, record); err != nil {
+			return errors.Wrapf(err, "failed to create team %s", record.Name)
+		}
+	}
+
+	for _, record := range d.Minecrafts {
+		if _, err := s.Minecrafts().Create(ctx, record); err != nil {
+			return errors.Wrapf(err, "failed to create minecraft %s", record.Name)
+		}
+	}
+
+	for _, record := range d.Forges {
+		if _, err := s.Forges().Create(ctx, record); err != nil {
+			return errors.Wrapf(err, "failed to create forge %s", record.Name)
+		}
+	}
+
+	for _, record := range d.Mods {
+		if _, err := s.Mods().Create(ctx, record); err != nil {
+			return errors.Wrapf(err, "failed to create mod %s", record.Name)
+		}
+	}
+
+	for _, record := range d.Versions {
+		if _, err := s.Versions().Create(ctx, record); err != nil {
+			return errors.Wrapf(err, "failed to create version %s", record.Name)
+		}
+	}
+
+	// The recommended and latest builds get assigned after the builds have
+	// been created as they must already exist.
+	for _, record := range d.Packs {
+		pack := *record
+		pack.RecommendedID = ""
+		pack.LatestID = ""
+
+		if _, err := s.Packs().Create(ctx, &pack); err != nil {
+			return errors.Wrapf(err, "failed to create pack %s", record.Name)
+		}
+	}
+
+	for _, record := range d.Builds {
+		if _, err := s.Builds().Create(ctx, record); err != nil {
+			return errors.Wrapf(err, "failed to create build %s", record.Name)
+		}
+	}
+
+	for _, record := range d.Packs {
+		if record.RecommendedID == "" && record.LatestID == "" {
+			continue
+		}
+
+		if err := s.Packs().Assign(ctx, record); err != nil {
+			return errors.Wrapf(err, "failed to assign builds to pack %s", record.Name)
+		}
+	}
+
+	for _, record := range d.TeamUsers {
+		if err := s.TeamUsers().Append(ctx, record); err != nil {
+			return errors.Wrapf(err, "failed to assign user %s to team %s", record.UserID, record.TeamID)
+		}
+	}
+
+	for _, record := range d.TeamPacks {
+		if err := s.TeamPacks().Append(ctx, record); err != nil {
+			return errors.Wrapf(err, "failed to assign pack %s to team %s", record.PackID, record.TeamID)
+		}
+	}
+
+	for _, record := range d.TeamMods {
+		if err := s.TeamMods().Append(ctx, record); err != nil {
+			return errors.Wrapf(err, "failed to assign mod %s to team %s", record.ModID, record.TeamID)
+		}
+	}
+
+	for _, record := range d.UserPacks {
+		if err := s.UserPacks().Append(ctx, record); err != nil {
+			return errors.Wrapf(err, "failed to assign pack %s to user %s", record.PackID, record.UserID)
+		}
+	}
+
+	for _, record := range d.UserMods {
+		if err := s.UserMods().Append(ctx, record); err != nil {
+			return errors.Wrapf(err, "failed to assign mod %s to user %s", record.ModID, record.UserID)
+		}
+	}
+
+	for _, record := range d.BuildVersions {
+		if err := s.BuildVersions().Append(ctx, record); err != nil {
+			return errors.Wrapf(err, "failed to assign version %s to build %s", record.VersionID, record.BuildID)
+		}
+	}
+
+	// The references of the blobs are not imported, they get counted again
+	// while the links are created.
+	for _, record := range d.Blobs {
+		if _, err := s.Blobs().Create(ctx, record); err != nil {
+			return errors.Wrapf(err, "failed to create blob %s", record.Digest)
+		}
+	}
+
+	for _, record := range d.Links {
+		if _, err := s.Links().Create(ctx, record); err != nil {
+			return errors.Wrapf(err, "failed to create link %s", record.Path)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package store
+
+import (
+	"context"
+
+	"github.com/kleister/kleister-api/pkg/model"
+)
+
+// Forges provides the repository interface for forge versions.
+type Forges interface {
+	// List returns all forge versions ordered by name.
+	List(context.Context) ([]*model.Forge, error)
+
+	// Show returns a single record by its ID or slug.
+	Show(context.Context, string) (*model.Forge, error)
+
+	// Create persists a new record, an ID and slug get generated if empty.
+	Create(context.Context, *model.Forge) (*model.Forge, error)
+
+	// Update persists the changes of an existing record.
+	Update(context.Context, *model.Forge) (*model.Forge, error)
+
+	// Delete removes a record by its ID or slug including its assignments.
+	Delete(context.Context, string) error
+}
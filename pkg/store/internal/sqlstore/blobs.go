@@ -0,0 +1,154 @@
+package sqlstore
+
+import (
+	"context"
+
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+var blobColumns = []string{
+	"digest",
+	"size",
+	"md5",
+	"refs",
+	"created_at",
+	"updated_at",
+}
+
+type blobs struct {
+	s *Store
+}
+
+// List implements the store.Blobs interface.
+func (r *blobs) List(ctx context.Context) ([]*model.Blob, error) {
+	rows, err := r.s.query(
+		ctx,
+		"SELECT "+columns("", blobColumns)+" FROM blobs ORDER BY digest",
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	records := make([]*model.Blob, 0)
+
+	for rows.Next() {
+		record := &model.Blob{}
+
+		if err := rows.Scan(blobFields(record)...); err != nil {
+			return nil, err
+		}
+
+		records = append(records, record)
+	}
+
+	return records, r.s.wrap(rows.Err())
+}
+
+// Show implements the store.Blobs interface.
+func (r *blobs) Show(ctx context.Context, digest string) (*model.Blob, error) {
+	record := &model.Blob{}
+
+	if err := r.s.row(
+		ctx,
+		"SELECT "+columns("", blobColumns)+" FROM blobs WHERE digest = ?",
+		[]interface{}{digest},
+		blobFields(record)...,
+	); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// Create implements the store.Blobs interface.
+func (r *blobs) Create(ctx context.Context, blob *model.Blob) (*model.Blob, error) {
+	record := *blob
+	current := now()
+
+	record.References = 0
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = current
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = current
+	}
+
+	if _, err := r.s.exec(
+		ctx,
+		"INSERT INTO blobs ("+columns("", blobColumns)+") VALUES ("+placeholders(blobColumns)+")",
+		blobValues(&record)...,
+	); err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Delete implements the store.Blobs interface.
+func (r *blobs) Delete(ctx context.Context, digest string) error {
+	return r.s.transact(ctx, func(s *Store) error {
+		record, err := s.Blobs().Show(ctx, digest)
+
+		if err != nil {
+			return err
+		}
+
+		if record.References > 0 {
+			return store.ErrConflict
+		}
+
+		_, err = s.exec(
+			ctx,
+			"DELETE FROM blobs WHERE digest = ? AND refs = 0",
+			record.Digest,
+		)
+
+		return err
+	})
+}
+
+// reference adjusts the reference counter of a blob.
+func (s *Store) reference(ctx context.Context, digest string, delta int) error {
+	res, err := s.exec(
+		ctx,
+		"UPDATE blobs SET refs = refs + ?, updated_at = ? WHERE digest = ?",
+		delta,
+		now(),
+		digest,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return affected(res)
+}
+
+// blobFields returns the scan destinations for the blob columns.
+func blobFields(record *model.Blob) []interface{} {
+	return []interface{}{
+		&record.Digest,
+		&record.Size,
+		&record.MD5,
+		&record.References,
+		timestamp{&record.CreatedAt},
+		timestamp{&record.UpdatedAt},
+	}
+}
+
+// blobValues returns the values for the blob columns.
+func blobValues(record *model.Blob) []interface{} {
+	return []interface{}{
+		record.Digest,
+		record.Size,
+		record.MD5,
+		record.References,
+		record.CreatedAt,
+		record.UpdatedAt,
+	}
+}
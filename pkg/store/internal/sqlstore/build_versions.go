@@ -0,0 +1,125 @@
+package sqlstore
+
+import (
+	"context"
+
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+type buildVersions struct {
+	s *Store
+}
+
+// ListByBuild implements the store.BuildVersions interface.
+func (r *buildVersions) ListByBuild(ctx context.Context, buildID string) ([]*model.BuildVersion, error) {
+	if err := r.s.exists(ctx, "builds", buildID); err != nil {
+		return nil, err
+	}
+
+	return r.list(ctx, "a.build_id = ?", "v.name, v.slug", buildID)
+}
+
+// ListByVersion implements the store.BuildVersions interface.
+func (r *buildVersions) ListByVersion(ctx context.Context, versionID string) ([]*model.BuildVersion, error) {
+	if err := r.s.exists(ctx, "versions", versionID); err != nil {
+		return nil, err
+	}
+
+	return r.list(ctx, "a.version_id = ?", "b.name, b.slug", versionID)
+}
+
+// Append implements the store.BuildVersions interface.
+func (r *buildVersions) Append(ctx context.Context, buildVersion *model.BuildVersion) error {
+	record := *buildVersion
+	current := now()
+
+	if !isUUID(record.BuildID) || !isUUID(record.VersionID) {
+		return store.ErrNotFound
+	}
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = current
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = current
+	}
+
+	_, err := r.s.exec(
+		ctx,
+		"INSERT INTO build_versions (build_id, version_id, created_at, updated_at) VALUES (?, ?, ?, ?)",
+		record.BuildID,
+		record.VersionID,
+		record.CreatedAt,
+		record.UpdatedAt,
+	)
+
+	return err
+}
+
+// Drop implements the store.BuildVersions interface.
+func (r *buildVersions) Drop(ctx context.Context, buildID, versionID string) error {
+	if !isUUID(buildID) || !isUUID(versionID) {
+		return store.ErrNotFound
+	}
+
+	res, err := r.s.exec(
+		ctx,
+		"DELETE FROM build_versions WHERE build_id = ? AND version_id = ?",
+		buildID,
+		versionID,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return affected(res)
+}
+
+// list fetches the assignments including both sides.
+func (r *buildVersions) list(ctx context.Context, cond, order string, args ...interface{}) ([]*model.BuildVersion, error) {
+	rows, err := r.s.query(
+		ctx,
+		"SELECT a.created_at, a.updated_at, "+columns("b", buildColumns)+", "+columns("v", versionColumns)+
+			" FROM build_versions a"+
+			" INNER JOIN builds b ON b.id = a.build_id"+
+			" INNER JOIN versions v ON v.id = a.version_id"+
+			" WHERE "+cond+" ORDER BY "+order,
+		args...,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	records := make([]*model.BuildVersion, 0)
+
+	for rows.Next() {
+		record := &model.BuildVersion{
+			Build:   &model.Build{},
+			Version: &model.Version{},
+		}
+
+		fields := []interface{}{
+			timestamp{&record.CreatedAt},
+			timestamp{&record.UpdatedAt},
+		}
+
+		fields = append(fields, buildFields(record.Build)...)
+		fields = append(fields, versionFields(record.Version)...)
+
+		if err := rows.Scan(fields...); err != nil {
+			return nil, err
+		}
+
+		record.BuildID = record.Build.ID
+		record.VersionID = record.Version.ID
+
+		records = append(records, record)
+	}
+
+	return records, r.s.wrap(rows.Err())
+}
@@ -0,0 +1,233 @@
+package sqlstore
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+var buildColumns = []string{
+	"id",
+	"pack_id",
+	"minecraft_id",
+	"forge_id",
+	"slug",
+	"name",
+	"min_java",
+	"min_memory",
+	"published",
+	"hidden",
+	"private",
+	"public",
+	"created_at",
+	"updated_at",
+}
+
+type builds struct {
+	s *Store
+}
+
+// List implements the store.Builds interface.
+func (r *builds) List(ctx context.Context, packID string) ([]*model.Build, error) {
+	if err := r.s.exists(ctx, "packs", packID); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.s.query(
+		ctx,
+		"SELECT "+columns("", buildColumns)+" FROM builds WHERE pack_id = ? ORDER BY name, slug",
+		packID,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	records := make([]*model.Build, 0)
+
+	for rows.Next() {
+		record := &model.Build{}
+
+		if err := rows.Scan(buildFields(record)...); err != nil {
+			return nil, err
+		}
+
+		records = append(records, record)
+	}
+
+	return records, r.s.wrap(rows.Err())
+}
+
+// Show implements the store.Builds interface.
+func (r *builds) Show(ctx context.Context, packID, id string) (*model.Build, error) {
+	if !isUUID(packID) {
+		return nil, store.ErrNotFound
+	}
+
+	cond, args := lookup(id)
+	record := &model.Build{}
+
+	if err := r.s.row(
+		ctx,
+		"SELECT "+columns("", buildColumns)+" FROM builds WHERE pack_id = ? AND "+cond,
+		append([]interface{}{packID}, args...),
+		buildFields(record)...,
+	); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// Create implements the store.Builds interface.
+func (r *builds) Create(ctx context.Context, build *model.Build) (*model.Build, error) {
+	record := *build
+	current := now()
+
+	if record.ID == "" {
+		record.ID = uuid.New().String()
+	}
+
+	record.Slug = store.SlugOrDefault(record.Slug, record.Name, record.ID)
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = current
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = current
+	}
+
+	if err := checkBuild(&record); err != nil {
+		return nil, err
+	}
+
+	if _, err := r.s.exec(
+		ctx,
+		"INSERT INTO builds ("+columns("", buildColumns)+") VALUES ("+placeholders(buildColumns)+")",
+		buildValues(&record)...,
+	); err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Update implements the store.Builds interface.
+func (r *builds) Update(ctx context.Context, build *model.Build) (*model.Build, error) {
+	record := *build
+
+	err := r.s.transact(ctx, func(s *Store) error {
+		if !isUUID(record.ID) {
+			return store.ErrNotFound
+		}
+
+		current, err := s.Builds().Show(ctx, record.PackID, record.ID)
+
+		if err != nil {
+			return err
+		}
+
+		record.Slug = store.SlugOrDefault(record.Slug, record.Name, current.Slug)
+		record.CreatedAt = current.CreatedAt
+		record.UpdatedAt = now()
+
+		if err := checkBuild(&record); err != nil {
+			return err
+		}
+
+		_, err = s.exec(
+			ctx,
+			"UPDATE builds SET "+assignments(buildColumns[1:])+" WHERE id = ?",
+			append(buildValues(&record)[1:], record.ID)...,
+		)
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Delete implements the store.Builds interface.
+func (r *builds) Delete(ctx context.Context, packID, id string) error {
+	return r.s.transact(ctx, func(s *Store) error {
+		record, err := s.Builds().Show(ctx, packID, id)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = s.exec(
+			ctx,
+			"DELETE FROM builds WHERE id = ?",
+			record.ID,
+		)
+
+		return err
+	})
+}
+
+// checkBuild verifies that all references of the build are valid UUIDs,
+// their existence gets checked by the foreign keys.
+func checkBuild(record *model.Build) error {
+	if !isUUID(record.PackID) {
+		return store.ErrNotFound
+	}
+
+	if record.MinecraftID != "" && !isUUID(record.MinecraftID) {
+		return store.ErrNotFound
+	}
+
+	if record.ForgeID != "" && !isUUID(record.ForgeID) {
+		return store.ErrNotFound
+	}
+
+	return nil
+}
+
+// buildFields returns the scan destinations for the build columns.
+func buildFields(record *model.Build) []interface{} {
+	return []interface{}{
+		&record.ID,
+		&record.PackID,
+		nullable{&record.MinecraftID},
+		nullable{&record.ForgeID},
+		&record.Slug,
+		&record.Name,
+		&record.MinJava,
+		&record.MinMemory,
+		&record.Published,
+		&record.Hidden,
+		&record.Private,
+		&record.Public,
+		timestamp{&record.CreatedAt},
+		timestamp{&record.UpdatedAt},
+	}
+}
+
+// buildValues returns the values for the build columns.
+func buildValues(record *model.Build) []interface{} {
+	return []interface{}{
+		record.ID,
+		record.PackID,
+		null(record.MinecraftID),
+		null(record.ForgeID),
+		record.Slug,
+		record.Name,
+		record.MinJava,
+		record.MinMemory,
+		record.Published,
+		record.Hidden,
+		record.Private,
+		record.Public,
+		record.CreatedAt,
+		record.UpdatedAt,
+	}
+}
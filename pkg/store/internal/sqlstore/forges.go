@@ -0,0 +1,174 @@
+package sqlstore
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+var forgeColumns = []string{
+	"id",
+	"slug",
+	"name",
+	"minecraft",
+	"created_at",
+	"updated_at",
+}
+
+type forges struct {
+	s *Store
+}
+
+// List implements the store.Forges interface.
+func (r *forges) List(ctx context.Context) ([]*model.Forge, error) {
+	rows, err := r.s.query(
+		ctx,
+		"SELECT "+columns("", forgeColumns)+" FROM forges ORDER BY name, slug",
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	records := make([]*model.Forge, 0)
+
+	for rows.Next() {
+		record := &model.Forge{}
+
+		if err := rows.Scan(forgeFields(record)...); err != nil {
+			return nil, err
+		}
+
+		records = append(records, record)
+	}
+
+	return records, r.s.wrap(rows.Err())
+}
+
+// Show implements the store.Forges interface.
+func (r *forges) Show(ctx context.Context, id string) (*model.Forge, error) {
+	cond, args := lookup(id)
+	record := &model.Forge{}
+
+	if err := r.s.row(
+		ctx,
+		"SELECT "+columns("", forgeColumns)+" FROM forges WHERE "+cond,
+		args,
+		forgeFields(record)...,
+	); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// Create implements the store.Forges interface.
+func (r *forges) Create(ctx context.Context, forge *model.Forge) (*model.Forge, error) {
+	record := *forge
+	current := now()
+
+	if record.ID == "" {
+		record.ID = uuid.New().String()
+	}
+
+	record.Slug = store.SlugOrDefault(record.Slug, record.Name, record.ID)
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = current
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = current
+	}
+
+	if _, err := r.s.exec(
+		ctx,
+		"INSERT INTO forges ("+columns("", forgeColumns)+") VALUES ("+placeholders(forgeColumns)+")",
+		forgeValues(&record)...,
+	); err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Update implements the store.Forges interface.
+func (r *forges) Update(ctx context.Context, forge *model.Forge) (*model.Forge, error) {
+	record := *forge
+
+	err := r.s.transact(ctx, func(s *Store) error {
+		if !isUUID(record.ID) {
+			return store.ErrNotFound
+		}
+
+		current, err := s.Forges().Show(ctx, record.ID)
+
+		if err != nil {
+			return err
+		}
+
+		record.Slug = store.SlugOrDefault(record.Slug, record.Name, current.Slug)
+		record.CreatedAt = current.CreatedAt
+		record.UpdatedAt = now()
+
+		_, err = s.exec(
+			ctx,
+			"UPDATE forges SET "+assignments(forgeColumns[1:])+" WHERE id = ?",
+			append(forgeValues(&record)[1:], record.ID)...,
+		)
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Delete implements the store.Forges interface.
+func (r *forges) Delete(ctx context.Context, id string) error {
+	return r.s.transact(ctx, func(s *Store) error {
+		record, err := s.Forges().Show(ctx, id)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = s.exec(
+			ctx,
+			"DELETE FROM forges WHERE id = ?",
+			record.ID,
+		)
+
+		return err
+	})
+}
+
+// forgeFields returns the scan destinations for the forge columns.
+func forgeFields(record *model.Forge) []interface{} {
+	return []interface{}{
+		&record.ID,
+		&record.Slug,
+		&record.Name,
+		&record.Minecraft,
+		timestamp{&record.CreatedAt},
+		timestamp{&record.UpdatedAt},
+	}
+}
+
+// forgeValues returns the values for the forge columns.
+func forgeValues(record *model.Forge) []interface{} {
+	return []interface{}{
+		record.ID,
+		record.Slug,
+		record.Name,
+		record.Minecraft,
+		record.CreatedAt,
+		record.UpdatedAt,
+	}
+}
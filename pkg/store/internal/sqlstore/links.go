@@ -0,0 +1,139 @@
+package sqlstore
+
+import (
+	"context"
+
+	"github.com/kleister/kleister-api/pkg/model"
+)
+
+var linkColumns = []string{
+	"path",
+	"digest",
+	"content_type",
+	"created_at",
+	"updated_at",
+}
+
+type links struct {
+	s *Store
+}
+
+// List implements the store.Links interface.
+func (r *links) List(ctx context.Context) ([]*model.Link, error) {
+	rows, err := r.s.query(
+		ctx,
+		"SELECT "+columns("", linkColumns)+" FROM links ORDER BY path",
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	records := make([]*model.Link, 0)
+
+	for rows.Next() {
+		record := &model.Link{}
+
+		if err := rows.Scan(linkFields(record)...); err != nil {
+			return nil, err
+		}
+
+		records = append(records, record)
+	}
+
+	return records, r.s.wrap(rows.Err())
+}
+
+// Show implements the store.Links interface.
+func (r *links) Show(ctx context.Context, path string) (*model.Link, error) {
+	record := &model.Link{}
+
+	if err := r.s.row(
+		ctx,
+		"SELECT "+columns("", linkColumns)+" FROM links WHERE path = ?",
+		[]interface{}{path},
+		linkFields(record)...,
+	); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// Create implements the store.Links interface.
+func (r *links) Create(ctx context.Context, link *model.Link) (*model.Link, error) {
+	record := *link
+	current := now()
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = current
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = current
+	}
+
+	err := r.s.transact(ctx, func(s *Store) error {
+		if err := s.reference(ctx, record.Digest, 1); err != nil {
+			return err
+		}
+
+		_, err := s.exec(
+			ctx,
+			"INSERT INTO links ("+columns("", linkColumns)+") VALUES ("+placeholders(linkColumns)+")",
+			linkValues(&record)...,
+		)
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Delete implements the store.Links interface.
+func (r *links) Delete(ctx context.Context, path string) error {
+	return r.s.transact(ctx, func(s *Store) error {
+		record, err := s.Links().Show(ctx, path)
+
+		if err != nil {
+			return err
+		}
+
+		if _, err := s.exec(
+			ctx,
+			"DELETE FROM links WHERE path = ?",
+			record.Path,
+		); err != nil {
+			return err
+		}
+
+		return s.reference(ctx, record.Digest, -1)
+	})
+}
+
+// linkFields returns the scan destinations for the link columns.
+func linkFields(record *model.Link) []interface{} {
+	return []interface{}{
+		&record.Path,
+		&record.Digest,
+		&record.ContentType,
+		timestamp{&record.CreatedAt},
+		timestamp{&record.UpdatedAt},
+	}
+}
+
+// linkValues returns the values for the link columns.
+func linkValues(record *model.Link) []interface{} {
+	return []interface{}{
+		record.Path,
+		record.Digest,
+		record.ContentType,
+		record.CreatedAt,
+		record.UpdatedAt,
+	}
+}
@@ -0,0 +1,183 @@
+package sqlstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/kleister/kleister-api/pkg/store"
+	"github.com/pkg/errors"
+)
+
+// Migration defines a versioned schema change for SQL databases.
+type Migration struct {
+	Version int
+	Name    string
+	Up      []string
+	Down    []string
+}
+
+type migrations struct {
+	s *Store
+}
+
+// Status implements the store.Migrations interface.
+func (r *migrations) Status(ctx context.Context) ([]*store.Migration, error) {
+	applied, err := r.applied(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*store.Migration, 0)
+
+	for _, m := range r.s.migrations {
+		records = append(records, &store.Migration{
+			Version:   m.Version,
+			Name:      m.Name,
+			AppliedAt: applied[m.Version],
+		})
+	}
+
+	return records, nil
+}
+
+// Up implements the store.Migrations interface.
+func (r *migrations) Up(ctx context.Context, dry bool) ([]*store.Migration, error) {
+	applied, err := r.applied(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*store.Migration, 0)
+
+	for _, m := range r.s.migrations {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+
+		record := &store.Migration{
+			Version: m.Version,
+			Name:    m.Name,
+		}
+
+		if !dry {
+			record.AppliedAt = now()
+
+			if err := r.run(
+				ctx,
+				m.Up,
+				"INSERT INTO migrations (version, name, applied_at) VALUES (?, ?, ?)",
+				m.Version,
+				m.Name,
+				record.AppliedAt,
+			); err != nil {
+				return records, errors.Wrapf(err, "failed to apply migration %d", m.Version)
+			}
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// Down implements the store.Migrations interface.
+func (r *migrations) Down(ctx context.Context, dry bool) ([]*store.Migration, error) {
+	applied, err := r.applied(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*store.Migration, 0)
+
+	for i := len(r.s.migrations) - 1; i >= 0; i-- {
+		m := r.s.migrations[i]
+		at, ok := applied[m.Version]
+
+		if !ok {
+			continue
+		}
+
+		record := &store.Migration{
+			Version:   m.Version,
+			Name:      m.Name,
+			AppliedAt: at,
+		}
+
+		if !dry {
+			if err := r.run(
+				ctx,
+				m.Down,
+				"DELETE FROM migrations WHERE version = ?",
+				m.Version,
+			); err != nil {
+				return records, errors.Wrapf(err, "failed to revert migration %d", m.Version)
+			}
+
+			record.AppliedAt = time.Time{}
+		}
+
+		records = append(records, record)
+		break
+	}
+
+	return records, nil
+}
+
+// applied fetches the versions which have already been applied.
+func (r *migrations) applied(ctx context.Context) (map[int]time.Time, error) {
+	rows, err := r.s.query(
+		ctx,
+		"SELECT version, applied_at FROM migrations",
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	result := make(map[int]time.Time)
+
+	for rows.Next() {
+		var (
+			version int
+			at      time.Time
+		)
+
+		if err := rows.Scan(&version, timestamp{&at}); err != nil {
+			return nil, err
+		}
+
+		result[version] = at
+	}
+
+	return result, r.s.wrap(rows.Err())
+}
+
+// run executes the statements and the bookkeeping within one transaction,
+// this also pins all statements to the same connection. Only PostgreSQL
+// applies a migration atomically, MySQL and MariaDB implicitly commit every
+// DDL statement and a failing migration stays partially applied.
+func (r *migrations) run(ctx context.Context, stmts []string, query string, args ...interface{}) error {
+	tx, err := r.s.handle.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, r.s.dialect.Rebind(query), args...); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,174 @@
+package sqlstore
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+var minecraftColumns = []string{
+	"id",
+	"slug",
+	"name",
+	"type",
+	"created_at",
+	"updated_at",
+}
+
+type minecrafts struct {
+	s *Store
+}
+
+// List implements the store.Minecrafts interface.
+func (r *minecrafts) List(ctx context.Context) ([]*model.Minecraft, error) {
+	rows, err := r.s.query(
+		ctx,
+		"SELECT "+columns("", minecraftColumns)+" FROM minecrafts ORDER BY name, slug",
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	records := make([]*model.Minecraft, 0)
+
+	for rows.Next() {
+		record := &model.Minecraft{}
+
+		if err := rows.Scan(minecraftFields(record)...); err != nil {
+			return nil, err
+		}
+
+		records = append(records, record)
+	}
+
+	return records, r.s.wrap(rows.Err())
+}
+
+// Show implements the store.Minecrafts interface.
+func (r *minecrafts) Show(ctx context.Context, id string) (*model.Minecraft, error) {
+	cond, args := lookup(id)
+	record := &model.Minecraft{}
+
+	if err := r.s.row(
+		ctx,
+		"SELECT "+columns("", minecraftColumns)+" FROM minecrafts WHERE "+cond,
+		args,
+		minecraftFields(record)...,
+	); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// Create implements the store.Minecrafts interface.
+func (r *minecrafts) Create(ctx context.Context, minecraft *model.Minecraft) (*model.Minecraft, error) {
+	record := *minecraft
+	current := now()
+
+	if record.ID == "" {
+		record.ID = uuid.New().String()
+	}
+
+	record.Slug = store.SlugOrDefault(record.Slug, record.Name, record.ID)
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = current
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = current
+	}
+
+	if _, err := r.s.exec(
+		ctx,
+		"INSERT INTO minecrafts ("+columns("", minecraftColumns)+") VALUES ("+placeholders(minecraftColumns)+")",
+		minecraftValues(&record)...,
+	); err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Update implements the store.Minecrafts interface.
+func (r *minecrafts) Update(ctx context.Context, minecraft *model.Minecraft) (*model.Minecraft, error) {
+	record := *minecraft
+
+	err := r.s.transact(ctx, func(s *Store) error {
+		if !isUUID(record.ID) {
+			return store.ErrNotFound
+		}
+
+		current, err := s.Minecrafts().Show(ctx, record.ID)
+
+		if err != nil {
+			return err
+		}
+
+		record.Slug = store.SlugOrDefault(record.Slug, record.Name, current.Slug)
+		record.CreatedAt = current.CreatedAt
+		record.UpdatedAt = now()
+
+		_, err = s.exec(
+			ctx,
+			"UPDATE minecrafts SET "+assignments(minecraftColumns[1:])+" WHERE id = ?",
+			append(minecraftValues(&record)[1:], record.ID)...,
+		)
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Delete implements the store.Minecrafts interface.
+func (r *minecrafts) Delete(ctx context.Context, id string) error {
+	return r.s.transact(ctx, func(s *Store) error {
+		record, err := s.Minecrafts().Show(ctx, id)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = s.exec(
+			ctx,
+			"DELETE FROM minecrafts WHERE id = ?",
+			record.ID,
+		)
+
+		return err
+	})
+}
+
+// minecraftFields returns the scan destinations for the minecraft columns.
+func minecraftFields(record *model.Minecraft) []interface{} {
+	return []interface{}{
+		&record.ID,
+		&record.Slug,
+		&record.Name,
+		&record.Type,
+		timestamp{&record.CreatedAt},
+		timestamp{&record.UpdatedAt},
+	}
+}
+
+// minecraftValues returns the values for the minecraft columns.
+func minecraftValues(record *model.Minecraft) []interface{} {
+	return []interface{}{
+		record.ID,
+		record.Slug,
+		record.Name,
+		record.Type,
+		record.CreatedAt,
+		record.UpdatedAt,
+	}
+}
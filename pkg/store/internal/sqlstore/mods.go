@@ -0,0 +1,186 @@
+package sqlstore
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+var modColumns = []string{
+	"id",
+	"slug",
+	"name",
+	"side",
+	"description",
+	"author",
+	"website",
+	"donate",
+	"created_at",
+	"updated_at",
+}
+
+type mods struct {
+	s *Store
+}
+
+// List implements the store.Mods interface.
+func (r *mods) List(ctx context.Context) ([]*model.Mod, error) {
+	rows, err := r.s.query(
+		ctx,
+		"SELECT "+columns("", modColumns)+" FROM mods ORDER BY name, slug",
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	records := make([]*model.Mod, 0)
+
+	for rows.Next() {
+		record := &model.Mod{}
+
+		if err := rows.Scan(modFields(record)...); err != nil {
+			return nil, err
+		}
+
+		records = append(records, record)
+	}
+
+	return records, r.s.wrap(rows.Err())
+}
+
+// Show implements the store.Mods interface.
+func (r *mods) Show(ctx context.Context, id string) (*model.Mod, error) {
+	cond, args := lookup(id)
+	record := &model.Mod{}
+
+	if err := r.s.row(
+		ctx,
+		"SELECT "+columns("", modColumns)+" FROM mods WHERE "+cond,
+		args,
+		modFields(record)...,
+	); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// Create implements the store.Mods interface.
+func (r *mods) Create(ctx context.Context, mod *model.Mod) (*model.Mod, error) {
+	record := *mod
+	current := now()
+
+	if record.ID == "" {
+		record.ID = uuid.New().String()
+	}
+
+	record.Slug = store.SlugOrDefault(record.Slug, record.Name, record.ID)
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = current
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = current
+	}
+
+	if _, err := r.s.exec(
+		ctx,
+		"INSERT INTO mods ("+columns("", modColumns)+") VALUES ("+placeholders(modColumns)+")",
+		modValues(&record)...,
+	); err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Update implements the store.Mods interface.
+func (r *mods) Update(ctx context.Context, mod *model.Mod) (*model.Mod, error) {
+	record := *mod
+
+	err := r.s.transact(ctx, func(s *Store) error {
+		if !isUUID(record.ID) {
+			return store.ErrNotFound
+		}
+
+		current, err := s.Mods().Show(ctx, record.ID)
+
+		if err != nil {
+			return err
+		}
+
+		record.Slug = store.SlugOrDefault(record.Slug, record.Name, current.Slug)
+		record.CreatedAt = current.CreatedAt
+		record.UpdatedAt = now()
+
+		_, err = s.exec(
+			ctx,
+			"UPDATE mods SET "+assignments(modColumns[1:])+" WHERE id = ?",
+			append(modValues(&record)[1:], record.ID)...,
+		)
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Delete implements the store.Mods interface.
+func (r *mods) Delete(ctx context.Context, id string) error {
+	return r.s.transact(ctx, func(s *Store) error {
+		record, err := s.Mods().Show(ctx, id)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = s.exec(
+			ctx,
+			"DELETE FROM mods WHERE id = ?",
+			record.ID,
+		)
+
+		return err
+	})
+}
+
+// modFields returns the scan destinations for the mod columns.
+func modFields(record *model.Mod) []interface{} {
+	return []interface{}{
+		&record.ID,
+		&record.Slug,
+		&record.Name,
+		&record.Side,
+		&record.Description,
+		&record.Author,
+		&record.Website,
+		&record.Donate,
+		timestamp{&record.CreatedAt},
+		timestamp{&record.UpdatedAt},
+	}
+}
+
+// modValues returns the values for the mod columns.
+func modValues(record *model.Mod) []interface{} {
+	return []interface{}{
+		record.ID,
+		record.Slug,
+		record.Name,
+		record.Side,
+		record.Description,
+		record.Author,
+		record.Website,
+		record.Donate,
+		record.CreatedAt,
+		record.UpdatedAt,
+	}
+}
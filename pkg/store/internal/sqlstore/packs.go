@@ -0,0 +1,300 @@
+package sqlstore
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+var packColumns = []string{
+	"id",
+	"recommended_id",
+	"latest_id",
+	"slug",
+	"name",
+	"website",
+	"published",
+	"hidden",
+	"private",
+	"public",
+	"logo_key",
+	"logo_md5",
+	"logo_size",
+	"icon_key",
+	"icon_md5",
+	"icon_size",
+	"background_key",
+	"background_md5",
+	"background_size",
+	"created_at",
+	"updated_at",
+}
+
+type packs struct {
+	s *Store
+}
+
+// List implements the store.Packs interface.
+func (r *packs) List(ctx context.Context) ([]*model.Pack, error) {
+	rows, err := r.s.query(
+		ctx,
+		"SELECT "+columns("", packColumns)+" FROM packs ORDER BY name, slug",
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	records := make([]*model.Pack, 0)
+
+	for rows.Next() {
+		record := &model.Pack{}
+
+		if err := rows.Scan(packFields(record)...); err != nil {
+			return nil, err
+		}
+
+		records = append(records, record)
+	}
+
+	return records, r.s.wrap(rows.Err())
+}
+
+// Show implements the store.Packs interface.
+func (r *packs) Show(ctx context.Context, id string) (*model.Pack, error) {
+	cond, args := lookup(id)
+	record := &model.Pack{}
+
+	if err := r.s.row(
+		ctx,
+		"SELECT "+columns("", packColumns)+" FROM packs WHERE "+cond,
+		args,
+		packFields(record)...,
+	); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// Create implements the store.Packs interface.
+func (r *packs) Create(ctx context.Context, pack *model.Pack) (*model.Pack, error) {
+	record := *pack
+	current := now()
+
+	if record.ID == "" {
+		record.ID = uuid.New().String()
+	}
+
+	record.Slug = store.SlugOrDefault(record.Slug, record.Name, record.ID)
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = current
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = current
+	}
+
+	err := r.s.transact(ctx, func(s *Store) error {
+		if err := checkPack(ctx, s, &record); err != nil {
+			return err
+		}
+
+		_, err := s.exec(
+			ctx,
+			"INSERT INTO packs ("+columns("", packColumns)+") VALUES ("+placeholders(packColumns)+")",
+			packValues(&record)...,
+		)
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Update implements the store.Packs interface.
+func (r *packs) Update(ctx context.Context, pack *model.Pack) (*model.Pack, error) {
+	record := *pack
+
+	err := r.s.transact(ctx, func(s *Store) error {
+		if !isUUID(record.ID) {
+			return store.ErrNotFound
+		}
+
+		current, err := s.Packs().Show(ctx, record.ID)
+
+		if err != nil {
+			return err
+		}
+
+		record.Slug = store.SlugOrDefault(record.Slug, record.Name, current.Slug)
+		record.CreatedAt = current.CreatedAt
+		record.UpdatedAt = now()
+
+		if err := checkPack(ctx, s, &record); err != nil {
+			return err
+		}
+
+		_, err = s.exec(
+			ctx,
+			"UPDATE packs SET "+assignments(packColumns[1:])+" WHERE id = ?",
+			append(packValues(&record)[1:], record.ID)...,
+		)
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Delete implements the store.Packs interface.
+func (r *packs) Delete(ctx context.Context, id string) error {
+	return r.s.transact(ctx, func(s *Store) error {
+		record, err := s.Packs().Show(ctx, id)
+
+		if err != nil {
+			return err
+		}
+
+		if _, err := s.exec(
+			ctx,
+			"UPDATE packs SET recommended_id = NULL, latest_id = NULL WHERE id = ?",
+			record.ID,
+		); err != nil {
+			return err
+		}
+
+		_, err = s.exec(
+			ctx,
+			"DELETE FROM packs WHERE id = ?",
+			record.ID,
+		)
+
+		return err
+	})
+}
+
+// Assign implements the store.Packs interface.
+func (r *packs) Assign(ctx context.Context, pack *model.Pack) error {
+	record := *pack
+
+	return r.s.transact(ctx, func(s *Store) error {
+		if !isUUID(record.ID) {
+			return store.ErrNotFound
+		}
+
+		if err := checkPack(ctx, s, &record); err != nil {
+			return err
+		}
+
+		res, err := s.exec(
+			ctx,
+			"UPDATE packs SET recommended_id = ?, latest_id = ? WHERE id = ?",
+			null(record.RecommendedID),
+			null(record.LatestID),
+			record.ID,
+		)
+
+		if err != nil {
+			return err
+		}
+
+		return affected(res)
+	})
+}
+
+// checkPack verifies that the referenced builds belong to the pack.
+func checkPack(ctx context.Context, s *Store, record *model.Pack) error {
+	for _, id := range []string{record.RecommendedID, record.LatestID} {
+		if id == "" {
+			continue
+		}
+
+		if !isUUID(id) {
+			return store.ErrNotFound
+		}
+
+		var packID string
+
+		if err := s.row(
+			ctx,
+			"SELECT pack_id FROM builds WHERE id = ?",
+			[]interface{}{id},
+			&packID,
+		); err != nil {
+			return err
+		}
+
+		if packID != record.ID {
+			return store.ErrNotFound
+		}
+	}
+
+	return nil
+}
+
+// packFields returns the scan destinations for the pack columns.
+func packFields(record *model.Pack) []interface{} {
+	return []interface{}{
+		&record.ID,
+		nullable{&record.RecommendedID},
+		nullable{&record.LatestID},
+		&record.Slug,
+		&record.Name,
+		&record.Website,
+		&record.Published,
+		&record.Hidden,
+		&record.Private,
+		&record.Public,
+		&record.LogoKey,
+		&record.LogoMD5,
+		&record.LogoSize,
+		&record.IconKey,
+		&record.IconMD5,
+		&record.IconSize,
+		&record.BackgroundKey,
+		&record.BackgroundMD5,
+		&record.BackgroundSize,
+		timestamp{&record.CreatedAt},
+		timestamp{&record.UpdatedAt},
+	}
+}
+
+// packValues returns the values for the pack columns.
+func packValues(record *model.Pack) []interface{} {
+	return []interface{}{
+		record.ID,
+		null(record.RecommendedID),
+		null(record.LatestID),
+		record.Slug,
+		record.Name,
+		record.Website,
+		record.Published,
+		record.Hidden,
+		record.Private,
+		record.Public,
+		record.LogoKey,
+		record.LogoMD5,
+		record.LogoSize,
+		record.IconKey,
+		record.IconMD5,
+		record.IconSize,
+		record.BackgroundKey,
+		record.BackgroundMD5,
+		record.BackgroundSize,
+		record.CreatedAt,
+		record.UpdatedAt,
+	}
+}
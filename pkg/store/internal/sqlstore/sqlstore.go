@@ -0,0 +1,386 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+// Dialect defines the database specific behaviour of a SQL driver.
+type Dialect interface {
+	// Rebind converts the ? placeholders into the driver format.
+	Rebind(string) string
+
+	// IsConflict checks if the error is a unique constraint violation.
+	IsConflict(error) bool
+
+	// IsForeignKey checks if the error is a foreign key violation.
+	IsForeignKey(error) bool
+}
+
+// executor is implemented by both, database handles and transactions.
+type executor interface {
+	ExecContext(context.Context, string, ...interface{}) (sql.Result, error)
+	QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error)
+	QueryRowContext(context.Context, string, ...interface{}) *sql.Row
+}
+
+// Store implements the store.Store interface for SQL databases.
+type Store struct {
+	handle     *sql.DB
+	dialect    Dialect
+	migrations []Migration
+	isolation  sql.IsolationLevel
+	ex         executor
+}
+
+// Users returns the repository for users.
+func (s *Store) Users() store.Users {
+	return &users{s: s}
+}
+
+// Teams returns the repository for teams.
+func (s *Store) Teams() store.Teams {
+	return &teams{s: s}
+}
+
+// Packs returns the repository for packs.
+func (s *Store) Packs() store.Packs {
+	return &packs{s: s}
+}
+
+// Builds returns the repository for builds.
+func (s *Store) Builds() store.Builds {
+	return &builds{s: s}
+}
+
+// Mods returns the repository for mods.
+func (s *Store) Mods() store.Mods {
+	return &mods{s: s}
+}
+
+// Versions returns the repository for versions.
+func (s *Store) Versions() store.Versions {
+	return &versions{s: s}
+}
+
+// Minecrafts returns the repository for Minecraft versions.
+func (s *Store) Minecrafts() store.Minecrafts {
+	return &minecrafts{s: s}
+}
+
+// Forges returns the repository for Forge versions.
+func (s *Store) Forges() store.Forges {
+	return &forges{s: s}
+}
+
+// TeamUsers returns the repository for users assigned to teams.
+func (s *Store) TeamUsers() store.TeamUsers {
+	return &teamUsers{s: s}
+}
+
+// TeamPacks returns the repository for packs assigned to teams.
+func (s *Store) TeamPacks() store.TeamPacks {
+	return &teamPacks{s: s}
+}
+
+// TeamMods returns the repository for mods assigned to teams.
+func (s *Store) TeamMods() store.TeamMods {
+	return &teamMods{s: s}
+}
+
+// UserPacks returns the repository for packs assigned to users.
+func (s *Store) UserPacks() store.UserPacks {
+	return &userPacks{s: s}
+}
+
+// UserMods returns the repository for mods assigned to users.
+func (s *Store) UserMods() store.UserMods {
+	return &userMods{s: s}
+}
+
+// BuildVersions returns the repository for versions assigned to builds.
+func (s *Store) BuildVersions() store.BuildVersions {
+	return &buildVersions{s: s}
+}
+
+// Blobs returns the repository for deduplicated upload content.
+func (s *Store) Blobs() store.Blobs {
+	return &blobs{s: s}
+}
+
+// Links returns the repository for logical upload paths.
+func (s *Store) Links() store.Links {
+	return &links{s: s}
+}
+
+// Migrations returns the repository for schema migrations.
+func (s *Store) Migrations() store.Migrations {
+	return &migrations{s: s}
+}
+
+// Transaction implements the store.Store interface.
+func (s *Store) Transaction(ctx context.Context, fn func(store.Repositories) error) error {
+	return s.transact(ctx, func(tx *Store) error {
+		return fn(tx)
+	})
+}
+
+// Snapshot implements the store.Store interface, it uses a read-only
+// transaction with at least repeatable read isolation.
+func (s *Store) Snapshot(ctx context.Context, fn func(store.Repositories) error) error {
+	if _, ok := s.ex.(*sql.Tx); ok {
+		return fn(s)
+	}
+
+	isolation := s.isolation
+
+	if isolation < sql.LevelRepeatableRead {
+		isolation = sql.LevelRepeatableRead
+	}
+
+	tx, err := s.handle.BeginTx(ctx, &sql.TxOptions{Isolation: isolation, ReadOnly: true})
+
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	return fn(s.with(tx))
+}
+
+// Close simply closes the database connection pool.
+func (s *Store) Close() error {
+	return s.handle.Close()
+}
+
+// exec executes a statement and maps the driver errors.
+func (s *Store) exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	res, err := s.ex.ExecContext(ctx, s.dialect.Rebind(query), args...)
+	return res, s.wrap(err)
+}
+
+// query executes a query and maps the driver errors.
+func (s *Store) query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	rows, err := s.ex.QueryContext(ctx, s.dialect.Rebind(query), args...)
+	return rows, s.wrap(err)
+}
+
+// row executes a query for a single row and scans it into dest.
+func (s *Store) row(ctx context.Context, query string, args []interface{}, dest ...interface{}) error {
+	return s.wrap(s.ex.QueryRowContext(ctx, s.dialect.Rebind(query), args...).Scan(dest...))
+}
+
+// exists checks if a record with the given ID exists within the table.
+func (s *Store) exists(ctx context.Context, table, id string) error {
+	if !isUUID(id) {
+		return store.ErrNotFound
+	}
+
+	var found int
+
+	return s.row(
+		ctx,
+		fmt.Sprintf("SELECT 1 FROM %s WHERE id = ?", table),
+		[]interface{}{id},
+		&found,
+	)
+}
+
+// transact executes the function within a transaction, it reuses the
+// current transaction if the store is already transactional.
+func (s *Store) transact(ctx context.Context, fn func(*Store) error) error {
+	if _, ok := s.ex.(*sql.Tx); ok {
+		return fn(s)
+	}
+
+	tx, err := s.handle.BeginTx(ctx, &sql.TxOptions{Isolation: s.isolation})
+
+	if err != nil {
+		return err
+	}
+
+	if err := fn(s.with(tx)); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// with returns a copy of the store which uses the given executor.
+func (s *Store) with(ex executor) *Store {
+	return &Store{
+		handle:     s.handle,
+		dialect:    s.dialect,
+		migrations: s.migrations,
+		isolation:  s.isolation,
+		ex:         ex,
+	}
+}
+
+// wrap maps driver specific errors to the errors of the store package.
+func (s *Store) wrap(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case err == sql.ErrNoRows:
+		return store.ErrNotFound
+	case s.dialect.IsConflict(err):
+		return store.ErrConflict
+	case s.dialect.IsForeignKey(err):
+		return store.ErrNotFound
+	}
+
+	return err
+}
+
+// now returns the current time with the precision of the database.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+// isUUID checks if the value is a valid UUID.
+func isUUID(val string) bool {
+	_, err := uuid.Parse(val)
+	return err == nil
+}
+
+// lookup generates the condition to find a record by ID or slug. Values
+// shaped like a UUID only match the ID, this way a slug which looks like a
+// UUID can't shadow the record with that ID.
+func lookup(id string) (string, []interface{}) {
+	if isUUID(id) {
+		return "id = ?", []interface{}{id}
+	}
+
+	return "slug = ?", []interface{}{id}
+}
+
+// columns joins the column names with an optional table alias.
+func columns(alias string, names []string) string {
+	result := make([]string, len(names))
+
+	for i, name := range names {
+		if alias != "" {
+			result[i] = alias + "." + name
+		} else {
+			result[i] = name
+		}
+	}
+
+	return strings.Join(result, ", ")
+}
+
+// placeholders generates a list of placeholders for the columns.
+func placeholders(names []string) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", len(names)), ", ")
+}
+
+// assignments generates the SET clause for the columns.
+func assignments(names []string) string {
+	result := make([]string, len(names))
+
+	for i, name := range names {
+		result[i] = name + " = ?"
+	}
+
+	return strings.Join(result, ", ")
+}
+
+// null converts empty strings to NULL values for optional references.
+func null(val string) interface{} {
+	if val == "" {
+		return nil
+	}
+
+	return val
+}
+
+// nullable scans a nullable column into a plain string.
+type nullable struct {
+	dest *string
+}
+
+// Scan implements the sql.Scanner interface.
+func (n nullable) Scan(src interface{}) error {
+	switch val := src.(type) {
+	case nil:
+		*n.dest = ""
+	case []byte:
+		*n.dest = string(val)
+	case string:
+		*n.dest = val
+	default:
+		return fmt.Errorf("unsupported type %T for nullable string", src)
+	}
+
+	return nil
+}
+
+// timestamp scans a time column and converts it to UTC.
+type timestamp struct {
+	dest *time.Time
+}
+
+// Scan implements the sql.Scanner interface.
+func (t timestamp) Scan(src interface{}) error {
+	switch val := src.(type) {
+	case time.Time:
+		*t.dest = val.UTC()
+	default:
+		return fmt.Errorf("unsupported type %T for timestamp", src)
+	}
+
+	return nil
+}
+
+// Isolation maps the isolation option of a dsn to the isolation level used
+// for transactions, unknown values fall back to the database default.
+func Isolation(val string) sql.IsolationLevel {
+	switch strings.ToLower(strings.Replace(val, "-", "_", -1)) {
+	case "read_uncommitted":
+		return sql.LevelReadUncommitted
+	case "read_committed":
+		return sql.LevelReadCommitted
+	case "repeatable_read":
+		return sql.LevelRepeatableRead
+	case "serializable":
+		return sql.LevelSerializable
+	}
+
+	return sql.LevelDefault
+}
+
+// New initializes a new store for the database handle and dialect, the
+// migrations have to be ordered by version.
+func New(handle *sql.DB, dialect Dialect, migrations []Migration, isolation sql.IsolationLevel) *Store {
+	return &Store{
+		handle:     handle,
+		dialect:    dialect,
+		migrations: migrations,
+		isolation:  isolation,
+		ex:         handle,
+	}
+}
+
+// affected checks if the statement affected any rows.
+func affected(res sql.Result) error {
+	count, err := res.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return store.ErrNotFound
+	}
+
+	return nil
+}
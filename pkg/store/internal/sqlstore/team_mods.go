@@ -0,0 +1,153 @@
+package sqlstore
+
+import (
+	"context"
+
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+type teamMods struct {
+	s *Store
+}
+
+// ListByTeam implements the store.TeamMods interface.
+func (r *teamMods) ListByTeam(ctx context.Context, teamID string) ([]*model.TeamMod, error) {
+	if err := r.s.exists(ctx, "teams", teamID); err != nil {
+		return nil, err
+	}
+
+	return r.list(ctx, "a.team_id = ?", "m.name, m.slug", teamID)
+}
+
+// ListByMod implements the store.TeamMods interface.
+func (r *teamMods) ListByMod(ctx context.Context, modID string) ([]*model.TeamMod, error) {
+	if err := r.s.exists(ctx, "mods", modID); err != nil {
+		return nil, err
+	}
+
+	return r.list(ctx, "a.mod_id = ?", "t.name, t.slug", modID)
+}
+
+// Append implements the store.TeamMods interface.
+func (r *teamMods) Append(ctx context.Context, teamMod *model.TeamMod) error {
+	record := *teamMod
+	current := now()
+
+	if !isUUID(record.TeamID) || !isUUID(record.ModID) {
+		return store.ErrNotFound
+	}
+
+	if record.Perm == "" {
+		record.Perm = model.PermUser
+	}
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = current
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = current
+	}
+
+	_, err := r.s.exec(
+		ctx,
+		"INSERT INTO team_mods (team_id, mod_id, perm, created_at, updated_at) VALUES (?, ?, ?, ?, ?)",
+		record.TeamID,
+		record.ModID,
+		record.Perm,
+		record.CreatedAt,
+		record.UpdatedAt,
+	)
+
+	return err
+}
+
+// Permit implements the store.TeamMods interface.
+func (r *teamMods) Permit(ctx context.Context, teamMod *model.TeamMod) error {
+	if !isUUID(teamMod.TeamID) || !isUUID(teamMod.ModID) {
+		return store.ErrNotFound
+	}
+
+	res, err := r.s.exec(
+		ctx,
+		"UPDATE team_mods SET perm = ?, updated_at = ? WHERE team_id = ? AND mod_id = ?",
+		teamMod.Perm,
+		now(),
+		teamMod.TeamID,
+		teamMod.ModID,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return affected(res)
+}
+
+// Drop implements the store.TeamMods interface.
+func (r *teamMods) Drop(ctx context.Context, teamID, modID string) error {
+	if !isUUID(teamID) || !isUUID(modID) {
+		return store.ErrNotFound
+	}
+
+	res, err := r.s.exec(
+		ctx,
+		"DELETE FROM team_mods WHERE team_id = ? AND mod_id = ?",
+		teamID,
+		modID,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return affected(res)
+}
+
+// list fetches the assignments including both sides.
+func (r *teamMods) list(ctx context.Context, cond, order string, args ...interface{}) ([]*model.TeamMod, error) {
+	rows, err := r.s.query(
+		ctx,
+		"SELECT a.perm, a.created_at, a.updated_at, "+columns("t", teamColumns)+", "+columns("m", modColumns)+
+			" FROM team_mods a"+
+			" INNER JOIN teams t ON t.id = a.team_id"+
+			" INNER JOIN mods m ON m.id = a.mod_id"+
+			" WHERE "+cond+" ORDER BY "+order,
+		args...,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	records := make([]*model.TeamMod, 0)
+
+	for rows.Next() {
+		record := &model.TeamMod{
+			Team: &model.Team{},
+			Mod:  &model.Mod{},
+		}
+
+		fields := []interface{}{
+			&record.Perm,
+			timestamp{&record.CreatedAt},
+			timestamp{&record.UpdatedAt},
+		}
+
+		fields = append(fields, teamFields(record.Team)...)
+		fields = append(fields, modFields(record.Mod)...)
+
+		if err := rows.Scan(fields...); err != nil {
+			return nil, err
+		}
+
+		record.TeamID = record.Team.ID
+		record.ModID = record.Mod.ID
+
+		records = append(records, record)
+	}
+
+	return records, r.s.wrap(rows.Err())
+}
@@ -0,0 +1,153 @@
+package sqlstore
+
+import (
+	"context"
+
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+type teamPacks struct {
+	s *Store
+}
+
+// ListByTeam implements the store.TeamPacks interface.
+func (r *teamPacks) ListByTeam(ctx context.Context, teamID string) ([]*model.TeamPack, error) {
+	if err := r.s.exists(ctx, "teams", teamID); err != nil {
+		return nil, err
+	}
+
+	return r.list(ctx, "a.team_id = ?", "p.name, p.slug", teamID)
+}
+
+// ListByPack implements the store.TeamPacks interface.
+func (r *teamPacks) ListByPack(ctx context.Context, packID string) ([]*model.TeamPack, error) {
+	if err := r.s.exists(ctx, "packs", packID); err != nil {
+		return nil, err
+	}
+
+	return r.list(ctx, "a.pack_id = ?", "t.name, t.slug", packID)
+}
+
+// Append implements the store.TeamPacks interface.
+func (r *teamPacks) Append(ctx context.Context, teamPack *model.TeamPack) error {
+	record := *teamPack
+	current := now()
+
+	if !isUUID(record.TeamID) || !isUUID(record.PackID) {
+		return store.ErrNotFound
+	}
+
+	if record.Perm == "" {
+		record.Perm = model.PermUser
+	}
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = current
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = current
+	}
+
+	_, err := r.s.exec(
+		ctx,
+		"INSERT INTO team_packs (team_id, pack_id, perm, created_at, updated_at) VALUES (?, ?, ?, ?, ?)",
+		record.TeamID,
+		record.PackID,
+		record.Perm,
+		record.CreatedAt,
+		record.UpdatedAt,
+	)
+
+	return err
+}
+
+// Permit implements the store.TeamPacks interface.
+func (r *teamPacks) Permit(ctx context.Context, teamPack *model.TeamPack) error {
+	if !isUUID(teamPack.TeamID) || !isUUID(teamPack.PackID) {
+		return store.ErrNotFound
+	}
+
+	res, err := r.s.exec(
+		ctx,
+		"UPDATE team_packs SET perm = ?, updated_at = ? WHERE team_id = ? AND pack_id = ?",
+		teamPack.Perm,
+		now(),
+		teamPack.TeamID,
+		teamPack.PackID,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return affected(res)
+}
+
+// Drop implements the store.TeamPacks interface.
+func (r *teamPacks) Drop(ctx context.Context, teamID, packID string) error {
+	if !isUUID(teamID) || !isUUID(packID) {
+		return store.ErrNotFound
+	}
+
+	res, err := r.s.exec(
+		ctx,
+		"DELETE FROM team_packs WHERE team_id = ? AND pack_id = ?",
+		teamID,
+		packID,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return affected(res)
+}
+
+// list fetches the assignments including both sides.
+func (r *teamPacks) list(ctx context.Context, cond, order string, args ...interface{}) ([]*model.TeamPack, error) {
+	rows, err := r.s.query(
+		ctx,
+		"SELECT a.perm, a.created_at, a.updated_at, "+columns("t", teamColumns)+", "+columns("p", packColumns)+
+			" FROM team_packs a"+
+			" INNER JOIN teams t ON t.id = a.team_id"+
+			" INNER JOIN packs p ON p.id = a.pack_id"+
+			" WHERE "+cond+" ORDER BY "+order,
+		args...,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	records := make([]*model.TeamPack, 0)
+
+	for rows.Next() {
+		record := &model.TeamPack{
+			Team: &model.Team{},
+			Pack: &model.Pack{},
+		}
+
+		fields := []interface{}{
+			&record.Perm,
+			timestamp{&record.CreatedAt},
+			timestamp{&record.UpdatedAt},
+		}
+
+		fields = append(fields, teamFields(record.Team)...)
+		fields = append(fields, packFields(record.Pack)...)
+
+		if err := rows.Scan(fields...); err != nil {
+			return nil, err
+		}
+
+		record.TeamID = record.Team.ID
+		record.PackID = record.Pack.ID
+
+		records = append(records, record)
+	}
+
+	return records, r.s.wrap(rows.Err())
+}
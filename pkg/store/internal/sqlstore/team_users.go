@@ -0,0 +1,153 @@
+package sqlstore
+
+import (
+	"context"
+
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+type teamUsers struct {
+	s *Store
+}
+
+// ListByTeam implements the store.TeamUsers interface.
+func (r *teamUsers) ListByTeam(ctx context.Context, teamID string) ([]*model.TeamUser, error) {
+	if err := r.s.exists(ctx, "teams", teamID); err != nil {
+		return nil, err
+	}
+
+	return r.list(ctx, "a.team_id = ?", "u.username, u.slug", teamID)
+}
+
+// ListByUser implements the store.TeamUsers interface.
+func (r *teamUsers) ListByUser(ctx context.Context, userID string) ([]*model.TeamUser, error) {
+	if err := r.s.exists(ctx, "users", userID); err != nil {
+		return nil, err
+	}
+
+	return r.list(ctx, "a.user_id = ?", "t.name, t.slug", userID)
+}
+
+// Append implements the store.TeamUsers interface.
+func (r *teamUsers) Append(ctx context.Context, teamUser *model.TeamUser) error {
+	record := *teamUser
+	current := now()
+
+	if !isUUID(record.TeamID) || !isUUID(record.UserID) {
+		return store.ErrNotFound
+	}
+
+	if record.Perm == "" {
+		record.Perm = model.PermUser
+	}
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = current
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = current
+	}
+
+	_, err := r.s.exec(
+		ctx,
+		"INSERT INTO team_users (team_id, user_id, perm, created_at, updated_at) VALUES (?, ?, ?, ?, ?)",
+		record.TeamID,
+		record.UserID,
+		record.Perm,
+		record.CreatedAt,
+		record.UpdatedAt,
+	)
+
+	return err
+}
+
+// Permit implements the store.TeamUsers interface.
+func (r *teamUsers) Permit(ctx context.Context, teamUser *model.TeamUser) error {
+	if !isUUID(teamUser.TeamID) || !isUUID(teamUser.UserID) {
+		return store.ErrNotFound
+	}
+
+	res, err := r.s.exec(
+		ctx,
+		"UPDATE team_users SET perm = ?, updated_at = ? WHERE team_id = ? AND user_id = ?",
+		teamUser.Perm,
+		now(),
+		teamUser.TeamID,
+		teamUser.UserID,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return affected(res)
+}
+
+// Drop implements the store.TeamUsers interface.
+func (r *teamUsers) Drop(ctx context.Context, teamID, userID string) error {
+	if !isUUID(teamID) || !isUUID(userID) {
+		return store.ErrNotFound
+	}
+
+	res, err := r.s.exec(
+		ctx,
+		"DELETE FROM team_users WHERE team_id = ? AND user_id = ?",
+		teamID,
+		userID,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return affected(res)
+}
+
+// list fetches the assignments including both sides.
+func (r *teamUsers) list(ctx context.Context, cond, order string, args ...interface{}) ([]*model.TeamUser, error) {
+	rows, err := r.s.query(
+		ctx,
+		"SELECT a.perm, a.created_at, a.updated_at, "+columns("t", teamColumns)+", "+columns("u", userColumns)+
+			" FROM team_users a"+
+			" INNER JOIN teams t ON t.id = a.team_id"+
+			" INNER JOIN users u ON u.id = a.user_id"+
+			" WHERE "+cond+" ORDER BY "+order,
+		args...,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	records := make([]*model.TeamUser, 0)
+
+	for rows.Next() {
+		record := &model.TeamUser{
+			Team: &model.Team{},
+			User: &model.User{},
+		}
+
+		fields := []interface{}{
+			&record.Perm,
+			timestamp{&record.CreatedAt},
+			timestamp{&record.UpdatedAt},
+		}
+
+		fields = append(fields, teamFields(record.Team)...)
+		fields = append(fields, userFields(record.User)...)
+
+		if err := rows.Scan(fields...); err != nil {
+			return nil, err
+		}
+
+		record.TeamID = record.Team.ID
+		record.UserID = record.User.ID
+
+		records = append(records, record)
+	}
+
+	return records, r.s.wrap(rows.Err())
+}
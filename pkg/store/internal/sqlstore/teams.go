@@ -0,0 +1,174 @@
+package sqlstore
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+var teamColumns = []string{
+	"id",
+	"slug",
+	"name",
+	"quota",
+	"created_at",
+	"updated_at",
+}
+
+type teams struct {
+	s *Store
+}
+
+// List implements the store.Teams interface.
+func (r *teams) List(ctx context.Context) ([]*model.Team, error) {
+	rows, err := r.s.query(
+		ctx,
+		"SELECT "+columns("", teamColumns)+" FROM teams ORDER BY name, slug",
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	records := make([]*model.Team, 0)
+
+	for rows.Next() {
+		record := &model.Team{}
+
+		if err := rows.Scan(teamFields(record)...); err != nil {
+			return nil, err
+		}
+
+		records = append(records, record)
+	}
+
+	return records, r.s.wrap(rows.Err())
+}
+
+// Show implements the store.Teams interface.
+func (r *teams) Show(ctx context.Context, id string) (*model.Team, error) {
+	cond, args := lookup(id)
+	record := &model.Team{}
+
+	if err := r.s.row(
+		ctx,
+		"SELECT "+columns("", teamColumns)+" FROM teams WHERE "+cond,
+		args,
+		teamFields(record)...,
+	); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// Create implements the store.Teams interface.
+func (r *teams) Create(ctx context.Context, team *model.Team) (*model.Team, error) {
+	record := *team
+	current := now()
+
+	if record.ID == "" {
+		record.ID = uuid.New().String()
+	}
+
+	record.Slug = store.SlugOrDefault(record.Slug, record.Name, record.ID)
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = current
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = current
+	}
+
+	if _, err := r.s.exec(
+		ctx,
+		"INSERT INTO teams ("+columns("", teamColumns)+") VALUES ("+placeholders(teamColumns)+")",
+		teamValues(&record)...,
+	); err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Update implements the store.Teams interface.
+func (r *teams) Update(ctx context.Context, team *model.Team) (*model.Team, error) {
+	record := *team
+
+	err := r.s.transact(ctx, func(s *Store) error {
+		if !isUUID(record.ID) {
+			return store.ErrNotFound
+		}
+
+		current, err := s.Teams().Show(ctx, record.ID)
+
+		if err != nil {
+			return err
+		}
+
+		record.Slug = store.SlugOrDefault(record.Slug, record.Name, current.Slug)
+		record.CreatedAt = current.CreatedAt
+		record.UpdatedAt = now()
+
+		_, err = s.exec(
+			ctx,
+			"UPDATE teams SET "+assignments(teamColumns[1:])+" WHERE id = ?",
+			append(teamValues(&record)[1:], record.ID)...,
+		)
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Delete implements the store.Teams interface.
+func (r *teams) Delete(ctx context.Context, id string) error {
+	return r.s.transact(ctx, func(s *Store) error {
+		record, err := s.Teams().Show(ctx, id)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = s.exec(
+			ctx,
+			"DELETE FROM teams WHERE id = ?",
+			record.ID,
+		)
+
+		return err
+	})
+}
+
+// teamFields returns the scan destinations for the team columns.
+func teamFields(record *model.Team) []interface{} {
+	return []interface{}{
+		&record.ID,
+		&record.Slug,
+		&record.Name,
+		&record.Quota,
+		timestamp{&record.CreatedAt},
+		timestamp{&record.UpdatedAt},
+	}
+}
+
+// teamValues returns the values for the team columns.
+func teamValues(record *model.Team) []interface{} {
+	return []interface{}{
+		record.ID,
+		record.Slug,
+		record.Name,
+		record.Quota,
+		record.CreatedAt,
+		record.UpdatedAt,
+	}
+}
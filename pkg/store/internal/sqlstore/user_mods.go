@@ -0,0 +1,153 @@
+package sqlstore
+
+import (
+	"context"
+
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+type userMods struct {
+	s *Store
+}
+
+// ListByUser implements the store.UserMods interface.
+func (r *userMods) ListByUser(ctx context.Context, userID string) ([]*model.UserMod, error) {
+	if err := r.s.exists(ctx, "users", userID); err != nil {
+		return nil, err
+	}
+
+	return r.list(ctx, "a.user_id = ?", "m.name, m.slug", userID)
+}
+
+// ListByMod implements the store.UserMods interface.
+func (r *userMods) ListByMod(ctx context.Context, modID string) ([]*model.UserMod, error) {
+	if err := r.s.exists(ctx, "mods", modID); err != nil {
+		return nil, err
+	}
+
+	return r.list(ctx, "a.mod_id = ?", "u.username, u.slug", modID)
+}
+
+// Append implements the store.UserMods interface.
+func (r *userMods) Append(ctx context.Context, userMod *model.UserMod) error {
+	record := *userMod
+	current := now()
+
+	if !isUUID(record.UserID) || !isUUID(record.ModID) {
+		return store.ErrNotFound
+	}
+
+	if record.Perm == "" {
+		record.Perm = model.PermUser
+	}
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = current
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = current
+	}
+
+	_, err := r.s.exec(
+		ctx,
+		"INSERT INTO user_mods (user_id, mod_id, perm, created_at, updated_at) VALUES (?, ?, ?, ?, ?)",
+		record.UserID,
+		record.ModID,
+		record.Perm,
+		record.CreatedAt,
+		record.UpdatedAt,
+	)
+
+	return err
+}
+
+// Permit implements the store.UserMods interface.
+func (r *userMods) Permit(ctx context.Context, userMod *model.UserMod) error {
+	if !isUUID(userMod.UserID) || !isUUID(userMod.ModID) {
+		return store.ErrNotFound
+	}
+
+	res, err := r.s.exec(
+		ctx,
+		"UPDATE user_mods SET perm = ?, updated_at = ? WHERE user_id = ? AND mod_id = ?",
+		userMod.Perm,
+		now(),
+		userMod.UserID,
+		userMod.ModID,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return affected(res)
+}
+
+// Drop implements the store.UserMods interface.
+func (r *userMods) Drop(ctx context.Context, userID, modID string) error {
+	if !isUUID(userID) || !isUUID(modID) {
+		return store.ErrNotFound
+	}
+
+	res, err := r.s.exec(
+		ctx,
+		"DELETE FROM user_mods WHERE user_id = ? AND mod_id = ?",
+		userID,
+		modID,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return affected(res)
+}
+
+// list fetches the assignments including both sides.
+func (r *userMods) list(ctx context.Context, cond, order string, args ...interface{}) ([]*model.UserMod, error) {
+	rows, err := r.s.query(
+		ctx,
+		"SELECT a.perm, a.created_at, a.updated_at, "+columns("u", userColumns)+", "+columns("m", modColumns)+
+			" FROM user_mods a"+
+			" INNER JOIN users u ON u.id = a.user_id"+
+			" INNER JOIN mods m ON m.id = a.mod_id"+
+			" WHERE "+cond+" ORDER BY "+order,
+		args...,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	records := make([]*model.UserMod, 0)
+
+	for rows.Next() {
+		record := &model.UserMod{
+			User: &model.User{},
+			Mod:  &model.Mod{},
+		}
+
+		fields := []interface{}{
+			&record.Perm,
+			timestamp{&record.CreatedAt},
+			timestamp{&record.UpdatedAt},
+		}
+
+		fields = append(fields, userFields(record.User)...)
+		fields = append(fields, modFields(record.Mod)...)
+
+		if err := rows.Scan(fields...); err != nil {
+			return nil, err
+		}
+
+		record.UserID = record.User.ID
+		record.ModID = record.Mod.ID
+
+		records = append(records, record)
+	}
+
+	return records, r.s.wrap(rows.Err())
+}
@@ -0,0 +1,153 @@
+package sqlstore
+
+import (
+	"context"
+
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+type userPacks struct {
+	s *Store
+}
+
+// ListByUser implements the store.UserPacks interface.
+func (r *userPacks) ListByUser(ctx context.Context, userID string) ([]*model.UserPack, error) {
+	if err := r.s.exists(ctx, "users", userID); err != nil {
+		return nil, err
+	}
+
+	return r.list(ctx, "a.user_id = ?", "p.name, p.slug", userID)
+}
+
+// ListByPack implements the store.UserPacks interface.
+func (r *userPacks) ListByPack(ctx context.Context, packID string) ([]*model.UserPack, error) {
+	if err := r.s.exists(ctx, "packs", packID); err != nil {
+		return nil, err
+	}
+
+	return r.list(ctx, "a.pack_id = ?", "u.username, u.slug", packID)
+}
+
+// Append implements the store.UserPacks interface.
+func (r *userPacks) Append(ctx context.Context, userPack *model.UserPack) error {
+	record := *userPack
+	current := now()
+
+	if !isUUID(record.UserID) || !isUUID(record.PackID) {
+		return store.ErrNotFound
+	}
+
+	if record.Perm == "" {
+		record.Perm = model.PermUser
+	}
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = current
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = current
+	}
+
+	_, err := r.s.exec(
+		ctx,
+		"INSERT INTO user_packs (user_id, pack_id, perm, created_at, updated_at) VALUES (?, ?, ?, ?, ?)",
+		record.UserID,
+		record.PackID,
+		record.Perm,
+		record.CreatedAt,
+		record.UpdatedAt,
+	)
+
+	return err
+}
+
+// Permit implements the store.UserPacks interface.
+func (r *userPacks) Permit(ctx context.Context, userPack *model.UserPack) error {
+	if !isUUID(userPack.UserID) || !isUUID(userPack.PackID) {
+		return store.ErrNotFound
+	}
+
+	res, err := r.s.exec(
+		ctx,
+		"UPDATE user_packs SET perm = ?, updated_at = ? WHERE user_id = ? AND pack_id = ?",
+		userPack.Perm,
+		now(),
+		userPack.UserID,
+		userPack.PackID,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return affected(res)
+}
+
+// Drop implements the store.UserPacks interface.
+func (r *userPacks) Drop(ctx context.Context, userID, packID string) error {
+	if !isUUID(userID) || !isUUID(packID) {
+		return store.ErrNotFound
+	}
+
+	res, err := r.s.exec(
+		ctx,
+		"DELETE FROM user_packs WHERE user_id = ? AND pack_id = ?",
+		userID,
+		packID,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return affected(res)
+}
+
+// list fetches the assignments including both sides.
+func (r *userPacks) list(ctx context.Context, cond, order string, args ...interface{}) ([]*model.UserPack, error) {
+	rows, err := r.s.query(
+		ctx,
+		"SELECT a.perm, a.created_at, a.updated_at, "+columns("u", userColumns)+", "+columns("p", packColumns)+
+			" FROM user_packs a"+
+			" INNER JOIN users u ON u.id = a.user_id"+
+			" INNER JOIN packs p ON p.id = a.pack_id"+
+			" WHERE "+cond+" ORDER BY "+order,
+		args...,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	records := make([]*model.UserPack, 0)
+
+	for rows.Next() {
+		record := &model.UserPack{
+			User: &model.User{},
+			Pack: &model.Pack{},
+		}
+
+		fields := []interface{}{
+			&record.Perm,
+			timestamp{&record.CreatedAt},
+			timestamp{&record.UpdatedAt},
+		}
+
+		fields = append(fields, userFields(record.User)...)
+		fields = append(fields, packFields(record.Pack)...)
+
+		if err := rows.Scan(fields...); err != nil {
+			return nil, err
+		}
+
+		record.UserID = record.User.ID
+		record.PackID = record.Pack.ID
+
+		records = append(records, record)
+	}
+
+	return records, r.s.wrap(rows.Err())
+}
@@ -0,0 +1,186 @@
+package sqlstore
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+var userColumns = []string{
+	"id",
+	"slug",
+	"username",
+	"password",
+	"email",
+	"admin",
+	"active",
+	"quota",
+	"created_at",
+	"updated_at",
+}
+
+type users struct {
+	s *Store
+}
+
+// List implements the store.Users interface.
+func (r *users) List(ctx context.Context) ([]*model.User, error) {
+	rows, err := r.s.query(
+		ctx,
+		"SELECT "+columns("", userColumns)+" FROM users ORDER BY username, slug",
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	records := make([]*model.User, 0)
+
+	for rows.Next() {
+		record := &model.User{}
+
+		if err := rows.Scan(userFields(record)...); err != nil {
+			return nil, err
+		}
+
+		records = append(records, record)
+	}
+
+	return records, r.s.wrap(rows.Err())
+}
+
+// Show implements the store.Users interface.
+func (r *users) Show(ctx context.Context, id string) (*model.User, error) {
+	cond, args := lookup(id)
+	record := &model.User{}
+
+	if err := r.s.row(
+		ctx,
+		"SELECT "+columns("", userColumns)+" FROM users WHERE "+cond,
+		args,
+		userFields(record)...,
+	); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// Create implements the store.Users interface.
+func (r *users) Create(ctx context.Context, user *model.User) (*model.User, error) {
+	record := *user
+	current := now()
+
+	if record.ID == "" {
+		record.ID = uuid.New().String()
+	}
+
+	record.Slug = store.SlugOrDefault(record.Slug, record.Username, record.ID)
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = current
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = current
+	}
+
+	if _, err := r.s.exec(
+		ctx,
+		"INSERT INTO users ("+columns("", userColumns)+") VALUES ("+placeholders(userColumns)+")",
+		userValues(&record)...,
+	); err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Update implements the store.Users interface.
+func (r *users) Update(ctx context.Context, user *model.User) (*model.User, error) {
+	record := *user
+
+	err := r.s.transact(ctx, func(s *Store) error {
+		if !isUUID(record.ID) {
+			return store.ErrNotFound
+		}
+
+		current, err := s.Users().Show(ctx, record.ID)
+
+		if err != nil {
+			return err
+		}
+
+		record.Slug = store.SlugOrDefault(record.Slug, record.Username, current.Slug)
+		record.CreatedAt = current.CreatedAt
+		record.UpdatedAt = now()
+
+		_, err = s.exec(
+			ctx,
+			"UPDATE users SET "+assignments(userColumns[1:])+" WHERE id = ?",
+			append(userValues(&record)[1:], record.ID)...,
+		)
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Delete implements the store.Users interface.
+func (r *users) Delete(ctx context.Context, id string) error {
+	return r.s.transact(ctx, func(s *Store) error {
+		record, err := s.Users().Show(ctx, id)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = s.exec(
+			ctx,
+			"DELETE FROM users WHERE id = ?",
+			record.ID,
+		)
+
+		return err
+	})
+}
+
+// userFields returns the scan destinations for the user columns.
+func userFields(record *model.User) []interface{} {
+	return []interface{}{
+		&record.ID,
+		&record.Slug,
+		&record.Username,
+		&record.Password,
+		&record.Email,
+		&record.Admin,
+		&record.Active,
+		&record.Quota,
+		timestamp{&record.CreatedAt},
+		timestamp{&record.UpdatedAt},
+	}
+}
+
+// userValues returns the values for the user columns.
+func userValues(record *model.User) []interface{} {
+	return []interface{}{
+		record.ID,
+		record.Slug,
+		record.Username,
+		record.Password,
+		record.Email,
+		record.Admin,
+		record.Active,
+		record.Quota,
+		record.CreatedAt,
+		record.UpdatedAt,
+	}
+}
@@ -0,0 +1,247 @@
+package sqlstore
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+var versionColumns = []string{
+	"id",
+	"mod_id",
+	"slug",
+	"name",
+	"file_key",
+	"file_name",
+	"file_size",
+	"file_md5",
+	"file_sha1",
+	"file_sha256",
+	"file_loader",
+	"file_minecraft",
+	"file_broken",
+	"created_at",
+	"updated_at",
+}
+
+type versions struct {
+	s *Store
+}
+
+// List implements the store.Versions interface.
+func (r *versions) List(ctx context.Context, modID string) ([]*model.Version, error) {
+	if err := r.s.exists(ctx, "mods", modID); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.s.query(
+		ctx,
+		"SELECT "+columns("", versionColumns)+" FROM versions WHERE mod_id = ? ORDER BY name, slug",
+		modID,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	records := make([]*model.Version, 0)
+
+	for rows.Next() {
+		record := &model.Version{}
+
+		if err := rows.Scan(versionFields(record)...); err != nil {
+			return nil, err
+		}
+
+		records = append(records, record)
+	}
+
+	return records, r.s.wrap(rows.Err())
+}
+
+// ListByFile implements the store.Versions interface.
+func (r *versions) ListByFile(ctx context.Context, key string) ([]*model.Version, error) {
+	records := make([]*model.Version, 0)
+
+	if key == "" {
+		return records, nil
+	}
+
+	rows, err := r.s.query(
+		ctx,
+		"SELECT "+columns("", versionColumns)+" FROM versions WHERE file_key = ? ORDER BY name, slug",
+		key,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		record := &model.Version{}
+
+		if err := rows.Scan(versionFields(record)...); err != nil {
+			return nil, err
+		}
+
+		records = append(records, record)
+	}
+
+	return records, r.s.wrap(rows.Err())
+}
+
+// Show implements the store.Versions interface.
+func (r *versions) Show(ctx context.Context, modID, id string) (*model.Version, error) {
+	if !isUUID(modID) {
+		return nil, store.ErrNotFound
+	}
+
+	cond, args := lookup(id)
+	record := &model.Version{}
+
+	if err := r.s.row(
+		ctx,
+		"SELECT "+columns("", versionColumns)+" FROM versions WHERE mod_id = ? AND "+cond,
+		append([]interface{}{modID}, args...),
+		versionFields(record)...,
+	); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// Create implements the store.Versions interface.
+func (r *versions) Create(ctx context.Context, version *model.Version) (*model.Version, error) {
+	record := *version
+	current := now()
+
+	if record.ID == "" {
+		record.ID = uuid.New().String()
+	}
+
+	record.Slug = store.SlugOrDefault(record.Slug, record.Name, record.ID)
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = current
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = current
+	}
+
+	if !isUUID(record.ModID) {
+		return nil, store.ErrNotFound
+	}
+
+	if _, err := r.s.exec(
+		ctx,
+		"INSERT INTO versions ("+columns("", versionColumns)+") VALUES ("+placeholders(versionColumns)+")",
+		versionValues(&record)...,
+	); err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Update implements the store.Versions interface.
+func (r *versions) Update(ctx context.Context, version *model.Version) (*model.Version, error) {
+	record := *version
+
+	err := r.s.transact(ctx, func(s *Store) error {
+		if !isUUID(record.ID) {
+			return store.ErrNotFound
+		}
+
+		current, err := s.Versions().Show(ctx, record.ModID, record.ID)
+
+		if err != nil {
+			return err
+		}
+
+		record.Slug = store.SlugOrDefault(record.Slug, record.Name, current.Slug)
+		record.CreatedAt = current.CreatedAt
+		record.UpdatedAt = now()
+
+		_, err = s.exec(
+			ctx,
+			"UPDATE versions SET "+assignments(versionColumns[1:])+" WHERE id = ?",
+			append(versionValues(&record)[1:], record.ID)...,
+		)
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Delete implements the store.Versions interface.
+func (r *versions) Delete(ctx context.Context, modID, id string) error {
+	return r.s.transact(ctx, func(s *Store) error {
+		record, err := s.Versions().Show(ctx, modID, id)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = s.exec(
+			ctx,
+			"DELETE FROM versions WHERE id = ?",
+			record.ID,
+		)
+
+		return err
+	})
+}
+
+// versionFields returns the scan destinations for the version columns.
+func versionFields(record *model.Version) []interface{} {
+	return []interface{}{
+		&record.ID,
+		&record.ModID,
+		&record.Slug,
+		&record.Name,
+		&record.FileKey,
+		&record.FileName,
+		&record.FileSize,
+		&record.FileMD5,
+		&record.FileSHA1,
+		&record.FileSHA256,
+		&record.FileLoader,
+		&record.FileMinecraft,
+		&record.FileBroken,
+		timestamp{&record.CreatedAt},
+		timestamp{&record.UpdatedAt},
+	}
+}
+
+// versionValues returns the values for the version columns.
+func versionValues(record *model.Version) []interface{} {
+	return []interface{}{
+		record.ID,
+		record.ModID,
+		record.Slug,
+		record.Name,
+		record.FileKey,
+		record.FileName,
+		record.FileSize,
+		record.FileMD5,
+		record.FileSHA1,
+		record.FileSHA256,
+		record.FileLoader,
+		record.FileMinecraft,
+		record.FileBroken,
+		record.CreatedAt,
+		record.UpdatedAt,
+	}
+}
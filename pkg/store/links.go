@@ -0,0 +1,24 @@
+package store
+
+import (
+	"context"
+
+	"github.com/kleister/kleister-api/pkg/model"
+)
+
+// Links provides the repository interface for logical upload paths.
+type Links interface {
+	// List returns all links ordered by path.
+	List(context.Context) ([]*model.Link, error)
+
+	// Show returns a single link by its path.
+	Show(context.Context, string) (*model.Link, error)
+
+	// Create persists a new link and increments the references of its blob,
+	// fails with ErrNotFound if the blob doesn't exist.
+	Create(context.Context, *model.Link) (*model.Link, error)
+
+	// Delete removes a link by its path and decrements the references of
+	// its blob, the blob is kept for the garbage collection.
+	Delete(context.Context, string) error
+}
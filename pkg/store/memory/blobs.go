@@ -0,0 +1,124 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+type blobs struct {
+	s *memory
+}
+
+// List implements the store.Blobs interface.
+func (r *blobs) List(ctx context.Context) ([]*model.Blob, error) {
+	records := make([]*model.Blob, 0)
+
+	err := r.s.view(func() error {
+		for _, blob := range r.s.blobs {
+			record := blob
+			records = append(records, &record)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Digest < records[j].Digest
+	})
+
+	return records, nil
+}
+
+// Show implements the store.Blobs interface.
+func (r *blobs) Show(ctx context.Context, digest string) (*model.Blob, error) {
+	var record model.Blob
+
+	err := r.s.view(func() error {
+		found, ok := r.s.blobs[digest]
+
+		if !ok {
+			return store.ErrNotFound
+		}
+
+		record = found
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Create implements the store.Blobs interface.
+func (r *blobs) Create(ctx context.Context, blob *model.Blob) (*model.Blob, error) {
+	record := *blob
+	now := time.Now().UTC()
+
+	record.References = 0
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = now
+	}
+
+	err := r.s.update(func() error {
+		if _, ok := r.s.blobs[record.Digest]; ok {
+			return store.ErrConflict
+		}
+
+		r.s.blobs[record.Digest] = record
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Delete implements the store.Blobs interface.
+func (r *blobs) Delete(ctx context.Context, digest string) error {
+	return r.s.update(func() error {
+		record, ok := r.s.blobs[digest]
+
+		if !ok {
+			return store.ErrNotFound
+		}
+
+		if record.References > 0 {
+			return store.ErrConflict
+		}
+
+		delete(r.s.blobs, digest)
+		return nil
+	})
+}
+
+// reference adjusts the reference counter of a blob.
+func (s *memory) reference(digest string, delta int) error {
+	record, ok := s.blobs[digest]
+
+	if !ok {
+		return store.ErrNotFound
+	}
+
+	record.References += delta
+	record.UpdatedAt = time.Now().UTC()
+
+	s.blobs[digest] = record
+	return nil
+}
@@ -0,0 +1,133 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+type buildVersions struct {
+	s *memory
+}
+
+// ListByBuild implements the store.BuildVersions interface.
+func (r *buildVersions) ListByBuild(ctx context.Context, buildID string) ([]*model.BuildVersion, error) {
+	records := make([]*model.BuildVersion, 0)
+
+	err := r.s.view(func() error {
+		if _, ok := r.s.builds[buildID]; !ok {
+			return store.ErrNotFound
+		}
+
+		for key := range r.s.buildVersions {
+			if key.left == buildID {
+				records = append(records, r.s.loadBuildVersion(key))
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Version.Name < records[j].Version.Name
+	})
+
+	return records, nil
+}
+
+// ListByVersion implements the store.BuildVersions interface.
+func (r *buildVersions) ListByVersion(ctx context.Context, versionID string) ([]*model.BuildVersion, error) {
+	records := make([]*model.BuildVersion, 0)
+
+	err := r.s.view(func() error {
+		if _, ok := r.s.versions[versionID]; !ok {
+			return store.ErrNotFound
+		}
+
+		for key := range r.s.buildVersions {
+			if key.right == versionID {
+				records = append(records, r.s.loadBuildVersion(key))
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Build.Name < records[j].Build.Name
+	})
+
+	return records, nil
+}
+
+// Append implements the store.BuildVersions interface.
+func (r *buildVersions) Append(ctx context.Context, buildVersion *model.BuildVersion) error {
+	record := *buildVersion
+	now := time.Now().UTC()
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = now
+	}
+
+	return r.s.update(func() error {
+		_, build := r.s.builds[record.BuildID]
+		_, version := r.s.versions[record.VersionID]
+
+		if !build || !version {
+			return store.ErrNotFound
+		}
+
+		key := pair{left: record.BuildID, right: record.VersionID}
+
+		if _, ok := r.s.buildVersions[key]; ok {
+			return store.ErrConflict
+		}
+
+		record.Build = nil
+		record.Version = nil
+
+		r.s.buildVersions[key] = record
+		return nil
+	})
+}
+
+// Drop implements the store.BuildVersions interface.
+func (r *buildVersions) Drop(ctx context.Context, buildID, versionID string) error {
+	return r.s.update(func() error {
+		key := pair{left: buildID, right: versionID}
+
+		if _, ok := r.s.buildVersions[key]; !ok {
+			return store.ErrNotFound
+		}
+
+		delete(r.s.buildVersions, key)
+		return nil
+	})
+}
+
+// loadBuildVersion copies an assignment including both sides.
+func (s *memory) loadBuildVersion(key pair) *model.BuildVersion {
+	record := s.buildVersions[key]
+	build := s.builds[key.left]
+	version := s.versions[key.right]
+
+	record.Build = &build
+	record.Version = &version
+
+	return &record
+}
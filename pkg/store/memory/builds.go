@@ -0,0 +1,226 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+type builds struct {
+	s *memory
+}
+
+// List implements the store.Builds interface.
+func (r *builds) List(ctx context.Context, packID string) ([]*model.Build, error) {
+	records := make([]*model.Build, 0)
+
+	err := r.s.view(func() error {
+		if _, ok := r.s.packs[packID]; !ok {
+			return store.ErrNotFound
+		}
+
+		for _, build := range r.s.builds {
+			if build.PackID != packID {
+				continue
+			}
+
+			record := build
+			records = append(records, &record)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Name < records[j].Name
+	})
+
+	return records, nil
+}
+
+// Show implements the store.Builds interface.
+func (r *builds) Show(ctx context.Context, packID, id string) (*model.Build, error) {
+	var record model.Build
+
+	err := r.s.view(func() error {
+		found, err := r.s.findBuild(packID, id)
+		record = found
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Create implements the store.Builds interface.
+func (r *builds) Create(ctx context.Context, build *model.Build) (*model.Build, error) {
+	record := *build
+	now := time.Now().UTC()
+
+	if record.ID == "" {
+		record.ID = uuid.New().String()
+	}
+
+	record.Slug = store.SlugOrDefault(record.Slug, record.Name, record.ID)
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = now
+	}
+
+	err := r.s.update(func() error {
+		if _, ok := r.s.builds[record.ID]; ok {
+			return store.ErrConflict
+		}
+
+		if err := r.s.checkBuild(record); err != nil {
+			return err
+		}
+
+		if r.s.buildSlugTaken(record.PackID, record.ID, record.Slug) {
+			return store.ErrConflict
+		}
+
+		r.s.builds[record.ID] = record
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Update implements the store.Builds interface.
+func (r *builds) Update(ctx context.Context, build *model.Build) (*model.Build, error) {
+	record := *build
+
+	err := r.s.update(func() error {
+		current, ok := r.s.builds[record.ID]
+
+		if !ok || current.PackID != record.PackID {
+			return store.ErrNotFound
+		}
+
+		record.Slug = store.SlugOrDefault(record.Slug, record.Name, current.Slug)
+		record.CreatedAt = current.CreatedAt
+		record.UpdatedAt = time.Now().UTC()
+
+		if err := r.s.checkBuild(record); err != nil {
+			return err
+		}
+
+		if r.s.buildSlugTaken(record.PackID, record.ID, record.Slug) {
+			return store.ErrConflict
+		}
+
+		r.s.builds[record.ID] = record
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Delete implements the store.Builds interface.
+func (r *builds) Delete(ctx context.Context, packID, id string) error {
+	return r.s.update(func() error {
+		record, err := r.s.findBuild(packID, id)
+
+		if err != nil {
+			return err
+		}
+
+		r.s.deleteBuild(record)
+		return nil
+	})
+}
+
+// findBuild resolves a build of a pack by ID or slug.
+func (s *memory) findBuild(packID, id string) (model.Build, error) {
+	if record, ok := s.builds[id]; ok && record.PackID == packID {
+		return record, nil
+	}
+
+	if isUUID(id) {
+		return model.Build{}, store.ErrNotFound
+	}
+
+	for _, record := range s.builds {
+		if record.PackID == packID && record.Slug == id {
+			return record, nil
+		}
+	}
+
+	return model.Build{}, store.ErrNotFound
+}
+
+// buildSlugTaken checks if the slug is used by another build of the pack.
+func (s *memory) buildSlugTaken(packID, id, slug string) bool {
+	for _, record := range s.builds {
+		if record.PackID == packID && record.Slug == slug && record.ID != id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkBuild verifies that all references of the build exist.
+func (s *memory) checkBuild(record model.Build) error {
+	if _, ok := s.packs[record.PackID]; !ok {
+		return store.ErrNotFound
+	}
+
+	if _, ok := s.minecrafts[record.MinecraftID]; record.MinecraftID != "" && !ok {
+		return store.ErrNotFound
+	}
+
+	if _, ok := s.forges[record.ForgeID]; record.ForgeID != "" && !ok {
+		return store.ErrNotFound
+	}
+
+	return nil
+}
+
+// deleteBuild removes a build including its assignments and detaches it
+// from the recommended and latest markers of the pack.
+func (s *memory) deleteBuild(record model.Build) {
+	for key := range s.buildVersions {
+		if key.left == record.ID {
+			delete(s.buildVersions, key)
+		}
+	}
+
+	if pack, ok := s.packs[record.PackID]; ok {
+		if pack.RecommendedID == record.ID {
+			pack.RecommendedID = ""
+		}
+
+		if pack.LatestID == record.ID {
+			pack.LatestID = ""
+		}
+
+		s.packs[pack.ID] = pack
+	}
+
+	delete(s.builds, record.ID)
+}
@@ -0,0 +1,28 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/kleister/kleister-api/pkg/store"
+	"github.com/pkg/errors"
+)
+
+// load seeds the store with the records of a JSON fixture, the fixture uses
+// the same format like the database dump of backups.
+func (s *memory) load(ctx context.Context, name string) error {
+	content, err := ioutil.ReadFile(name)
+
+	if err != nil {
+		return err
+	}
+
+	d := &store.Dump{}
+
+	if err := json.Unmarshal(content, d); err != nil {
+		return errors.Wrap(err, "failed to parse fixture")
+	}
+
+	return store.Import(ctx, d, s)
+}
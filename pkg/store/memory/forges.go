@@ -0,0 +1,181 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+type forges struct {
+	s *memory
+}
+
+// List implements the store.Forges interface.
+func (r *forges) List(ctx context.Context) ([]*model.Forge, error) {
+	records := make([]*model.Forge, 0)
+
+	err := r.s.view(func() error {
+		for _, forge := range r.s.forges {
+			record := forge
+			records = append(records, &record)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Name < records[j].Name
+	})
+
+	return records, nil
+}
+
+// Show implements the store.Forges interface.
+func (r *forges) Show(ctx context.Context, id string) (*model.Forge, error) {
+	var record model.Forge
+
+	err := r.s.view(func() error {
+		found, err := r.s.findForge(id)
+		record = found
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Create implements the store.Forges interface.
+func (r *forges) Create(ctx context.Context, forge *model.Forge) (*model.Forge, error) {
+	record := *forge
+	now := time.Now().UTC()
+
+	if record.ID == "" {
+		record.ID = uuid.New().String()
+	}
+
+	record.Slug = store.SlugOrDefault(record.Slug, record.Name, record.ID)
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = now
+	}
+
+	err := r.s.update(func() error {
+		if _, ok := r.s.forges[record.ID]; ok {
+			return store.ErrConflict
+		}
+
+		if r.s.forgeSlugTaken(record.ID, record.Slug) {
+			return store.ErrConflict
+		}
+
+		r.s.forges[record.ID] = record
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Update implements the store.Forges interface.
+func (r *forges) Update(ctx context.Context, forge *model.Forge) (*model.Forge, error) {
+	record := *forge
+
+	err := r.s.update(func() error {
+		current, ok := r.s.forges[record.ID]
+
+		if !ok {
+			return store.ErrNotFound
+		}
+
+		record.Slug = store.SlugOrDefault(record.Slug, record.Name, current.Slug)
+		record.CreatedAt = current.CreatedAt
+		record.UpdatedAt = time.Now().UTC()
+
+		if r.s.forgeSlugTaken(record.ID, record.Slug) {
+			return store.ErrConflict
+		}
+
+		r.s.forges[record.ID] = record
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Delete implements the store.Forges interface.
+func (r *forges) Delete(ctx context.Context, id string) error {
+	return r.s.update(func() error {
+		record, err := r.s.findForge(id)
+
+		if err != nil {
+			return err
+		}
+
+		r.s.deleteForge(record)
+		return nil
+	})
+}
+
+// findForge resolves a forge by ID or slug.
+func (s *memory) findForge(id string) (model.Forge, error) {
+	if record, ok := s.forges[id]; ok {
+		return record, nil
+	}
+
+	if isUUID(id) {
+		return model.Forge{}, store.ErrNotFound
+	}
+
+	for _, record := range s.forges {
+		if record.Slug == id {
+			return record, nil
+		}
+	}
+
+	return model.Forge{}, store.ErrNotFound
+}
+
+// forgeSlugTaken checks if the slug is used by another forge.
+func (s *memory) forgeSlugTaken(id, slug string) bool {
+	for _, record := range s.forges {
+		if record.Slug == slug && record.ID != id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// deleteForge removes a Forge version and detaches it from builds.
+func (s *memory) deleteForge(record model.Forge) {
+	for id, build := range s.builds {
+		if build.ForgeID == record.ID {
+			build.ForgeID = ""
+			s.builds[id] = build
+		}
+	}
+
+	delete(s.forges, record.ID)
+}
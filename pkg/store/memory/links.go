@@ -0,0 +1,111 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+type links struct {
+	s *memory
+}
+
+// List implements the store.Links interface.
+func (r *links) List(ctx context.Context) ([]*model.Link, error) {
+	records := make([]*model.Link, 0)
+
+	err := r.s.view(func() error {
+		for _, link := range r.s.links {
+			record := link
+			records = append(records, &record)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Path < records[j].Path
+	})
+
+	return records, nil
+}
+
+// Show implements the store.Links interface.
+func (r *links) Show(ctx context.Context, path string) (*model.Link, error) {
+	var record model.Link
+
+	err := r.s.view(func() error {
+		found, ok := r.s.links[path]
+
+		if !ok {
+			return store.ErrNotFound
+		}
+
+		record = found
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Create implements the store.Links interface.
+func (r *links) Create(ctx context.Context, link *model.Link) (*model.Link, error) {
+	record := *link
+	now := time.Now().UTC()
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = now
+	}
+
+	err := r.s.update(func() error {
+		if _, ok := r.s.links[record.Path]; ok {
+			return store.ErrConflict
+		}
+
+		if err := r.s.reference(record.Digest, 1); err != nil {
+			return err
+		}
+
+		r.s.links[record.Path] = record
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Delete implements the store.Links interface.
+func (r *links) Delete(ctx context.Context, path string) error {
+	return r.s.update(func() error {
+		record, ok := r.s.links[path]
+
+		if !ok {
+			return store.ErrNotFound
+		}
+
+		if err := r.s.reference(record.Digest, -1); err != nil {
+			return err
+		}
+
+		delete(r.s.links, path)
+		return nil
+	})
+}
@@ -0,0 +1,364 @@
+package memory
+
+import (
+	"context"
+	"net/url"
+	"path"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+	"github.com/pkg/errors"
+)
+
+// pair defines the key of an assignment between two records.
+type pair struct {
+	left  string
+	right string
+}
+
+type memory struct {
+	dsn  *url.URL
+	lock *sync.RWMutex
+	tx   bool
+
+	users      map[string]model.User
+	teams      map[string]model.Team
+	packs      map[string]model.Pack
+	builds     map[string]model.Build
+	mods       map[string]model.Mod
+	versions   map[string]model.Version
+	minecrafts map[string]model.Minecraft
+	forges     map[string]model.Forge
+
+	teamUsers     map[pair]model.TeamUser
+	teamPacks     map[pair]model.TeamPack
+	teamMods      map[pair]model.TeamMod
+	userPacks     map[pair]model.UserPack
+	userMods      map[pair]model.UserMod
+	buildVersions map[pair]model.BuildVersion
+
+	blobs map[string]model.Blob
+	links map[string]model.Link
+}
+
+// Users returns the repository for users.
+func (s *memory) Users() store.Users {
+	return &users{s: s}
+}
+
+// Teams returns the repository for teams.
+func (s *memory) Teams() store.Teams {
+	return &teams{s: s}
+}
+
+// Packs returns the repository for packs.
+func (s *memory) Packs() store.Packs {
+	return &packs{s: s}
+}
+
+// Builds returns the repository for builds.
+func (s *memory) Builds() store.Builds {
+	return &builds{s: s}
+}
+
+// Mods returns the repository for mods.
+func (s *memory) Mods() store.Mods {
+	return &mods{s: s}
+}
+
+// Versions returns the repository for versions.
+func (s *memory) Versions() store.Versions {
+	return &versions{s: s}
+}
+
+// Minecrafts returns the repository for Minecraft versions.
+func (s *memory) Minecrafts() store.Minecrafts {
+	return &minecrafts{s: s}
+}
+
+// Forges returns the repository for Forge versions.
+func (s *memory) Forges() store.Forges {
+	return &forges{s: s}
+}
+
+// TeamUsers returns the repository for users assigned to teams.
+func (s *memory) TeamUsers() store.TeamUsers {
+	return &teamUsers{s: s}
+}
+
+// TeamPacks returns the repository for packs assigned to teams.
+func (s *memory) TeamPacks() store.TeamPacks {
+	return &teamPacks{s: s}
+}
+
+// TeamMods returns the repository for mods assigned to teams.
+func (s *memory) TeamMods() store.TeamMods {
+	return &teamMods{s: s}
+}
+
+// UserPacks returns the repository for packs assigned to users.
+func (s *memory) UserPacks() store.UserPacks {
+	return &userPacks{s: s}
+}
+
+// UserMods returns the repository for mods assigned to users.
+func (s *memory) UserMods() store.UserMods {
+	return &userMods{s: s}
+}
+
+// BuildVersions returns the repository for versions assigned to builds.
+func (s *memory) BuildVersions() store.BuildVersions {
+	return &buildVersions{s: s}
+}
+
+// Blobs returns the repository for deduplicated upload content.
+func (s *memory) Blobs() store.Blobs {
+	return &blobs{s: s}
+}
+
+// Links returns the repository for logical upload paths.
+func (s *memory) Links() store.Links {
+	return &links{s: s}
+}
+
+// Migrations returns the repository for schema migrations.
+func (s *memory) Migrations() store.Migrations {
+	return &migrations{s: s}
+}
+
+// Transaction implements the store.Store interface, the records get restored
+// from a snapshot if the function fails.
+func (s *memory) Transaction(ctx context.Context, fn func(store.Repositories) error) error {
+	if s.tx {
+		return fn(s)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	snapshot := s.snapshot()
+
+	tx := *s
+	tx.tx = true
+
+	if err := fn(&tx); err != nil {
+		s.restore(snapshot)
+		return err
+	}
+
+	return nil
+}
+
+// Snapshot implements the store.Store interface, it holds the lock until
+// the function returns to keep the records unchanged.
+func (s *memory) Snapshot(ctx context.Context, fn func(store.Repositories) error) error {
+	if s.tx {
+		return fn(s)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	tx := *s
+	tx.tx = true
+
+	return fn(&tx)
+}
+
+// Close simply drops all records.
+func (s *memory) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.reset()
+	return nil
+}
+
+// view executes a read-only function while holding the read lock, the lock
+// is already held if the store is transactional.
+func (s *memory) view(fn func() error) error {
+	if s.tx {
+		return fn()
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return fn()
+}
+
+// update executes a read-write function while holding the write lock, the
+// lock is already held if the store is transactional.
+func (s *memory) update(fn func() error) error {
+	if s.tx {
+		return fn()
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return fn()
+}
+
+// reset initializes empty maps for all records.
+func (s *memory) reset() {
+	s.users = make(map[string]model.User)
+	s.teams = make(map[string]model.Team)
+	s.packs = make(map[string]model.Pack)
+	s.builds = make(map[string]model.Build)
+	s.mods = make(map[string]model.Mod)
+	s.versions = make(map[string]model.Version)
+	s.minecrafts = make(map[string]model.Minecraft)
+	s.forges = make(map[string]model.Forge)
+
+	s.teamUsers = make(map[pair]model.TeamUser)
+	s.teamPacks = make(map[pair]model.TeamPack)
+	s.teamMods = make(map[pair]model.TeamMod)
+	s.userPacks = make(map[pair]model.UserPack)
+	s.userMods = make(map[pair]model.UserMod)
+	s.buildVersions = make(map[pair]model.BuildVersion)
+
+	s.blobs = make(map[string]model.Blob)
+	s.links = make(map[string]model.Link)
+}
+
+// snapshot copies all records to restore them on a rollback.
+func (s *memory) snapshot() *memory {
+	result := &memory{}
+	result.reset()
+
+	for k, v := range s.users {
+		result.users[k] = v
+	}
+
+	for k, v := range s.teams {
+		result.teams[k] = v
+	}
+
+	for k, v := range s.packs {
+		result.packs[k] = v
+	}
+
+	for k, v := range s.builds {
+		result.builds[k] = v
+	}
+
+	for k, v := range s.mods {
+		result.mods[k] = v
+	}
+
+	for k, v := range s.versions {
+		result.versions[k] = v
+	}
+
+	for k, v := range s.minecrafts {
+		result.minecrafts[k] = v
+	}
+
+	for k, v := range s.forges {
+		result.forges[k] = v
+	}
+
+	for k, v := range s.teamUsers {
+		result.teamUsers[k] = v
+	}
+
+	for k, v := range s.teamPacks {
+		result.teamPacks[k] = v
+	}
+
+	for k, v := range s.teamMods {
+		result.teamMods[k] = v
+	}
+
+	for k, v := range s.userPacks {
+		result.userPacks[k] = v
+	}
+
+	for k, v := range s.userMods {
+		result.userMods[k] = v
+	}
+
+	for k, v := range s.buildVersions {
+		result.buildVersions[k] = v
+	}
+
+	for k, v := range s.blobs {
+		result.blobs[k] = v
+	}
+
+	for k, v := range s.links {
+		result.links[k] = v
+	}
+
+	return result
+}
+
+// restore replaces all records with the records of the snapshot.
+func (s *memory) restore(snapshot *memory) {
+	s.users = snapshot.users
+	s.teams = snapshot.teams
+	s.packs = snapshot.packs
+	s.builds = snapshot.builds
+	s.mods = snapshot.mods
+	s.versions = snapshot.versions
+	s.minecrafts = snapshot.minecrafts
+	s.forges = snapshot.forges
+
+	s.teamUsers = snapshot.teamUsers
+	s.teamPacks = snapshot.teamPacks
+	s.teamMods = snapshot.teamMods
+	s.userPacks = snapshot.userPacks
+	s.userMods = snapshot.userMods
+	s.buildVersions = snapshot.buildVersions
+
+	s.blobs = snapshot.blobs
+	s.links = snapshot.links
+}
+
+// fixture cleans the dsn and returns the path of the optional fixture.
+func (s *memory) fixture() string {
+	return path.Join(
+		s.dsn.Host,
+		s.dsn.EscapedPath(),
+	)
+}
+
+// New initializes a new in-memory store, it gets seeded from a JSON fixture
+// if the dsn contains a path like memory://testdata/fixture.json.
+func New(dsn *url.URL) (store.Store, error) {
+	s := &memory{
+		dsn:  dsn,
+		lock: &sync.RWMutex{},
+	}
+
+	s.reset()
+
+	if fixture := s.fixture(); fixture != "" {
+		if err := s.load(context.Background(), fixture); err != nil {
+			return nil, errors.Wrap(err, "failed to load fixture")
+		}
+	}
+
+	return s, nil
+}
+
+// Must simply calls New and panics on an error.
+func Must(dsn *url.URL) store.Store {
+	db, err := New(dsn)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return db
+}
+
+// isUUID checks if the value is a valid UUID, these values only match IDs
+// like within the SQL drivers.
+func isUUID(val string) bool {
+	_, err := uuid.Parse(val)
+	return err == nil
+}
@@ -0,0 +1,16 @@
+package memory
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/kleister/kleister-api/pkg/store"
+	"github.com/kleister/kleister-api/pkg/store/storetest"
+)
+
+func TestConformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.Store {
+		dsn, _ := url.Parse("memory://")
+		return Must(dsn)
+	})
+}
@@ -0,0 +1,27 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+// migrations is a no-op as the in-memory store does not have a schema.
+type migrations struct {
+	s *memory
+}
+
+// Status implements the store.Migrations interface.
+func (r *migrations) Status(ctx context.Context) ([]*store.Migration, error) {
+	return make([]*store.Migration, 0), nil
+}
+
+// Up implements the store.Migrations interface.
+func (r *migrations) Up(ctx context.Context, dry bool) ([]*store.Migration, error) {
+	return make([]*store.Migration, 0), nil
+}
+
+// Down implements the store.Migrations interface.
+func (r *migrations) Down(ctx context.Context, dry bool) ([]*store.Migration, error) {
+	return make([]*store.Migration, 0), nil
+}
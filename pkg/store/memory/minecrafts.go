@@ -0,0 +1,181 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+type minecrafts struct {
+	s *memory
+}
+
+// List implements the store.Minecrafts interface.
+func (r *minecrafts) List(ctx context.Context) ([]*model.Minecraft, error) {
+	records := make([]*model.Minecraft, 0)
+
+	err := r.s.view(func() error {
+		for _, minecraft := range r.s.minecrafts {
+			record := minecraft
+			records = append(records, &record)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Name < records[j].Name
+	})
+
+	return records, nil
+}
+
+// Show implements the store.Minecrafts interface.
+func (r *minecrafts) Show(ctx context.Context, id string) (*model.Minecraft, error) {
+	var record model.Minecraft
+
+	err := r.s.view(func() error {
+		found, err := r.s.findMinecraft(id)
+		record = found
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Create implements the store.Minecrafts interface.
+func (r *minecrafts) Create(ctx context.Context, minecraft *model.Minecraft) (*model.Minecraft, error) {
+	record := *minecraft
+	now := time.Now().UTC()
+
+	if record.ID == "" {
+		record.ID = uuid.New().String()
+	}
+
+	record.Slug = store.SlugOrDefault(record.Slug, record.Name, record.ID)
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = now
+	}
+
+	err := r.s.update(func() error {
+		if _, ok := r.s.minecrafts[record.ID]; ok {
+			return store.ErrConflict
+		}
+
+		if r.s.minecraftSlugTaken(record.ID, record.Slug) {
+			return store.ErrConflict
+		}
+
+		r.s.minecrafts[record.ID] = record
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Update implements the store.Minecrafts interface.
+func (r *minecrafts) Update(ctx context.Context, minecraft *model.Minecraft) (*model.Minecraft, error) {
+	record := *minecraft
+
+	err := r.s.update(func() error {
+		current, ok := r.s.minecrafts[record.ID]
+
+		if !ok {
+			return store.ErrNotFound
+		}
+
+		record.Slug = store.SlugOrDefault(record.Slug, record.Name, current.Slug)
+		record.CreatedAt = current.CreatedAt
+		record.UpdatedAt = time.Now().UTC()
+
+		if r.s.minecraftSlugTaken(record.ID, record.Slug) {
+			return store.ErrConflict
+		}
+
+		r.s.minecrafts[record.ID] = record
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Delete implements the store.Minecrafts interface.
+func (r *minecrafts) Delete(ctx context.Context, id string) error {
+	return r.s.update(func() error {
+		record, err := r.s.findMinecraft(id)
+
+		if err != nil {
+			return err
+		}
+
+		r.s.deleteMinecraft(record)
+		return nil
+	})
+}
+
+// findMinecraft resolves a minecraft by ID or slug.
+func (s *memory) findMinecraft(id string) (model.Minecraft, error) {
+	if record, ok := s.minecrafts[id]; ok {
+		return record, nil
+	}
+
+	if isUUID(id) {
+		return model.Minecraft{}, store.ErrNotFound
+	}
+
+	for _, record := range s.minecrafts {
+		if record.Slug == id {
+			return record, nil
+		}
+	}
+
+	return model.Minecraft{}, store.ErrNotFound
+}
+
+// minecraftSlugTaken checks if the slug is used by another minecraft.
+func (s *memory) minecraftSlugTaken(id, slug string) bool {
+	for _, record := range s.minecrafts {
+		if record.Slug == slug && record.ID != id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// deleteMinecraft removes a Minecraft version and detaches it from builds.
+func (s *memory) deleteMinecraft(record model.Minecraft) {
+	for id, build := range s.builds {
+		if build.MinecraftID == record.ID {
+			build.MinecraftID = ""
+			s.builds[id] = build
+		}
+	}
+
+	delete(s.minecrafts, record.ID)
+}
@@ -0,0 +1,192 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+type mods struct {
+	s *memory
+}
+
+// List implements the store.Mods interface.
+func (r *mods) List(ctx context.Context) ([]*model.Mod, error) {
+	records := make([]*model.Mod, 0)
+
+	err := r.s.view(func() error {
+		for _, mod := range r.s.mods {
+			record := mod
+			records = append(records, &record)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Name < records[j].Name
+	})
+
+	return records, nil
+}
+
+// Show implements the store.Mods interface.
+func (r *mods) Show(ctx context.Context, id string) (*model.Mod, error) {
+	var record model.Mod
+
+	err := r.s.view(func() error {
+		found, err := r.s.findMod(id)
+		record = found
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Create implements the store.Mods interface.
+func (r *mods) Create(ctx context.Context, mod *model.Mod) (*model.Mod, error) {
+	record := *mod
+	now := time.Now().UTC()
+
+	if record.ID == "" {
+		record.ID = uuid.New().String()
+	}
+
+	record.Slug = store.SlugOrDefault(record.Slug, record.Name, record.ID)
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = now
+	}
+
+	err := r.s.update(func() error {
+		if _, ok := r.s.mods[record.ID]; ok {
+			return store.ErrConflict
+		}
+
+		if r.s.modSlugTaken(record.ID, record.Slug) {
+			return store.ErrConflict
+		}
+
+		r.s.mods[record.ID] = record
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Update implements the store.Mods interface.
+func (r *mods) Update(ctx context.Context, mod *model.Mod) (*model.Mod, error) {
+	record := *mod
+
+	err := r.s.update(func() error {
+		current, ok := r.s.mods[record.ID]
+
+		if !ok {
+			return store.ErrNotFound
+		}
+
+		record.Slug = store.SlugOrDefault(record.Slug, record.Name, current.Slug)
+		record.CreatedAt = current.CreatedAt
+		record.UpdatedAt = time.Now().UTC()
+
+		if r.s.modSlugTaken(record.ID, record.Slug) {
+			return store.ErrConflict
+		}
+
+		r.s.mods[record.ID] = record
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Delete implements the store.Mods interface.
+func (r *mods) Delete(ctx context.Context, id string) error {
+	return r.s.update(func() error {
+		record, err := r.s.findMod(id)
+
+		if err != nil {
+			return err
+		}
+
+		r.s.deleteMod(record)
+		return nil
+	})
+}
+
+// findMod resolves a mod by ID or slug.
+func (s *memory) findMod(id string) (model.Mod, error) {
+	if record, ok := s.mods[id]; ok {
+		return record, nil
+	}
+
+	if isUUID(id) {
+		return model.Mod{}, store.ErrNotFound
+	}
+
+	for _, record := range s.mods {
+		if record.Slug == id {
+			return record, nil
+		}
+	}
+
+	return model.Mod{}, store.ErrNotFound
+}
+
+// modSlugTaken checks if the slug is used by another mod.
+func (s *memory) modSlugTaken(id, slug string) bool {
+	for _, record := range s.mods {
+		if record.Slug == slug && record.ID != id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// deleteMod removes a mod including its versions and assignments.
+func (s *memory) deleteMod(record model.Mod) {
+	for _, version := range s.versions {
+		if version.ModID == record.ID {
+			s.deleteVersion(version)
+		}
+	}
+
+	for key := range s.teamMods {
+		if key.right == record.ID {
+			delete(s.teamMods, key)
+		}
+	}
+
+	for key := range s.userMods {
+		if key.right == record.ID {
+			delete(s.userMods, key)
+		}
+	}
+
+	delete(s.mods, record.ID)
+}
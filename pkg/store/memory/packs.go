@@ -0,0 +1,238 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+type packs struct {
+	s *memory
+}
+
+// List implements the store.Packs interface.
+func (r *packs) List(ctx context.Context) ([]*model.Pack, error) {
+	records := make([]*model.Pack, 0)
+
+	err := r.s.view(func() error {
+		for _, pack := range r.s.packs {
+			record := pack
+			records = append(records, &record)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Name < records[j].Name
+	})
+
+	return records, nil
+}
+
+// Show implements the store.Packs interface.
+func (r *packs) Show(ctx context.Context, id string) (*model.Pack, error) {
+	var record model.Pack
+
+	err := r.s.view(func() error {
+		found, err := r.s.findPack(id)
+		record = found
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Create implements the store.Packs interface.
+func (r *packs) Create(ctx context.Context, pack *model.Pack) (*model.Pack, error) {
+	record := *pack
+	now := time.Now().UTC()
+
+	if record.ID == "" {
+		record.ID = uuid.New().String()
+	}
+
+	record.Slug = store.SlugOrDefault(record.Slug, record.Name, record.ID)
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = now
+	}
+
+	err := r.s.update(func() error {
+		if _, ok := r.s.packs[record.ID]; ok {
+			return store.ErrConflict
+		}
+
+		if err := r.s.checkPack(record); err != nil {
+			return err
+		}
+
+		if r.s.packSlugTaken(record.ID, record.Slug) {
+			return store.ErrConflict
+		}
+
+		r.s.packs[record.ID] = record
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Update implements the store.Packs interface.
+func (r *packs) Update(ctx context.Context, pack *model.Pack) (*model.Pack, error) {
+	record := *pack
+
+	err := r.s.update(func() error {
+		current, ok := r.s.packs[record.ID]
+
+		if !ok {
+			return store.ErrNotFound
+		}
+
+		record.Slug = store.SlugOrDefault(record.Slug, record.Name, current.Slug)
+		record.CreatedAt = current.CreatedAt
+		record.UpdatedAt = time.Now().UTC()
+
+		if err := r.s.checkPack(record); err != nil {
+			return err
+		}
+
+		if r.s.packSlugTaken(record.ID, record.Slug) {
+			return store.ErrConflict
+		}
+
+		r.s.packs[record.ID] = record
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Delete implements the store.Packs interface.
+func (r *packs) Delete(ctx context.Context, id string) error {
+	return r.s.update(func() error {
+		record, err := r.s.findPack(id)
+
+		if err != nil {
+			return err
+		}
+
+		r.s.deletePack(record)
+		return nil
+	})
+}
+
+// Assign implements the store.Packs interface.
+func (r *packs) Assign(ctx context.Context, pack *model.Pack) error {
+	return r.s.update(func() error {
+		record, ok := r.s.packs[pack.ID]
+
+		if !ok {
+			return store.ErrNotFound
+		}
+
+		record.RecommendedID = pack.RecommendedID
+		record.LatestID = pack.LatestID
+
+		if err := r.s.checkPack(record); err != nil {
+			return err
+		}
+
+		r.s.packs[record.ID] = record
+		return nil
+	})
+}
+
+// findPack resolves a pack by ID or slug.
+func (s *memory) findPack(id string) (model.Pack, error) {
+	if record, ok := s.packs[id]; ok {
+		return record, nil
+	}
+
+	if isUUID(id) {
+		return model.Pack{}, store.ErrNotFound
+	}
+
+	for _, record := range s.packs {
+		if record.Slug == id {
+			return record, nil
+		}
+	}
+
+	return model.Pack{}, store.ErrNotFound
+}
+
+// packSlugTaken checks if the slug is used by another pack.
+func (s *memory) packSlugTaken(id, slug string) bool {
+	for _, record := range s.packs {
+		if record.Slug == slug && record.ID != id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkPack verifies that the referenced builds belong to the pack.
+func (s *memory) checkPack(record model.Pack) error {
+	for _, id := range []string{record.RecommendedID, record.LatestID} {
+		if id == "" {
+			continue
+		}
+
+		build, ok := s.builds[id]
+
+		if !ok || build.PackID != record.ID {
+			return store.ErrNotFound
+		}
+	}
+
+	return nil
+}
+
+// deletePack removes a pack including its builds and assignments.
+func (s *memory) deletePack(record model.Pack) {
+	for _, build := range s.builds {
+		if build.PackID == record.ID {
+			s.deleteBuild(build)
+		}
+	}
+
+	for key := range s.teamPacks {
+		if key.right == record.ID {
+			delete(s.teamPacks, key)
+		}
+	}
+
+	for key := range s.userPacks {
+		if key.right == record.ID {
+			delete(s.userPacks, key)
+		}
+	}
+
+	delete(s.packs, record.ID)
+}
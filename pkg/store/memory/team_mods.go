@@ -0,0 +1,155 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+type teamMods struct {
+	s *memory
+}
+
+// ListByTeam implements the store.TeamMods interface.
+func (r *teamMods) ListByTeam(ctx context.Context, teamID string) ([]*model.TeamMod, error) {
+	records := make([]*model.TeamMod, 0)
+
+	err := r.s.view(func() error {
+		if _, ok := r.s.teams[teamID]; !ok {
+			return store.ErrNotFound
+		}
+
+		for key := range r.s.teamMods {
+			if key.left == teamID {
+				records = append(records, r.s.loadTeamMod(key))
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Mod.Name < records[j].Mod.Name
+	})
+
+	return records, nil
+}
+
+// ListByMod implements the store.TeamMods interface.
+func (r *teamMods) ListByMod(ctx context.Context, modID string) ([]*model.TeamMod, error) {
+	records := make([]*model.TeamMod, 0)
+
+	err := r.s.view(func() error {
+		if _, ok := r.s.mods[modID]; !ok {
+			return store.ErrNotFound
+		}
+
+		for key := range r.s.teamMods {
+			if key.right == modID {
+				records = append(records, r.s.loadTeamMod(key))
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Team.Name < records[j].Team.Name
+	})
+
+	return records, nil
+}
+
+// Append implements the store.TeamMods interface.
+func (r *teamMods) Append(ctx context.Context, teamMod *model.TeamMod) error {
+	record := *teamMod
+	now := time.Now().UTC()
+
+	if record.Perm == "" {
+		record.Perm = model.PermUser
+	}
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = now
+	}
+
+	return r.s.update(func() error {
+		_, team := r.s.teams[record.TeamID]
+		_, mod := r.s.mods[record.ModID]
+
+		if !team || !mod {
+			return store.ErrNotFound
+		}
+
+		key := pair{left: record.TeamID, right: record.ModID}
+
+		if _, ok := r.s.teamMods[key]; ok {
+			return store.ErrConflict
+		}
+
+		record.Team = nil
+		record.Mod = nil
+
+		r.s.teamMods[key] = record
+		return nil
+	})
+}
+
+// Permit implements the store.TeamMods interface.
+func (r *teamMods) Permit(ctx context.Context, teamMod *model.TeamMod) error {
+	return r.s.update(func() error {
+		key := pair{left: teamMod.TeamID, right: teamMod.ModID}
+		record, ok := r.s.teamMods[key]
+
+		if !ok {
+			return store.ErrNotFound
+		}
+
+		record.Perm = teamMod.Perm
+		record.UpdatedAt = time.Now().UTC()
+
+		r.s.teamMods[key] = record
+		return nil
+	})
+}
+
+// Drop implements the store.TeamMods interface.
+func (r *teamMods) Drop(ctx context.Context, teamID, modID string) error {
+	return r.s.update(func() error {
+		key := pair{left: teamID, right: modID}
+
+		if _, ok := r.s.teamMods[key]; !ok {
+			return store.ErrNotFound
+		}
+
+		delete(r.s.teamMods, key)
+		return nil
+	})
+}
+
+// loadTeamMod copies an assignment including both sides.
+func (s *memory) loadTeamMod(key pair) *model.TeamMod {
+	record := s.teamMods[key]
+	team := s.teams[key.left]
+	mod := s.mods[key.right]
+
+	record.Team = &team
+	record.Mod = &mod
+
+	return &record
+}
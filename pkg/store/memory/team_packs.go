@@ -0,0 +1,155 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+type teamPacks struct {
+	s *memory
+}
+
+// ListByTeam implements the store.TeamPacks interface.
+func (r *teamPacks) ListByTeam(ctx context.Context, teamID string) ([]*model.TeamPack, error) {
+	records := make([]*model.TeamPack, 0)
+
+	err := r.s.view(func() error {
+		if _, ok := r.s.teams[teamID]; !ok {
+			return store.ErrNotFound
+		}
+
+		for key := range r.s.teamPacks {
+			if key.left == teamID {
+				records = append(records, r.s.loadTeamPack(key))
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Pack.Name < records[j].Pack.Name
+	})
+
+	return records, nil
+}
+
+// ListByPack implements the store.TeamPacks interface.
+func (r *teamPacks) ListByPack(ctx context.Context, packID string) ([]*model.TeamPack, error) {
+	records := make([]*model.TeamPack, 0)
+
+	err := r.s.view(func() error {
+		if _, ok := r.s.packs[packID]; !ok {
+			return store.ErrNotFound
+		}
+
+		for key := range r.s.teamPacks {
+			if key.right == packID {
+				records = append(records, r.s.loadTeamPack(key))
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Team.Name < records[j].Team.Name
+	})
+
+	return records, nil
+}
+
+// Append implements the store.TeamPacks interface.
+func (r *teamPacks) Append(ctx context.Context, teamPack *model.TeamPack) error {
+	record := *teamPack
+	now := time.Now().UTC()
+
+	if record.Perm == "" {
+		record.Perm = model.PermUser
+	}
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = now
+	}
+
+	return r.s.update(func() error {
+		_, team := r.s.teams[record.TeamID]
+		_, pack := r.s.packs[record.PackID]
+
+		if !team || !pack {
+			return store.ErrNotFound
+		}
+
+		key := pair{left: record.TeamID, right: record.PackID}
+
+		if _, ok := r.s.teamPacks[key]; ok {
+			return store.ErrConflict
+		}
+
+		record.Team = nil
+		record.Pack = nil
+
+		r.s.teamPacks[key] = record
+		return nil
+	})
+}
+
+// Permit implements the store.TeamPacks interface.
+func (r *teamPacks) Permit(ctx context.Context, teamPack *model.TeamPack) error {
+	return r.s.update(func() error {
+		key := pair{left: teamPack.TeamID, right: teamPack.PackID}
+		record, ok := r.s.teamPacks[key]
+
+		if !ok {
+			return store.ErrNotFound
+		}
+
+		record.Perm = teamPack.Perm
+		record.UpdatedAt = time.Now().UTC()
+
+		r.s.teamPacks[key] = record
+		return nil
+	})
+}
+
+// Drop implements the store.TeamPacks interface.
+func (r *teamPacks) Drop(ctx context.Context, teamID, packID string) error {
+	return r.s.update(func() error {
+		key := pair{left: teamID, right: packID}
+
+		if _, ok := r.s.teamPacks[key]; !ok {
+			return store.ErrNotFound
+		}
+
+		delete(r.s.teamPacks, key)
+		return nil
+	})
+}
+
+// loadTeamPack copies an assignment including both sides.
+func (s *memory) loadTeamPack(key pair) *model.TeamPack {
+	record := s.teamPacks[key]
+	team := s.teams[key.left]
+	pack := s.packs[key.right]
+
+	record.Team = &team
+	record.Pack = &pack
+
+	return &record
+}
@@ -0,0 +1,155 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+type teamUsers struct {
+	s *memory
+}
+
+// ListByTeam implements the store.TeamUsers interface.
+func (r *teamUsers) ListByTeam(ctx context.Context, teamID string) ([]*model.TeamUser, error) {
+	records := make([]*model.TeamUser, 0)
+
+	err := r.s.view(func() error {
+		if _, ok := r.s.teams[teamID]; !ok {
+			return store.ErrNotFound
+		}
+
+		for key := range r.s.teamUsers {
+			if key.left == teamID {
+				records = append(records, r.s.loadTeamUser(key))
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].User.Username < records[j].User.Username
+	})
+
+	return records, nil
+}
+
+// ListByUser implements the store.TeamUsers interface.
+func (r *teamUsers) ListByUser(ctx context.Context, userID string) ([]*model.TeamUser, error) {
+	records := make([]*model.TeamUser, 0)
+
+	err := r.s.view(func() error {
+		if _, ok := r.s.users[userID]; !ok {
+			return store.ErrNotFound
+		}
+
+		for key := range r.s.teamUsers {
+			if key.right == userID {
+				records = append(records, r.s.loadTeamUser(key))
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Team.Name < records[j].Team.Name
+	})
+
+	return records, nil
+}
+
+// Append implements the store.TeamUsers interface.
+func (r *teamUsers) Append(ctx context.Context, teamUser *model.TeamUser) error {
+	record := *teamUser
+	now := time.Now().UTC()
+
+	if record.Perm == "" {
+		record.Perm = model.PermUser
+	}
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = now
+	}
+
+	return r.s.update(func() error {
+		_, team := r.s.teams[record.TeamID]
+		_, user := r.s.users[record.UserID]
+
+		if !team || !user {
+			return store.ErrNotFound
+		}
+
+		key := pair{left: record.TeamID, right: record.UserID}
+
+		if _, ok := r.s.teamUsers[key]; ok {
+			return store.ErrConflict
+		}
+
+		record.Team = nil
+		record.User = nil
+
+		r.s.teamUsers[key] = record
+		return nil
+	})
+}
+
+// Permit implements the store.TeamUsers interface.
+func (r *teamUsers) Permit(ctx context.Context, teamUser *model.TeamUser) error {
+	return r.s.update(func() error {
+		key := pair{left: teamUser.TeamID, right: teamUser.UserID}
+		record, ok := r.s.teamUsers[key]
+
+		if !ok {
+			return store.ErrNotFound
+		}
+
+		record.Perm = teamUser.Perm
+		record.UpdatedAt = time.Now().UTC()
+
+		r.s.teamUsers[key] = record
+		return nil
+	})
+}
+
+// Drop implements the store.TeamUsers interface.
+func (r *teamUsers) Drop(ctx context.Context, teamID, userID string) error {
+	return r.s.update(func() error {
+		key := pair{left: teamID, right: userID}
+
+		if _, ok := r.s.teamUsers[key]; !ok {
+			return store.ErrNotFound
+		}
+
+		delete(r.s.teamUsers, key)
+		return nil
+	})
+}
+
+// loadTeamUser copies an assignment including both sides.
+func (s *memory) loadTeamUser(key pair) *model.TeamUser {
+	record := s.teamUsers[key]
+	team := s.teams[key.left]
+	user := s.users[key.right]
+
+	record.Team = &team
+	record.User = &user
+
+	return &record
+}
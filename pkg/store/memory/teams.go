@@ -0,0 +1,192 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+type teams struct {
+	s *memory
+}
+
+// List implements the store.Teams interface.
+func (r *teams) List(ctx context.Context) ([]*model.Team, error) {
+	records := make([]*model.Team, 0)
+
+	err := r.s.view(func() error {
+		for _, team := range r.s.teams {
+			record := team
+			records = append(records, &record)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Name < records[j].Name
+	})
+
+	return records, nil
+}
+
+// Show implements the store.Teams interface.
+func (r *teams) Show(ctx context.Context, id string) (*model.Team, error) {
+	var record model.Team
+
+	err := r.s.view(func() error {
+		found, err := r.s.findTeam(id)
+		record = found
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Create implements the store.Teams interface.
+func (r *teams) Create(ctx context.Context, team *model.Team) (*model.Team, error) {
+	record := *team
+	now := time.Now().UTC()
+
+	if record.ID == "" {
+		record.ID = uuid.New().String()
+	}
+
+	record.Slug = store.SlugOrDefault(record.Slug, record.Name, record.ID)
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = now
+	}
+
+	err := r.s.update(func() error {
+		if _, ok := r.s.teams[record.ID]; ok {
+			return store.ErrConflict
+		}
+
+		if r.s.teamSlugTaken(record.ID, record.Slug) {
+			return store.ErrConflict
+		}
+
+		r.s.teams[record.ID] = record
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Update implements the store.Teams interface.
+func (r *teams) Update(ctx context.Context, team *model.Team) (*model.Team, error) {
+	record := *team
+
+	err := r.s.update(func() error {
+		current, ok := r.s.teams[record.ID]
+
+		if !ok {
+			return store.ErrNotFound
+		}
+
+		record.Slug = store.SlugOrDefault(record.Slug, record.Name, current.Slug)
+		record.CreatedAt = current.CreatedAt
+		record.UpdatedAt = time.Now().UTC()
+
+		if r.s.teamSlugTaken(record.ID, record.Slug) {
+			return store.ErrConflict
+		}
+
+		r.s.teams[record.ID] = record
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Delete implements the store.Teams interface.
+func (r *teams) Delete(ctx context.Context, id string) error {
+	return r.s.update(func() error {
+		record, err := r.s.findTeam(id)
+
+		if err != nil {
+			return err
+		}
+
+		r.s.deleteTeam(record)
+		return nil
+	})
+}
+
+// findTeam resolves a team by ID or slug.
+func (s *memory) findTeam(id string) (model.Team, error) {
+	if record, ok := s.teams[id]; ok {
+		return record, nil
+	}
+
+	if isUUID(id) {
+		return model.Team{}, store.ErrNotFound
+	}
+
+	for _, record := range s.teams {
+		if record.Slug == id {
+			return record, nil
+		}
+	}
+
+	return model.Team{}, store.ErrNotFound
+}
+
+// teamSlugTaken checks if the slug is used by another team.
+func (s *memory) teamSlugTaken(id, slug string) bool {
+	for _, record := range s.teams {
+		if record.Slug == slug && record.ID != id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// deleteTeam removes a team including all of its assignments.
+func (s *memory) deleteTeam(record model.Team) {
+	for key := range s.teamUsers {
+		if key.left == record.ID {
+			delete(s.teamUsers, key)
+		}
+	}
+
+	for key := range s.teamPacks {
+		if key.left == record.ID {
+			delete(s.teamPacks, key)
+		}
+	}
+
+	for key := range s.teamMods {
+		if key.left == record.ID {
+			delete(s.teamMods, key)
+		}
+	}
+
+	delete(s.teams, record.ID)
+}
@@ -0,0 +1,155 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+type userMods struct {
+	s *memory
+}
+
+// ListByUser implements the store.UserMods interface.
+func (r *userMods) ListByUser(ctx context.Context, userID string) ([]*model.UserMod, error) {
+	records := make([]*model.UserMod, 0)
+
+	err := r.s.view(func() error {
+		if _, ok := r.s.users[userID]; !ok {
+			return store.ErrNotFound
+		}
+
+		for key := range r.s.userMods {
+			if key.left == userID {
+				records = append(records, r.s.loadUserMod(key))
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Mod.Name < records[j].Mod.Name
+	})
+
+	return records, nil
+}
+
+// ListByMod implements the store.UserMods interface.
+func (r *userMods) ListByMod(ctx context.Context, modID string) ([]*model.UserMod, error) {
+	records := make([]*model.UserMod, 0)
+
+	err := r.s.view(func() error {
+		if _, ok := r.s.mods[modID]; !ok {
+			return store.ErrNotFound
+		}
+
+		for key := range r.s.userMods {
+			if key.right == modID {
+				records = append(records, r.s.loadUserMod(key))
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].User.Username < records[j].User.Username
+	})
+
+	return records, nil
+}
+
+// Append implements the store.UserMods interface.
+func (r *userMods) Append(ctx context.Context, userMod *model.UserMod) error {
+	record := *userMod
+	now := time.Now().UTC()
+
+	if record.Perm == "" {
+		record.Perm = model.PermUser
+	}
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = now
+	}
+
+	return r.s.update(func() error {
+		_, user := r.s.users[record.UserID]
+		_, mod := r.s.mods[record.ModID]
+
+		if !user || !mod {
+			return store.ErrNotFound
+		}
+
+		key := pair{left: record.UserID, right: record.ModID}
+
+		if _, ok := r.s.userMods[key]; ok {
+			return store.ErrConflict
+		}
+
+		record.User = nil
+		record.Mod = nil
+
+		r.s.userMods[key] = record
+		return nil
+	})
+}
+
+// Permit implements the store.UserMods interface.
+func (r *userMods) Permit(ctx context.Context, userMod *model.UserMod) error {
+	return r.s.update(func() error {
+		key := pair{left: userMod.UserID, right: userMod.ModID}
+		record, ok := r.s.userMods[key]
+
+		if !ok {
+			return store.ErrNotFound
+		}
+
+		record.Perm = userMod.Perm
+		record.UpdatedAt = time.Now().UTC()
+
+		r.s.userMods[key] = record
+		return nil
+	})
+}
+
+// Drop implements the store.UserMods interface.
+func (r *userMods) Drop(ctx context.Context, userID, modID string) error {
+	return r.s.update(func() error {
+		key := pair{left: userID, right: modID}
+
+		if _, ok := r.s.userMods[key]; !ok {
+			return store.ErrNotFound
+		}
+
+		delete(r.s.userMods, key)
+		return nil
+	})
+}
+
+// loadUserMod copies an assignment including both sides.
+func (s *memory) loadUserMod(key pair) *model.UserMod {
+	record := s.userMods[key]
+	user := s.users[key.left]
+	mod := s.mods[key.right]
+
+	record.User = &user
+	record.Mod = &mod
+
+	return &record
+}
@@ -0,0 +1,155 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+type userPacks struct {
+	s *memory
+}
+
+// ListByUser implements the store.UserPacks interface.
+func (r *userPacks) ListByUser(ctx context.Context, userID string) ([]*model.UserPack, error) {
+	records := make([]*model.UserPack, 0)
+
+	err := r.s.view(func() error {
+		if _, ok := r.s.users[userID]; !ok {
+			return store.ErrNotFound
+		}
+
+		for key := range r.s.userPacks {
+			if key.left == userID {
+				records = append(records, r.s.loadUserPack(key))
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Pack.Name < records[j].Pack.Name
+	})
+
+	return records, nil
+}
+
+// ListByPack implements the store.UserPacks interface.
+func (r *userPacks) ListByPack(ctx context.Context, packID string) ([]*model.UserPack, error) {
+	records := make([]*model.UserPack, 0)
+
+	err := r.s.view(func() error {
+		if _, ok := r.s.packs[packID]; !ok {
+			return store.ErrNotFound
+		}
+
+		for key := range r.s.userPacks {
+			if key.right == packID {
+				records = append(records, r.s.loadUserPack(key))
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].User.Username < records[j].User.Username
+	})
+
+	return records, nil
+}
+
+// Append implements the store.UserPacks interface.
+func (r *userPacks) Append(ctx context.Context, userPack *model.UserPack) error {
+	record := *userPack
+	now := time.Now().UTC()
+
+	if record.Perm == "" {
+		record.Perm = model.PermUser
+	}
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = now
+	}
+
+	return r.s.update(func() error {
+		_, user := r.s.users[record.UserID]
+		_, pack := r.s.packs[record.PackID]
+
+		if !user || !pack {
+			return store.ErrNotFound
+		}
+
+		key := pair{left: record.UserID, right: record.PackID}
+
+		if _, ok := r.s.userPacks[key]; ok {
+			return store.ErrConflict
+		}
+
+		record.User = nil
+		record.Pack = nil
+
+		r.s.userPacks[key] = record
+		return nil
+	})
+}
+
+// Permit implements the store.UserPacks interface.
+func (r *userPacks) Permit(ctx context.Context, userPack *model.UserPack) error {
+	return r.s.update(func() error {
+		key := pair{left: userPack.UserID, right: userPack.PackID}
+		record, ok := r.s.userPacks[key]
+
+		if !ok {
+			return store.ErrNotFound
+		}
+
+		record.Perm = userPack.Perm
+		record.UpdatedAt = time.Now().UTC()
+
+		r.s.userPacks[key] = record
+		return nil
+	})
+}
+
+// Drop implements the store.UserPacks interface.
+func (r *userPacks) Drop(ctx context.Context, userID, packID string) error {
+	return r.s.update(func() error {
+		key := pair{left: userID, right: packID}
+
+		if _, ok := r.s.userPacks[key]; !ok {
+			return store.ErrNotFound
+		}
+
+		delete(r.s.userPacks, key)
+		return nil
+	})
+}
+
+// loadUserPack copies an assignment including both sides.
+func (s *memory) loadUserPack(key pair) *model.UserPack {
+	record := s.userPacks[key]
+	user := s.users[key.left]
+	pack := s.packs[key.right]
+
+	record.User = &user
+	record.Pack = &pack
+
+	return &record
+}
@@ -0,0 +1,192 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+type users struct {
+	s *memory
+}
+
+// List implements the store.Users interface.
+func (r *users) List(ctx context.Context) ([]*model.User, error) {
+	records := make([]*model.User, 0)
+
+	err := r.s.view(func() error {
+		for _, user := range r.s.users {
+			record := user
+			records = append(records, &record)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Username < records[j].Username
+	})
+
+	return records, nil
+}
+
+// Show implements the store.Users interface.
+func (r *users) Show(ctx context.Context, id string) (*model.User, error) {
+	var record model.User
+
+	err := r.s.view(func() error {
+		found, err := r.s.findUser(id)
+		record = found
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Create implements the store.Users interface.
+func (r *users) Create(ctx context.Context, user *model.User) (*model.User, error) {
+	record := *user
+	now := time.Now().UTC()
+
+	if record.ID == "" {
+		record.ID = uuid.New().String()
+	}
+
+	record.Slug = store.SlugOrDefault(record.Slug, record.Username, record.ID)
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = now
+	}
+
+	err := r.s.update(func() error {
+		if _, ok := r.s.users[record.ID]; ok {
+			return store.ErrConflict
+		}
+
+		if r.s.userSlugTaken(record.ID, record.Slug) {
+			return store.ErrConflict
+		}
+
+		r.s.users[record.ID] = record
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Update implements the store.Users interface.
+func (r *users) Update(ctx context.Context, user *model.User) (*model.User, error) {
+	record := *user
+
+	err := r.s.update(func() error {
+		current, ok := r.s.users[record.ID]
+
+		if !ok {
+			return store.ErrNotFound
+		}
+
+		record.Slug = store.SlugOrDefault(record.Slug, record.Username, current.Slug)
+		record.CreatedAt = current.CreatedAt
+		record.UpdatedAt = time.Now().UTC()
+
+		if r.s.userSlugTaken(record.ID, record.Slug) {
+			return store.ErrConflict
+		}
+
+		r.s.users[record.ID] = record
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Delete implements the store.Users interface.
+func (r *users) Delete(ctx context.Context, id string) error {
+	return r.s.update(func() error {
+		record, err := r.s.findUser(id)
+
+		if err != nil {
+			return err
+		}
+
+		r.s.deleteUser(record)
+		return nil
+	})
+}
+
+// findUser resolves a user by ID or slug.
+func (s *memory) findUser(id string) (model.User, error) {
+	if record, ok := s.users[id]; ok {
+		return record, nil
+	}
+
+	if isUUID(id) {
+		return model.User{}, store.ErrNotFound
+	}
+
+	for _, record := range s.users {
+		if record.Slug == id {
+			return record, nil
+		}
+	}
+
+	return model.User{}, store.ErrNotFound
+}
+
+// userSlugTaken checks if the slug is used by another user.
+func (s *memory) userSlugTaken(id, slug string) bool {
+	for _, record := range s.users {
+		if record.Slug == slug && record.ID != id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// deleteUser removes a user including all of its assignments.
+func (s *memory) deleteUser(record model.User) {
+	for key := range s.teamUsers {
+		if key.right == record.ID {
+			delete(s.teamUsers, key)
+		}
+	}
+
+	for key := range s.userPacks {
+		if key.left == record.ID {
+			delete(s.userPacks, key)
+		}
+	}
+
+	for key := range s.userMods {
+		if key.left == record.ID {
+			delete(s.userMods, key)
+		}
+	}
+
+	delete(s.users, record.ID)
+}
@@ -0,0 +1,224 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+type versions struct {
+	s *memory
+}
+
+// List implements the store.Versions interface.
+func (r *versions) List(ctx context.Context, modID string) ([]*model.Version, error) {
+	records := make([]*model.Version, 0)
+
+	err := r.s.view(func() error {
+		if _, ok := r.s.mods[modID]; !ok {
+			return store.ErrNotFound
+		}
+
+		for _, version := range r.s.versions {
+			if version.ModID != modID {
+				continue
+			}
+
+			record := version
+			records = append(records, &record)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Name < records[j].Name
+	})
+
+	return records, nil
+}
+
+// ListByFile implements the store.Versions interface.
+func (r *versions) ListByFile(ctx context.Context, key string) ([]*model.Version, error) {
+	records := make([]*model.Version, 0)
+
+	if key == "" {
+		return records, nil
+	}
+
+	err := r.s.view(func() error {
+		for _, version := range r.s.versions {
+			if version.FileKey != key {
+				continue
+			}
+
+			record := version
+			records = append(records, &record)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Name < records[j].Name
+	})
+
+	return records, nil
+}
+
+// Show implements the store.Versions interface.
+func (r *versions) Show(ctx context.Context, modID, id string) (*model.Version, error) {
+	var record model.Version
+
+	err := r.s.view(func() error {
+		found, err := r.s.findVersion(modID, id)
+		record = found
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Create implements the store.Versions interface.
+func (r *versions) Create(ctx context.Context, version *model.Version) (*model.Version, error) {
+	record := *version
+	now := time.Now().UTC()
+
+	if record.ID == "" {
+		record.ID = uuid.New().String()
+	}
+
+	record.Slug = store.SlugOrDefault(record.Slug, record.Name, record.ID)
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = now
+	}
+
+	err := r.s.update(func() error {
+		if _, ok := r.s.versions[record.ID]; ok {
+			return store.ErrConflict
+		}
+
+		if _, ok := r.s.mods[record.ModID]; !ok {
+			return store.ErrNotFound
+		}
+
+		if r.s.versionSlugTaken(record.ModID, record.ID, record.Slug) {
+			return store.ErrConflict
+		}
+
+		r.s.versions[record.ID] = record
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Update implements the store.Versions interface.
+func (r *versions) Update(ctx context.Context, version *model.Version) (*model.Version, error) {
+	record := *version
+
+	err := r.s.update(func() error {
+		current, ok := r.s.versions[record.ID]
+
+		if !ok || current.ModID != record.ModID {
+			return store.ErrNotFound
+		}
+
+		record.Slug = store.SlugOrDefault(record.Slug, record.Name, current.Slug)
+		record.CreatedAt = current.CreatedAt
+		record.UpdatedAt = time.Now().UTC()
+
+		if r.s.versionSlugTaken(record.ModID, record.ID, record.Slug) {
+			return store.ErrConflict
+		}
+
+		r.s.versions[record.ID] = record
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Delete implements the store.Versions interface.
+func (r *versions) Delete(ctx context.Context, modID, id string) error {
+	return r.s.update(func() error {
+		record, err := r.s.findVersion(modID, id)
+
+		if err != nil {
+			return err
+		}
+
+		r.s.deleteVersion(record)
+		return nil
+	})
+}
+
+// findVersion resolves a version of a mod by ID or slug.
+func (s *memory) findVersion(modID, id string) (model.Version, error) {
+	if record, ok := s.versions[id]; ok && record.ModID == modID {
+		return record, nil
+	}
+
+	if isUUID(id) {
+		return model.Version{}, store.ErrNotFound
+	}
+
+	for _, record := range s.versions {
+		if record.ModID == modID && record.Slug == id {
+			return record, nil
+		}
+	}
+
+	return model.Version{}, store.ErrNotFound
+}
+
+// versionSlugTaken checks if the slug is used by another version of the mod.
+func (s *memory) versionSlugTaken(modID, id, slug string) bool {
+	for _, record := range s.versions {
+		if record.ModID == modID && record.Slug == slug && record.ID != id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// deleteVersion removes a version including its build assignments.
+func (s *memory) deleteVersion(record model.Version) {
+	for key := range s.buildVersions {
+		if key.right == record.ID {
+			delete(s.buildVersions, key)
+		}
+	}
+
+	delete(s.versions, record.ID)
+}
@@ -0,0 +1,49 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// Migration represents a single versioned change of the schema.
+type Migration struct {
+	Version   int
+	Name      string
+	AppliedAt time.Time
+}
+
+// Applied checks if the migration has already been applied.
+func (m *Migration) Applied() bool {
+	return !m.AppliedAt.IsZero()
+}
+
+// Migrations provides the interface to manage the schema migrations.
+type Migrations interface {
+	// Status lists all known migrations ordered by version.
+	Status(context.Context) ([]*Migration, error)
+
+	// Up applies all pending migrations, a dry run only lists them.
+	Up(context.Context, bool) ([]*Migration, error)
+
+	// Down reverts the latest applied migration, a dry run only lists it.
+	Down(context.Context, bool) ([]*Migration, error)
+}
+
+// Pending counts the migrations that have not been applied yet.
+func Pending(ctx context.Context, s Store) (int, error) {
+	records, err := s.Migrations().Status(ctx)
+
+	if err != nil {
+		return 0, err
+	}
+
+	result := 0
+
+	for _, record := range records {
+		if !record.Applied() {
+			result++
+		}
+	}
+
+	return result, nil
+}
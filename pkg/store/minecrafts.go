@@ -0,0 +1,25 @@
+package store
+
+import (
+	"context"
+
+	"github.com/kleister/kleister-api/pkg/model"
+)
+
+// Minecrafts provides the repository interface for minecraft versions.
+type Minecrafts interface {
+	// List returns all minecraft versions ordered by name.
+	List(context.Context) ([]*model.Minecraft, error)
+
+	// Show returns a single record by its ID or slug.
+	Show(context.Context, string) (*model.Minecraft, error)
+
+	// Create persists a new record, an ID and slug get generated if empty.
+	Create(context.Context, *model.Minecraft) (*model.Minecraft, error)
+
+	// Update persists the changes of an existing record.
+	Update(context.Context, *model.Minecraft) (*model.Minecraft, error)
+
+	// Delete removes a record by its ID or slug including its assignments.
+	Delete(context.Context, string) error
+}
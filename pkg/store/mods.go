@@ -0,0 +1,25 @@
+package store
+
+import (
+	"context"
+
+	"github.com/kleister/kleister-api/pkg/model"
+)
+
+// Mods provides the repository interface for mods.
+type Mods interface {
+	// List returns all mods ordered by name.
+	List(context.Context) ([]*model.Mod, error)
+
+	// Show returns a single record by its ID or slug.
+	Show(context.Context, string) (*model.Mod, error)
+
+	// Create persists a new record, an ID and slug get generated if empty.
+	Create(context.Context, *model.Mod) (*model.Mod, error)
+
+	// Update persists the changes of an existing record.
+	Update(context.Context, *model.Mod) (*model.Mod, error)
+
+	// Delete removes a record by its ID or slug including its assignments.
+	Delete(context.Context, string) error
+}
@@ -0,0 +1,359 @@
+package mysql
+
+import (
+	"github.com/kleister/kleister-api/pkg/store/internal/sqlstore"
+)
+
+const migrationsTable = `CREATE TABLE IF NOT EXISTS migrations (
+	version INTEGER PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	applied_at DATETIME(6) NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`
+
+// MySQL and MariaDB commit every DDL statement on its own, so a migration
+// can't be rolled back if it fails partway. Therefore every migration is
+// either a single statement, which gets applied completely or not at all, or
+// idempotent like the creation of tables. The initial migration also adopts
+// databases which have been created before the migrations have been
+// introduced. The foreign key checks get disabled to resolve the circular
+// references between packs and builds, the slug columns are limited to 191
+// characters to stay within the index size limit of utf8mb4 on older MySQL
+// and MariaDB versions. The paths of links allow 255 characters like the
+// upload keys of the other columns and Postgres, their uniqueness is
+// enforced by a generated SHA-256 column while lookups use an index on the
+// first 191 characters.
+var migrations = []sqlstore.Migration{
+	{
+		Version: 1,
+		Name:    "create_schema",
+		Up: []string{
+			"SET FOREIGN_KEY_CHECKS = 0",
+			`CREATE TABLE IF NOT EXISTS users (
+			id CHAR(36) CHARACTER SET ascii COLLATE ascii_bin PRIMARY KEY,
+			slug VARCHAR(191) COLLATE utf8mb4_bin NOT NULL UNIQUE,
+			username VARCHAR(255) NOT NULL,
+			password VARCHAR(255) NOT NULL DEFAULT '',
+			email VARCHAR(255) NOT NULL DEFAULT '',
+			admin BOOLEAN NOT NULL DEFAULT FALSE,
+			active BOOLEAN NOT NULL DEFAULT FALSE,
+			created_at DATETIME(6) NOT NULL,
+			updated_at DATETIME(6) NOT NULL
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+			`CREATE TABLE IF NOT EXISTS teams (
+			id CHAR(36) CHARACTER SET ascii COLLATE ascii_bin PRIMARY KEY,
+			slug VARCHAR(191) COLLATE utf8mb4_bin NOT NULL UNIQUE,
+			name VARCHAR(255) NOT NULL,
+			created_at DATETIME(6) NOT NULL,
+			updated_at DATETIME(6) NOT NULL
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+			`CREATE TABLE IF NOT EXISTS minecrafts (
+			id CHAR(36) CHARACTER SET ascii COLLATE ascii_bin PRIMARY KEY,
+			slug VARCHAR(191) COLLATE utf8mb4_bin NOT NULL UNIQUE,
+			name VARCHAR(255) NOT NULL,
+			type VARCHAR(255) NOT NULL DEFAULT '',
+			created_at DATETIME(6) NOT NULL,
+			updated_at DATETIME(6) NOT NULL
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+			`CREATE TABLE IF NOT EXISTS forges (
+			id CHAR(36) CHARACTER SET ascii COLLATE ascii_bin PRIMARY KEY,
+			slug VARCHAR(191) COLLATE utf8mb4_bin NOT NULL UNIQUE,
+			name VARCHAR(255) NOT NULL,
+			minecraft VARCHAR(255) NOT NULL DEFAULT '',
+			created_at DATETIME(6) NOT NULL,
+			updated_at DATETIME(6) NOT NULL
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+			`CREATE TABLE IF NOT EXISTS mods (
+			id CHAR(36) CHARACTER SET ascii COLLATE ascii_bin PRIMARY KEY,
+			slug VARCHAR(191) COLLATE utf8mb4_bin NOT NULL UNIQUE,
+			name VARCHAR(255) NOT NULL,
+			side VARCHAR(255) NOT NULL DEFAULT '',
+			description TEXT NOT NULL,
+			author VARCHAR(255) NOT NULL DEFAULT '',
+			website VARCHAR(255) NOT NULL DEFAULT '',
+			donate VARCHAR(255) NOT NULL DEFAULT '',
+			created_at DATETIME(6) NOT NULL,
+			updated_at DATETIME(6) NOT NULL
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+			`CREATE TABLE IF NOT EXISTS versions (
+			id CHAR(36) CHARACTER SET ascii COLLATE ascii_bin PRIMARY KEY,
+			mod_id CHAR(36) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,
+			slug VARCHAR(191) COLLATE utf8mb4_bin NOT NULL,
+			name VARCHAR(255) NOT NULL,
+			created_at DATETIME(6) NOT NULL,
+			updated_at DATETIME(6) NOT NULL,
+			UNIQUE (mod_id, slug),
+			FOREIGN KEY (mod_id) REFERENCES mods(id) ON DELETE CASCADE
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+			`CREATE TABLE IF NOT EXISTS packs (
+			id CHAR(36) CHARACTER SET ascii COLLATE ascii_bin PRIMARY KEY,
+			recommended_id CHAR(36) CHARACTER SET ascii COLLATE ascii_bin,
+			latest_id CHAR(36) CHARACTER SET ascii COLLATE ascii_bin,
+			slug VARCHAR(191) COLLATE utf8mb4_bin NOT NULL UNIQUE,
+			name VARCHAR(255) NOT NULL,
+			website VARCHAR(255) NOT NULL DEFAULT '',
+			published BOOLEAN NOT NULL DEFAULT FALSE,
+			hidden BOOLEAN NOT NULL DEFAULT FALSE,
+			private BOOLEAN NOT NULL DEFAULT FALSE,
+			public BOOLEAN NOT NULL DEFAULT FALSE,
+			created_at DATETIME(6) NOT NULL,
+			updated_at DATETIME(6) NOT NULL,
+			FOREIGN KEY (recommended_id) REFERENCES builds(id) ON DELETE SET NULL,
+			FOREIGN KEY (latest_id) REFERENCES builds(id) ON DELETE SET NULL
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+			`CREATE TABLE IF NOT EXISTS builds (
+			id CHAR(36) CHARACTER SET ascii COLLATE ascii_bin PRIMARY KEY,
+			pack_id CHAR(36) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,
+			minecraft_id CHAR(36) CHARACTER SET ascii COLLATE ascii_bin,
+			forge_id CHAR(36) CHARACTER SET ascii COLLATE ascii_bin,
+			slug VARCHAR(191) COLLATE utf8mb4_bin NOT NULL,
+			name VARCHAR(255) NOT NULL,
+			min_java VARCHAR(255) NOT NULL DEFAULT '',
+			min_memory VARCHAR(255) NOT NULL DEFAULT '',
+			published BOOLEAN NOT NULL DEFAULT FALSE,
+			hidden BOOLEAN NOT NULL DEFAULT FALSE,
+			private BOOLEAN NOT NULL DEFAULT FALSE,
+			public BOOLEAN NOT NULL DEFAULT FALSE,
+			created_at DATETIME(6) NOT NULL,
+			updated_at DATETIME(6) NOT NULL,
+			UNIQUE (pack_id, slug),
+			FOREIGN KEY (pack_id) REFERENCES packs(id) ON DELETE CASCADE,
+			FOREIGN KEY (minecraft_id) REFERENCES minecrafts(id) ON DELETE SET NULL,
+			FOREIGN KEY (forge_id) REFERENCES forges(id) ON DELETE SET NULL
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+			`CREATE TABLE IF NOT EXISTS build_versions (
+			build_id CHAR(36) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,
+			version_id CHAR(36) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,
+			created_at DATETIME(6) NOT NULL,
+			updated_at DATETIME(6) NOT NULL,
+			PRIMARY KEY (build_id, version_id),
+			FOREIGN KEY (build_id) REFERENCES builds(id) ON DELETE CASCADE,
+			FOREIGN KEY (version_id) REFERENCES versions(id) ON DELETE CASCADE
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+			`CREATE TABLE IF NOT EXISTS team_users (
+			team_id CHAR(36) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,
+			user_id CHAR(36) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,
+			perm VARCHAR(32) NOT NULL DEFAULT 'user',
+			created_at DATETIME(6) NOT NULL,
+			updated_at DATETIME(6) NOT NULL,
+			PRIMARY KEY (team_id, user_id),
+			FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+			`CREATE TABLE IF NOT EXISTS team_packs (
+			team_id CHAR(36) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,
+			pack_id CHAR(36) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,
+			perm VARCHAR(32) NOT NULL DEFAULT 'user',
+			created_at DATETIME(6) NOT NULL,
+			updated_at DATETIME(6) NOT NULL,
+			PRIMARY KEY (team_id, pack_id),
+			FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE,
+			FOREIGN KEY (pack_id) REFERENCES packs(id) ON DELETE CASCADE
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+			`CREATE TABLE IF NOT EXISTS team_mods (
+			team_id CHAR(36) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,
+			mod_id CHAR(36) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,
+			perm VARCHAR(32) NOT NULL DEFAULT 'user',
+			created_at DATETIME(6) NOT NULL,
+			updated_at DATETIME(6) NOT NULL,
+			PRIMARY KEY (team_id, mod_id),
+			FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE,
+			FOREIGN KEY (mod_id) REFERENCES mods(id) ON DELETE CASCADE
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+			`CREATE TABLE IF NOT EXISTS user_packs (
+			user_id CHAR(36) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,
+			pack_id CHAR(36) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,
+			perm VARCHAR(32) NOT NULL DEFAULT 'user',
+			created_at DATETIME(6) NOT NULL,
+			updated_at DATETIME(6) NOT NULL,
+			PRIMARY KEY (user_id, pack_id),
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+			FOREIGN KEY (pack_id) REFERENCES packs(id) ON DELETE CASCADE
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+			`CREATE TABLE IF NOT EXISTS user_mods (
+			user_id CHAR(36) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,
+			mod_id CHAR(36) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,
+			perm VARCHAR(32) NOT NULL DEFAULT 'user',
+			created_at DATETIME(6) NOT NULL,
+			updated_at DATETIME(6) NOT NULL,
+			PRIMARY KEY (user_id, mod_id),
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+			FOREIGN KEY (mod_id) REFERENCES mods(id) ON DELETE CASCADE
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+			"SET FOREIGN_KEY_CHECKS = 1",
+		},
+		Down: []string{
+			"SET FOREIGN_KEY_CHECKS = 0",
+			"DROP TABLE IF EXISTS user_mods, user_packs, team_mods, team_packs, team_users, build_versions, builds, packs, versions, mods, forges, minecrafts, teams, users",
+			"SET FOREIGN_KEY_CHECKS = 1",
+		},
+	},
+	{
+		Version: 2,
+		Name:    "add_version_file",
+		Up: []string{
+			`ALTER TABLE versions
+			ADD COLUMN file_size BIGINT NOT NULL DEFAULT 0,
+			ADD COLUMN file_md5 VARCHAR(32) NOT NULL DEFAULT ''`,
+		},
+		Down: []string{
+			`ALTER TABLE versions
+			DROP COLUMN file_md5,
+			DROP COLUMN file_size`,
+		},
+	},
+	{
+		Version: 3,
+		Name:    "add_version_file_details",
+		Up: []string{
+			`ALTER TABLE versions
+			ADD COLUMN file_key VARCHAR(255) NOT NULL DEFAULT '',
+			ADD COLUMN file_name VARCHAR(255) NOT NULL DEFAULT '',
+			ADD COLUMN file_sha1 VARCHAR(40) NOT NULL DEFAULT '',
+			ADD COLUMN file_sha256 VARCHAR(64) NOT NULL DEFAULT ''`,
+		},
+		Down: []string{
+			`ALTER TABLE versions
+			DROP COLUMN file_sha256,
+			DROP COLUMN file_sha1,
+			DROP COLUMN file_name,
+			DROP COLUMN file_key`,
+		},
+	},
+	{
+		Version: 4,
+		Name:    "create_blobs",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS blobs (
+			digest CHAR(64) CHARACTER SET ascii COLLATE ascii_bin PRIMARY KEY,
+			size BIGINT NOT NULL DEFAULT 0,
+			md5 VARCHAR(32) NOT NULL DEFAULT '',
+			refs INTEGER NOT NULL DEFAULT 0,
+			created_at DATETIME(6) NOT NULL,
+			updated_at DATETIME(6) NOT NULL
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+			`CREATE TABLE IF NOT EXISTS links (
+			path VARCHAR(191) COLLATE utf8mb4_bin PRIMARY KEY,
+			digest CHAR(64) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,
+			content_type VARCHAR(255) NOT NULL DEFAULT '',
+			created_at DATETIME(6) NOT NULL,
+			updated_at DATETIME(6) NOT NULL,
+			FOREIGN KEY (digest) REFERENCES blobs(digest)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+		},
+		Down: []string{
+			"DROP TABLE IF EXISTS links, blobs",
+		},
+	},
+	{
+		Version: 5,
+		Name:    "add_users_quota",
+		Up: []string{
+			"ALTER TABLE users ADD COLUMN quota BIGINT NOT NULL DEFAULT 0",
+		},
+		Down: []string{
+			"ALTER TABLE users DROP COLUMN quota",
+		},
+	},
+	{
+		Version: 6,
+		Name:    "add_teams_quota",
+		Up: []string{
+			"ALTER TABLE teams ADD COLUMN quota BIGINT NOT NULL DEFAULT 0",
+		},
+		Down: []string{
+			"ALTER TABLE teams DROP COLUMN quota",
+		},
+	},
+	{
+		Version: 7,
+		Name:    "add_version_file_metadata",
+		Up: []string{
+			`ALTER TABLE versions
+			ADD COLUMN file_loader VARCHAR(32) NOT NULL DEFAULT '',
+			ADD COLUMN file_minecraft VARCHAR(255) NOT NULL DEFAULT ''`,
+		},
+		Down: []string{
+			`ALTER TABLE versions
+			DROP COLUMN file_minecraft,
+			DROP COLUMN file_loader`,
+		},
+	},
+	{
+		Version: 8,
+		Name:    "create_versions_file_key_index",
+		Up: []string{
+			"CREATE INDEX versions_file_key_idx ON versions (file_key(191))",
+		},
+		Down: []string{
+			"DROP INDEX versions_file_key_idx ON versions",
+		},
+	},
+	{
+		Version: 9,
+		Name:    "add_version_file_broken",
+		Up: []string{
+			"ALTER TABLE versions ADD COLUMN file_broken BOOLEAN NOT NULL DEFAULT FALSE",
+		},
+		Down: []string{
+			"ALTER TABLE versions DROP COLUMN file_broken",
+		},
+	},
+	{
+		Version: 10,
+		Name:    "add_pack_images",
+		Up: []string{
+			`ALTER TABLE packs
+			ADD COLUMN logo_key VARCHAR(255) NOT NULL DEFAULT '',
+			ADD COLUMN logo_md5 VARCHAR(32) NOT NULL DEFAULT '',
+			ADD COLUMN icon_key VARCHAR(255) NOT NULL DEFAULT '',
+			ADD COLUMN icon_md5 VARCHAR(32) NOT NULL DEFAULT '',
+			ADD COLUMN background_key VARCHAR(255) NOT NULL DEFAULT '',
+			ADD COLUMN background_md5 VARCHAR(32) NOT NULL DEFAULT ''`,
+		},
+		Down: []string{
+			`ALTER TABLE packs
+			DROP COLUMN background_md5,
+			DROP COLUMN background_key,
+			DROP COLUMN icon_md5,
+			DROP COLUMN icon_key,
+			DROP COLUMN logo_md5,
+			DROP COLUMN logo_key`,
+		},
+	},
+	{
+		Version: 11,
+		Name:    "hash_links_path",
+		Up: []string{
+			`ALTER TABLE links
+			DROP PRIMARY KEY,
+			MODIFY path VARCHAR(255) COLLATE utf8mb4_bin NOT NULL,
+			ADD COLUMN path_hash CHAR(64) CHARACTER SET ascii COLLATE ascii_bin AS (SHA2(path, 256)) STORED,
+			ADD UNIQUE INDEX links_path_hash_idx (path_hash),
+			ADD INDEX links_path_idx (path(191))`,
+		},
+		Down: []string{
+			`ALTER TABLE links
+			DROP INDEX links_path_idx,
+			DROP INDEX links_path_hash_idx,
+			DROP COLUMN path_hash,
+			MODIFY path VARCHAR(191) COLLATE utf8mb4_bin NOT NULL,
+			ADD PRIMARY KEY (path)`,
+		},
+	},
+	{
+		Version: 12,
+		Name:    "add_pack_image_sizes",
+		Up: []string{
+			`ALTER TABLE packs
+			ADD COLUMN logo_size BIGINT NOT NULL DEFAULT 0,
+			ADD COLUMN icon_size BIGINT NOT NULL DEFAULT 0,
+			ADD COLUMN background_size BIGINT NOT NULL DEFAULT 0`,
+		},
+		Down: []string{
+			`ALTER TABLE packs
+			DROP COLUMN background_size,
+			DROP COLUMN icon_size,
+			DROP COLUMN logo_size`,
+		},
+	},
+}
@@ -1,25 +1,192 @@
 package mysql
 
 import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
 	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
+	driver "github.com/go-sql-driver/mysql"
 	"github.com/kleister/kleister-api/pkg/store"
+	"github.com/kleister/kleister-api/pkg/store/internal/sqlstore"
+	"github.com/pkg/errors"
 )
 
 type mysql struct {
 	dsn *url.URL
 }
 
-// Close simply closes the MySQL connection.
-func (s *mysql) Close() error {
-	return nil
+// Rebind simply returns the query as MySQL uses ? placeholders.
+func (s *mysql) Rebind(query string) string {
+	return query
+}
+
+// IsConflict checks for duplicate entry errors.
+func (s *mysql) IsConflict(err error) bool {
+	if e, ok := err.(*driver.MySQLError); ok {
+		return e.Number == 1062
+	}
+
+	return false
+}
+
+// IsForeignKey checks for foreign key violations.
+func (s *mysql) IsForeignKey(err error) bool {
+	if e, ok := err.(*driver.MySQLError); ok {
+		return e.Number == 1452
+	}
+
+	return false
+}
+
+// maxOpen retrieves the max open connections from dsn or fallback.
+func (s *mysql) maxOpen() int {
+	if val := s.dsn.Query().Get("max_open"); val != "" {
+		i, err := strconv.Atoi(val)
+
+		if err != nil {
+			return 25
+		}
+
+		return i
+	}
+
+	return 25
+}
+
+// maxIdle retrieves the max idle connections from dsn or fallback.
+func (s *mysql) maxIdle() int {
+	if val := s.dsn.Query().Get("max_idle"); val != "" {
+		i, err := strconv.Atoi(val)
+
+		if err != nil {
+			return 25
+		}
+
+		return i
+	}
+
+	return 25
+}
+
+// isolation retrieves the transaction isolation level from dsn or fallback.
+func (s *mysql) isolation() sql.IsolationLevel {
+	return sqlstore.Isolation(s.dsn.Query().Get("isolation"))
+}
+
+// connLifetime retrieves the connection lifetime from dsn or fallback.
+func (s *mysql) connLifetime() time.Duration {
+	if val := s.dsn.Query().Get("conn_lifetime"); val != "" {
+		d, err := time.ParseDuration(val)
+
+		if err != nil {
+			return 5 * time.Minute
+		}
+
+		return d
+	}
+
+	return 5 * time.Minute
+}
+
+// source translates the URL into the format of the MySQL driver. The
+// charset defaults to utf8mb4 while parseTime, loc and clientFoundRows
+// are enforced as the store depends on them.
+func (s *mysql) source() (string, error) {
+	query := s.dsn.Query()
+
+	query.Del("max_open")
+	query.Del("max_idle")
+	query.Del("conn_lifetime")
+	query.Del("isolation")
+
+	if query.Get("charset") == "" {
+		query.Set("charset", "utf8mb4")
+	}
+
+	if query.Get("collation") == "" {
+		query.Set("collation", "utf8mb4_unicode_ci")
+	}
+
+	query.Set("parseTime", "true")
+	query.Set("loc", "UTC")
+	query.Set("clientFoundRows", "true")
+
+	port := s.dsn.Port()
+
+	if port == "" {
+		port = "3306"
+	}
+
+	var auth string
+
+	if s.dsn.User != nil {
+		auth = s.dsn.User.Username()
+
+		if pass, ok := s.dsn.User.Password(); ok {
+			auth = auth + ":" + pass
+		}
+
+		auth = auth + "@"
+	}
+
+	cfg, err := driver.ParseDSN(
+		fmt.Sprintf(
+			"%stcp(%s)/%s?%s",
+			auth,
+			net.JoinHostPort(s.dsn.Hostname(), port),
+			strings.TrimPrefix(s.dsn.Path, "/"),
+			query.Encode(),
+		),
+	)
+
+	if err != nil {
+		return "", err
+	}
+
+	return cfg.FormatDSN(), nil
 }
 
 // New initializes a new MySQL connection.
 func New(dsn *url.URL) (store.Store, error) {
-	return &mysql{
+	s := &mysql{
 		dsn: dsn,
-	}, nil
+	}
+
+	source, err := s.source()
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse dsn")
+	}
+
+	handle, err := sql.Open("mysql", source)
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open database")
+	}
+
+	handle.SetMaxOpenConns(s.maxOpen())
+	handle.SetMaxIdleConns(s.maxIdle())
+	handle.SetConnMaxLifetime(s.connLifetime())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := handle.PingContext(ctx); err != nil {
+		handle.Close()
+		return nil, errors.Wrap(err, "failed to connect database")
+	}
+
+	if _, err := handle.ExecContext(ctx, migrationsTable); err != nil {
+		handle.Close()
+		return nil, errors.Wrap(err, "failed to create migrations table")
+	}
+
+	return sqlstore.New(handle, s, migrations, s.isolation()), nil
 }
 
 // Must simply calls New and panics on an error.
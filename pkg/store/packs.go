@@ -0,0 +1,29 @@
+package store
+
+import (
+	"context"
+
+	"github.com/kleister/kleister-api/pkg/model"
+)
+
+// Packs provides the repository interface for packs.
+type Packs interface {
+	// List returns all packs ordered by name.
+	List(context.Context) ([]*model.Pack, error)
+
+	// Show returns a single record by its ID or slug.
+	Show(context.Context, string) (*model.Pack, error)
+
+	// Create persists a new record, an ID and slug get generated if empty.
+	Create(context.Context, *model.Pack) (*model.Pack, error)
+
+	// Update persists the changes of an existing record.
+	Update(context.Context, *model.Pack) (*model.Pack, error)
+
+	// Delete removes a record by its ID or slug including its assignments.
+	Delete(context.Context, string) error
+
+	// Assign sets the recommended and latest builds of an existing record
+	// without touching the timestamps, it gets used to copy records.
+	Assign(context.Context, *model.Pack) error
+}
@@ -0,0 +1,305 @@
+package postgres
+
+import (
+	"github.com/kleister/kleister-api/pkg/store/internal/sqlstore"
+)
+
+const migrationsTable = `CREATE TABLE IF NOT EXISTS migrations (
+	version INTEGER PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	applied_at TIMESTAMP WITH TIME ZONE NOT NULL
+)`
+
+// The initial migration is idempotent to adopt databases which have been
+// created before the migrations have been introduced.
+var migrations = []sqlstore.Migration{
+	{
+		Version: 1,
+		Name:    "create_schema",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS users (
+			id UUID PRIMARY KEY,
+			slug VARCHAR(255) NOT NULL UNIQUE,
+			username VARCHAR(255) NOT NULL,
+			password VARCHAR(255) NOT NULL DEFAULT '',
+			email VARCHAR(255) NOT NULL DEFAULT '',
+			admin BOOLEAN NOT NULL DEFAULT FALSE,
+			active BOOLEAN NOT NULL DEFAULT FALSE,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+		)`,
+			`CREATE TABLE IF NOT EXISTS teams (
+			id UUID PRIMARY KEY,
+			slug VARCHAR(255) NOT NULL UNIQUE,
+			name VARCHAR(255) NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+		)`,
+			`CREATE TABLE IF NOT EXISTS minecrafts (
+			id UUID PRIMARY KEY,
+			slug VARCHAR(255) NOT NULL UNIQUE,
+			name VARCHAR(255) NOT NULL,
+			type VARCHAR(255) NOT NULL DEFAULT '',
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+		)`,
+			`CREATE TABLE IF NOT EXISTS forges (
+			id UUID PRIMARY KEY,
+			slug VARCHAR(255) NOT NULL UNIQUE,
+			name VARCHAR(255) NOT NULL,
+			minecraft VARCHAR(255) NOT NULL DEFAULT '',
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+		)`,
+			`CREATE TABLE IF NOT EXISTS mods (
+			id UUID PRIMARY KEY,
+			slug VARCHAR(255) NOT NULL UNIQUE,
+			name VARCHAR(255) NOT NULL,
+			side VARCHAR(255) NOT NULL DEFAULT '',
+			description TEXT NOT NULL DEFAULT '',
+			author VARCHAR(255) NOT NULL DEFAULT '',
+			website VARCHAR(255) NOT NULL DEFAULT '',
+			donate VARCHAR(255) NOT NULL DEFAULT '',
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+		)`,
+			`CREATE TABLE IF NOT EXISTS versions (
+			id UUID PRIMARY KEY,
+			mod_id UUID NOT NULL REFERENCES mods(id) ON DELETE CASCADE,
+			slug VARCHAR(255) NOT NULL,
+			name VARCHAR(255) NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			UNIQUE (mod_id, slug)
+		)`,
+			`CREATE TABLE IF NOT EXISTS packs (
+			id UUID PRIMARY KEY,
+			recommended_id UUID,
+			latest_id UUID,
+			slug VARCHAR(255) NOT NULL UNIQUE,
+			name VARCHAR(255) NOT NULL,
+			website VARCHAR(255) NOT NULL DEFAULT '',
+			published BOOLEAN NOT NULL DEFAULT FALSE,
+			hidden BOOLEAN NOT NULL DEFAULT FALSE,
+			private BOOLEAN NOT NULL DEFAULT FALSE,
+			public BOOLEAN NOT NULL DEFAULT FALSE,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+		)`,
+			`CREATE TABLE IF NOT EXISTS builds (
+			id UUID PRIMARY KEY,
+			pack_id UUID NOT NULL REFERENCES packs(id) ON DELETE CASCADE,
+			minecraft_id UUID REFERENCES minecrafts(id) ON DELETE SET NULL,
+			forge_id UUID REFERENCES forges(id) ON DELETE SET NULL,
+			slug VARCHAR(255) NOT NULL,
+			name VARCHAR(255) NOT NULL,
+			min_java VARCHAR(255) NOT NULL DEFAULT '',
+			min_memory VARCHAR(255) NOT NULL DEFAULT '',
+			published BOOLEAN NOT NULL DEFAULT FALSE,
+			hidden BOOLEAN NOT NULL DEFAULT FALSE,
+			private BOOLEAN NOT NULL DEFAULT FALSE,
+			public BOOLEAN NOT NULL DEFAULT FALSE,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			UNIQUE (pack_id, slug)
+		)`,
+			`DO $$ BEGIN
+			IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'packs_recommended_id_fkey') THEN
+				ALTER TABLE packs ADD CONSTRAINT packs_recommended_id_fkey FOREIGN KEY (recommended_id) REFERENCES builds(id) ON DELETE SET NULL;
+			END IF;
+			IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'packs_latest_id_fkey') THEN
+				ALTER TABLE packs ADD CONSTRAINT packs_latest_id_fkey FOREIGN KEY (latest_id) REFERENCES builds(id) ON DELETE SET NULL;
+			END IF;
+		END $$`,
+			`CREATE TABLE IF NOT EXISTS build_versions (
+			build_id UUID NOT NULL REFERENCES builds(id) ON DELETE CASCADE,
+			version_id UUID NOT NULL REFERENCES versions(id) ON DELETE CASCADE,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			PRIMARY KEY (build_id, version_id)
+		)`,
+			`CREATE TABLE IF NOT EXISTS team_users (
+			team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			perm VARCHAR(32) NOT NULL DEFAULT 'user',
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			PRIMARY KEY (team_id, user_id)
+		)`,
+			`CREATE TABLE IF NOT EXISTS team_packs (
+			team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+			pack_id UUID NOT NULL REFERENCES packs(id) ON DELETE CASCADE,
+			perm VARCHAR(32) NOT NULL DEFAULT 'user',
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			PRIMARY KEY (team_id, pack_id)
+		)`,
+			`CREATE TABLE IF NOT EXISTS team_mods (
+			team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+			mod_id UUID NOT NULL REFERENCES mods(id) ON DELETE CASCADE,
+			perm VARCHAR(32) NOT NULL DEFAULT 'user',
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			PRIMARY KEY (team_id, mod_id)
+		)`,
+			`CREATE TABLE IF NOT EXISTS user_packs (
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			pack_id UUID NOT NULL REFERENCES packs(id) ON DELETE CASCADE,
+			perm VARCHAR(32) NOT NULL DEFAULT 'user',
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			PRIMARY KEY (user_id, pack_id)
+		)`,
+			`CREATE TABLE IF NOT EXISTS user_mods (
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			mod_id UUID NOT NULL REFERENCES mods(id) ON DELETE CASCADE,
+			perm VARCHAR(32) NOT NULL DEFAULT 'user',
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			PRIMARY KEY (user_id, mod_id)
+		)`,
+			`CREATE INDEX IF NOT EXISTS builds_minecraft_id_idx ON builds (minecraft_id)`,
+			`CREATE INDEX IF NOT EXISTS builds_forge_id_idx ON builds (forge_id)`,
+			`CREATE INDEX IF NOT EXISTS build_versions_version_id_idx ON build_versions (version_id)`,
+			`CREATE INDEX IF NOT EXISTS team_users_user_id_idx ON team_users (user_id)`,
+			`CREATE INDEX IF NOT EXISTS team_packs_pack_id_idx ON team_packs (pack_id)`,
+			`CREATE INDEX IF NOT EXISTS team_mods_mod_id_idx ON team_mods (mod_id)`,
+			`CREATE INDEX IF NOT EXISTS user_packs_pack_id_idx ON user_packs (pack_id)`,
+			`CREATE INDEX IF NOT EXISTS user_mods_mod_id_idx ON user_mods (mod_id)`,
+		},
+		Down: []string{
+			"DROP TABLE IF EXISTS user_mods, user_packs, team_mods, team_packs, team_users, build_versions, builds, packs, versions, mods, forges, minecrafts, teams, users CASCADE",
+		},
+	},
+	{
+		Version: 2,
+		Name:    "add_version_file",
+		Up: []string{
+			"ALTER TABLE versions ADD COLUMN file_size BIGINT NOT NULL DEFAULT 0",
+			"ALTER TABLE versions ADD COLUMN file_md5 VARCHAR(32) NOT NULL DEFAULT ''",
+		},
+		Down: []string{
+			"ALTER TABLE versions DROP COLUMN file_md5",
+			"ALTER TABLE versions DROP COLUMN file_size",
+		},
+	},
+	{
+		Version: 3,
+		Name:    "add_version_file_details",
+		Up: []string{
+			"ALTER TABLE versions ADD COLUMN file_key VARCHAR(255) NOT NULL DEFAULT ''",
+			"ALTER TABLE versions ADD COLUMN file_name VARCHAR(255) NOT NULL DEFAULT ''",
+			"ALTER TABLE versions ADD COLUMN file_sha1 VARCHAR(40) NOT NULL DEFAULT ''",
+			"ALTER TABLE versions ADD COLUMN file_sha256 VARCHAR(64) NOT NULL DEFAULT ''",
+		},
+		Down: []string{
+			"ALTER TABLE versions DROP COLUMN file_sha256",
+			"ALTER TABLE versions DROP COLUMN file_sha1",
+			"ALTER TABLE versions DROP COLUMN file_name",
+			"ALTER TABLE versions DROP COLUMN file_key",
+		},
+	},
+	{
+		Version: 4,
+		Name:    "create_blobs",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS blobs (
+			digest VARCHAR(64) PRIMARY KEY,
+			size BIGINT NOT NULL DEFAULT 0,
+			md5 VARCHAR(32) NOT NULL DEFAULT '',
+			refs INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+		)`,
+			`CREATE TABLE IF NOT EXISTS links (
+			path VARCHAR(255) PRIMARY KEY,
+			digest VARCHAR(64) NOT NULL REFERENCES blobs(digest),
+			content_type VARCHAR(255) NOT NULL DEFAULT '',
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+		)`,
+			`CREATE INDEX IF NOT EXISTS links_digest_idx ON links (digest)`,
+		},
+		Down: []string{
+			"DROP TABLE IF EXISTS links, blobs",
+		},
+	},
+	{
+		Version: 5,
+		Name:    "add_quotas",
+		Up: []string{
+			"ALTER TABLE users ADD COLUMN quota BIGINT NOT NULL DEFAULT 0",
+			"ALTER TABLE teams ADD COLUMN quota BIGINT NOT NULL DEFAULT 0",
+		},
+		Down: []string{
+			"ALTER TABLE teams DROP COLUMN quota",
+			"ALTER TABLE users DROP COLUMN quota",
+		},
+	},
+	{
+		Version: 6,
+		Name:    "add_version_file_metadata",
+		Up: []string{
+			"ALTER TABLE versions ADD COLUMN file_loader VARCHAR(32) NOT NULL DEFAULT ''",
+			"ALTER TABLE versions ADD COLUMN file_minecraft VARCHAR(255) NOT NULL DEFAULT ''",
+		},
+		Down: []string{
+			"ALTER TABLE versions DROP COLUMN file_minecraft",
+			"ALTER TABLE versions DROP COLUMN file_loader",
+		},
+	},
+	{
+		Version: 7,
+		Name:    "create_versions_file_key_index",
+		Up: []string{
+			"CREATE INDEX IF NOT EXISTS versions_file_key_idx ON versions (file_key)",
+		},
+		Down: []string{
+			"DROP INDEX IF EXISTS versions_file_key_idx",
+		},
+	},
+	{
+		Version: 8,
+		Name:    "add_version_file_broken",
+		Up: []string{
+			"ALTER TABLE versions ADD COLUMN file_broken BOOLEAN NOT NULL DEFAULT FALSE",
+		},
+		Down: []string{
+			"ALTER TABLE versions DROP COLUMN file_broken",
+		},
+	},
+	{
+		Version: 9,
+		Name:    "add_pack_images",
+		Up: []string{
+			"ALTER TABLE packs ADD COLUMN logo_key VARCHAR(255) NOT NULL DEFAULT ''",
+			"ALTER TABLE packs ADD COLUMN logo_md5 VARCHAR(32) NOT NULL DEFAULT ''",
+			"ALTER TABLE packs ADD COLUMN icon_key VARCHAR(255) NOT NULL DEFAULT ''",
+			"ALTER TABLE packs ADD COLUMN icon_md5 VARCHAR(32) NOT NULL DEFAULT ''",
+			"ALTER TABLE packs ADD COLUMN background_key VARCHAR(255) NOT NULL DEFAULT ''",
+			"ALTER TABLE packs ADD COLUMN background_md5 VARCHAR(32) NOT NULL DEFAULT ''",
+		},
+		Down: []string{
+			"ALTER TABLE packs DROP COLUMN background_md5",
+			"ALTER TABLE packs DROP COLUMN background_key",
+			"ALTER TABLE packs DROP COLUMN icon_md5",
+			"ALTER TABLE packs DROP COLUMN icon_key",
+			"ALTER TABLE packs DROP COLUMN logo_md5",
+			"ALTER TABLE packs DROP COLUMN logo_key",
+		},
+	},
+	{
+		Version: 10,
+		Name:    "add_pack_image_sizes",
+		Up: []string{
+			"ALTER TABLE packs ADD COLUMN logo_size BIGINT NOT NULL DEFAULT 0",
+			"ALTER TABLE packs ADD COLUMN icon_size BIGINT NOT NULL DEFAULT 0",
+			"ALTER TABLE packs ADD COLUMN background_size BIGINT NOT NULL DEFAULT 0",
+		},
+		Down: []string{
+			"ALTER TABLE packs DROP COLUMN background_size",
+			"ALTER TABLE packs DROP COLUMN icon_size",
+			"ALTER TABLE packs DROP COLUMN logo_size",
+		},
+	},
+}
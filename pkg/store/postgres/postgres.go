@@ -1,25 +1,157 @@
 package postgres
 
 import (
+	"context"
+	"database/sql"
 	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/kleister/kleister-api/pkg/store"
+	"github.com/kleister/kleister-api/pkg/store/internal/sqlstore"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
 )
 
 type postgres struct {
 	dsn *url.URL
 }
 
-// Close simply closes the PostgreSQL connection.
-func (s *postgres) Close() error {
-	return nil
+// Rebind converts the ? placeholders into numbered placeholders.
+func (s *postgres) Rebind(query string) string {
+	var (
+		b strings.Builder
+		n int
+	)
+
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// IsConflict checks for unique violations.
+func (s *postgres) IsConflict(err error) bool {
+	if e, ok := err.(*pq.Error); ok {
+		return e.Code == "23505"
+	}
+
+	return false
+}
+
+// IsForeignKey checks for foreign key violations.
+func (s *postgres) IsForeignKey(err error) bool {
+	if e, ok := err.(*pq.Error); ok {
+		return e.Code == "23503"
+	}
+
+	return false
+}
+
+// maxOpen retrieves the max open connections from dsn or fallback.
+func (s *postgres) maxOpen() int {
+	if val := s.dsn.Query().Get("max_open"); val != "" {
+		i, err := strconv.Atoi(val)
+
+		if err != nil {
+			return 25
+		}
+
+		return i
+	}
+
+	return 25
+}
+
+// maxIdle retrieves the max idle connections from dsn or fallback.
+func (s *postgres) maxIdle() int {
+	if val := s.dsn.Query().Get("max_idle"); val != "" {
+		i, err := strconv.Atoi(val)
+
+		if err != nil {
+			return 25
+		}
+
+		return i
+	}
+
+	return 25
+}
+
+// isolation retrieves the transaction isolation level from dsn or fallback.
+func (s *postgres) isolation() sql.IsolationLevel {
+	return sqlstore.Isolation(s.dsn.Query().Get("isolation"))
+}
+
+// connLifetime retrieves the connection lifetime from dsn or fallback.
+func (s *postgres) connLifetime() time.Duration {
+	if val := s.dsn.Query().Get("conn_lifetime"); val != "" {
+		d, err := time.ParseDuration(val)
+
+		if err != nil {
+			return 5 * time.Minute
+		}
+
+		return d
+	}
+
+	return 5 * time.Minute
+}
+
+// source strips the pool and transaction options from the dsn as they are
+// not supported by the driver itself, all other query options like sslmode
+// are kept.
+func (s *postgres) source() string {
+	result := *s.dsn
+	query := result.Query()
+
+	query.Del("max_open")
+	query.Del("max_idle")
+	query.Del("conn_lifetime")
+	query.Del("isolation")
+
+	result.RawQuery = query.Encode()
+	return result.String()
 }
 
 // New initializes a new PostgreSQL connection.
 func New(dsn *url.URL) (store.Store, error) {
-	return &postgres{
+	s := &postgres{
 		dsn: dsn,
-	}, nil
+	}
+
+	handle, err := sql.Open("postgres", s.source())
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open database")
+	}
+
+	handle.SetMaxOpenConns(s.maxOpen())
+	handle.SetMaxIdleConns(s.maxIdle())
+	handle.SetConnMaxLifetime(s.connLifetime())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := handle.PingContext(ctx); err != nil {
+		handle.Close()
+		return nil, errors.Wrap(err, "failed to connect database")
+	}
+
+	if _, err := handle.ExecContext(ctx, migrationsTable); err != nil {
+		handle.Close()
+		return nil, errors.Wrap(err, "failed to create migrations table")
+	}
+
+	return sqlstore.New(handle, s, migrations, s.isolation()), nil
 }
 
 // Must simply calls New and panics on an error.
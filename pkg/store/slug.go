@@ -0,0 +1,46 @@
+package store
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Slugify generates a URL friendly slug from the given name.
+func Slugify(name string) string {
+	var (
+		b    strings.Builder
+		dash bool
+	)
+
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			b.WriteRune(r)
+			dash = false
+		case r == '.' || r == '_':
+			b.WriteRune(r)
+			dash = false
+		default:
+			if !dash && b.Len() > 0 {
+				b.WriteRune('-')
+				dash = true
+			}
+		}
+	}
+
+	return strings.TrimRight(b.String(), "-")
+}
+
+// SlugOrDefault returns the slug or generates one from the name, the
+// fallback gets used if the name doesn't contain any usable characters.
+func SlugOrDefault(slug, name, fallback string) string {
+	if slug != "" {
+		return slug
+	}
+
+	if val := Slugify(name); val != "" {
+		return val
+	}
+
+	return fallback
+}
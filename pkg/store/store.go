@@ -1,15 +1,56 @@
 package store
 
 import (
+	"context"
+
 	"github.com/pkg/errors"
 )
 
 var (
 	// ErrUnknownDriver defines a named error for unknown store drivers.
 	ErrUnknownDriver = errors.New("unknown database driver")
+
+	// ErrNotFound defines a named error if a record could not be found.
+	ErrNotFound = errors.New("record not found")
+
+	// ErrConflict defines a named error if a record or slug already exists.
+	ErrConflict = errors.New("record already exists")
 )
 
+// Repositories provides access to the repositories of all entities.
+type Repositories interface {
+	Users() Users
+	Teams() Teams
+	Packs() Packs
+	Builds() Builds
+	Mods() Mods
+	Versions() Versions
+	Minecrafts() Minecrafts
+	Forges() Forges
+
+	TeamUsers() TeamUsers
+	TeamPacks() TeamPacks
+	TeamMods() TeamMods
+	UserPacks() UserPacks
+	UserMods() UserMods
+	BuildVersions() BuildVersions
+	Blobs() Blobs
+	Links() Links
+}
+
 // Store provides the interface for the store implementations.
 type Store interface {
+	Repositories
+
+	// Transaction executes the function with repositories bound to a single
+	// transaction, it gets committed if the function returns nil and rolled
+	// back otherwise.
+	Transaction(context.Context, func(Repositories) error) error
+
+	// Snapshot executes the function with repositories bound to a consistent
+	// read-only view of all records, it must not write any records.
+	Snapshot(context.Context, func(Repositories) error) error
+
+	Migrations() Migrations
 	Close() error
 }
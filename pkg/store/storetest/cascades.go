@@ -0,0 +1,136 @@
+package storetest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+func testCascades(t *testing.T, s store.Store) {
+	ctx := context.Background()
+
+	team, err := s.Teams().Create(ctx, &model.Team{Name: "Cascade"})
+	must(t, err)
+
+	user, err := s.Users().Create(ctx, &model.User{Username: "cascade"})
+	must(t, err)
+
+	minecraft, err := s.Minecrafts().Create(ctx, &model.Minecraft{Name: "1.12.2"})
+	must(t, err)
+
+	forge, err := s.Forges().Create(ctx, &model.Forge{Name: "14.23.5.2838"})
+	must(t, err)
+
+	pack, err := s.Packs().Create(ctx, &model.Pack{Name: "Cascade Pack"})
+	must(t, err)
+
+	build, err := s.Builds().Create(ctx, &model.Build{PackID: pack.ID, MinecraftID: minecraft.ID, ForgeID: forge.ID, Name: "1.0.0"})
+	must(t, err)
+
+	latest, err := s.Builds().Create(ctx, &model.Build{PackID: pack.ID, Name: "2.0.0"})
+	must(t, err)
+
+	pack.RecommendedID = build.ID
+	pack.LatestID = latest.ID
+
+	_, err = s.Packs().Update(ctx, pack)
+	must(t, err)
+
+	mod, err := s.Mods().Create(ctx, &model.Mod{Name: "Cascade Mod"})
+	must(t, err)
+
+	version, err := s.Versions().Create(ctx, &model.Version{ModID: mod.ID, Name: "1.0.0"})
+	must(t, err)
+
+	must(t, s.BuildVersions().Append(ctx, &model.BuildVersion{BuildID: build.ID, VersionID: version.ID}))
+	must(t, s.BuildVersions().Append(ctx, &model.BuildVersion{BuildID: latest.ID, VersionID: version.ID}))
+	must(t, s.TeamPacks().Append(ctx, &model.TeamPack{TeamID: team.ID, PackID: pack.ID}))
+	must(t, s.TeamMods().Append(ctx, &model.TeamMod{TeamID: team.ID, ModID: mod.ID}))
+	must(t, s.UserPacks().Append(ctx, &model.UserPack{UserID: user.ID, PackID: pack.ID}))
+	must(t, s.UserMods().Append(ctx, &model.UserMod{UserID: user.ID, ModID: mod.ID}))
+	must(t, s.TeamUsers().Append(ctx, &model.TeamUser{TeamID: team.ID, UserID: user.ID}))
+
+	t.Run("Minecraft", func(t *testing.T) {
+		must(t, s.Minecrafts().Delete(ctx, minecraft.ID))
+
+		shown, err := s.Builds().Show(ctx, pack.ID, build.ID)
+		must(t, err)
+		equal(t, "minecraft", shown.MinecraftID, "")
+		equal(t, "forge", shown.ForgeID, forge.ID)
+	})
+
+	t.Run("Forge", func(t *testing.T) {
+		must(t, s.Forges().Delete(ctx, forge.ID))
+
+		shown, err := s.Builds().Show(ctx, pack.ID, build.ID)
+		must(t, err)
+		equal(t, "forge", shown.ForgeID, "")
+	})
+
+	t.Run("Build", func(t *testing.T) {
+		must(t, s.Builds().Delete(ctx, pack.ID, latest.ID))
+
+		shown, err := s.Packs().Show(ctx, pack.ID)
+		must(t, err)
+		equal(t, "recommended", shown.RecommendedID, build.ID)
+		equal(t, "latest", shown.LatestID, "")
+
+		records, err := s.BuildVersions().ListByVersion(ctx, version.ID)
+		must(t, err)
+		equal(t, "count", len(records), 1)
+	})
+
+	t.Run("Mod", func(t *testing.T) {
+		must(t, s.Mods().Delete(ctx, mod.ID))
+
+		_, err := s.Versions().Show(ctx, mod.ID, version.ID)
+		expect(t, err, store.ErrNotFound)
+
+		versions, err := s.BuildVersions().ListByBuild(ctx, build.ID)
+		must(t, err)
+		equal(t, "versions", len(versions), 0)
+
+		teams, err := s.TeamMods().ListByTeam(ctx, team.ID)
+		must(t, err)
+		equal(t, "teams", len(teams), 0)
+
+		users, err := s.UserMods().ListByUser(ctx, user.ID)
+		must(t, err)
+		equal(t, "users", len(users), 0)
+	})
+
+	t.Run("Pack", func(t *testing.T) {
+		must(t, s.Packs().Delete(ctx, pack.ID))
+
+		_, err := s.Builds().Show(ctx, pack.ID, build.ID)
+		expect(t, err, store.ErrNotFound)
+
+		_, err = s.Builds().List(ctx, pack.ID)
+		expect(t, err, store.ErrNotFound)
+
+		teams, err := s.TeamPacks().ListByTeam(ctx, team.ID)
+		must(t, err)
+		equal(t, "teams", len(teams), 0)
+
+		users, err := s.UserPacks().ListByUser(ctx, user.ID)
+		must(t, err)
+		equal(t, "users", len(users), 0)
+	})
+
+	t.Run("User", func(t *testing.T) {
+		must(t, s.Users().Delete(ctx, user.ID))
+
+		records, err := s.TeamUsers().ListByTeam(ctx, team.ID)
+		must(t, err)
+		equal(t, "count", len(records), 0)
+	})
+
+	t.Run("Team", func(t *testing.T) {
+		must(t, s.Teams().Delete(ctx, team.ID))
+
+		_, err := s.TeamUsers().ListByTeam(ctx, team.ID)
+		expect(t, err, store.ErrNotFound)
+	})
+}
@@ -0,0 +1,536 @@
+package storetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+func testUsers(t *testing.T, s store.Store) {
+	ctx := context.Background()
+
+	created, err := s.Users().Create(ctx, &model.User{Username: "Jane Doe", Email: "jane@example.com"})
+	must(t, err)
+
+	if created.ID == "" || created.CreatedAt.IsZero() || created.UpdatedAt.IsZero() {
+		t.Fatalf("expected generated id and timestamps, got %+v", created)
+	}
+
+	equal(t, "slug", created.Slug, "jane-doe")
+
+	for _, id := range []string{created.ID, created.Slug} {
+		shown, err := s.Users().Show(ctx, id)
+		must(t, err)
+		equal(t, "id", shown.ID, created.ID)
+		equal(t, "email", shown.Email, "jane@example.com")
+	}
+
+	created.Email = "doe@example.com"
+	created.Admin = true
+	created.Quota = 1 << 30
+
+	updated, err := s.Users().Update(ctx, created)
+	must(t, err)
+	equal(t, "email", updated.Email, "doe@example.com")
+	equal(t, "quota", updated.Quota, int64(1<<30))
+	equal(t, "created", updated.CreatedAt.Equal(created.CreatedAt), true)
+	equal(t, "updated", updated.UpdatedAt.Before(created.UpdatedAt), false)
+
+	shown, err := s.Users().Show(ctx, created.ID)
+	must(t, err)
+	equal(t, "admin", shown.Admin, true)
+
+	_, err = s.Users().Create(ctx, &model.User{Username: "Alice"})
+	must(t, err)
+
+	records, err := s.Users().List(ctx)
+	must(t, err)
+	equal(t, "count", len(records), 2)
+	equal(t, "first", records[0].Username, "Alice")
+	equal(t, "second", records[1].Username, "Jane Doe")
+
+	must(t, s.Users().Delete(ctx, created.Slug))
+
+	_, err = s.Users().Show(ctx, created.ID)
+	expect(t, err, store.ErrNotFound)
+	expect(t, s.Users().Delete(ctx, created.ID), store.ErrNotFound)
+
+	_, err = s.Users().Update(ctx, &model.User{ID: uuid.New().String(), Username: "Ghost"})
+	expect(t, err, store.ErrNotFound)
+
+	_, err = s.Users().Show(ctx, "unknown")
+	expect(t, err, store.ErrNotFound)
+}
+
+func testTeams(t *testing.T, s store.Store) {
+	ctx := context.Background()
+
+	created, err := s.Teams().Create(ctx, &model.Team{Name: "Owners"})
+	must(t, err)
+	equal(t, "slug", created.Slug, "owners")
+
+	_, err = s.Teams().Create(ctx, &model.Team{Name: "Developers"})
+	must(t, err)
+
+	created.Name = "Maintainers"
+	created.Slug = "maintainers"
+	created.Quota = -1
+
+	_, err = s.Teams().Update(ctx, created)
+	must(t, err)
+
+	_, err = s.Teams().Show(ctx, "owners")
+	expect(t, err, store.ErrNotFound)
+
+	shown, err := s.Teams().Show(ctx, "maintainers")
+	must(t, err)
+	equal(t, "name", shown.Name, "Maintainers")
+	equal(t, "quota", shown.Quota, int64(-1))
+
+	records, err := s.Teams().List(ctx)
+	must(t, err)
+	equal(t, "count", len(records), 2)
+	equal(t, "first", records[0].Name, "Developers")
+
+	must(t, s.Teams().Delete(ctx, created.ID))
+	expect(t, s.Teams().Delete(ctx, created.ID), store.ErrNotFound)
+}
+
+func testMinecrafts(t *testing.T, s store.Store) {
+	ctx := context.Background()
+
+	created, err := s.Minecrafts().Create(ctx, &model.Minecraft{Name: "1.12.2", Type: "release"})
+	must(t, err)
+	equal(t, "slug", created.Slug, "1.12.2")
+
+	_, err = s.Minecrafts().Create(ctx, &model.Minecraft{Name: "1.10.2", Type: "release"})
+	must(t, err)
+
+	created.Type = "snapshot"
+
+	_, err = s.Minecrafts().Update(ctx, created)
+	must(t, err)
+
+	shown, err := s.Minecrafts().Show(ctx, "1.12.2")
+	must(t, err)
+	equal(t, "type", shown.Type, "snapshot")
+
+	records, err := s.Minecrafts().List(ctx)
+	must(t, err)
+	equal(t, "count", len(records), 2)
+	equal(t, "first", records[0].Name, "1.10.2")
+
+	must(t, s.Minecrafts().Delete(ctx, created.ID))
+
+	_, err = s.Minecrafts().Show(ctx, created.ID)
+	expect(t, err, store.ErrNotFound)
+}
+
+func testForges(t *testing.T, s store.Store) {
+	ctx := context.Background()
+
+	created, err := s.Forges().Create(ctx, &model.Forge{Name: "14.23.5.2838", Minecraft: "1.12.2"})
+	must(t, err)
+	equal(t, "slug", created.Slug, "14.23.5.2838")
+
+	_, err = s.Forges().Create(ctx, &model.Forge{Name: "12.18.3.2511", Minecraft: "1.10.2"})
+	must(t, err)
+
+	created.Minecraft = "1.12"
+
+	_, err = s.Forges().Update(ctx, created)
+	must(t, err)
+
+	shown, err := s.Forges().Show(ctx, created.ID)
+	must(t, err)
+	equal(t, "minecraft", shown.Minecraft, "1.12")
+
+	records, err := s.Forges().List(ctx)
+	must(t, err)
+	equal(t, "count", len(records), 2)
+	equal(t, "first", records[0].Name, "12.18.3.2511")
+
+	must(t, s.Forges().Delete(ctx, created.Slug))
+
+	_, err = s.Forges().Show(ctx, created.ID)
+	expect(t, err, store.ErrNotFound)
+}
+
+func testMods(t *testing.T, s store.Store) {
+	ctx := context.Background()
+
+	created, err := s.Mods().Create(ctx, &model.Mod{Name: "Just Enough Items", Side: "both"})
+	must(t, err)
+	equal(t, "slug", created.Slug, "just-enough-items")
+
+	_, err = s.Mods().Create(ctx, &model.Mod{Name: "Applied Energistics"})
+	must(t, err)
+
+	shadow, err := s.Mods().Create(ctx, &model.Mod{Name: "Shadow", Slug: created.ID})
+	must(t, err)
+	equal(t, "slug", shadow.Slug, created.ID)
+
+	shown, err := s.Mods().Show(ctx, created.ID)
+	must(t, err)
+	equal(t, "id", shown.ID, created.ID)
+
+	must(t, s.Mods().Delete(ctx, shadow.ID))
+
+	orphan, err := s.Mods().Create(ctx, &model.Mod{Name: "Orphan", Slug: uuid.New().String()})
+	must(t, err)
+
+	_, err = s.Mods().Show(ctx, orphan.Slug)
+	expect(t, err, store.ErrNotFound)
+
+	must(t, s.Mods().Delete(ctx, orphan.ID))
+
+	created.Author = "mezz"
+
+	_, err = s.Mods().Update(ctx, created)
+	must(t, err)
+
+	shown, err = s.Mods().Show(ctx, "just-enough-items")
+	must(t, err)
+	equal(t, "author", shown.Author, "mezz")
+	equal(t, "side", shown.Side, "both")
+
+	records, err := s.Mods().List(ctx)
+	must(t, err)
+	equal(t, "count", len(records), 2)
+	equal(t, "first", records[0].Name, "Applied Energistics")
+
+	must(t, s.Mods().Delete(ctx, created.ID))
+
+	_, err = s.Mods().Show(ctx, created.ID)
+	expect(t, err, store.ErrNotFound)
+}
+
+func testVersions(t *testing.T, s store.Store) {
+	ctx := context.Background()
+
+	mod, err := s.Mods().Create(ctx, &model.Mod{Name: "Thaumcraft"})
+	must(t, err)
+
+	other, err := s.Mods().Create(ctx, &model.Mod{Name: "Botania"})
+	must(t, err)
+
+	created, err := s.Versions().Create(ctx, &model.Version{
+		ModID:         mod.ID,
+		Name:          "6.1.0",
+		FileKey:       "mods/thaumcraft/thaumcraft-6.1.0.jar",
+		FileName:      "thaumcraft-6.1.0.jar",
+		FileSize:      1024,
+		FileMD5:       "0cc175b9c0f1b6a831c399e269772661",
+		FileSHA1:      "86f7e437faa5a7fce15d1ddcb9eaeaea377667b8",
+		FileSHA256:    "ca978112ca1bbdcafac231b39a23dc4da786eff8147c4e72b9807785afee48bb",
+		FileLoader:    "forge",
+		FileMinecraft: "[1.12,1.13)",
+	})
+	must(t, err)
+	equal(t, "slug", created.Slug, "6.1.0")
+
+	_, err = s.Versions().Create(ctx, &model.Version{ModID: mod.ID, Name: "5.2.4"})
+	must(t, err)
+
+	_, err = s.Versions().Create(ctx, &model.Version{ModID: uuid.New().String(), Name: "1.0.0"})
+	expect(t, err, store.ErrNotFound)
+
+	shown, err := s.Versions().Show(ctx, mod.ID, "6.1.0")
+	must(t, err)
+	equal(t, "id", shown.ID, created.ID)
+	equal(t, "file size", shown.FileSize, int64(1024))
+	equal(t, "file md5", shown.FileMD5, created.FileMD5)
+	equal(t, "file key", shown.FileKey, created.FileKey)
+	equal(t, "file name", shown.FileName, created.FileName)
+	equal(t, "file sha1", shown.FileSHA1, created.FileSHA1)
+	equal(t, "file sha256", shown.FileSHA256, created.FileSHA256)
+	equal(t, "file loader", shown.FileLoader, "forge")
+	equal(t, "file minecraft", shown.FileMinecraft, created.FileMinecraft)
+	equal(t, "file broken", shown.FileBroken, false)
+
+	_, err = s.Versions().Show(ctx, other.ID, created.ID)
+	expect(t, err, store.ErrNotFound)
+
+	created.Name = "6.1.1"
+	created.FileBroken = true
+
+	updated, err := s.Versions().Update(ctx, created)
+	must(t, err)
+	equal(t, "slug", updated.Slug, "6.1.0")
+
+	shown, err = s.Versions().Show(ctx, mod.ID, created.ID)
+	must(t, err)
+	equal(t, "file broken", shown.FileBroken, true)
+
+	records, err := s.Versions().List(ctx, mod.ID)
+	must(t, err)
+	equal(t, "count", len(records), 2)
+	equal(t, "first", records[0].Name, "5.2.4")
+	equal(t, "second", records[1].Name, "6.1.1")
+
+	records, err = s.Versions().List(ctx, other.ID)
+	must(t, err)
+	equal(t, "count", len(records), 0)
+
+	_, err = s.Versions().List(ctx, uuid.New().String())
+	expect(t, err, store.ErrNotFound)
+
+	records, err = s.Versions().ListByFile(ctx, created.FileKey)
+	must(t, err)
+	equal(t, "count", len(records), 1)
+	equal(t, "file", records[0].ID, created.ID)
+
+	records, err = s.Versions().ListByFile(ctx, "")
+	must(t, err)
+	equal(t, "count", len(records), 0)
+
+	expect(t, s.Versions().Delete(ctx, other.ID, created.ID), store.ErrNotFound)
+	must(t, s.Versions().Delete(ctx, mod.ID, created.ID))
+
+	_, err = s.Versions().Show(ctx, mod.ID, created.ID)
+	expect(t, err, store.ErrNotFound)
+}
+
+func testPacks(t *testing.T, s store.Store) {
+	ctx := context.Background()
+
+	created, err := s.Packs().Create(ctx, &model.Pack{Name: "Hexxit", Website: "https://example.com"})
+	must(t, err)
+	equal(t, "slug", created.Slug, "hexxit")
+
+	other, err := s.Packs().Create(ctx, &model.Pack{Name: "Attack of the B-Team"})
+	must(t, err)
+
+	own, err := s.Builds().Create(ctx, &model.Build{PackID: created.ID, Name: "1.0.0"})
+	must(t, err)
+
+	foreign, err := s.Builds().Create(ctx, &model.Build{PackID: other.ID, Name: "1.0.0"})
+	must(t, err)
+
+	created.RecommendedID = foreign.ID
+
+	_, err = s.Packs().Update(ctx, created)
+	expect(t, err, store.ErrNotFound)
+
+	created.RecommendedID = own.ID
+	created.LatestID = own.ID
+	created.Published = true
+	created.LogoKey = "packs/hexxit/logo/launcher.png"
+	created.LogoMD5 = "0cc175b9c0f1b6a831c399e269772661"
+	created.LogoSize = 4096
+
+	_, err = s.Packs().Update(ctx, created)
+	must(t, err)
+
+	shown, err := s.Packs().Show(ctx, "hexxit")
+	must(t, err)
+	equal(t, "recommended", shown.RecommendedID, own.ID)
+	equal(t, "latest", shown.LatestID, own.ID)
+	equal(t, "published", shown.Published, true)
+	equal(t, "logo key", shown.LogoKey, created.LogoKey)
+	equal(t, "logo md5", shown.LogoMD5, created.LogoMD5)
+	equal(t, "logo size", shown.LogoSize, int64(4096))
+	equal(t, "icon key", shown.IconKey, "")
+
+	records, err := s.Packs().List(ctx)
+	must(t, err)
+	equal(t, "count", len(records), 2)
+	equal(t, "first", records[0].Name, "Attack of the B-Team")
+
+	must(t, s.Packs().Delete(ctx, created.ID))
+
+	_, err = s.Packs().Show(ctx, created.ID)
+	expect(t, err, store.ErrNotFound)
+}
+
+func testBuilds(t *testing.T, s store.Store) {
+	ctx := context.Background()
+
+	pack, err := s.Packs().Create(ctx, &model.Pack{Name: "Tekkit"})
+	must(t, err)
+
+	other, err := s.Packs().Create(ctx, &model.Pack{Name: "Voltz"})
+	must(t, err)
+
+	minecraft, err := s.Minecrafts().Create(ctx, &model.Minecraft{Name: "1.7.10"})
+	must(t, err)
+
+	forge, err := s.Forges().Create(ctx, &model.Forge{Name: "10.13.4.1614"})
+	must(t, err)
+
+	created, err := s.Builds().Create(ctx, &model.Build{
+		PackID:      pack.ID,
+		MinecraftID: minecraft.ID,
+		ForgeID:     forge.ID,
+		Name:        "2.0.0",
+		MinJava:     "1.8",
+	})
+
+	must(t, err)
+	equal(t, "slug", created.Slug, "2.0.0")
+
+	_, err = s.Builds().Create(ctx, &model.Build{PackID: pack.ID, Name: "1.0.0"})
+	must(t, err)
+
+	_, err = s.Builds().Create(ctx, &model.Build{PackID: pack.ID, MinecraftID: uuid.New().String(), Name: "3.0.0"})
+	expect(t, err, store.ErrNotFound)
+
+	_, err = s.Builds().Create(ctx, &model.Build{PackID: pack.ID, ForgeID: uuid.New().String(), Name: "3.0.0"})
+	expect(t, err, store.ErrNotFound)
+
+	_, err = s.Builds().Create(ctx, &model.Build{PackID: uuid.New().String(), Name: "3.0.0"})
+	expect(t, err, store.ErrNotFound)
+
+	shown, err := s.Builds().Show(ctx, pack.ID, "2.0.0")
+	must(t, err)
+	equal(t, "minecraft", shown.MinecraftID, minecraft.ID)
+	equal(t, "forge", shown.ForgeID, forge.ID)
+	equal(t, "java", shown.MinJava, "1.8")
+
+	_, err = s.Builds().Show(ctx, other.ID, created.ID)
+	expect(t, err, store.ErrNotFound)
+
+	created.ForgeID = ""
+	created.Hidden = true
+
+	_, err = s.Builds().Update(ctx, created)
+	must(t, err)
+
+	shown, err = s.Builds().Show(ctx, pack.ID, created.ID)
+	must(t, err)
+	equal(t, "forge", shown.ForgeID, "")
+	equal(t, "hidden", shown.Hidden, true)
+
+	moved := *created
+	moved.PackID = other.ID
+
+	_, err = s.Builds().Update(ctx, &moved)
+	expect(t, err, store.ErrNotFound)
+
+	records, err := s.Builds().List(ctx, pack.ID)
+	must(t, err)
+	equal(t, "count", len(records), 2)
+	equal(t, "first", records[0].Name, "1.0.0")
+	equal(t, "second", records[1].Name, "2.0.0")
+
+	_, err = s.Builds().List(ctx, uuid.New().String())
+	expect(t, err, store.ErrNotFound)
+
+	expect(t, s.Builds().Delete(ctx, other.ID, created.ID), store.ErrNotFound)
+	must(t, s.Builds().Delete(ctx, pack.ID, "2.0.0"))
+
+	_, err = s.Builds().Show(ctx, pack.ID, created.ID)
+	expect(t, err, store.ErrNotFound)
+}
+
+func testPreserve(t *testing.T, s store.Store) {
+	ctx := context.Background()
+	id := uuid.New().String()
+	created := time.Date(2019, 5, 1, 10, 30, 0, 0, time.UTC)
+	updated := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	record, err := s.Packs().Create(ctx, &model.Pack{
+		ID:        id,
+		Slug:      "imported",
+		Name:      "Imported Pack",
+		CreatedAt: created,
+		UpdatedAt: updated,
+	})
+
+	must(t, err)
+	equal(t, "id", record.ID, id)
+
+	shown, err := s.Packs().Show(ctx, id)
+	must(t, err)
+	equal(t, "slug", shown.Slug, "imported")
+	equal(t, "created", shown.CreatedAt.Equal(created), true)
+	equal(t, "updated", shown.UpdatedAt.Equal(updated), true)
+
+	_, err = s.Packs().Create(ctx, &model.Pack{ID: id, Name: "Duplicate"})
+	expect(t, err, store.ErrConflict)
+
+	build, err := s.Builds().Create(ctx, &model.Build{PackID: id, Name: "1.0.0"})
+	must(t, err)
+
+	expect(t, s.Packs().Assign(ctx, &model.Pack{ID: id, RecommendedID: uuid.New().String()}), store.ErrNotFound)
+	must(t, s.Packs().Assign(ctx, &model.Pack{ID: id, RecommendedID: build.ID, LatestID: build.ID}))
+
+	shown, err = s.Packs().Show(ctx, id)
+	must(t, err)
+	equal(t, "recommended", shown.RecommendedID, build.ID)
+	equal(t, "latest", shown.LatestID, build.ID)
+	equal(t, "name", shown.Name, "Imported Pack")
+	equal(t, "updated", shown.UpdatedAt.Equal(updated), true)
+
+	team, err := s.Teams().Create(ctx, &model.Team{Name: "Importers"})
+	must(t, err)
+
+	must(t, s.TeamPacks().Append(ctx, &model.TeamPack{
+		TeamID:    team.ID,
+		PackID:    id,
+		Perm:      model.PermAdmin,
+		CreatedAt: created,
+		UpdatedAt: updated,
+	}))
+
+	records, err := s.TeamPacks().ListByPack(ctx, id)
+	must(t, err)
+	equal(t, "count", len(records), 1)
+	equal(t, "created", records[0].CreatedAt.Equal(created), true)
+	equal(t, "updated", records[0].UpdatedAt.Equal(updated), true)
+}
+
+func testSlugs(t *testing.T, s store.Store) {
+	ctx := context.Background()
+
+	_, err := s.Users().Create(ctx, &model.User{Username: "Admin"})
+	must(t, err)
+
+	_, err = s.Users().Create(ctx, &model.User{Username: "admin"})
+	expect(t, err, store.ErrConflict)
+
+	first, err := s.Mods().Create(ctx, &model.Mod{Name: "Buildcraft"})
+	must(t, err)
+
+	second, err := s.Mods().Create(ctx, &model.Mod{Name: "Industrialcraft"})
+	must(t, err)
+
+	second.Slug = first.Slug
+
+	_, err = s.Mods().Update(ctx, second)
+	expect(t, err, store.ErrConflict)
+
+	shown, err := s.Mods().Show(ctx, second.ID)
+	must(t, err)
+	equal(t, "slug", shown.Slug, "industrialcraft")
+
+	fallback, err := s.Teams().Create(ctx, &model.Team{Name: "!!!"})
+	must(t, err)
+	equal(t, "slug", fallback.Slug, fallback.ID)
+
+	for _, mod := range []*model.Mod{first, shown} {
+		_, err := s.Versions().Create(ctx, &model.Version{ModID: mod.ID, Name: "1.0.0"})
+		must(t, err)
+	}
+
+	_, err = s.Versions().Create(ctx, &model.Version{ModID: first.ID, Name: "1.0.0"})
+	expect(t, err, store.ErrConflict)
+
+	one, err := s.Packs().Create(ctx, &model.Pack{Name: "One"})
+	must(t, err)
+
+	two, err := s.Packs().Create(ctx, &model.Pack{Name: "Two"})
+	must(t, err)
+
+	for _, pack := range []*model.Pack{one, two} {
+		_, err := s.Builds().Create(ctx, &model.Build{PackID: pack.ID, Name: "1.0.0"})
+		must(t, err)
+	}
+
+	_, err = s.Builds().Create(ctx, &model.Build{PackID: one.ID, Name: "1.0.0"})
+	expect(t, err, store.ErrConflict)
+}
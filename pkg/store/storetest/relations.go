@@ -0,0 +1,222 @@
+package storetest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+func testTeamUsers(t *testing.T, s store.Store) {
+	ctx := context.Background()
+
+	team, err := s.Teams().Create(ctx, &model.Team{Name: "Admins"})
+	must(t, err)
+
+	other, err := s.Teams().Create(ctx, &model.Team{Name: "Builders"})
+	must(t, err)
+
+	bob, err := s.Users().Create(ctx, &model.User{Username: "bob"})
+	must(t, err)
+
+	alice, err := s.Users().Create(ctx, &model.User{Username: "alice"})
+	must(t, err)
+
+	must(t, s.TeamUsers().Append(ctx, &model.TeamUser{TeamID: team.ID, UserID: bob.ID}))
+	must(t, s.TeamUsers().Append(ctx, &model.TeamUser{TeamID: team.ID, UserID: alice.ID, Perm: model.PermOwner}))
+	must(t, s.TeamUsers().Append(ctx, &model.TeamUser{TeamID: other.ID, UserID: bob.ID, Perm: model.PermAdmin}))
+
+	expect(t, s.TeamUsers().Append(ctx, &model.TeamUser{TeamID: team.ID, UserID: bob.ID}), store.ErrConflict)
+	expect(t, s.TeamUsers().Append(ctx, &model.TeamUser{TeamID: team.ID, UserID: uuid.New().String()}), store.ErrNotFound)
+	expect(t, s.TeamUsers().Append(ctx, &model.TeamUser{TeamID: "unknown", UserID: bob.ID}), store.ErrNotFound)
+
+	records, err := s.TeamUsers().ListByTeam(ctx, team.ID)
+	must(t, err)
+	equal(t, "count", len(records), 2)
+	equal(t, "first", records[0].User.Username, "alice")
+	equal(t, "first perm", records[0].Perm, model.PermOwner)
+	equal(t, "second", records[1].User.Username, "bob")
+	equal(t, "second perm", records[1].Perm, model.PermUser)
+	equal(t, "team", records[1].Team.Name, "Admins")
+
+	must(t, s.TeamUsers().Permit(ctx, &model.TeamUser{TeamID: team.ID, UserID: bob.ID, Perm: model.PermAdmin}))
+	expect(t, s.TeamUsers().Permit(ctx, &model.TeamUser{TeamID: other.ID, UserID: alice.ID, Perm: model.PermAdmin}), store.ErrNotFound)
+
+	records, err = s.TeamUsers().ListByUser(ctx, bob.ID)
+	must(t, err)
+	equal(t, "count", len(records), 2)
+	equal(t, "first", records[0].Team.Name, "Admins")
+	equal(t, "first perm", records[0].Perm, model.PermAdmin)
+	equal(t, "second", records[1].Team.Name, "Builders")
+	equal(t, "user", records[1].User.Username, "bob")
+
+	_, err = s.TeamUsers().ListByTeam(ctx, uuid.New().String())
+	expect(t, err, store.ErrNotFound)
+
+	_, err = s.TeamUsers().ListByUser(ctx, "unknown")
+	expect(t, err, store.ErrNotFound)
+
+	must(t, s.TeamUsers().Drop(ctx, team.ID, bob.ID))
+	expect(t, s.TeamUsers().Drop(ctx, team.ID, bob.ID), store.ErrNotFound)
+
+	records, err = s.TeamUsers().ListByUser(ctx, bob.ID)
+	must(t, err)
+	equal(t, "count", len(records), 1)
+}
+
+func testTeamPacks(t *testing.T, s store.Store) {
+	ctx := context.Background()
+
+	team, err := s.Teams().Create(ctx, &model.Team{Name: "Packers"})
+	must(t, err)
+
+	pack, err := s.Packs().Create(ctx, &model.Pack{Name: "Big Dig"})
+	must(t, err)
+
+	must(t, s.TeamPacks().Append(ctx, &model.TeamPack{TeamID: team.ID, PackID: pack.ID}))
+	expect(t, s.TeamPacks().Append(ctx, &model.TeamPack{TeamID: team.ID, PackID: pack.ID}), store.ErrConflict)
+	expect(t, s.TeamPacks().Append(ctx, &model.TeamPack{TeamID: team.ID, PackID: uuid.New().String()}), store.ErrNotFound)
+	must(t, s.TeamPacks().Permit(ctx, &model.TeamPack{TeamID: team.ID, PackID: pack.ID, Perm: model.PermOwner}))
+
+	records, err := s.TeamPacks().ListByTeam(ctx, team.ID)
+	must(t, err)
+	equal(t, "count", len(records), 1)
+	equal(t, "pack", records[0].Pack.Name, "Big Dig")
+	equal(t, "perm", records[0].Perm, model.PermOwner)
+
+	records, err = s.TeamPacks().ListByPack(ctx, pack.ID)
+	must(t, err)
+	equal(t, "count", len(records), 1)
+	equal(t, "team", records[0].Team.Name, "Packers")
+
+	must(t, s.TeamPacks().Drop(ctx, team.ID, pack.ID))
+	expect(t, s.TeamPacks().Drop(ctx, team.ID, pack.ID), store.ErrNotFound)
+}
+
+func testTeamMods(t *testing.T, s store.Store) {
+	ctx := context.Background()
+
+	team, err := s.Teams().Create(ctx, &model.Team{Name: "Modders"})
+	must(t, err)
+
+	mod, err := s.Mods().Create(ctx, &model.Mod{Name: "Railcraft"})
+	must(t, err)
+
+	must(t, s.TeamMods().Append(ctx, &model.TeamMod{TeamID: team.ID, ModID: mod.ID}))
+	expect(t, s.TeamMods().Append(ctx, &model.TeamMod{TeamID: team.ID, ModID: mod.ID}), store.ErrConflict)
+	expect(t, s.TeamMods().Append(ctx, &model.TeamMod{TeamID: uuid.New().String(), ModID: mod.ID}), store.ErrNotFound)
+	must(t, s.TeamMods().Permit(ctx, &model.TeamMod{TeamID: team.ID, ModID: mod.ID, Perm: model.PermAdmin}))
+
+	records, err := s.TeamMods().ListByTeam(ctx, team.ID)
+	must(t, err)
+	equal(t, "count", len(records), 1)
+	equal(t, "mod", records[0].Mod.Name, "Railcraft")
+	equal(t, "perm", records[0].Perm, model.PermAdmin)
+
+	records, err = s.TeamMods().ListByMod(ctx, mod.ID)
+	must(t, err)
+	equal(t, "count", len(records), 1)
+	equal(t, "team", records[0].Team.Name, "Modders")
+
+	must(t, s.TeamMods().Drop(ctx, team.ID, mod.ID))
+	expect(t, s.TeamMods().Drop(ctx, team.ID, mod.ID), store.ErrNotFound)
+}
+
+func testUserPacks(t *testing.T, s store.Store) {
+	ctx := context.Background()
+
+	user, err := s.Users().Create(ctx, &model.User{Username: "packer"})
+	must(t, err)
+
+	pack, err := s.Packs().Create(ctx, &model.Pack{Name: "Yogcraft"})
+	must(t, err)
+
+	must(t, s.UserPacks().Append(ctx, &model.UserPack{UserID: user.ID, PackID: pack.ID}))
+	expect(t, s.UserPacks().Append(ctx, &model.UserPack{UserID: user.ID, PackID: pack.ID}), store.ErrConflict)
+	expect(t, s.UserPacks().Append(ctx, &model.UserPack{UserID: uuid.New().String(), PackID: pack.ID}), store.ErrNotFound)
+	must(t, s.UserPacks().Permit(ctx, &model.UserPack{UserID: user.ID, PackID: pack.ID, Perm: model.PermAdmin}))
+
+	records, err := s.UserPacks().ListByUser(ctx, user.ID)
+	must(t, err)
+	equal(t, "count", len(records), 1)
+	equal(t, "pack", records[0].Pack.Name, "Yogcraft")
+	equal(t, "perm", records[0].Perm, model.PermAdmin)
+
+	records, err = s.UserPacks().ListByPack(ctx, pack.ID)
+	must(t, err)
+	equal(t, "count", len(records), 1)
+	equal(t, "user", records[0].User.Username, "packer")
+
+	must(t, s.UserPacks().Drop(ctx, user.ID, pack.ID))
+	expect(t, s.UserPacks().Drop(ctx, user.ID, pack.ID), store.ErrNotFound)
+}
+
+func testUserMods(t *testing.T, s store.Store) {
+	ctx := context.Background()
+
+	user, err := s.Users().Create(ctx, &model.User{Username: "modder"})
+	must(t, err)
+
+	mod, err := s.Mods().Create(ctx, &model.Mod{Name: "Forestry"})
+	must(t, err)
+
+	must(t, s.UserMods().Append(ctx, &model.UserMod{UserID: user.ID, ModID: mod.ID}))
+	expect(t, s.UserMods().Append(ctx, &model.UserMod{UserID: user.ID, ModID: mod.ID}), store.ErrConflict)
+	expect(t, s.UserMods().Append(ctx, &model.UserMod{UserID: user.ID, ModID: uuid.New().String()}), store.ErrNotFound)
+	must(t, s.UserMods().Permit(ctx, &model.UserMod{UserID: user.ID, ModID: mod.ID, Perm: model.PermOwner}))
+
+	records, err := s.UserMods().ListByUser(ctx, user.ID)
+	must(t, err)
+	equal(t, "count", len(records), 1)
+	equal(t, "mod", records[0].Mod.Name, "Forestry")
+	equal(t, "perm", records[0].Perm, model.PermOwner)
+
+	records, err = s.UserMods().ListByMod(ctx, mod.ID)
+	must(t, err)
+	equal(t, "count", len(records), 1)
+	equal(t, "user", records[0].User.Username, "modder")
+
+	must(t, s.UserMods().Drop(ctx, user.ID, mod.ID))
+	expect(t, s.UserMods().Drop(ctx, user.ID, mod.ID), store.ErrNotFound)
+}
+
+func testBuildVersions(t *testing.T, s store.Store) {
+	ctx := context.Background()
+
+	pack, err := s.Packs().Create(ctx, &model.Pack{Name: "FTB Infinity"})
+	must(t, err)
+
+	build, err := s.Builds().Create(ctx, &model.Build{PackID: pack.ID, Name: "3.0.0"})
+	must(t, err)
+
+	mod, err := s.Mods().Create(ctx, &model.Mod{Name: "Mekanism"})
+	must(t, err)
+
+	newer, err := s.Versions().Create(ctx, &model.Version{ModID: mod.ID, Name: "9.4.0"})
+	must(t, err)
+
+	older, err := s.Versions().Create(ctx, &model.Version{ModID: mod.ID, Name: "9.1.0"})
+	must(t, err)
+
+	must(t, s.BuildVersions().Append(ctx, &model.BuildVersion{BuildID: build.ID, VersionID: newer.ID}))
+	must(t, s.BuildVersions().Append(ctx, &model.BuildVersion{BuildID: build.ID, VersionID: older.ID}))
+	expect(t, s.BuildVersions().Append(ctx, &model.BuildVersion{BuildID: build.ID, VersionID: older.ID}), store.ErrConflict)
+	expect(t, s.BuildVersions().Append(ctx, &model.BuildVersion{BuildID: uuid.New().String(), VersionID: older.ID}), store.ErrNotFound)
+
+	records, err := s.BuildVersions().ListByBuild(ctx, build.ID)
+	must(t, err)
+	equal(t, "count", len(records), 2)
+	equal(t, "first", records[0].Version.Name, "9.1.0")
+	equal(t, "second", records[1].Version.Name, "9.4.0")
+	equal(t, "build", records[0].Build.Name, "3.0.0")
+
+	records, err = s.BuildVersions().ListByVersion(ctx, newer.ID)
+	must(t, err)
+	equal(t, "count", len(records), 1)
+	equal(t, "build", records[0].Build.ID, build.ID)
+
+	must(t, s.BuildVersions().Drop(ctx, build.ID, newer.ID))
+	expect(t, s.BuildVersions().Drop(ctx, build.ID, newer.ID), store.ErrNotFound)
+}
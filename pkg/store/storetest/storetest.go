@@ -0,0 +1,104 @@
+// Package storetest provides a conformance suite which gets executed by the
+// tests of every store driver to keep their behaviour aligned.
+package storetest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+// Factory opens a new and empty store for a single test.
+type Factory func(t *testing.T) store.Store
+
+// Run executes the conformance suite against the stores of the factory.
+func Run(t *testing.T, factory Factory) {
+	tests := []struct {
+		name string
+		fn   func(*testing.T, store.Store)
+	}{
+		{"Users", testUsers},
+		{"Teams", testTeams},
+		{"Minecrafts", testMinecrafts},
+		{"Forges", testForges},
+		{"Mods", testMods},
+		{"Versions", testVersions},
+		{"Packs", testPacks},
+		{"Builds", testBuilds},
+		{"Preserve", testPreserve},
+		{"Slugs", testSlugs},
+		{"Cascades", testCascades},
+		{"TeamUsers", testTeamUsers},
+		{"TeamPacks", testTeamPacks},
+		{"TeamMods", testTeamMods},
+		{"UserPacks", testUserPacks},
+		{"UserMods", testUserMods},
+		{"BuildVersions", testBuildVersions},
+		{"Blobs", testBlobs},
+		{"Links", testLinks},
+		{"Transactions", testTransactions},
+		{"Snapshot", testSnapshot},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			s := factory(t)
+			defer s.Close()
+
+			test.fn(t, s)
+		})
+	}
+}
+
+// Reset reverts all applied migrations and applies them again, this way
+// drivers backed by a persistent database start with empty tables.
+func Reset(t *testing.T, s store.Store) {
+	t.Helper()
+	ctx := context.Background()
+
+	for {
+		records, err := s.Migrations().Down(ctx, false)
+
+		if err != nil {
+			t.Fatalf("failed to revert migrations: %v", err)
+		}
+
+		if len(records) == 0 {
+			break
+		}
+	}
+
+	if _, err := s.Migrations().Up(ctx, false); err != nil {
+		t.Fatalf("failed to apply migrations: %v", err)
+	}
+}
+
+// must fails the test on any unexpected error.
+func must(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// expect fails the test if the error doesn't match the expected one.
+func expect(t *testing.T, err, expected error) {
+	t.Helper()
+
+	if err != expected {
+		t.Fatalf("expected error %v, got %v", expected, err)
+	}
+}
+
+// equal fails the test if the values don't match.
+func equal(t *testing.T, name string, got, expected interface{}) {
+	t.Helper()
+
+	if got != expected {
+		t.Fatalf("expected %s to be %v, got %v", name, expected, got)
+	}
+}
@@ -0,0 +1,148 @@
+package storetest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+	"github.com/pkg/errors"
+)
+
+func testTransactions(t *testing.T, s store.Store) {
+	ctx := context.Background()
+	failure := errors.New("rollback")
+
+	var committed *model.Pack
+
+	must(t, s.Transaction(ctx, func(tx store.Repositories) error {
+		pack, err := tx.Packs().Create(ctx, &model.Pack{Name: "Committed"})
+
+		if err != nil {
+			return err
+		}
+
+		committed = pack
+
+		_, err = tx.Builds().Create(ctx, &model.Build{PackID: pack.ID, Name: "1.0.0"})
+		return err
+	}))
+
+	records, err := s.Builds().List(ctx, committed.ID)
+	must(t, err)
+	equal(t, "builds", len(records), 1)
+
+	var rolled *model.Pack
+
+	err = s.Transaction(ctx, func(tx store.Repositories) error {
+		pack, err := tx.Packs().Create(ctx, &model.Pack{Name: "Rolled Back"})
+
+		if err != nil {
+			return err
+		}
+
+		rolled = pack
+
+		mod, err := tx.Mods().Create(ctx, &model.Mod{Name: "Rolled Back"})
+
+		if err != nil {
+			return err
+		}
+
+		version, err := tx.Versions().Create(ctx, &model.Version{ModID: mod.ID, Name: "1.0.0"})
+
+		if err != nil {
+			return err
+		}
+
+		build, err := tx.Builds().Create(ctx, &model.Build{PackID: pack.ID, Name: "1.0.0"})
+
+		if err != nil {
+			return err
+		}
+
+		if err := tx.BuildVersions().Append(ctx, &model.BuildVersion{BuildID: build.ID, VersionID: version.ID}); err != nil {
+			return err
+		}
+
+		if _, err := tx.Builds().Show(ctx, pack.ID, build.ID); err != nil {
+			return err
+		}
+
+		return failure
+	})
+
+	expect(t, err, failure)
+
+	_, err = s.Packs().Show(ctx, rolled.ID)
+	expect(t, err, store.ErrNotFound)
+
+	mods, err := s.Mods().List(ctx)
+	must(t, err)
+	equal(t, "mods", len(mods), 0)
+
+	err = s.Transaction(ctx, func(tx store.Repositories) error {
+		if err := tx.Packs().Delete(ctx, committed.ID); err != nil {
+			return err
+		}
+
+		return failure
+	})
+
+	expect(t, err, failure)
+
+	records, err = s.Builds().List(ctx, committed.ID)
+	must(t, err)
+	equal(t, "builds", len(records), 1)
+
+	err = s.Transaction(ctx, func(tx store.Repositories) error {
+		if _, err := tx.Teams().Create(ctx, &model.Team{Name: "Partial"}); err != nil {
+			return err
+		}
+
+		_, err := tx.Packs().Create(ctx, &model.Pack{Name: "Committed"})
+		return err
+	})
+
+	expect(t, err, store.ErrConflict)
+
+	_, err = s.Teams().Show(ctx, "partial")
+	expect(t, err, store.ErrNotFound)
+}
+
+func testSnapshot(t *testing.T, s store.Store) {
+	ctx := context.Background()
+	failure := errors.New("snapshot")
+
+	mod, err := s.Mods().Create(ctx, &model.Mod{Name: "Snapshot"})
+	must(t, err)
+
+	_, err = s.Versions().Create(ctx, &model.Version{ModID: mod.ID, Name: "1.0.0"})
+	must(t, err)
+
+	must(t, s.Snapshot(ctx, func(tx store.Repositories) error {
+		mods, err := tx.Mods().List(ctx)
+
+		if err != nil {
+			return err
+		}
+
+		equal(t, "mods", len(mods), 1)
+
+		versions, err := tx.Versions().List(ctx, mod.ID)
+
+		if err != nil {
+			return err
+		}
+
+		equal(t, "versions", len(versions), 1)
+		return nil
+	}))
+
+	expect(t, s.Snapshot(ctx, func(tx store.Repositories) error {
+		return failure
+	}), failure)
+
+	_, err = s.Mods().Create(ctx, &model.Mod{Name: "After Snapshot"})
+	must(t, err)
+}
@@ -0,0 +1,111 @@
+package storetest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+)
+
+func testBlobs(t *testing.T, s store.Store) {
+	ctx := context.Background()
+	first := strings.Repeat("b", 64)
+	second := strings.Repeat("a", 64)
+
+	created, err := s.Blobs().Create(ctx, &model.Blob{Digest: first, Size: 11, MD5: strings.Repeat("0", 32), References: 5})
+	must(t, err)
+	equal(t, "references", created.References, 0)
+
+	_, err = s.Blobs().Create(ctx, &model.Blob{Digest: first})
+	expect(t, err, store.ErrConflict)
+
+	_, err = s.Blobs().Create(ctx, &model.Blob{Digest: second, Size: 3})
+	must(t, err)
+
+	shown, err := s.Blobs().Show(ctx, first)
+	must(t, err)
+	equal(t, "size", shown.Size, int64(11))
+	equal(t, "md5", shown.MD5, strings.Repeat("0", 32))
+
+	records, err := s.Blobs().List(ctx)
+	must(t, err)
+	equal(t, "count", len(records), 2)
+	equal(t, "first", records[0].Digest, second)
+
+	must(t, s.Blobs().Delete(ctx, second))
+
+	_, err = s.Blobs().Show(ctx, second)
+	expect(t, err, store.ErrNotFound)
+
+	expect(t, s.Blobs().Delete(ctx, second), store.ErrNotFound)
+}
+
+func testLinks(t *testing.T, s store.Store) {
+	ctx := context.Background()
+	digest := strings.Repeat("c", 64)
+
+	_, err := s.Links().Create(ctx, &model.Link{Path: "mods/foo.zip", Digest: digest})
+	expect(t, err, store.ErrNotFound)
+
+	_, err = s.Blobs().Create(ctx, &model.Blob{Digest: digest, Size: 3})
+	must(t, err)
+
+	created, err := s.Links().Create(ctx, &model.Link{Path: "mods/foo.zip", Digest: digest, ContentType: "application/zip"})
+	must(t, err)
+	equal(t, "path", created.Path, "mods/foo.zip")
+
+	_, err = s.Links().Create(ctx, &model.Link{Path: "mods/foo.zip", Digest: digest})
+	expect(t, err, store.ErrConflict)
+
+	_, err = s.Links().Create(ctx, &model.Link{Path: "mods/bar.zip", Digest: digest})
+	must(t, err)
+
+	blob, err := s.Blobs().Show(ctx, digest)
+	must(t, err)
+	equal(t, "references", blob.References, 2)
+
+	shown, err := s.Links().Show(ctx, "mods/foo.zip")
+	must(t, err)
+	equal(t, "digest", shown.Digest, digest)
+	equal(t, "content type", shown.ContentType, "application/zip")
+
+	records, err := s.Links().List(ctx)
+	must(t, err)
+	equal(t, "count", len(records), 2)
+	equal(t, "first", records[0].Path, "mods/bar.zip")
+
+	expect(t, s.Blobs().Delete(ctx, digest), store.ErrConflict)
+
+	must(t, s.Links().Delete(ctx, "mods/foo.zip"))
+	must(t, s.Links().Delete(ctx, "mods/bar.zip"))
+	expect(t, s.Links().Delete(ctx, "mods/bar.zip"), store.ErrNotFound)
+
+	_, err = s.Links().Show(ctx, "mods/foo.zip")
+	expect(t, err, store.ErrNotFound)
+
+	blob, err = s.Blobs().Show(ctx, digest)
+	must(t, err)
+	equal(t, "references", blob.References, 0)
+
+	// Paths up to 255 characters are supported, even if they only differ
+	// after the indexed prefix of MySQL.
+	long := "mods/" + strings.Repeat("x", 246)
+
+	for _, suffix := range []string{".jar", ".zip"} {
+		_, err = s.Links().Create(ctx, &model.Link{Path: long + suffix, Digest: digest})
+		must(t, err)
+	}
+
+	_, err = s.Links().Create(ctx, &model.Link{Path: long + ".zip", Digest: digest})
+	expect(t, err, store.ErrConflict)
+
+	shown, err = s.Links().Show(ctx, long+".jar")
+	must(t, err)
+	equal(t, "path", shown.Path, long+".jar")
+
+	must(t, s.Links().Delete(ctx, long+".jar"))
+	must(t, s.Links().Delete(ctx, long+".zip"))
+	must(t, s.Blobs().Delete(ctx, digest))
+}
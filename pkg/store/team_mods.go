@@ -0,0 +1,25 @@
+package store
+
+import (
+	"context"
+
+	"github.com/kleister/kleister-api/pkg/model"
+)
+
+// TeamMods provides the repository interface for mods assigned to teams.
+type TeamMods interface {
+	// ListByTeam returns all assignments of a team ordered by mod name.
+	ListByTeam(context.Context, string) ([]*model.TeamMod, error)
+
+	// ListByMod returns all assignments of a mod ordered by team name.
+	ListByMod(context.Context, string) ([]*model.TeamMod, error)
+
+	// Append assigns a mod to a team, fails with ErrConflict if assigned.
+	Append(context.Context, *model.TeamMod) error
+
+	// Permit updates the permission of an existing assignment.
+	Permit(context.Context, *model.TeamMod) error
+
+	// Drop removes the assignment between a team and a mod.
+	Drop(context.Context, string, string) error
+}
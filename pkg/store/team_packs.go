@@ -0,0 +1,25 @@
+package store
+
+import (
+	"context"
+
+	"github.com/kleister/kleister-api/pkg/model"
+)
+
+// TeamPacks provides the repository interface for packs assigned to teams.
+type TeamPacks interface {
+	// ListByTeam returns all assignments of a team ordered by pack name.
+	ListByTeam(context.Context, string) ([]*model.TeamPack, error)
+
+	// ListByPack returns all assignments of a pack ordered by team name.
+	ListByPack(context.Context, string) ([]*model.TeamPack, error)
+
+	// Append assigns a pack to a team, fails with ErrConflict if assigned.
+	Append(context.Context, *model.TeamPack) error
+
+	// Permit updates the permission of an existing assignment.
+	Permit(context.Context, *model.TeamPack) error
+
+	// Drop removes the assignment between a team and a pack.
+	Drop(context.Context, string, string) error
+}
@@ -0,0 +1,25 @@
+package store
+
+import (
+	"context"
+
+	"github.com/kleister/kleister-api/pkg/model"
+)
+
+// TeamUsers provides the repository interface for users assigned to teams.
+type TeamUsers interface {
+	// ListByTeam returns all assignments of a team ordered by user name.
+	ListByTeam(context.Context, string) ([]*model.TeamUser, error)
+
+	// ListByUser returns all assignments of a user ordered by team name.
+	ListByUser(context.Context, string) ([]*model.TeamUser, error)
+
+	// Append assigns a user to a team, fails with ErrConflict if assigned.
+	Append(context.Context, *model.TeamUser) error
+
+	// Permit updates the permission of an existing assignment.
+	Permit(context.Context, *model.TeamUser) error
+
+	// Drop removes the assignment between a team and a user.
+	Drop(context.Context, string, string) error
+}
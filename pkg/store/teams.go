@@ -0,0 +1,25 @@
+package store
+
+import (
+	"context"
+
+	"github.com/kleister/kleister-api/pkg/model"
+)
+
+// Teams provides the repository interface for teams.
+type Teams interface {
+	// List returns all teams ordered by name.
+	List(context.Context) ([]*model.Team, error)
+
+	// Show returns a single record by its ID or slug.
+	Show(context.Context, string) (*model.Team, error)
+
+	// Create persists a new record, an ID and slug get generated if empty.
+	Create(context.Context, *model.Team) (*model.Team, error)
+
+	// Update persists the changes of an existing record.
+	Update(context.Context, *model.Team) (*model.Team, error)
+
+	// Delete removes a record by its ID or slug including its assignments.
+	Delete(context.Context, string) error
+}
@@ -0,0 +1,25 @@
+package store
+
+import (
+	"context"
+
+	"github.com/kleister/kleister-api/pkg/model"
+)
+
+// UserMods provides the repository interface for mods assigned to users.
+type UserMods interface {
+	// ListByUser returns all assignments of a user ordered by mod name.
+	ListByUser(context.Context, string) ([]*model.UserMod, error)
+
+	// ListByMod returns all assignments of a mod ordered by user name.
+	ListByMod(context.Context, string) ([]*model.UserMod, error)
+
+	// Append assigns a mod to a user, fails with ErrConflict if assigned.
+	Append(context.Context, *model.UserMod) error
+
+	// Permit updates the permission of an existing assignment.
+	Permit(context.Context, *model.UserMod) error
+
+	// Drop removes the assignment between a user and a mod.
+	Drop(context.Context, string, string) error
+}
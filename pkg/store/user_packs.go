@@ -0,0 +1,25 @@
+package store
+
+import (
+	"context"
+
+	"github.com/kleister/kleister-api/pkg/model"
+)
+
+// UserPacks provides the repository interface for packs assigned to users.
+type UserPacks interface {
+	// ListByUser returns all assignments of a user ordered by pack name.
+	ListByUser(context.Context, string) ([]*model.UserPack, error)
+
+	// ListByPack returns all assignments of a pack ordered by user name.
+	ListByPack(context.Context, string) ([]*model.UserPack, error)
+
+	// Append assigns a pack to a user, fails with ErrConflict if assigned.
+	Append(context.Context, *model.UserPack) error
+
+	// Permit updates the permission of an existing assignment.
+	Permit(context.Context, *model.UserPack) error
+
+	// Drop removes the assignment between a user and a pack.
+	Drop(context.Context, string, string) error
+}
@@ -0,0 +1,25 @@
+package store
+
+import (
+	"context"
+
+	"github.com/kleister/kleister-api/pkg/model"
+)
+
+// Users provides the repository interface for users.
+type Users interface {
+	// List returns all users ordered by username.
+	List(context.Context) ([]*model.User, error)
+
+	// Show returns a single record by its ID or slug.
+	Show(context.Context, string) (*model.User, error)
+
+	// Create persists a new record, an ID and slug get generated if empty.
+	Create(context.Context, *model.User) (*model.User, error)
+
+	// Update persists the changes of an existing record.
+	Update(context.Context, *model.User) (*model.User, error)
+
+	// Delete removes a record by its ID or slug including its assignments.
+	Delete(context.Context, string) error
+}
@@ -0,0 +1,28 @@
+package store
+
+import (
+	"context"
+
+	"github.com/kleister/kleister-api/pkg/model"
+)
+
+// Versions provides the repository interface for versions of a mod.
+type Versions interface {
+	// List returns all versions of the mod ordered by name.
+	List(context.Context, string) ([]*model.Version, error)
+
+	// ListByFile returns all versions referencing the uploaded file ordered by name.
+	ListByFile(context.Context, string) ([]*model.Version, error)
+
+	// Show returns a single version of the mod by its ID or slug.
+	Show(context.Context, string, string) (*model.Version, error)
+
+	// Create persists a new version, an ID and slug get generated if empty.
+	Create(context.Context, *model.Version) (*model.Version, error)
+
+	// Update persists the changes of an existing version.
+	Update(context.Context, *model.Version) (*model.Version, error)
+
+	// Delete removes a version of the mod by its ID or slug.
+	Delete(context.Context, string, string) error
+}
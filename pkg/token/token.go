@@ -1,12 +1,14 @@
 package token
 
 import (
+	"crypto/rand"
 	"encoding/base32"
 	"net/http"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/dgrijalva/jwt-go/request"
+	"github.com/pkg/errors"
 )
 
 const (
@@ -16,6 +18,9 @@ const (
 	// SessToken is the kind of token to represent a session token.
 	SessToken = "sess"
 
+	// DownloadToken is the kind of token to represent a signed download.
+	DownloadToken = "download"
+
 	// SignerAlgo is the default algorithm used to sign JWT tokens.
 	SignerAlgo = "HS256"
 )
@@ -42,30 +47,63 @@ func (t *Token) SignUnlimited(secret string) (*Result, error) {
 
 // SignExpiring signs a token that maybe expires.
 func (t *Token) SignExpiring(secret string, exp time.Duration) (*Result, error) {
+	signingKey, err := Key(secret)
+
+	if err != nil {
+		return nil, err
+	}
+
 	token := jwt.New(jwt.SigningMethodHS256)
 	claims := token.Claims.(jwt.MapClaims)
 
 	claims["type"] = t.Kind
 	claims["text"] = t.Text
 
-	signingKey, _ := base32.StdEncoding.DecodeString(secret)
-	tokenString, err := token.SignedString(signingKey)
-
 	if exp > 0 {
 		expire := time.Now().Add(exp)
 		claims["exp"] = expire.Unix()
 
+		tokenString, err := token.SignedString(signingKey)
+
 		return &Result{
 			Token:  tokenString,
 			Expire: expire.Format(time.RFC3339),
 		}, err
 	}
 
+	tokenString, err := token.SignedString(signingKey)
+
 	return &Result{
 		Token: tokenString,
 	}, err
 }
 
+// Key decodes the base32 encoded secret into the signing key.
+func Key(secret string) ([]byte, error) {
+	signingKey, err := base32.StdEncoding.DecodeString(secret)
+
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid secret")
+	}
+
+	if len(signingKey) == 0 {
+		return nil, errors.New("empty secret")
+	}
+
+	return signingKey, nil
+}
+
+// Generate creates a random base32 encoded secret.
+func Generate() (string, error) {
+	buf := make([]byte, 32)
+
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return base32.StdEncoding.EncodeToString(buf), nil
+}
+
 // New initializes a new simple token of a specified kind.
 func New(kind, text string) *Token {
 	return &Token{
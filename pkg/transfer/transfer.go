@@ -0,0 +1,174 @@
+// Package transfer copies all objects between two upload drivers, this way
+// the uploads can be moved to another storage while the server keeps running.
+package transfer
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kleister/kleister-api/pkg/upload"
+	"github.com/pkg/errors"
+)
+
+const (
+	// StateCopied marks an object which has been copied to the target.
+	StateCopied = "copied"
+
+	// StateSkipped marks an object which has already been present with the
+	// same size and checksum on the target.
+	StateSkipped = "skipped"
+
+	// StateFailed marks an object which could not be copied.
+	StateFailed = "failed"
+)
+
+var (
+	// ErrChecksumMismatch defines a named error if the copied content
+	// doesn't match the checksum of the source.
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+)
+
+// Entry describes the result for a single object, the position counts the
+// finished objects including this one.
+type Entry struct {
+	Key      string
+	Size     int64
+	State    string
+	Err      error
+	Position int
+	Total    int
+}
+
+// Report sums up the results of all objects.
+type Report struct {
+	Total   int
+	Copied  int
+	Skipped int
+	Failed  int
+	Size    int64
+}
+
+// Copy streams every object of the source to the target with the given
+// number of parallel workers. Objects which are already present with the
+// same checksum get skipped, so an interrupted copy can be resumed by
+// running it again. Objects without a checksum on either side always get
+// copied as their content can't be compared. The progress function gets called for every object.
+func Copy(ctx context.Context, from, to upload.Upload, concurrency int, progress func(*Entry)) (*Report, error) {
+	objects, err := from.List(ctx, "")
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list source")
+	}
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		report  = &Report{Total: len(objects)}
+		queue   = make(chan *upload.Object)
+		mutex   sync.Mutex
+		workers sync.WaitGroup
+	)
+
+	for i := 0; i < concurrency; i++ {
+		workers.Add(1)
+
+		go func() {
+			defer workers.Done()
+
+			for obj := range queue {
+				entry := &Entry{
+					Key:   obj.Key,
+					Size:  obj.Size,
+					Total: len(objects),
+				}
+
+				entry.State, entry.Err = object(ctx, from, to, obj)
+
+				mutex.Lock()
+				report.add(entry)
+				entry.Position = report.Copied + report.Skipped + report.Failed
+
+				if progress != nil {
+					progress(entry)
+				}
+
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	for _, obj := range objects {
+		if ctx.Err() != nil {
+			break
+		}
+
+		queue <- obj
+	}
+
+	close(queue)
+	workers.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+
+	if report.Failed > 0 {
+		return report, errors.Errorf("failed to copy %d objects", report.Failed)
+	}
+
+	return report, nil
+}
+
+// add counts the entry within the report.
+func (r *Report) add(entry *Entry) {
+	switch entry.State {
+	case StateCopied:
+		r.Copied++
+		r.Size += entry.Size
+	case StateSkipped:
+		r.Skipped++
+	default:
+		r.Failed++
+	}
+}
+
+// object copies a single object unless it's already present on the target,
+// a copy which doesn't match the checksum of the source gets removed again.
+// Missing checksums never count as a match, but can't be verified either.
+func object(ctx context.Context, from, to upload.Upload, obj *upload.Object) (string, error) {
+	existing, err := to.Stat(ctx, obj.Key)
+
+	if err == nil && existing.Size == obj.Size && obj.Checksum != "" && existing.Checksum == obj.Checksum {
+		return StateSkipped, nil
+	}
+
+	if err != nil && err != upload.ErrNotFound {
+		return StateFailed, errors.Wrap(err, "failed to check target")
+	}
+
+	r, source, err := from.Get(ctx, obj.Key)
+
+	if err != nil {
+		return StateFailed, errors.Wrap(err, "failed to open source")
+	}
+
+	defer r.Close()
+
+	created, err := to.Put(ctx, obj.Key, r, source.Size, source.ContentType)
+
+	if err != nil {
+		return StateFailed, errors.Wrap(err, "failed to write target")
+	}
+
+	if created.Checksum != "" && source.Checksum != "" && created.Checksum != source.Checksum {
+		if err := to.Delete(ctx, obj.Key); err != nil {
+			return StateFailed, errors.Wrap(err, "failed to remove mismatching target")
+		}
+
+		return StateFailed, ErrChecksumMismatch
+	}
+
+	return StateCopied, nil
+}
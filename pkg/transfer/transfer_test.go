@@ -0,0 +1,180 @@
+package transfer
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/kleister/kleister-api/pkg/upload"
+	"github.com/kleister/kleister-api/pkg/upload/file"
+)
+
+// unchecked hides the checksums like drivers which can't report them.
+type unchecked struct {
+	upload.Upload
+}
+
+// Put drops the checksum of the written object.
+func (u *unchecked) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (*upload.Object, error) {
+	obj, err := u.Upload.Put(ctx, key, r, size, contentType)
+
+	if obj != nil {
+		obj.Checksum = ""
+	}
+
+	return obj, err
+}
+
+// List drops the checksums of the objects.
+func (u *unchecked) List(ctx context.Context, prefix string) ([]*upload.Object, error) {
+	objects, err := u.Upload.List(ctx, prefix)
+
+	for _, obj := range objects {
+		obj.Checksum = ""
+	}
+
+	return objects, err
+}
+
+// Stat drops the checksum of the object.
+func (u *unchecked) Stat(ctx context.Context, key string) (*upload.Object, error) {
+	obj, err := u.Upload.Stat(ctx, key)
+
+	if obj != nil {
+		obj.Checksum = ""
+	}
+
+	return obj, err
+}
+
+func TestCopy(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kleister-transfer")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	from := file.Must(&url.URL{Scheme: "file", Path: dir + "/from"})
+	to := file.Must(&url.URL{Scheme: "file", Path: dir + "/to"})
+
+	for key, body := range map[string]string{
+		"mods/foo-1.0.jar":  "foo",
+		"mods/bar-1.0.jar":  "bar",
+		"packs/logo.png":    "logo",
+		"blobs/sha256/abcd": "blob",
+	} {
+		_, err := from.Put(ctx, key, strings.NewReader(body), -1, "")
+		must(t, err)
+	}
+
+	_, err = to.Put(ctx, "mods/foo-1.0.jar", strings.NewReader("foo"), -1, "")
+	must(t, err)
+
+	_, err = to.Put(ctx, "mods/bar-1.0.jar", strings.NewReader("outdated"), -1, "")
+	must(t, err)
+
+	states := make(map[string]string)
+
+	report, err := Copy(ctx, from, to, 2, func(entry *Entry) {
+		states[entry.Key] = entry.State
+	})
+
+	must(t, err)
+
+	if report.Total != 4 || report.Copied != 3 || report.Skipped != 1 || report.Failed != 0 {
+		t.Fatalf("expected 3 copied and 1 skipped, got %+v", report)
+	}
+
+	if states["mods/foo-1.0.jar"] != StateSkipped || states["mods/bar-1.0.jar"] != StateCopied {
+		t.Fatalf("expected present object to be skipped and outdated to be copied, got %v", states)
+	}
+
+	for key, body := range map[string]string{
+		"mods/bar-1.0.jar":  "bar",
+		"packs/logo.png":    "logo",
+		"blobs/sha256/abcd": "blob",
+	} {
+		r, obj, err := to.Get(ctx, key)
+		must(t, err)
+
+		content, err := ioutil.ReadAll(r)
+		r.Close()
+		must(t, err)
+
+		if string(content) != body {
+			t.Fatalf("expected content %q for %s, got %q", body, key, content)
+		}
+
+		if key == "packs/logo.png" && obj.ContentType != "image/png" {
+			t.Fatalf("expected content type to be kept, got %s", obj.ContentType)
+		}
+	}
+
+	report, err = Copy(ctx, from, to, 2, nil)
+	must(t, err)
+
+	if report.Skipped != 4 || report.Copied != 0 {
+		t.Fatalf("expected everything to be skipped on resume, got %+v", report)
+	}
+}
+
+func TestCopyUnchecked(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kleister-transfer")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	from := file.Must(&url.URL{Scheme: "file", Path: dir + "/from"})
+	to := file.Must(&url.URL{Scheme: "file", Path: dir + "/to"})
+
+	_, err = from.Put(ctx, "mods/foo-1.0.jar", strings.NewReader("foo"), -1, "")
+	must(t, err)
+
+	_, err = to.Put(ctx, "mods/foo-1.0.jar", strings.NewReader("old"), -1, "")
+	must(t, err)
+
+	for _, c := range []struct {
+		from upload.Upload
+		to   upload.Upload
+	}{
+		{from, &unchecked{to}},
+		{&unchecked{from}, to},
+	} {
+		report, err := Copy(ctx, c.from, c.to, 1, nil)
+		must(t, err)
+
+		if report.Copied != 1 || report.Skipped != 0 {
+			t.Fatalf("expected objects without checksum to be copied, got %+v", report)
+		}
+	}
+
+	r, _, err := to.Get(ctx, "mods/foo-1.0.jar")
+	must(t, err)
+
+	content, err := ioutil.ReadAll(r)
+	r.Close()
+	must(t, err)
+
+	if string(content) != "foo" {
+		t.Fatalf("expected content of the same size to be replaced, got %q", content)
+	}
+}
+
+func must(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,398 @@
+// Package dedup wraps an upload driver to store every content only once.
+// The content gets stored below its SHA-256 as a blob, the store records
+// the references of the blobs and maps the logical keys to them. Objects
+// which have been stored before without a mapping are still served.
+package dedup
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+	"github.com/kleister/kleister-api/pkg/upload"
+	"github.com/pkg/errors"
+)
+
+const (
+	// BlobPrefix defines the key prefix of the blobs within the driver.
+	BlobPrefix = "blobs/sha256/"
+)
+
+type dedup struct {
+	uploads upload.Upload
+	store   store.Store
+}
+
+// Info prepares some informational message about the handler.
+func (u *dedup) Info() string {
+	return u.uploads.Info() + " with deduplication"
+}
+
+// Prepare simply prepares the wrapped upload handler.
+func (u *dedup) Prepare() (upload.Upload, error) {
+	if _, err := u.uploads.Prepare(); err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
+
+// Close simply closes the wrapped upload handler.
+func (u *dedup) Close() error {
+	return u.uploads.Close()
+}
+
+// Handler implements an HTTP handler for asset uploads, mapped keys get
+// rewritten to their blob before the request is passed to the handler of
+// the wrapped driver. The content headers are taken from the mapping and
+// the blobs can't be requested directly.
+func (u *dedup) Handler(root string) http.Handler {
+	uploads := u.uploads.Handler(root)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.URL.Path, root+"/")
+
+		if key == r.URL.Path || !valid(key) {
+			http.NotFound(w, r)
+			return
+		}
+
+		link, err := u.store.Links().Show(r.Context(), key)
+
+		if err == store.ErrNotFound {
+			uploads.ServeHTTP(w, r)
+			return
+		}
+
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		contentType := upload.ContentType(link.Path, link.ContentType)
+
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", upload.Disposition(link.Path, contentType))
+
+		rewritten := new(http.Request)
+		*rewritten = *r
+		rewritten.URL = new(url.URL)
+		*rewritten.URL = *r.URL
+		rewritten.URL.Path = root + "/" + Key(link.Digest)
+		rewritten.URL.RawPath = ""
+
+		uploads.ServeHTTP(w, rewritten)
+	})
+}
+
+// Put implements the upload.Upload interface, the content gets spooled to
+// a temporary file to calculate the digest before it is stored as a blob.
+// Existing blobs are reused, only the mapping of the key gets replaced. The
+// blob gets linked before its content is stored, this way the garbage
+// collection can't remove it in between.
+func (u *dedup) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (*upload.Object, error) {
+	if !valid(key) {
+		return nil, upload.ErrInvalidKey
+	}
+
+	spool, err := ioutil.TempFile("", "kleister-upload-")
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		spool.Close()
+		os.Remove(spool.Name())
+	}()
+
+	var (
+		md5sum    = md5.New()
+		sha256sum = sha256.New()
+	)
+
+	written, err := io.Copy(spool, io.TeeReader(r, io.MultiWriter(md5sum, sha256sum)))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if size >= 0 && written != size {
+		return nil, upload.ErrSizeMismatch
+	}
+
+	blob := &model.Blob{
+		Digest: hex.EncodeToString(sha256sum.Sum(nil)),
+		Size:   written,
+		MD5:    hex.EncodeToString(md5sum.Sum(nil)),
+	}
+
+	var (
+		link     *model.Link
+		previous *model.Link
+	)
+
+	if err := u.store.Transaction(ctx, func(tx store.Repositories) error {
+		if _, err := tx.Blobs().Show(ctx, blob.Digest); err == store.ErrNotFound {
+			if _, err := tx.Blobs().Create(ctx, blob); err != nil {
+				return err
+			}
+		} else if err != nil {
+			return err
+		}
+
+		if previous, err = tx.Links().Show(ctx, key); err == nil {
+			if err := tx.Links().Delete(ctx, key); err != nil {
+				return err
+			}
+		} else if err != store.ErrNotFound {
+			return err
+		}
+
+		link, err = tx.Links().Create(ctx, &model.Link{
+			Path:        key,
+			Digest:      blob.Digest,
+			ContentType: contentType,
+		})
+
+		return err
+	}); err != nil {
+		return nil, errors.Wrap(err, "failed to link blob")
+	}
+
+	if err := u.write(ctx, blob, spool, contentType); err != nil {
+		u.unlink(ctx, key, previous)
+		return nil, errors.Wrap(err, "failed to store blob")
+	}
+
+	if err := u.uploads.Delete(ctx, key); err != nil && err != upload.ErrNotFound {
+		return nil, errors.Wrap(err, "failed to remove unlinked object")
+	}
+
+	return object(link, blob), nil
+}
+
+// Get implements the upload.Upload interface.
+func (u *dedup) Get(ctx context.Context, key string) (io.ReadCloser, *upload.Object, error) {
+	if !valid(key) {
+		return nil, nil, upload.ErrInvalidKey
+	}
+
+	link, blob, err := u.lookup(ctx, key)
+
+	if err == store.ErrNotFound {
+		return u.uploads.Get(ctx, key)
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r, _, err := u.uploads.Get(ctx, Key(blob.Digest))
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return r, object(link, blob), nil
+}
+
+// Stat implements the upload.Upload interface.
+func (u *dedup) Stat(ctx context.Context, key string) (*upload.Object, error) {
+	if !valid(key) {
+		return nil, upload.ErrInvalidKey
+	}
+
+	link, blob, err := u.lookup(ctx, key)
+
+	if err == store.ErrNotFound {
+		return u.uploads.Stat(ctx, key)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return object(link, blob), nil
+}
+
+// Delete implements the upload.Upload interface, only the mapping gets
+// removed while the blob is kept for the garbage collection.
+func (u *dedup) Delete(ctx context.Context, key string) error {
+	if !valid(key) {
+		return upload.ErrInvalidKey
+	}
+
+	err := u.store.Links().Delete(ctx, key)
+
+	if err == store.ErrNotFound {
+		return u.uploads.Delete(ctx, key)
+	}
+
+	return err
+}
+
+// List implements the upload.Upload interface, it merges the mapped keys
+// with the objects which have been stored without a mapping.
+func (u *dedup) List(ctx context.Context, prefix string) ([]*upload.Object, error) {
+	var (
+		links []*model.Link
+		blobs []*model.Blob
+	)
+
+	if err := u.store.Snapshot(ctx, func(tx store.Repositories) error {
+		var err error
+
+		if links, err = tx.Links().List(ctx); err != nil {
+			return err
+		}
+
+		blobs, err = tx.Blobs().List(ctx)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	digests := make(map[string]*model.Blob, len(blobs))
+
+	for _, blob := range blobs {
+		digests[blob.Digest] = blob
+	}
+
+	records := make([]*upload.Object, 0)
+	linked := make(map[string]bool, len(links))
+
+	for _, link := range links {
+		linked[link.Path] = true
+
+		if !strings.HasPrefix(link.Path, prefix) {
+			continue
+		}
+
+		records = append(records, object(link, digests[link.Digest]))
+	}
+
+	objects, err := u.uploads.List(ctx, prefix)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, obj := range objects {
+		if linked[obj.Key] || strings.HasPrefix(obj.Key, BlobPrefix) {
+			continue
+		}
+
+		records = append(records, obj)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Key < records[j].Key
+	})
+
+	return records, nil
+}
+
+// lookup fetches the mapping of the key together with its blob.
+func (u *dedup) lookup(ctx context.Context, key string) (*model.Link, *model.Blob, error) {
+	var (
+		link *model.Link
+		blob *model.Blob
+	)
+
+	if err := u.store.Snapshot(ctx, func(tx store.Repositories) error {
+		var err error
+
+		if link, err = tx.Links().Show(ctx, key); err != nil {
+			return err
+		}
+
+		blob, err = tx.Blobs().Show(ctx, link.Digest)
+		return err
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	return link, blob, nil
+}
+
+// write stores the content of the blob unless it already exists.
+func (u *dedup) write(ctx context.Context, blob *model.Blob, spool io.ReadSeeker, contentType string) error {
+	_, err := u.uploads.Stat(ctx, Key(blob.Digest))
+
+	if err != upload.ErrNotFound {
+		return err
+	}
+
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	_, err = u.uploads.Put(ctx, Key(blob.Digest), spool, blob.Size, contentType)
+	return err
+}
+
+// unlink removes the mapping of a blob which couldn't be stored and
+// restores the previous mapping of the key, failures are ignored as the
+// garbage collection catches the leftovers.
+func (u *dedup) unlink(ctx context.Context, key string, previous *model.Link) {
+	u.store.Transaction(ctx, func(tx store.Repositories) error {
+		if err := tx.Links().Delete(ctx, key); err != nil {
+			return err
+		}
+
+		if previous != nil {
+			_, err := tx.Links().Create(ctx, previous)
+			return err
+		}
+
+		return nil
+	})
+}
+
+// Key generates the key of a blob within the wrapped driver, the blobs get
+// spread across directories by the first characters of the digest.
+func Key(digest string) string {
+	return path.Join(BlobPrefix, digest[:2], digest)
+}
+
+// valid checks if the key is valid and doesn't point to the blobs.
+func valid(key string) bool {
+	return upload.ValidKey(key) && !strings.HasPrefix(key, BlobPrefix)
+}
+
+// object converts a mapping and its blob into an object.
+func object(link *model.Link, blob *model.Blob) *upload.Object {
+	result := &upload.Object{
+		Key:         link.Path,
+		ContentType: link.ContentType,
+		ModifiedAt:  link.UpdatedAt,
+	}
+
+	if blob != nil {
+		result.Size = blob.Size
+		result.Checksum = blob.MD5
+	}
+
+	return result
+}
+
+// New wraps the uploads to store the content deduplicated, the mappings and
+// references get recorded within the store.
+func New(uploads upload.Upload, s store.Store) upload.Upload {
+	return &dedup{
+		uploads: uploads,
+		store:   s,
+	}
+}
@@ -0,0 +1,147 @@
+package dedup
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/kleister/kleister-api/pkg/store/memory"
+	"github.com/kleister/kleister-api/pkg/upload"
+	"github.com/kleister/kleister-api/pkg/upload/file"
+	"github.com/kleister/kleister-api/pkg/upload/uploadtest"
+)
+
+func TestConformance(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kleister-dedup")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+	counter := 0
+
+	uploadtest.Run(t, func(t *testing.T) upload.Upload {
+		counter++
+		return open(t, filepath.Join(dir, strconv.Itoa(counter)))
+	})
+}
+
+func TestDeduplication(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kleister-dedup")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	raw := file.Must(&url.URL{Scheme: "file", Path: dir})
+	s := memory.Must(&url.URL{Scheme: "memory"})
+	u := New(raw, s)
+
+	if _, err := raw.Put(ctx, "mods/legacy.zip", strings.NewReader("legacy"), -1, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"mods/foo/foo-1.0.jar", "mods/bar/bar-1.0.jar"} {
+		if _, err := u.Put(ctx, key, strings.NewReader("content"), -1, "application/java-archive"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	blobs, err := s.Blobs().List(ctx)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(blobs) != 1 || blobs[0].References != 2 {
+		t.Fatalf("expected a single blob with two references, got %+v", blobs)
+	}
+
+	objects, err := raw.List(ctx, "")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(objects) != 2 || objects[0].Key != Key(blobs[0].Digest) || objects[1].Key != "mods/legacy.zip" {
+		t.Fatalf("expected the blob and the legacy object, got %d objects", len(objects))
+	}
+
+	objects, err = u.List(ctx, "mods/")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(objects) != 3 || objects[2].Key != "mods/legacy.zip" {
+		t.Fatalf("expected mapped and legacy objects, got %d objects", len(objects))
+	}
+
+	handler := u.Handler("/storage")
+
+	for key, expected := range map[string]int{
+		"mods/foo/foo-1.0.jar":        http.StatusOK,
+		"mods/legacy.zip":             http.StatusOK,
+		"mods/missing.jar":            http.StatusNotFound,
+		Key(blobs[0].Digest):          http.StatusNotFound,
+		"mods/../" + BlobPrefix + "x": http.StatusNotFound,
+	} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/storage/"+key, nil))
+
+		if w.Code != expected {
+			t.Fatalf("expected status %d for %s, got %d", expected, key, w.Code)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/storage/mods/bar/bar-1.0.jar", nil))
+
+	if w.Body.String() != "content" || w.Header().Get("Content-Type") != "application/java-archive" {
+		t.Fatalf("unexpected response %q with %s", w.Body.String(), w.Header().Get("Content-Type"))
+	}
+
+	if err := u.Delete(ctx, "mods/foo/foo-1.0.jar"); err != nil {
+		t.Fatal(err)
+	}
+
+	blob, err := s.Blobs().Show(ctx, blobs[0].Digest)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if blob.References != 1 {
+		t.Fatalf("expected one reference after delete, got %d", blob.References)
+	}
+
+	if _, err := u.Put(ctx, "mods/legacy.zip", strings.NewReader("content"), -1, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := raw.Stat(ctx, "mods/legacy.zip"); err != upload.ErrNotFound {
+		t.Fatalf("expected legacy object to be replaced by a mapping, got %v", err)
+	}
+}
+
+// open wraps a file driver within the directory with a memory store.
+func open(t *testing.T, dir string) upload.Upload {
+	raw, err := file.New(&url.URL{Scheme: "file", Path: dir})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return New(raw, memory.Must(&url.URL{Scheme: "memory"}))
+}
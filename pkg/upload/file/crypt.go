@@ -0,0 +1,425 @@
+package file
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Encrypted objects start with a header which contains the id of the master
+// key, the data key wrapped by the master key and the nonce prefix. The
+// content follows in chunks which get sealed separately by the data key,
+// this way ranges can be read without decrypting the whole object.
+const (
+	// magic identifies encrypted objects.
+	magic = "KLSTENC\x01"
+
+	// keySize defines the size of master and data keys for AES-256.
+	keySize = 32
+
+	// keyIDSize defines the size of the key id within the header.
+	keyIDSize = 8
+
+	// prefixSize defines the size of the random nonce prefix of the chunks,
+	// the nonce gets completed by the chunk index and a flag for the last one.
+	prefixSize = 7
+
+	// chunkSize defines the size of the plain content of a chunk.
+	chunkSize = 64 * 1024
+
+	// overhead defines the size of the authentication tag of every chunk.
+	overhead = 16
+
+	// wrappedSize defines the size of the wrapped data key including nonce.
+	wrappedSize = 12 + keySize + overhead
+
+	// headerSize defines the size of the header of encrypted objects.
+	headerSize = len(magic) + keyIDSize + wrappedSize + prefixSize
+)
+
+var (
+	// ErrInvalidEncryptionKey defines a named error for keys which are no
+	// base64 encoded 32 bytes.
+	ErrInvalidEncryptionKey = errors.New("invalid encryption key")
+
+	// ErrUnknownEncryptionKey defines a named error for objects which have
+	// been encrypted by a key which is not configured.
+	ErrUnknownEncryptionKey = errors.New("object encrypted with unknown key")
+
+	// ErrEncryptionDisabled defines a named error if an operation requires
+	// an encryption key but none is configured.
+	ErrEncryptionDisabled = errors.New("encryption is not configured")
+
+	// ErrCorruptObject defines a named error for encrypted objects which
+	// can't be authenticated.
+	ErrCorruptObject = errors.New("corrupt encrypted object")
+)
+
+// Key decodes a base64 encoded encryption key, surrounding whitespace like
+// the trailing newline of a key file gets ignored.
+func Key(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+
+	if err != nil || len(key) != keySize {
+		return nil, ErrInvalidEncryptionKey
+	}
+
+	return key, nil
+}
+
+// master represents a configured master key which wraps the data keys.
+type master struct {
+	id   []byte
+	aead cipher.AEAD
+}
+
+// newMaster prepares a master key, the id derives from its hash.
+func newMaster(key []byte) (*master, error) {
+	if len(key) != keySize {
+		return nil, ErrInvalidEncryptionKey
+	}
+
+	aead, err := newAEAD(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(key)
+
+	return &master{
+		id:   sum[:keyIDSize],
+		aead: aead,
+	}, nil
+}
+
+// header describes the header of an encrypted object.
+type header struct {
+	id     []byte
+	key    []byte
+	prefix []byte
+}
+
+// newHeader generates a random data key and nonce prefix.
+func newHeader() (*header, error) {
+	h := &header{
+		key:    make([]byte, keySize),
+		prefix: make([]byte, prefixSize),
+	}
+
+	if _, err := io.ReadFull(rand.Reader, h.key); err != nil {
+		return nil, err
+	}
+
+	if _, err := io.ReadFull(rand.Reader, h.prefix); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+// encode wraps the data key by the master key and serializes the header.
+func (h *header) encode(m *master) ([]byte, error) {
+	nonce := make([]byte, m.aead.NonceSize())
+
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	h.id = m.id
+
+	result := make([]byte, 0, headerSize)
+	result = append(result, magic...)
+	result = append(result, m.id...)
+	result = append(result, nonce...)
+	result = m.aead.Seal(result, nonce, h.key, result[:len(magic)+keyIDSize])
+	result = append(result, h.prefix...)
+
+	return result, nil
+}
+
+// readHeader reads the header of an object, it returns nil if the object is
+// not encrypted. The data key gets unwrapped by the matching master key.
+func readHeader(r io.ReaderAt, masters []*master) (*header, error) {
+	raw := make([]byte, headerSize)
+
+	if n, err := r.ReadAt(raw, 0); n < len(magic) || string(raw[:len(magic)]) != magic {
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		return nil, nil
+	} else if n < headerSize {
+		return nil, ErrCorruptObject
+	}
+
+	h := &header{
+		id:     raw[len(magic) : len(magic)+keyIDSize],
+		prefix: raw[headerSize-prefixSize:],
+	}
+
+	for _, m := range masters {
+		if !bytes.Equal(m.id, h.id) {
+			continue
+		}
+
+		wrapped := raw[len(magic)+keyIDSize : headerSize-prefixSize]
+		nonce := wrapped[:m.aead.NonceSize()]
+
+		key, err := m.aead.Open(nil, nonce, wrapped[len(nonce):], raw[:len(magic)+keyIDSize])
+
+		if err != nil {
+			return nil, ErrCorruptObject
+		}
+
+		h.key = key
+		return h, nil
+	}
+
+	return nil, ErrUnknownEncryptionKey
+}
+
+// nonce builds the nonce of a chunk, the last chunk gets flagged to detect
+// truncated objects.
+func (h *header) nonce(index int64, last bool) []byte {
+	result := make([]byte, prefixSize+5)
+	copy(result, h.prefix)
+	binary.BigEndian.PutUint32(result[prefixSize:], uint32(index))
+
+	if last {
+		result[len(result)-1] = 1
+	}
+
+	return result
+}
+
+// plainSize calculates the size of the content from the size of an
+// encrypted object.
+func plainSize(stored int64) (int64, error) {
+	content := stored - int64(headerSize)
+
+	if content < overhead {
+		return 0, ErrCorruptObject
+	}
+
+	chunks := (content + chunkSize + overhead - 1) / (chunkSize + overhead)
+
+	if content-(chunks-1)*(chunkSize+overhead) < overhead {
+		return 0, ErrCorruptObject
+	}
+
+	return content - chunks*overhead, nil
+}
+
+// encrypter seals the written content in chunks, it has to be closed to
+// write the last chunk.
+type encrypter struct {
+	w      io.Writer
+	aead   cipher.AEAD
+	header *header
+	index  int64
+	buf    []byte
+}
+
+// newEncrypter writes the header and prepares the chunks.
+func newEncrypter(w io.Writer, m *master) (*encrypter, error) {
+	h, err := newHeader()
+
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(h.key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := h.encode(m)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := w.Write(raw); err != nil {
+		return nil, err
+	}
+
+	return &encrypter{
+		w:      w,
+		aead:   aead,
+		header: h,
+		buf:    make([]byte, 0, chunkSize),
+	}, nil
+}
+
+// Write implements the io.Writer interface, a full chunk only gets sealed
+// when more content follows as the last chunk is flagged.
+func (e *encrypter) Write(p []byte) (int, error) {
+	written := 0
+
+	for len(p) > 0 {
+		if len(e.buf) == chunkSize {
+			if err := e.seal(false); err != nil {
+				return written, err
+			}
+		}
+
+		n := copy(e.buf[len(e.buf):chunkSize], p)
+		e.buf = e.buf[:len(e.buf)+n]
+
+		p = p[n:]
+		written += n
+	}
+
+	return written, nil
+}
+
+// Close seals the last chunk, it doesn't close the underlying writer.
+func (e *encrypter) Close() error {
+	return e.seal(true)
+}
+
+// seal encrypts the buffered chunk and writes it.
+func (e *encrypter) seal(last bool) error {
+	sealed := e.aead.Seal(nil, e.header.nonce(e.index, last), e.buf, nil)
+
+	if _, err := e.w.Write(sealed); err != nil {
+		return err
+	}
+
+	e.index++
+	e.buf = e.buf[:0]
+
+	return nil
+}
+
+// decrypter reads and seeks within the content of an encrypted object, it
+// only decrypts the chunks which get read.
+type decrypter struct {
+	f      *os.File
+	aead   cipher.AEAD
+	header *header
+	size   int64
+	offset int64
+	index  int64
+	chunk  []byte
+}
+
+// newDecrypter prepares reading the content of an encrypted object.
+func newDecrypter(f *os.File, h *header, size int64) (*decrypter, error) {
+	aead, err := newAEAD(h.key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &decrypter{
+		f:      f,
+		aead:   aead,
+		header: h,
+		size:   size,
+		index:  -1,
+	}, nil
+}
+
+// Read implements the io.Reader interface.
+func (d *decrypter) Read(p []byte) (int, error) {
+	if d.offset >= d.size {
+		return 0, io.EOF
+	}
+
+	index := d.offset / chunkSize
+
+	if index != d.index {
+		if err := d.load(index); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, d.chunk[d.offset-index*chunkSize:])
+	d.offset += int64(n)
+
+	return n, nil
+}
+
+// Seek implements the io.Seeker interface.
+func (d *decrypter) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += d.offset
+	case io.SeekEnd:
+		offset += d.size
+	default:
+		return 0, errors.New("invalid whence")
+	}
+
+	if offset < 0 {
+		return 0, errors.New("negative position")
+	}
+
+	d.offset = offset
+	return offset, nil
+}
+
+// Close implements the io.Closer interface.
+func (d *decrypter) Close() error {
+	return d.f.Close()
+}
+
+// load reads and authenticates a single chunk.
+func (d *decrypter) load(index int64) error {
+	last := (d.size - 1) / chunkSize
+
+	if d.size == 0 {
+		last = 0
+	}
+
+	length := int64(chunkSize)
+
+	if index == last {
+		length = d.size - index*chunkSize
+	}
+
+	sealed := make([]byte, length+overhead)
+
+	if _, err := d.f.ReadAt(sealed, int64(headerSize)+index*(chunkSize+overhead)); err != nil {
+		if err == io.EOF {
+			return ErrCorruptObject
+		}
+
+		return err
+	}
+
+	chunk, err := d.aead.Open(d.chunk[:0], d.header.nonce(index, index == last), sealed, nil)
+
+	if err != nil {
+		return ErrCorruptObject
+	}
+
+	d.chunk = chunk
+	d.index = index
+
+	return nil
+}
+
+// newAEAD prepares AES-GCM for a key.
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
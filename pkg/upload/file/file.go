@@ -2,6 +2,7 @@ package file
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"os"
@@ -12,11 +13,16 @@ import (
 )
 
 type file struct {
-	dsn *url.URL
+	dsn     *url.URL
+	masters []*master
 }
 
 // Info prepares some informational message about the handler.
 func (u *file) Info() string {
+	if len(u.masters) > 0 {
+		return fmt.Sprintf("prepared encrypted file storage at %s", u.path())
+	}
+
 	return fmt.Sprintf("prepared file storage at %s", u.path())
 }
 
@@ -36,13 +42,34 @@ func (u *file) Close() error {
 	return nil
 }
 
-// Handler implements an HTTP handler for asset uploads.
+// Handler implements an HTTP handler for asset uploads, it supports range
+// and conditional requests. Directories don't get listed and paths with
+// segments starting with a dot are reserved for metadata and temporary files.
 func (u *file) Handler(root string) http.Handler {
 	return http.StripPrefix(
 		root+"/",
-		http.FileServer(
-			http.Dir(u.path()),
-		),
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				w.Header().Set("Allow", "GET, HEAD")
+				http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+				return
+			}
+
+			body, obj, err := u.Get(r.Context(), r.URL.Path)
+
+			if err == upload.ErrNotFound || err == upload.ErrInvalidKey {
+				http.NotFound(w, r)
+				return
+			}
+
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+
+			defer body.Close()
+			upload.Serve(w, r, obj, body.(io.ReadSeeker))
+		}),
 	)
 }
 
@@ -69,18 +96,31 @@ func (u *file) path() string {
 	)
 }
 
-// New initializes a new file handler.
-func New(dsn *url.URL) (upload.Upload, error) {
+// New initializes a new file handler. If keys are given new objects get
+// encrypted by the first one, the others are only used to decrypt objects
+// until they have been rotated. Plain objects can always be read.
+func New(dsn *url.URL, keys ...[]byte) (upload.Upload, error) {
 	f := &file{
-		dsn: dsn,
+		dsn:     dsn,
+		masters: make([]*master, 0, len(keys)),
+	}
+
+	for _, key := range keys {
+		m, err := newMaster(key)
+
+		if err != nil {
+			return nil, err
+		}
+
+		f.masters = append(f.masters, m)
 	}
 
 	return f.Prepare()
 }
 
 // Must simply calls New and panics on an error.
-func Must(dsn *url.URL) upload.Upload {
-	db, err := New(dsn)
+func Must(dsn *url.URL, keys ...[]byte) upload.Upload {
+	db, err := New(dsn, keys...)
 
 	if err != nil {
 		panic(err)
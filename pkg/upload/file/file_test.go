@@ -0,0 +1,259 @@
+package file
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/kleister/kleister-api/pkg/upload"
+	"github.com/kleister/kleister-api/pkg/upload/uploadtest"
+)
+
+func TestConformance(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kleister-file")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+	counter := 0
+
+	uploadtest.Run(t, func(t *testing.T) upload.Upload {
+		counter++
+
+		u, err := New(&url.URL{
+			Scheme: "file",
+			Path:   filepath.Join(dir, strconv.Itoa(counter)),
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return u
+	})
+}
+
+func TestEncryptedConformance(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kleister-file")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+	counter := 0
+	key := generate(t)
+
+	uploadtest.Run(t, func(t *testing.T) upload.Upload {
+		counter++
+
+		u, err := New(&url.URL{
+			Scheme: "file",
+			Path:   filepath.Join(dir, strconv.Itoa(counter)),
+		}, key)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return u
+	})
+}
+
+func TestEncryption(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kleister-file")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	dsn := &url.URL{Scheme: "file", Path: dir}
+	content := bytes.Repeat([]byte("0123456789abcdef"), chunkSize/8+5)
+
+	u := Must(dsn, generate(t))
+
+	_, err = u.Put(ctx, "mods/foo.jar", bytes.NewReader(content), int64(len(content)), "")
+	must(t, err)
+
+	raw, err := ioutil.ReadFile(filepath.Join(dir, "mods", "foo.jar"))
+	must(t, err)
+
+	if bytes.Contains(raw, content[:64]) {
+		t.Fatalf("expected content to be encrypted")
+	}
+
+	if size, err := plainSize(int64(len(raw))); err != nil || size != int64(len(content)) {
+		t.Fatalf("expected plain size %d, got %d with %v", len(content), size, err)
+	}
+
+	must(t, os.RemoveAll(filepath.Join(dir, metaDir)))
+
+	r, obj, err := u.Get(ctx, "mods/foo.jar")
+	must(t, err)
+
+	defer r.Close()
+
+	if obj.Size != int64(len(content)) || obj.Checksum != checksum(content) {
+		t.Fatalf("expected metadata of the plain content, got %+v", obj)
+	}
+
+	seeker := r.(io.ReadSeeker)
+
+	for _, offset := range []int64{chunkSize + 3, 7, chunkSize*2 - 1} {
+		_, err := seeker.Seek(offset, io.SeekStart)
+		must(t, err)
+
+		part := make([]byte, 32)
+		_, err = io.ReadFull(seeker, part)
+		must(t, err)
+
+		if !bytes.Equal(part, content[offset:offset+32]) {
+			t.Fatalf("expected content at offset %d, got %q", offset, part)
+		}
+	}
+
+	if _, _, err := Must(dsn, generate(t)).Get(ctx, "mods/foo.jar"); err != ErrUnknownEncryptionKey {
+		t.Fatalf("expected unknown key, got %v", err)
+	}
+
+	raw[len(raw)-1] ^= 1
+	must(t, ioutil.WriteFile(filepath.Join(dir, "mods", "foo.jar"), raw, 0644))
+
+	if _, err := u.Stat(ctx, "mods/foo.jar"); err != ErrCorruptObject {
+		t.Fatalf("expected corrupt object, got %v", err)
+	}
+}
+
+func TestPlainMagic(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kleister-file")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	dsn := &url.URL{Scheme: "file", Path: dir}
+	content := append([]byte(magic), bytes.Repeat([]byte{0}, headerSize)...)
+
+	_, err = Must(dsn).Put(ctx, "mods/magic.jar", bytes.NewReader(content), int64(len(content)), "")
+	must(t, err)
+
+	for _, u := range []upload.Upload{Must(dsn), Must(dsn, generate(t))} {
+		r, obj, err := u.Get(ctx, "mods/magic.jar")
+		must(t, err)
+
+		served, err := ioutil.ReadAll(r)
+		r.Close()
+		must(t, err)
+
+		if !bytes.Equal(served, content) || obj.Checksum != checksum(content) {
+			t.Fatalf("expected plain content to be served as it is, got %d bytes", len(served))
+		}
+	}
+
+	rotated, err := Rotate(ctx, Must(dsn, generate(t)), "mods/magic.jar")
+	must(t, err)
+
+	if !rotated {
+		t.Fatalf("expected plain content to be encrypted")
+	}
+}
+
+func TestRotate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kleister-file")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	dsn := &url.URL{Scheme: "file", Path: dir}
+	previous, current := generate(t), generate(t)
+
+	_, err = Must(dsn).Put(ctx, "mods/plain.jar", strings.NewReader("plain"), -1, "")
+	must(t, err)
+
+	_, err = Must(dsn, previous).Put(ctx, "mods/previous.jar", strings.NewReader("previous"), -1, "")
+	must(t, err)
+
+	if _, err := Rotate(ctx, Must(dsn), "mods/plain.jar"); err != ErrEncryptionDisabled {
+		t.Fatalf("expected encryption to be required, got %v", err)
+	}
+
+	u := Must(dsn, current, previous)
+
+	for key, body := range map[string]string{"mods/plain.jar": "plain", "mods/previous.jar": "previous"} {
+		before, err := u.Stat(ctx, key)
+		must(t, err)
+
+		rotated, err := Rotate(ctx, u, key)
+		must(t, err)
+
+		if !rotated {
+			t.Fatalf("expected %s to be rotated", key)
+		}
+
+		if rotated, err := Rotate(ctx, u, key); err != nil || rotated {
+			t.Fatalf("expected %s to be rotated only once, got %v", key, err)
+		}
+
+		r, obj, err := Must(dsn, current).Get(ctx, key)
+		must(t, err)
+
+		content, err := ioutil.ReadAll(r)
+		r.Close()
+		must(t, err)
+
+		if string(content) != body {
+			t.Fatalf("expected content %q, got %q", body, content)
+		}
+
+		if !obj.ModifiedAt.Equal(before.ModifiedAt) || obj.Checksum != before.Checksum {
+			t.Fatalf("expected metadata to be kept, got %+v instead of %+v", obj, before)
+		}
+	}
+}
+
+// generate creates a random encryption key.
+func generate(t *testing.T) []byte {
+	key := make([]byte, keySize)
+
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
+
+// checksum calculates the expected checksum of the content.
+func checksum(content []byte) string {
+	sum := md5.Sum(content)
+	return hex.EncodeToString(sum[:])
+}
+
+func must(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,502 @@
+package file
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kleister/kleister-api/pkg/upload"
+)
+
+const (
+	// metaDir defines the directory below the root to store the metadata.
+	metaDir = ".meta"
+
+	// tempPrefix defines the prefix of temporary files while writing.
+	tempPrefix = ".upload-"
+)
+
+// meta defines the metadata stored next to every object, it is only valid
+// as long as size and modification time match the content. The size and
+// checksum always refer to the plain content, the stored size is only
+// recorded for encrypted objects. Whether an object is encrypted gets
+// recorded as well, this way plain content which starts like an encrypted
+// object is still served as it is.
+type meta struct {
+	Size        int64     `json:"size"`
+	Stored      int64     `json:"stored,omitempty"`
+	Encrypted   bool      `json:"encrypted,omitempty"`
+	ContentType string    `json:"content_type"`
+	Checksum    string    `json:"checksum"`
+	ModifiedAt  time.Time `json:"modified_at"`
+}
+
+// stored returns the size of the object on disk.
+func (m *meta) stored() int64 {
+	if m.Stored > 0 {
+		return m.Stored
+	}
+
+	return m.Size
+}
+
+// Put implements the upload.Upload interface, the content gets written to a
+// temporary file which replaces the object after a successful write. It gets
+// encrypted if a key is configured.
+func (u *file) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (*upload.Object, error) {
+	if !upload.ValidKey(key) {
+		return nil, upload.ErrInvalidKey
+	}
+
+	target := u.object(key)
+
+	if err := os.MkdirAll(filepath.Dir(target), u.perms()); err != nil {
+		return nil, err
+	}
+
+	f, err := ioutil.TempFile(filepath.Dir(target), tempPrefix)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer os.Remove(f.Name())
+
+	var (
+		w   io.Writer = f
+		enc *encrypter
+	)
+
+	if len(u.masters) > 0 {
+		if enc, err = newEncrypter(f, u.masters[0]); err != nil {
+			f.Close()
+			return nil, err
+		}
+
+		w = enc
+	}
+
+	hash := md5.New()
+	written, err := io.Copy(w, io.TeeReader(&reader{ctx: ctx, r: r}, hash))
+
+	if err == nil && enc != nil {
+		err = enc.Close()
+	}
+
+	if err == nil && size >= 0 && written != size {
+		err = upload.ErrSizeMismatch
+	}
+
+	if err == nil {
+		err = f.Sync()
+	}
+
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.Chmod(f.Name(), 0644); err != nil {
+		return nil, err
+	}
+
+	// The previous metadata gets removed first, this way a failure in between
+	// results in metadata which gets detected from the content.
+	if err := os.Remove(u.meta(key)); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	if err := os.Rename(f.Name(), target); err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(target)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if contentType == "" {
+		contentType = detect(key)
+	}
+
+	m := &meta{
+		Size:        written,
+		ContentType: contentType,
+		Checksum:    hex.EncodeToString(hash.Sum(nil)),
+		ModifiedAt:  info.ModTime().UTC(),
+	}
+
+	if enc != nil {
+		m.Stored = info.Size()
+		m.Encrypted = true
+	}
+
+	if err := u.store(key, m); err != nil {
+		return nil, err
+	}
+
+	return object(key, m), nil
+}
+
+// Get implements the upload.Upload interface, encrypted objects get
+// decrypted transparently and the reader supports seeking in any case.
+func (u *file) Get(ctx context.Context, key string) (io.ReadCloser, *upload.Object, error) {
+	m, err := u.stat(key)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	obj := object(key, m)
+
+	f, err := os.Open(u.object(key))
+
+	if os.IsNotExist(err) {
+		return nil, nil, upload.ErrNotFound
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	h, err := u.header(f, m)
+
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+
+	if h == nil {
+		return f, obj, nil
+	}
+
+	d, err := newDecrypter(f, h, obj.Size)
+
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+
+	return d, obj, nil
+}
+
+// Stat implements the upload.Upload interface, the metadata gets detected
+// from the content if it's missing or outdated.
+func (u *file) Stat(ctx context.Context, key string) (*upload.Object, error) {
+	m, err := u.stat(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return object(key, m), nil
+}
+
+// stat loads the metadata of an object or detects it from the content.
+func (u *file) stat(key string) (*meta, error) {
+	if !upload.ValidKey(key) {
+		return nil, upload.ErrInvalidKey
+	}
+
+	info, err := os.Stat(u.object(key))
+
+	if os.IsNotExist(err) {
+		return nil, upload.ErrNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.Mode().IsRegular() {
+		return nil, upload.ErrNotFound
+	}
+
+	m, err := u.load(key)
+
+	if err != nil || m.stored() != info.Size() || !m.ModifiedAt.Equal(info.ModTime().UTC()) {
+		if m, err = u.detect(key, info); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// Delete implements the upload.Upload interface, empty parent directories
+// get removed as well.
+func (u *file) Delete(ctx context.Context, key string) error {
+	if !upload.ValidKey(key) {
+		return upload.ErrInvalidKey
+	}
+
+	if err := os.Remove(u.object(key)); err != nil {
+		if os.IsNotExist(err) {
+			return upload.ErrNotFound
+		}
+
+		return err
+	}
+
+	if err := os.Remove(u.meta(key)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	u.prune(u.path(), key)
+	u.prune(filepath.Join(u.path(), metaDir), key)
+
+	return nil
+}
+
+// prune removes the empty parent directories of a key below the root.
+func (u *file) prune(root, key string) {
+	for dir := path.Dir(key); dir != "."; dir = path.Dir(dir) {
+		if os.Remove(filepath.Join(root, filepath.FromSlash(dir))) != nil {
+			break
+		}
+	}
+}
+
+// List implements the upload.Upload interface.
+func (u *file) List(ctx context.Context, prefix string) ([]*upload.Object, error) {
+	records := make([]*upload.Object, 0)
+	start := u.path()
+
+	if idx := strings.LastIndex(prefix, "/"); idx > 0 {
+		start = filepath.Join(start, filepath.FromSlash(prefix[:idx]))
+	}
+
+	err := filepath.Walk(start, func(name string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && name == start {
+				return filepath.SkipDir
+			}
+
+			return err
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if strings.HasPrefix(info.Name(), ".") && name != start {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(u.path(), name)
+
+		if err != nil {
+			return err
+		}
+
+		key := filepath.ToSlash(rel)
+
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		record, err := u.Stat(ctx, key)
+
+		if err != nil {
+			return err
+		}
+
+		records = append(records, record)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Key < records[j].Key
+	})
+
+	return records, nil
+}
+
+// object returns the path of the content of an object.
+func (u *file) object(key string) string {
+	return filepath.Join(u.path(), filepath.FromSlash(key))
+}
+
+// meta returns the path of the metadata of an object.
+func (u *file) meta(key string) string {
+	return filepath.Join(u.path(), metaDir, filepath.FromSlash(key)+".json")
+}
+
+// load reads the metadata of an object.
+func (u *file) load(key string) (*meta, error) {
+	content, err := ioutil.ReadFile(u.meta(key))
+
+	if err != nil {
+		return nil, err
+	}
+
+	m := &meta{}
+
+	if err := json.Unmarshal(content, m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// header reads the header of an object which is encrypted according to its
+// metadata, it returns nil for plain objects.
+func (u *file) header(r io.ReaderAt, m *meta) (*header, error) {
+	if !m.Encrypted {
+		return nil, nil
+	}
+
+	h, err := readHeader(r, u.masters)
+
+	if err == nil && h == nil {
+		return nil, ErrCorruptObject
+	}
+
+	return h, err
+}
+
+// store writes the metadata of an object through a temporary file.
+func (u *file) store(key string, m *meta) error {
+	target := u.meta(key)
+
+	if err := os.MkdirAll(filepath.Dir(target), u.perms()); err != nil {
+		return err
+	}
+
+	content, err := json.Marshal(m)
+
+	if err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(filepath.Dir(target), tempPrefix)
+
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), target)
+}
+
+// detect builds the metadata of an object from its content, this is used
+// for files which have been placed without the driver. Only here an object
+// is considered to be encrypted if it starts with the encryption header.
+func (u *file) detect(key string, info os.FileInfo) (*meta, error) {
+	f, err := os.Open(u.object(key))
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	var (
+		r io.Reader = f
+		m           = &meta{
+			ContentType: detect(key),
+			ModifiedAt:  info.ModTime().UTC(),
+		}
+	)
+
+	h, err := readHeader(f, u.masters)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if h != nil {
+		size, err := plainSize(info.Size())
+
+		if err != nil {
+			return nil, err
+		}
+
+		if r, err = newDecrypter(f, h, size); err != nil {
+			return nil, err
+		}
+
+		m.Stored = info.Size()
+		m.Encrypted = true
+	}
+
+	hash := md5.New()
+
+	if m.Size, err = io.Copy(hash, r); err != nil {
+		return nil, err
+	}
+
+	m.Checksum = hex.EncodeToString(hash.Sum(nil))
+	return m, nil
+}
+
+// detect guesses the content type from the extension of the key.
+func detect(key string) string {
+	if val := mime.TypeByExtension(path.Ext(key)); val != "" {
+		return val
+	}
+
+	return "application/octet-stream"
+}
+
+// object converts the metadata into an object.
+func object(key string, m *meta) *upload.Object {
+	return &upload.Object{
+		Key:         key,
+		Size:        m.Size,
+		ContentType: m.ContentType,
+		Checksum:    m.Checksum,
+		ModifiedAt:  m.ModifiedAt,
+	}
+}
+
+// reader aborts reading as soon as the context gets canceled.
+type reader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+// Read implements the io.Reader interface.
+func (r *reader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return r.r.Read(p)
+}
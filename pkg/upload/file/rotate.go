@@ -0,0 +1,160 @@
+package file
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/kleister/kleister-api/pkg/upload"
+	"github.com/pkg/errors"
+)
+
+// Rotate re-encrypts an object by the first key of the file driver. Objects
+// encrypted by another key only get their data key wrapped again while plain
+// objects get encrypted completely, the modification time is kept. It
+// returns false if the object is already encrypted by the first key.
+func Rotate(ctx context.Context, u upload.Upload, key string) (bool, error) {
+	f, ok := u.(*file)
+
+	if !ok {
+		return false, upload.ErrUnknownDriver
+	}
+
+	return f.rotate(ctx, key)
+}
+
+// rotate replaces the object by a temporary file encrypted by the first key.
+func (u *file) rotate(ctx context.Context, key string) (bool, error) {
+	if len(u.masters) == 0 {
+		return false, ErrEncryptionDisabled
+	}
+
+	m, err := u.stat(key)
+
+	if err != nil {
+		return false, err
+	}
+
+	target := u.object(key)
+	source, err := os.Open(target)
+
+	if os.IsNotExist(err) {
+		return false, upload.ErrNotFound
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	defer source.Close()
+
+	info, err := source.Stat()
+
+	if err != nil {
+		return false, err
+	}
+
+	h, err := u.header(source, m)
+
+	if err != nil {
+		return false, err
+	}
+
+	if h != nil && bytes.Equal(h.id, u.masters[0].id) {
+		return false, nil
+	}
+
+	f, err := ioutil.TempFile(filepath.Dir(target), tempPrefix)
+
+	if err != nil {
+		return false, err
+	}
+
+	defer os.Remove(f.Name())
+
+	if h != nil {
+		err = rewrap(f, source, h, u.masters[0], info.Size())
+	} else {
+		err = encrypt(ctx, f, source, u.masters[0])
+	}
+
+	if err == nil {
+		err = f.Sync()
+	}
+
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	if err := os.Chmod(f.Name(), 0644); err != nil {
+		return false, err
+	}
+
+	if err := os.Chtimes(f.Name(), info.ModTime(), info.ModTime()); err != nil {
+		return false, err
+	}
+
+	// Objects which have been replaced in the meantime must not be
+	// overwritten by the outdated content.
+	if current, err := os.Stat(target); err != nil || !os.SameFile(info, current) || !current.ModTime().Equal(info.ModTime()) {
+		return false, errors.New("object changed during rotation")
+	}
+
+	if err := os.Rename(f.Name(), target); err != nil {
+		return false, err
+	}
+
+	stored, err := os.Stat(target)
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, u.store(key, &meta{
+		Size:        m.Size,
+		Stored:      stored.Size(),
+		Encrypted:   true,
+		ContentType: m.ContentType,
+		Checksum:    m.Checksum,
+		ModifiedAt:  stored.ModTime().UTC(),
+	})
+}
+
+// rewrap writes the header with the data key wrapped by the master key and
+// copies the encrypted chunks.
+func rewrap(w io.Writer, source io.ReaderAt, h *header, m *master, size int64) error {
+	raw, err := h.encode(m)
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(raw); err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w, io.NewSectionReader(source, int64(headerSize), size-int64(headerSize)))
+	return err
+}
+
+// encrypt writes the plain content encrypted by the master key.
+func encrypt(ctx context.Context, w io.Writer, source io.Reader, m *master) error {
+	enc, err := newEncrypter(w, m)
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(enc, &reader{ctx: ctx, r: source}); err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
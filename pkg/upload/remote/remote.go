@@ -0,0 +1,559 @@
+// Package remote implements the uploads on top of remote filesystems like
+// SFTP or WebDAV which only provide basic file operations. The metadata gets
+// stored next to the objects like for the file driver.
+package remote
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kleister/kleister-api/pkg/upload"
+)
+
+const (
+	// metaDir defines the directory below the root to store the metadata.
+	metaDir = ".meta"
+
+	// tempPrefix defines the prefix of temporary files while writing.
+	tempPrefix = ".upload-"
+)
+
+// File defines an opened remote file.
+type File interface {
+	io.Reader
+	io.Seeker
+	io.Closer
+}
+
+// Filesystem provides the operations of a remote filesystem, names are
+// slash separated and relative to the root of the storage. Missing files
+// have to be reported by errors which are detected by os.IsNotExist.
+type Filesystem interface {
+	// Info describes the location of the storage.
+	Info() string
+
+	// Connect checks the connectivity and creates the root if required.
+	Connect(ctx context.Context) error
+
+	// Close releases the connection.
+	Close() error
+
+	// Stat returns the details of a file or directory.
+	Stat(ctx context.Context, name string) (os.FileInfo, error)
+
+	// ReadDir lists the files and directories within a directory.
+	ReadDir(ctx context.Context, name string) ([]os.FileInfo, error)
+
+	// Open prepares reading a file, the size is negative if it's unknown.
+	Open(ctx context.Context, name string, size int64) (File, error)
+
+	// Write creates or truncates the file with the content of the reader,
+	// the size is negative if it's unknown.
+	Write(ctx context.Context, name string, r io.Reader, size int64) error
+
+	// Rename moves a file and replaces an existing target.
+	Rename(ctx context.Context, oldname, newname string) error
+
+	// Remove deletes a file or an empty directory.
+	Remove(ctx context.Context, name string) error
+
+	// Mkdir creates a directory including its parents.
+	Mkdir(ctx context.Context, name string) error
+}
+
+// meta defines the metadata stored next to every object, it is only valid
+// as long as size and modification time match the content.
+type meta struct {
+	Size        int64     `json:"size"`
+	ContentType string    `json:"content_type"`
+	Checksum    string    `json:"checksum"`
+	ModifiedAt  time.Time `json:"modified_at"`
+}
+
+type remote struct {
+	fs Filesystem
+}
+
+// Info prepares some informational message about the handler.
+func (u *remote) Info() string {
+	return u.fs.Info()
+}
+
+// Prepare connects to the remote filesystem.
+func (u *remote) Prepare() (upload.Upload, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := u.fs.Connect(ctx); err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
+
+// Close closes the connection to the remote filesystem.
+func (u *remote) Close() error {
+	return u.fs.Close()
+}
+
+// Handler implements an HTTP handler for asset uploads, the content gets
+// proxied from the remote filesystem including range requests.
+func (u *remote) Handler(root string) http.Handler {
+	return http.StripPrefix(
+		root+"/",
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				w.Header().Set("Allow", "GET, HEAD")
+				http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+				return
+			}
+
+			body, obj, err := u.Get(r.Context(), r.URL.Path)
+
+			if err == upload.ErrNotFound || err == upload.ErrInvalidKey {
+				http.NotFound(w, r)
+				return
+			}
+
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+
+			defer body.Close()
+			upload.Serve(w, r, obj, body.(io.ReadSeeker))
+		}),
+	)
+}
+
+// Put implements the upload.Upload interface, the content gets written to a
+// temporary file which replaces the object after a successful write.
+func (u *remote) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (*upload.Object, error) {
+	if !upload.ValidKey(key) {
+		return nil, upload.ErrInvalidKey
+	}
+
+	if err := u.fs.Mkdir(ctx, path.Dir(key)); err != nil {
+		return nil, err
+	}
+
+	temp, err := temporary(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	body := &reader{
+		ctx:  ctx,
+		r:    r,
+		size: size,
+		hash: md5.New(),
+	}
+
+	if err := u.fs.Write(ctx, temp, body, size); err != nil {
+		u.fs.Remove(ctx, temp)
+
+		if body.err != nil {
+			return nil, body.err
+		}
+
+		return nil, err
+	}
+
+	if size >= 0 && body.read != size {
+		u.fs.Remove(ctx, temp)
+		return nil, upload.ErrSizeMismatch
+	}
+
+	// The previous metadata gets removed first, this way a failure in between
+	// results in metadata which gets detected from the content.
+	if err := u.fs.Remove(ctx, metaName(key)); err != nil && !os.IsNotExist(err) {
+		u.fs.Remove(ctx, temp)
+		return nil, err
+	}
+
+	if err := u.fs.Rename(ctx, temp, key); err != nil {
+		u.fs.Remove(ctx, temp)
+		return nil, err
+	}
+
+	info, err := u.fs.Stat(ctx, key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if contentType == "" {
+		contentType = detect(key)
+	}
+
+	m := &meta{
+		Size:        body.read,
+		ContentType: contentType,
+		Checksum:    hex.EncodeToString(body.hash.Sum(nil)),
+		ModifiedAt:  info.ModTime().UTC(),
+	}
+
+	if err := u.store(ctx, key, m); err != nil {
+		return nil, err
+	}
+
+	return object(key, m), nil
+}
+
+// Get implements the upload.Upload interface, the reader supports seeking.
+func (u *remote) Get(ctx context.Context, key string) (io.ReadCloser, *upload.Object, error) {
+	obj, err := u.Stat(ctx, key)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	f, err := u.fs.Open(ctx, key, obj.Size)
+
+	if os.IsNotExist(err) {
+		return nil, nil, upload.ErrNotFound
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return f, obj, nil
+}
+
+// Stat implements the upload.Upload interface, the metadata gets detected
+// from the content if it's missing or outdated.
+func (u *remote) Stat(ctx context.Context, key string) (*upload.Object, error) {
+	if !upload.ValidKey(key) {
+		return nil, upload.ErrInvalidKey
+	}
+
+	info, err := u.fs.Stat(ctx, key)
+
+	if os.IsNotExist(err) {
+		return nil, upload.ErrNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return u.stat(ctx, key, info)
+}
+
+// stat loads the metadata of an object, detected metadata gets stored to
+// avoid reading the content again.
+func (u *remote) stat(ctx context.Context, key string, info os.FileInfo) (*upload.Object, error) {
+	if info.IsDir() {
+		return nil, upload.ErrNotFound
+	}
+
+	m, err := u.load(ctx, key)
+
+	if err != nil || m.Size != info.Size() || !m.ModifiedAt.Equal(info.ModTime().UTC()) {
+		if m, err = u.detect(ctx, key, info); err != nil {
+			return nil, err
+		}
+
+		u.store(ctx, key, m)
+	}
+
+	return object(key, m), nil
+}
+
+// Delete implements the upload.Upload interface, empty parent directories
+// get removed as well.
+func (u *remote) Delete(ctx context.Context, key string) error {
+	if !upload.ValidKey(key) {
+		return upload.ErrInvalidKey
+	}
+
+	// Some filesystems remove directories recursively, so only files get
+	// deleted directly.
+	info, err := u.fs.Stat(ctx, key)
+
+	if os.IsNotExist(err) {
+		return upload.ErrNotFound
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if info.IsDir() {
+		return upload.ErrNotFound
+	}
+
+	if err := u.fs.Remove(ctx, key); err != nil {
+		if os.IsNotExist(err) {
+			return upload.ErrNotFound
+		}
+
+		return err
+	}
+
+	if err := u.fs.Remove(ctx, metaName(key)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	u.prune(ctx, "", key)
+	u.prune(ctx, metaDir, key)
+
+	return nil
+}
+
+// prune removes the empty parent directories of a key below the root.
+func (u *remote) prune(ctx context.Context, root, key string) {
+	for dir := path.Dir(key); dir != "."; dir = path.Dir(dir) {
+		name := path.Join(root, dir)
+		entries, err := u.fs.ReadDir(ctx, name)
+
+		if err != nil || len(entries) > 0 {
+			break
+		}
+
+		if u.fs.Remove(ctx, name) != nil {
+			break
+		}
+	}
+}
+
+// List implements the upload.Upload interface.
+func (u *remote) List(ctx context.Context, prefix string) ([]*upload.Object, error) {
+	records := make([]*upload.Object, 0)
+	start := "."
+
+	if idx := strings.LastIndex(prefix, "/"); idx > 0 {
+		start = prefix[:idx]
+	}
+
+	if err := u.walk(ctx, start, prefix, &records); err != nil && !(os.IsNotExist(err) && len(records) == 0) {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Key < records[j].Key
+	})
+
+	return records, nil
+}
+
+// walk collects the objects below a directory which match the prefix.
+func (u *remote) walk(ctx context.Context, dir, prefix string, records *[]*upload.Object) error {
+	entries, err := u.fs.ReadDir(ctx, dir)
+
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		key := path.Join(dir, entry.Name())
+
+		if entry.IsDir() {
+			if !strings.HasPrefix(key+"/", prefix) && !strings.HasPrefix(prefix, key+"/") {
+				continue
+			}
+
+			if err := u.walk(ctx, key, prefix, records); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+
+		record, err := u.stat(ctx, key, entry)
+
+		if err != nil {
+			return err
+		}
+
+		*records = append(*records, record)
+	}
+
+	return nil
+}
+
+// load reads the metadata of an object.
+func (u *remote) load(ctx context.Context, key string) (*meta, error) {
+	f, err := u.fs.Open(ctx, metaName(key), -1)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	content, err := ioutil.ReadAll(f)
+
+	if err != nil {
+		return nil, err
+	}
+
+	m := &meta{}
+
+	if err := json.Unmarshal(content, m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// store writes the metadata of an object through a temporary file.
+func (u *remote) store(ctx context.Context, key string, m *meta) error {
+	target := metaName(key)
+
+	if err := u.fs.Mkdir(ctx, path.Dir(target)); err != nil {
+		return err
+	}
+
+	content, err := json.Marshal(m)
+
+	if err != nil {
+		return err
+	}
+
+	temp, err := temporary(target)
+
+	if err != nil {
+		return err
+	}
+
+	if err := u.fs.Write(ctx, temp, strings.NewReader(string(content)), int64(len(content))); err != nil {
+		u.fs.Remove(ctx, temp)
+		return err
+	}
+
+	if err := u.fs.Rename(ctx, temp, target); err != nil {
+		u.fs.Remove(ctx, temp)
+		return err
+	}
+
+	return nil
+}
+
+// detect builds the metadata of an object from its content, this is used
+// for files which have been placed without the driver.
+func (u *remote) detect(ctx context.Context, key string, info os.FileInfo) (*meta, error) {
+	f, err := u.fs.Open(ctx, key, info.Size())
+
+	if os.IsNotExist(err) {
+		return nil, upload.ErrNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	hash := md5.New()
+
+	if _, err := io.Copy(hash, f); err != nil {
+		return nil, err
+	}
+
+	return &meta{
+		Size:        info.Size(),
+		ContentType: detect(key),
+		Checksum:    hex.EncodeToString(hash.Sum(nil)),
+		ModifiedAt:  info.ModTime().UTC(),
+	}, nil
+}
+
+// New wraps a remote filesystem, it has to be prepared before usage.
+func New(fs Filesystem) upload.Upload {
+	return &remote{
+		fs: fs,
+	}
+}
+
+// metaName returns the name of the metadata of an object.
+func metaName(key string) string {
+	return path.Join(metaDir, key+".json")
+}
+
+// temporary generates a random name for a temporary file next to the target.
+func temporary(target string) (string, error) {
+	suffix := make([]byte, 8)
+
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+
+	return path.Join(path.Dir(target), tempPrefix+hex.EncodeToString(suffix)), nil
+}
+
+// detect guesses the content type from the extension of the key.
+func detect(key string) string {
+	if val := mime.TypeByExtension(path.Ext(key)); val != "" {
+		return val
+	}
+
+	return "application/octet-stream"
+}
+
+// object converts the metadata into an object.
+func object(key string, m *meta) *upload.Object {
+	return &upload.Object{
+		Key:         key,
+		Size:        m.Size,
+		ContentType: m.ContentType,
+		Checksum:    m.Checksum,
+		ModifiedAt:  m.ModifiedAt,
+	}
+}
+
+// reader verifies the size and calculates the checksum while reading, it
+// aborts as soon as the context gets canceled.
+type reader struct {
+	ctx  context.Context
+	r    io.Reader
+	size int64
+	read int64
+	hash hash.Hash
+	err  error
+}
+
+// Read implements the io.Reader interface.
+func (r *reader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	n, err := r.r.Read(p)
+	r.read += int64(n)
+	r.hash.Write(p[:n])
+
+	if r.size >= 0 && (r.read > r.size || (err == io.EOF && r.read != r.size)) {
+		r.err = upload.ErrSizeMismatch
+		return n, r.err
+	}
+
+	return n, err
+}
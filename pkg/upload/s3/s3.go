@@ -1,23 +1,101 @@
 package s3
 
 import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
 	"net/http"
 	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	s3api "github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/kleister/kleister-api/pkg/upload"
+	"github.com/pkg/errors"
+)
+
+const (
+	// checksumMeta defines the user metadata storing the MD5 if the ETag of
+	// an object is not the MD5 of its content, e.g. for multipart uploads.
+	checksumMeta = "Md5"
 )
 
 type s3 struct {
-	dsn *url.URL
+	dsn      *url.URL
+	client   *s3api.S3
+	uploader *s3manager.Uploader
 }
 
 // Info prepares some informational message about the handler.
 func (u *s3) Info() string {
-	return ""
+	endpoint := u.endpoint()
+
+	if endpoint == "" {
+		endpoint = "aws"
+	}
+
+	return fmt.Sprintf("prepared s3 storage for bucket %s at %s", u.bucket(), endpoint)
 }
 
-// Prepare simply prepares the upload handler.
+// Prepare initializes the client and checks if the bucket exists.
 func (u *s3) Prepare() (upload.Upload, error) {
+	if u.bucket() == "" {
+		return nil, errors.New("missing bucket within dsn")
+	}
+
+	cfg := aws.NewConfig().
+		WithRegion(u.region()).
+		WithS3ForcePathStyle(u.pathStyle()).
+		WithDisableSSL(!u.ssl())
+
+	if endpoint := u.endpoint(); endpoint != "" {
+		cfg = cfg.WithEndpoint(endpoint)
+	}
+
+	if u.dsn.User != nil {
+		secret, _ := u.dsn.User.Password()
+
+		cfg = cfg.WithCredentials(credentials.NewStaticCredentials(
+			u.dsn.User.Username(),
+			secret,
+			"",
+		))
+	}
+
+	sess, err := session.NewSession(cfg)
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create session")
+	}
+
+	u.client = s3api.New(sess)
+	u.uploader = s3manager.NewUploaderWithClient(u.client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := u.client.HeadBucketWithContext(ctx, &s3api.HeadBucketInput{
+		Bucket: aws.String(u.bucket()),
+	}); err != nil {
+		if notFound(err) {
+			return nil, errors.Errorf("bucket %s doesn't exist", u.bucket())
+		}
+
+		return nil, errors.Wrap(err, "failed to access bucket")
+	}
+
 	return u, nil
 }
 
@@ -26,11 +104,398 @@ func (u *s3) Close() error {
 	return nil
 }
 
-// Handler implements an HTTP handler for asset uploads.
+// Handler implements an HTTP handler for asset uploads, the objects get
+// proxied or the client gets redirected to a presigned URL if redirect is
+// enabled within the dsn.
 func (u *s3) Handler(root string) http.Handler {
+	return http.StripPrefix(
+		root+"/",
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				w.Header().Set("Allow", "GET, HEAD")
+				http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+				return
+			}
+
+			key := r.URL.Path
+
+			if !upload.ValidKey(key) {
+				http.NotFound(w, r)
+				return
+			}
+
+			if u.redirect() {
+				u.presign(w, r, key)
+				return
+			}
+
+			u.proxy(w, r, key)
+		}),
+	)
+}
+
+// Put implements the upload.Upload interface, the content gets streamed to
+// the bucket and an aborted or short read never replaces the object.
+func (u *s3) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (*upload.Object, error) {
+	if !upload.ValidKey(key) {
+		return nil, upload.ErrInvalidKey
+	}
+
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	body := &reader{
+		r:    r,
+		size: size,
+		hash: md5.New(),
+	}
+
+	if _, err := u.uploader.UploadWithContext(ctx, &s3manager.UploadInput{
+		Bucket:      aws.String(u.bucket()),
+		Key:         aws.String(u.key(key)),
+		Body:        body,
+		ContentType: aws.String(contentType),
+	}); err != nil {
+		if body.err != nil {
+			return nil, body.err
+		}
+
+		return nil, errors.Wrap(err, "failed to upload object")
+	}
+
+	checksum := hex.EncodeToString(body.hash.Sum(nil))
+	obj, err := u.Stat(ctx, key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Multipart uploads and some encryption modes don't use the MD5 as the
+	// ETag, the checksum gets attached as metadata by copying the object.
+	if obj.Checksum != checksum {
+		if _, err := u.client.CopyObjectWithContext(ctx, &s3api.CopyObjectInput{
+			Bucket:            aws.String(u.bucket()),
+			Key:               aws.String(u.key(key)),
+			CopySource:        aws.String(url.PathEscape(u.bucket()) + "/" + escape(u.key(key))),
+			ContentType:       aws.String(contentType),
+			MetadataDirective: aws.String(s3api.MetadataDirectiveReplace),
+			Metadata: map[string]*string{
+				checksumMeta: aws.String(checksum),
+			},
+		}); err != nil {
+			return nil, errors.Wrap(err, "failed to store checksum")
+		}
+
+		if obj, err = u.Stat(ctx, key); err != nil {
+			return nil, err
+		}
+	}
+
+	return obj, nil
+}
+
+// Get implements the upload.Upload interface.
+func (u *s3) Get(ctx context.Context, key string) (io.ReadCloser, *upload.Object, error) {
+	if !upload.ValidKey(key) {
+		return nil, nil, upload.ErrInvalidKey
+	}
+
+	resp, err := u.client.GetObjectWithContext(ctx, &s3api.GetObjectInput{
+		Bucket: aws.String(u.bucket()),
+		Key:    aws.String(u.key(key)),
+	})
+
+	if err != nil {
+		if notFound(err) {
+			return nil, nil, upload.ErrNotFound
+		}
+
+		return nil, nil, err
+	}
+
+	return resp.Body, object(
+		key,
+		resp.ContentLength,
+		resp.ContentType,
+		resp.ETag,
+		resp.Metadata,
+		resp.LastModified,
+	), nil
+}
+
+// Stat implements the upload.Upload interface.
+func (u *s3) Stat(ctx context.Context, key string) (*upload.Object, error) {
+	if !upload.ValidKey(key) {
+		return nil, upload.ErrInvalidKey
+	}
+
+	resp, err := u.client.HeadObjectWithContext(ctx, &s3api.HeadObjectInput{
+		Bucket: aws.String(u.bucket()),
+		Key:    aws.String(u.key(key)),
+	})
+
+	if err != nil {
+		if notFound(err) {
+			return nil, upload.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return object(
+		key,
+		resp.ContentLength,
+		resp.ContentType,
+		resp.ETag,
+		resp.Metadata,
+		resp.LastModified,
+	), nil
+}
+
+// Delete implements the upload.Upload interface, the bucket doesn't report
+// missing objects so it gets checked before.
+func (u *s3) Delete(ctx context.Context, key string) error {
+	if _, err := u.Stat(ctx, key); err != nil {
+		return err
+	}
+
+	if _, err := u.client.DeleteObjectWithContext(ctx, &s3api.DeleteObjectInput{
+		Bucket: aws.String(u.bucket()),
+		Key:    aws.String(u.key(key)),
+	}); err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// List implements the upload.Upload interface, the metadata of every object
+// gets fetched as the listing doesn't include it.
+func (u *s3) List(ctx context.Context, prefix string) ([]*upload.Object, error) {
+	keys := make([]string, 0)
+	base := u.key("")
+
+	if err := u.client.ListObjectsV2PagesWithContext(ctx, &s3api.ListObjectsV2Input{
+		Bucket: aws.String(u.bucket()),
+		Prefix: aws.String(base + prefix),
+	}, func(page *s3api.ListObjectsV2Output, last bool) bool {
+		for _, record := range page.Contents {
+			key := strings.TrimPrefix(aws.StringValue(record.Key), base)
+
+			if upload.ValidKey(key) {
+				keys = append(keys, key)
+			}
+		}
+
+		return true
+	}); err != nil {
+		return nil, errors.Wrap(err, "failed to list objects")
+	}
+
+	sort.Strings(keys)
+	records := make([]*upload.Object, 0, len(keys))
+
+	for _, key := range keys {
+		record, err := u.Stat(ctx, key)
+
+		if err == upload.ErrNotFound {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// proxy streams the object through the server, only the requested range
+// of the content gets fetched from the bucket.
+func (u *s3) proxy(w http.ResponseWriter, r *http.Request, key string) {
+	obj, err := u.Stat(r.Context(), key)
+
+	if err == upload.ErrNotFound {
+		http.NotFound(w, r)
+		return
+	}
+
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	content := &seeker{
+		ctx:    r.Context(),
+		client: u.client,
+		bucket: u.bucket(),
+		key:    u.key(key),
+		size:   obj.Size,
+	}
+
+	defer content.Close()
+	upload.Serve(w, r, obj, content)
+}
+
+// presign redirects the client to a presigned URL of the object, the bucket
+// handles range and conditional requests. Content headers get passed to the
+// bucket while the redirect itself must not be cached as it expires.
+func (u *s3) presign(w http.ResponseWriter, r *http.Request, key string) {
+	obj, err := u.Stat(r.Context(), key)
+
+	if err != nil {
+		if err == upload.ErrNotFound {
+			http.NotFound(w, r)
+			return
+		}
+
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	var (
+		req         *request.Request
+		contentType = w.Header().Get("Content-Type")
+		disposition = w.Header().Get("Content-Disposition")
+	)
+
+	if contentType == "" {
+		contentType = upload.ContentType(key, obj.ContentType)
+	}
+
+	if disposition == "" {
+		disposition = upload.Disposition(key, contentType)
+	}
+
+	if r.Method == http.MethodHead {
+		req, _ = u.client.HeadObjectRequest(&s3api.HeadObjectInput{
+			Bucket: aws.String(u.bucket()),
+			Key:    aws.String(u.key(key)),
+		})
+	} else {
+		input := &s3api.GetObjectInput{
+			Bucket:                     aws.String(u.bucket()),
+			Key:                        aws.String(u.key(key)),
+			ResponseContentType:        aws.String(contentType),
+			ResponseContentDisposition: aws.String(disposition),
+		}
+
+		if val := w.Header().Get("Cache-Control"); val != "" {
+			input.ResponseCacheControl = aws.String(val)
+		}
+
+		req, _ = u.client.GetObjectRequest(input)
+	}
+
+	location, err := req.Presign(u.expiry())
+
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Del("Content-Type")
+	w.Header().Del("Content-Disposition")
+	w.Header().Set("Cache-Control", "no-store")
+
+	http.Redirect(w, r, location, http.StatusTemporaryRedirect)
+}
+
+// endpoint retrieves the endpoint from dsn, the default AWS endpoint of the
+// region gets used if it's empty.
+func (u *s3) endpoint() string {
+	return u.dsn.Host
+}
+
+// bucket retrieves the bucket from the first segment of the dsn path.
+func (u *s3) bucket() string {
+	return strings.SplitN(strings.TrimPrefix(u.dsn.Path, "/"), "/", 2)[0]
+}
+
+// key prepends the prefix defined by the remaining segments of the dsn path.
+func (u *s3) key(key string) string {
+	parts := strings.SplitN(strings.Trim(u.dsn.Path, "/"), "/", 2)
+
+	if len(parts) < 2 || parts[1] == "" {
+		return key
+	}
+
+	return path.Clean(parts[1]) + "/" + key
+}
+
+// region retrieves the region from dsn or fallback.
+func (u *s3) region() string {
+	if val := u.dsn.Query().Get("region"); val != "" {
+		return val
+	}
+
+	return "us-east-1"
+}
+
+// pathStyle retrieves the path style option from dsn or fallback, MinIO
+// defaults to path style while S3 defaults to virtual hosted buckets.
+func (u *s3) pathStyle() bool {
+	if val := u.dsn.Query().Get("path_style"); val != "" {
+		b, err := strconv.ParseBool(val)
+
+		if err != nil {
+			return u.dsn.Scheme == "minio"
+		}
+
+		return b
+	}
+
+	return u.dsn.Scheme == "minio"
+}
+
+// ssl retrieves the ssl option from dsn or fallback.
+func (u *s3) ssl() bool {
+	if val := u.dsn.Query().Get("ssl"); val != "" {
+		b, err := strconv.ParseBool(val)
+
+		if err != nil {
+			return true
+		}
+
+		return b
+	}
+
+	return true
+}
+
+// redirect retrieves the redirect option from dsn or fallback.
+func (u *s3) redirect() bool {
+	if val := u.dsn.Query().Get("redirect"); val != "" {
+		b, err := strconv.ParseBool(val)
+
+		if err != nil {
+			return false
+		}
+
+		return b
+	}
+
+	return false
+}
+
+// expiry retrieves the lifetime of presigned URLs from dsn or fallback.
+func (u *s3) expiry() time.Duration {
+	if val := u.dsn.Query().Get("expiry"); val != "" {
+		d, err := time.ParseDuration(val)
+
+		if err != nil {
+			return 15 * time.Minute
+		}
+
+		return d
+	}
+
+	return 15 * time.Minute
+}
+
 // New initializes a new S3 handler.
 func New(dsn *url.URL) (upload.Upload, error) {
 	f := &s3{
@@ -50,3 +515,154 @@ func Must(dsn *url.URL) upload.Upload {
 
 	return db
 }
+
+// object converts the response headers into an object, the checksum is
+// taken from the metadata or the ETag if it's a plain MD5.
+func object(key string, size *int64, contentType, etag *string, meta map[string]*string, modified *time.Time) *upload.Object {
+	checksum := ""
+
+	for name, val := range meta {
+		if strings.EqualFold(name, checksumMeta) {
+			checksum = aws.StringValue(val)
+		}
+	}
+
+	if checksum == "" {
+		if val := strings.Trim(aws.StringValue(etag), `"`); len(val) == md5.Size*2 {
+			checksum = val
+		}
+	}
+
+	return &upload.Object{
+		Key:         key,
+		Size:        aws.Int64Value(size),
+		ContentType: aws.StringValue(contentType),
+		Checksum:    strings.ToLower(checksum),
+		ModifiedAt:  aws.TimeValue(modified).UTC(),
+	}
+}
+
+// notFound checks if the error reports a missing bucket or object.
+func notFound(err error) bool {
+	if aerr, ok := err.(awserr.RequestFailure); ok {
+		return aerr.StatusCode() == http.StatusNotFound
+	}
+
+	if aerr, ok := err.(awserr.Error); ok {
+		switch aerr.Code() {
+		case s3api.ErrCodeNoSuchKey, s3api.ErrCodeNoSuchBucket, "NotFound":
+			return true
+		}
+	}
+
+	return false
+}
+
+// escape encodes all segments of a key for the copy source.
+func escape(key string) string {
+	parts := strings.Split(key, "/")
+
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+
+	return strings.Join(parts, "/")
+}
+
+// reader hashes the content and fails if it doesn't match the size, this
+// aborts the upload before the object gets replaced.
+type reader struct {
+	r    io.Reader
+	size int64
+	read int64
+	hash hash.Hash
+	err  error
+}
+
+// Read implements the io.Reader interface.
+func (r *reader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.read += int64(n)
+	r.hash.Write(p[:n])
+
+	if r.size >= 0 && (r.read > r.size || (err == io.EOF && r.read != r.size)) {
+		r.err = upload.ErrSizeMismatch
+		return n, r.err
+	}
+
+	return n, err
+}
+
+// seeker reads an object by ranges, the content gets requested from the
+// current offset on the first read after seeking.
+type seeker struct {
+	ctx    context.Context
+	client *s3api.S3
+	bucket string
+	key    string
+	size   int64
+	offset int64
+	body   io.ReadCloser
+}
+
+// Read implements the io.Reader interface.
+func (s *seeker) Read(p []byte) (int, error) {
+	if s.offset >= s.size {
+		return 0, io.EOF
+	}
+
+	if s.body == nil {
+		resp, err := s.client.GetObjectWithContext(s.ctx, &s3api.GetObjectInput{
+			Bucket: aws.String(s.bucket),
+			Key:    aws.String(s.key),
+			Range:  aws.String(fmt.Sprintf("bytes=%d-", s.offset)),
+		})
+
+		if err != nil {
+			return 0, err
+		}
+
+		s.body = resp.Body
+	}
+
+	n, err := s.body.Read(p)
+	s.offset += int64(n)
+
+	return n, err
+}
+
+// Seek implements the io.Seeker interface.
+func (s *seeker) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += s.offset
+	case io.SeekEnd:
+		offset += s.size
+	default:
+		return 0, errors.New("invalid whence")
+	}
+
+	if offset < 0 {
+		return 0, errors.New("negative position")
+	}
+
+	if offset != s.offset {
+		s.Close()
+		s.offset = offset
+	}
+
+	return offset, nil
+}
+
+// Close implements the io.Closer interface.
+func (s *seeker) Close() error {
+	if s.body == nil {
+		return nil
+	}
+
+	err := s.body.Close()
+	s.body = nil
+
+	return err
+}
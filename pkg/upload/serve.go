@@ -0,0 +1,72 @@
+package upload
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// archiveTypes defines the content types of archives which are missing in
+// the mime types of most systems.
+var archiveTypes = map[string]string{
+	".jar": "application/java-archive",
+	".zip": "application/zip",
+}
+
+// Serve writes the content of an object to the response. The ETag derives
+// from the checksum, this way conditional and range requests are handled
+// consistently by all drivers. Content type and disposition are only set if
+// they haven't been set before, e.g. by a wrapping driver.
+func Serve(w http.ResponseWriter, r *http.Request, obj *Object, content io.ReadSeeker) {
+	if obj.Checksum != "" {
+		w.Header().Set("ETag", `"`+obj.Checksum+`"`)
+	}
+
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", ContentType(obj.Key, obj.ContentType))
+	}
+
+	if w.Header().Get("Content-Disposition") == "" {
+		w.Header().Set("Content-Disposition", Disposition(obj.Key, w.Header().Get("Content-Type")))
+	}
+
+	http.ServeContent(w, r, path.Base(obj.Key), obj.ModifiedAt, content)
+}
+
+// ContentType returns the stored content type or detects it by the
+// extension of the key if the stored one is missing or generic.
+func ContentType(key, contentType string) string {
+	if contentType != "" && contentType != "application/octet-stream" {
+		return contentType
+	}
+
+	ext := strings.ToLower(path.Ext(key))
+
+	if val, ok := archiveTypes[ext]; ok {
+		return val
+	}
+
+	if val := mime.TypeByExtension(ext); val != "" {
+		return val
+	}
+
+	return "application/octet-stream"
+}
+
+// Disposition builds the content disposition of a key, images are shown
+// inline while everything else gets downloaded with the name of the key.
+func Disposition(key, contentType string) string {
+	disposition := "attachment"
+
+	if strings.HasPrefix(contentType, "image/") {
+		disposition = "inline"
+	}
+
+	if val := mime.FormatMediaType(disposition, map[string]string{"filename": path.Base(key)}); val != "" {
+		return val
+	}
+
+	return disposition
+}
@@ -0,0 +1,363 @@
+package sftp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kleister/kleister-api/pkg/upload"
+	"github.com/kleister/kleister-api/pkg/upload/remote"
+	"github.com/pkg/errors"
+	sftpapi "github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// conn defines the underlying connection of the client, waiting returns
+// as soon as the connection is lost.
+type conn interface {
+	io.Closer
+	Wait() error
+}
+
+type sftp struct {
+	dsn    *url.URL
+	dial   func(ctx context.Context) (*sftpapi.Client, conn, error)
+	mutex  sync.Mutex
+	client *sftpapi.Client
+	conn   conn
+}
+
+// Info prepares some informational message about the handler.
+func (u *sftp) Info() string {
+	return fmt.Sprintf("prepared sftp storage at %s%s", u.address(), u.root())
+}
+
+// Connect establishes the connection and creates the root if it's missing.
+func (u *sftp) Connect(ctx context.Context) error {
+	client, err := u.connect(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	if err := client.MkdirAll(u.root()); err != nil {
+		return errors.Wrap(err, "failed to create root")
+	}
+
+	return nil
+}
+
+// Close closes the connection.
+func (u *sftp) Close() error {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	if u.client == nil {
+		return nil
+	}
+
+	u.client.Close()
+	err := u.conn.Close()
+
+	u.client = nil
+	u.conn = nil
+
+	return err
+}
+
+// Stat implements the remote.Filesystem interface.
+func (u *sftp) Stat(ctx context.Context, name string) (os.FileInfo, error) {
+	client, err := u.connect(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return client.Stat(u.path(name))
+}
+
+// ReadDir implements the remote.Filesystem interface.
+func (u *sftp) ReadDir(ctx context.Context, name string) ([]os.FileInfo, error) {
+	client, err := u.connect(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return client.ReadDir(u.path(name))
+}
+
+// Open implements the remote.Filesystem interface.
+func (u *sftp) Open(ctx context.Context, name string, size int64) (remote.File, error) {
+	client, err := u.connect(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return client.Open(u.path(name))
+}
+
+// Write implements the remote.Filesystem interface.
+func (u *sftp) Write(ctx context.Context, name string, r io.Reader, size int64) error {
+	client, err := u.connect(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	f, err := client.Create(u.path(name))
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// Rename implements the remote.Filesystem interface, servers without the
+// POSIX rename extension can't replace files, so the target gets removed
+// before.
+func (u *sftp) Rename(ctx context.Context, oldname, newname string) error {
+	client, err := u.connect(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	if err := client.PosixRename(u.path(oldname), u.path(newname)); err == nil {
+		return nil
+	}
+
+	if err := client.Remove(u.path(newname)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return client.Rename(u.path(oldname), u.path(newname))
+}
+
+// Remove implements the remote.Filesystem interface.
+func (u *sftp) Remove(ctx context.Context, name string) error {
+	client, err := u.connect(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	return client.Remove(u.path(name))
+}
+
+// Mkdir implements the remote.Filesystem interface.
+func (u *sftp) Mkdir(ctx context.Context, name string) error {
+	client, err := u.connect(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	return client.MkdirAll(u.path(name))
+}
+
+// connect returns the current client, the connection gets established
+// again if it has been lost.
+func (u *sftp) connect(ctx context.Context) (*sftpapi.Client, error) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	if u.client != nil {
+		return u.client, nil
+	}
+
+	client, conn, err := u.dial(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	u.client = client
+	u.conn = conn
+
+	go func() {
+		conn.Wait()
+
+		u.mutex.Lock()
+		defer u.mutex.Unlock()
+
+		if u.client == client {
+			u.client.Close()
+			u.client = nil
+			u.conn = nil
+		}
+	}()
+
+	return client, nil
+}
+
+// ssh dials the server by SSH and starts the SFTP subsystem.
+func (u *sftp) ssh(ctx context.Context) (*sftpapi.Client, conn, error) {
+	cfg, err := u.config()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dialer := &net.Dialer{}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		dialer.Deadline = deadline
+	}
+
+	raw, err := dialer.Dial("tcp", u.address())
+
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to connect")
+	}
+
+	sshconn, chans, reqs, err := ssh.NewClientConn(raw, u.address(), cfg)
+
+	if err != nil {
+		raw.Close()
+		return nil, nil, errors.Wrap(err, "failed to establish ssh connection")
+	}
+
+	sshclient := ssh.NewClient(sshconn, chans, reqs)
+	client, err := sftpapi.NewClient(sshclient)
+
+	if err != nil {
+		sshclient.Close()
+		return nil, nil, errors.Wrap(err, "failed to start sftp")
+	}
+
+	return client, sshclient, nil
+}
+
+// config builds the SSH configuration, it authenticates by the password or
+// the key file of the dsn and verifies the host by the known hosts.
+func (u *sftp) config() (*ssh.ClientConfig, error) {
+	cfg := &ssh.ClientConfig{
+		Timeout: 10 * time.Second,
+	}
+
+	if u.dsn.User != nil {
+		cfg.User = u.dsn.User.Username()
+
+		if password, ok := u.dsn.User.Password(); ok {
+			cfg.Auth = append(cfg.Auth, ssh.Password(password))
+		}
+	}
+
+	if val := u.dsn.Query().Get("key"); val != "" {
+		content, err := ioutil.ReadFile(val)
+
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read key")
+		}
+
+		signer, err := ssh.ParsePrivateKey(content)
+
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse key")
+		}
+
+		cfg.Auth = append(cfg.Auth, ssh.PublicKeys(signer))
+	}
+
+	if u.insecure() {
+		cfg.HostKeyCallback = ssh.InsecureIgnoreHostKey()
+		return cfg, nil
+	}
+
+	callback, err := knownhosts.New(u.knownHosts())
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load known hosts")
+	}
+
+	cfg.HostKeyCallback = callback
+	return cfg, nil
+}
+
+// address retrieves the host and port from the dsn.
+func (u *sftp) address() string {
+	if u.dsn.Port() == "" {
+		return net.JoinHostPort(u.dsn.Hostname(), "22")
+	}
+
+	return u.dsn.Host
+}
+
+// root retrieves the root directory from the dsn, it's relative to the
+// home directory if the dsn doesn't define any path.
+func (u *sftp) root() string {
+	if u.dsn.Path == "" {
+		return "."
+	}
+
+	return path.Clean(u.dsn.Path)
+}
+
+// path returns the path of a file below the root.
+func (u *sftp) path(name string) string {
+	return path.Join(u.root(), name)
+}
+
+// knownHosts retrieves the known hosts file from dsn or fallback.
+func (u *sftp) knownHosts() string {
+	if val := u.dsn.Query().Get("known_hosts"); val != "" {
+		return val
+	}
+
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".ssh", "known_hosts")
+}
+
+// insecure retrieves the insecure option from dsn or fallback.
+func (u *sftp) insecure() bool {
+	if val := u.dsn.Query().Get("insecure"); val != "" {
+		b, err := strconv.ParseBool(val)
+
+		if err != nil {
+			return false
+		}
+
+		return b
+	}
+
+	return false
+}
+
+// New initializes a new sftp handler.
+func New(dsn *url.URL) (upload.Upload, error) {
+	u := &sftp{
+		dsn: dsn,
+	}
+
+	u.dial = u.ssh
+	return remote.New(u).Prepare()
+}
+
+// Must simply calls New and panics on an error.
+func Must(dsn *url.URL) upload.Upload {
+	db, err := New(dsn)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return db
+}
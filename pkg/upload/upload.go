@@ -1,7 +1,12 @@
 package upload
 
 import (
+	"context"
+	"io"
 	"net/http"
+	"path"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -9,12 +14,65 @@ import (
 var (
 	// ErrUnknownDriver defines a named error for unknown upload drivers.
 	ErrUnknownDriver = errors.New("unknown upload driver")
+
+	// ErrNotFound defines a named error if an object could not be found.
+	ErrNotFound = errors.New("object not found")
+
+	// ErrInvalidKey defines a named error for keys which can't be stored.
+	ErrInvalidKey = errors.New("invalid object key")
+
+	// ErrSizeMismatch defines a named error if the content doesn't match
+	// the announced size.
+	ErrSizeMismatch = errors.New("object size mismatch")
 )
 
+// Object describes a stored object, the checksum is the hex encoded MD5 of
+// the content.
+type Object struct {
+	Key         string
+	Size        int64
+	ContentType string
+	Checksum    string
+	ModifiedAt  time.Time
+}
+
 // Upload provides the interface for the upload implementations.
 type Upload interface {
 	Info() string
 	Prepare() (Upload, error)
 	Close() error
 	Handler(string) http.Handler
+
+	// Put stores the content of the reader, the size is verified unless it
+	// is negative. An existing object gets replaced.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (*Object, error)
+
+	// Get opens the content of an object, the reader has to be closed.
+	Get(ctx context.Context, key string) (io.ReadCloser, *Object, error)
+
+	// Stat returns the metadata of an object.
+	Stat(ctx context.Context, key string) (*Object, error)
+
+	// Delete removes an object.
+	Delete(ctx context.Context, key string) error
+
+	// List returns all objects with keys starting with the prefix, ordered
+	// by key.
+	List(ctx context.Context, prefix string) ([]*Object, error)
+}
+
+// ValidKey checks if the key is a clean relative path, segments starting
+// with a dot are reserved for the drivers.
+func ValidKey(key string) bool {
+	if key == "" || path.Clean(key) != key || strings.HasPrefix(key, "/") {
+		return false
+	}
+
+	for _, part := range strings.Split(key, "/") {
+		if strings.HasPrefix(part, ".") {
+			return false
+		}
+	}
+
+	return true
 }
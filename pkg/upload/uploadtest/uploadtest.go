@@ -0,0 +1,363 @@
+// Package uploadtest provides a conformance suite which gets executed by the
+// tests of every upload driver to keep their behaviour aligned.
+package uploadtest
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kleister/kleister-api/pkg/upload"
+)
+
+// Factory opens a new and empty upload for a single test.
+type Factory func(t *testing.T) upload.Upload
+
+// Run executes the conformance suite against the uploads of the factory.
+func Run(t *testing.T, factory Factory) {
+	tests := []struct {
+		name string
+		fn   func(*testing.T, upload.Upload)
+	}{
+		{"Objects", testObjects},
+		{"Replace", testReplace},
+		{"List", testList},
+		{"Keys", testKeys},
+		{"Size", testSize},
+		{"Aborted", testAborted},
+		{"Handler", testHandler},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			u := factory(t)
+			defer u.Close()
+
+			test.fn(t, u)
+		})
+	}
+}
+
+// Reset removes all objects, this way drivers backed by a persistent
+// storage start empty.
+func Reset(t *testing.T, u upload.Upload) {
+	t.Helper()
+	ctx := context.Background()
+
+	records, err := u.List(ctx, "")
+
+	if err != nil {
+		t.Fatalf("failed to list objects: %v", err)
+	}
+
+	for _, record := range records {
+		if err := u.Delete(ctx, record.Key); err != nil {
+			t.Fatalf("failed to delete %s: %v", record.Key, err)
+		}
+	}
+}
+
+func testObjects(t *testing.T, u upload.Upload) {
+	ctx := context.Background()
+	content := []byte("hello world")
+
+	created, err := u.Put(ctx, "mods/foo/foo-1.0.zip", bytes.NewReader(content), int64(len(content)), "application/zip")
+	must(t, err)
+
+	if created.Key != "mods/foo/foo-1.0.zip" || created.Size != int64(len(content)) {
+		t.Fatalf("unexpected object %+v", created)
+	}
+
+	if created.Checksum != checksum(content) {
+		t.Fatalf("expected checksum %s, got %s", checksum(content), created.Checksum)
+	}
+
+	if created.ContentType != "application/zip" {
+		t.Fatalf("expected content type application/zip, got %s", created.ContentType)
+	}
+
+	if created.ModifiedAt.IsZero() {
+		t.Fatalf("expected modified time")
+	}
+
+	stat, err := u.Stat(ctx, "mods/foo/foo-1.0.zip")
+	must(t, err)
+
+	if stat.Size != created.Size || stat.Checksum != created.Checksum || stat.ContentType != created.ContentType {
+		t.Fatalf("expected %+v, got %+v", created, stat)
+	}
+
+	r, obj, err := u.Get(ctx, "mods/foo/foo-1.0.zip")
+	must(t, err)
+
+	read, err := ioutil.ReadAll(r)
+	r.Close()
+	must(t, err)
+
+	if !bytes.Equal(read, content) {
+		t.Fatalf("expected content %q, got %q", content, read)
+	}
+
+	if obj.Checksum != created.Checksum {
+		t.Fatalf("expected checksum %s, got %s", created.Checksum, obj.Checksum)
+	}
+
+	must(t, u.Delete(ctx, "mods/foo/foo-1.0.zip"))
+
+	if _, err := u.Stat(ctx, "mods/foo/foo-1.0.zip"); err != upload.ErrNotFound {
+		t.Fatalf("expected not found after delete, got %v", err)
+	}
+
+	if _, _, err := u.Get(ctx, "mods/foo/foo-1.0.zip"); err != upload.ErrNotFound {
+		t.Fatalf("expected not found after delete, got %v", err)
+	}
+
+	if err := u.Delete(ctx, "mods/foo/foo-1.0.zip"); err != upload.ErrNotFound {
+		t.Fatalf("expected not found for second delete, got %v", err)
+	}
+}
+
+func testReplace(t *testing.T, u upload.Upload) {
+	ctx := context.Background()
+
+	_, err := u.Put(ctx, "packs/foo.png", strings.NewReader("first"), -1, "")
+	must(t, err)
+
+	replaced, err := u.Put(ctx, "packs/foo.png", strings.NewReader("second"), -1, "image/png")
+	must(t, err)
+
+	if replaced.Size != 6 || replaced.Checksum != checksum([]byte("second")) {
+		t.Fatalf("unexpected replaced object %+v", replaced)
+	}
+
+	r, _, err := u.Get(ctx, "packs/foo.png")
+	must(t, err)
+
+	read, err := ioutil.ReadAll(r)
+	r.Close()
+	must(t, err)
+
+	if string(read) != "second" {
+		t.Fatalf("expected replaced content, got %q", read)
+	}
+}
+
+func testList(t *testing.T, u upload.Upload) {
+	ctx := context.Background()
+
+	for _, key := range []string{"mods/b/b-1.zip", "mods/a/a-1.zip", "mods/a/a-2.zip", "packs/a.png"} {
+		_, err := u.Put(ctx, key, strings.NewReader(key), -1, "")
+		must(t, err)
+	}
+
+	all, err := u.List(ctx, "")
+	must(t, err)
+
+	if keys(all) != "mods/a/a-1.zip,mods/a/a-2.zip,mods/b/b-1.zip,packs/a.png" {
+		t.Fatalf("unexpected list %s", keys(all))
+	}
+
+	mods, err := u.List(ctx, "mods/a/")
+	must(t, err)
+
+	if keys(mods) != "mods/a/a-1.zip,mods/a/a-2.zip" {
+		t.Fatalf("unexpected list for directory prefix %s", keys(mods))
+	}
+
+	partial, err := u.List(ctx, "mods/a/a-2")
+	must(t, err)
+
+	if keys(partial) != "mods/a/a-2.zip" {
+		t.Fatalf("unexpected list for partial prefix %s", keys(partial))
+	}
+
+	if partial[0].Checksum != checksum([]byte("mods/a/a-2.zip")) {
+		t.Fatalf("expected list to include checksums")
+	}
+
+	missing, err := u.List(ctx, "missing/")
+	must(t, err)
+
+	if len(missing) != 0 {
+		t.Fatalf("expected empty list, got %s", keys(missing))
+	}
+}
+
+func testKeys(t *testing.T, u upload.Upload) {
+	ctx := context.Background()
+
+	for _, key := range []string{"", "/mods/foo.zip", "mods/../foo.zip", "mods//foo.zip", ".meta/foo.zip", "mods/.foo.zip"} {
+		if _, err := u.Put(ctx, key, strings.NewReader("x"), -1, ""); err != upload.ErrInvalidKey {
+			t.Fatalf("expected invalid key for %q, got %v", key, err)
+		}
+
+		if _, err := u.Stat(ctx, key); err != upload.ErrInvalidKey {
+			t.Fatalf("expected invalid key for %q, got %v", key, err)
+		}
+	}
+}
+
+func testSize(t *testing.T, u upload.Upload) {
+	ctx := context.Background()
+
+	_, err := u.Put(ctx, "mods/foo.zip", strings.NewReader("original"), -1, "")
+	must(t, err)
+
+	if _, err := u.Put(ctx, "mods/foo.zip", strings.NewReader("short"), 10, ""); err != upload.ErrSizeMismatch {
+		t.Fatalf("expected size mismatch for short content, got %v", err)
+	}
+
+	if _, err := u.Put(ctx, "mods/foo.zip", strings.NewReader("too long content"), 3, ""); err != upload.ErrSizeMismatch {
+		t.Fatalf("expected size mismatch for long content, got %v", err)
+	}
+
+	obj, err := u.Stat(ctx, "mods/foo.zip")
+	must(t, err)
+
+	if obj.Checksum != checksum([]byte("original")) {
+		t.Fatalf("expected failed writes to keep the object")
+	}
+
+	if _, err := u.Put(ctx, "mods/bar.zip", strings.NewReader("short"), 10, ""); err != upload.ErrSizeMismatch {
+		t.Fatalf("expected size mismatch, got %v", err)
+	}
+
+	if _, err := u.Stat(ctx, "mods/bar.zip"); err != upload.ErrNotFound {
+		t.Fatalf("expected failed write to leave no object, got %v", err)
+	}
+}
+
+func testAborted(t *testing.T, u upload.Upload) {
+	ctx := context.Background()
+
+	if _, err := u.Put(ctx, "mods/foo.zip", io.MultiReader(
+		strings.NewReader("partial"),
+		&failing{},
+	), -1, ""); err == nil {
+		t.Fatalf("expected failing reader to abort the write")
+	}
+
+	if _, err := u.Stat(ctx, "mods/foo.zip"); err != upload.ErrNotFound {
+		t.Fatalf("expected aborted write to leave no object, got %v", err)
+	}
+
+	records, err := u.List(ctx, "")
+	must(t, err)
+
+	if len(records) != 0 {
+		t.Fatalf("expected aborted write to leave nothing, got %s", keys(records))
+	}
+}
+
+func testHandler(t *testing.T, u upload.Upload) {
+	ctx := context.Background()
+	content := []byte("hello world")
+
+	created, err := u.Put(ctx, "handler/foo.jar", bytes.NewReader(content), int64(len(content)), "")
+	must(t, err)
+
+	handler := u.Handler("/storage")
+	etag := `"` + created.Checksum + `"`
+
+	tests := []struct {
+		name    string
+		method  string
+		target  string
+		headers map[string]string
+		status  int
+		body    string
+	}{
+		{"full", http.MethodGet, "/storage/handler/foo.jar", nil, http.StatusOK, "hello world"},
+		{"head", http.MethodHead, "/storage/handler/foo.jar", nil, http.StatusOK, ""},
+		{"range", http.MethodGet, "/storage/handler/foo.jar", map[string]string{"Range": "bytes=6-"}, http.StatusPartialContent, "world"},
+		{"matching", http.MethodGet, "/storage/handler/foo.jar", map[string]string{"If-None-Match": etag}, http.StatusNotModified, ""},
+		{"current", http.MethodGet, "/storage/handler/foo.jar", map[string]string{"Range": "bytes=0-4", "If-Range": etag}, http.StatusPartialContent, "hello"},
+		{"stale", http.MethodGet, "/storage/handler/foo.jar", map[string]string{"Range": "bytes=0-4", "If-Range": `"stale"`}, http.StatusOK, "hello world"},
+		{"directory", http.MethodGet, "/storage/handler/", nil, http.StatusNotFound, ""},
+		{"missing", http.MethodGet, "/storage/handler/bar.jar", nil, http.StatusNotFound, ""},
+		{"method", http.MethodPost, "/storage/handler/foo.jar", nil, http.StatusMethodNotAllowed, ""},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.target, nil)
+
+		for key, val := range tt.headers {
+			req.Header.Set(key, val)
+		}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Fatalf("%s: expected status %d, got %d", tt.name, tt.status, w.Code)
+		}
+
+		if tt.status >= http.StatusBadRequest {
+			continue
+		}
+
+		if w.Header().Get("ETag") != etag {
+			t.Fatalf("%s: expected etag %s, got %s", tt.name, etag, w.Header().Get("ETag"))
+		}
+
+		if tt.body != "" && w.Body.String() != tt.body {
+			t.Fatalf("%s: expected body %q, got %q", tt.name, tt.body, w.Body.String())
+		}
+
+		if tt.status != http.StatusOK {
+			continue
+		}
+
+		if val, expected := w.Header().Get("Content-Type"), upload.ContentType(created.Key, created.ContentType); val != expected {
+			t.Fatalf("%s: expected content type %s, got %s", tt.name, expected, val)
+		}
+
+		if val := w.Header().Get("Content-Disposition"); val != `attachment; filename=foo.jar` {
+			t.Fatalf("%s: expected attachment, got %s", tt.name, val)
+		}
+	}
+}
+
+// failing is a reader which always fails.
+type failing struct{}
+
+// Read implements the io.Reader interface.
+func (f *failing) Read(p []byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+// checksum calculates the expected checksum of the content.
+func checksum(content []byte) string {
+	sum := md5.Sum(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// keys joins the keys of the objects for simple comparisons.
+func keys(records []*upload.Object) string {
+	result := make([]string, 0, len(records))
+
+	for _, record := range records {
+		result = append(result, record.Key)
+	}
+
+	return strings.Join(result, ",")
+}
+
+// must fails the test on any unexpected error.
+func must(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
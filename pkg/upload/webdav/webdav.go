@@ -0,0 +1,573 @@
+package webdav
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kleister/kleister-api/pkg/upload"
+	"github.com/kleister/kleister-api/pkg/upload/remote"
+	"github.com/pkg/errors"
+)
+
+// propfind requests the properties required to describe files.
+const propfind = `<?xml version="1.0" encoding="utf-8"?>
+<propfind xmlns="DAV:"><prop><resourcetype/><getcontentlength/><getlastmodified/></prop></propfind>`
+
+type webdav struct {
+	dsn    *url.URL
+	client *http.Client
+}
+
+// Info prepares some informational message about the handler.
+func (u *webdav) Info() string {
+	return fmt.Sprintf("prepared webdav storage at %s%s", u.dsn.Host, u.root())
+}
+
+// Connect checks if the root is accessible and creates it if it's missing,
+// the parent of the root has to exist.
+func (u *webdav) Connect(ctx context.Context) error {
+	info, err := u.Stat(ctx, ".")
+
+	if os.IsNotExist(err) {
+		return u.mkcol(ctx, ".")
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "failed to access root")
+	}
+
+	if !info.IsDir() {
+		return errors.Errorf("root %s is no collection", u.root())
+	}
+
+	return nil
+}
+
+// Close simply closes the upload handler.
+func (u *webdav) Close() error {
+	return nil
+}
+
+// Stat implements the remote.Filesystem interface.
+func (u *webdav) Stat(ctx context.Context, name string) (os.FileInfo, error) {
+	entries, err := u.propfind(ctx, name, "0")
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return nil, notExist("stat", name)
+	}
+
+	return entries[0], nil
+}
+
+// ReadDir implements the remote.Filesystem interface.
+func (u *webdav) ReadDir(ctx context.Context, name string) ([]os.FileInfo, error) {
+	entries, err := u.propfind(ctx, name, "1")
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]os.FileInfo, 0, len(entries))
+	self := strings.TrimSuffix(u.location(name).Path, "/")
+
+	for _, entry := range entries {
+		if entry.(*info).path == self {
+			continue
+		}
+
+		result = append(result, entry)
+	}
+
+	return result, nil
+}
+
+// Open implements the remote.Filesystem interface, the content gets
+// requested on the first read.
+func (u *webdav) Open(ctx context.Context, name string, size int64) (remote.File, error) {
+	return &seeker{
+		ctx:    ctx,
+		webdav: u,
+		name:   name,
+		size:   size,
+	}, nil
+}
+
+// Write implements the remote.Filesystem interface.
+func (u *webdav) Write(ctx context.Context, name string, r io.Reader, size int64) error {
+	req, err := u.request(ctx, http.MethodPut, name, r)
+
+	if err != nil {
+		return err
+	}
+
+	if size >= 0 {
+		req.ContentLength = size
+	}
+
+	resp, err := u.client.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+		return nil
+	}
+
+	return status("put", name, resp)
+}
+
+// Rename implements the remote.Filesystem interface.
+func (u *webdav) Rename(ctx context.Context, oldname, newname string) error {
+	req, err := u.request(ctx, "MOVE", oldname, nil)
+
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Destination", u.location(newname).String())
+	req.Header.Set("Overwrite", "T")
+
+	resp, err := u.client.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusCreated, http.StatusNoContent:
+		return nil
+	}
+
+	return status("move", oldname, resp)
+}
+
+// Remove implements the remote.Filesystem interface.
+func (u *webdav) Remove(ctx context.Context, name string) error {
+	req, err := u.request(ctx, http.MethodDelete, name, nil)
+
+	if err != nil {
+		return err
+	}
+
+	resp, err := u.client.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		return nil
+	}
+
+	return status("delete", name, resp)
+}
+
+// Mkdir implements the remote.Filesystem interface, the collections get
+// created one after another below the root.
+func (u *webdav) Mkdir(ctx context.Context, name string) error {
+	name = path.Clean(name)
+
+	if name == "." {
+		return nil
+	}
+
+	if info, err := u.Stat(ctx, name); err == nil && info.IsDir() {
+		return nil
+	}
+
+	current := ""
+
+	for _, part := range strings.Split(name, "/") {
+		current = path.Join(current, part)
+
+		if err := u.mkcol(ctx, current); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mkcol creates a single collection, existing ones are accepted.
+func (u *webdav) mkcol(ctx context.Context, name string) error {
+	req, err := u.request(ctx, "MKCOL", name, nil)
+
+	if err != nil {
+		return err
+	}
+
+	resp, err := u.client.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusCreated, http.StatusMethodNotAllowed:
+		return nil
+	}
+
+	return status("mkcol", name, resp)
+}
+
+// propfind lists the properties of a file or a collection including its
+// members, depending on the depth.
+func (u *webdav) propfind(ctx context.Context, name, depth string) ([]os.FileInfo, error) {
+	req, err := u.request(ctx, "PROPFIND", name, strings.NewReader(propfind))
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Depth", depth)
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+
+	resp, err := u.client.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil, status("propfind", name, resp)
+	}
+
+	result := multistatus{}
+
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, errors.Wrap(err, "failed to parse propfind")
+	}
+
+	entries := make([]os.FileInfo, 0, len(result.Responses))
+
+	for _, response := range result.Responses {
+		entry, err := response.info()
+
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// request prepares a request for the file, the credentials of the dsn get
+// attached as basic auth.
+func (u *webdav) request(ctx context.Context, method, name string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, u.location(name).String(), body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if u.dsn.User != nil {
+		password, _ := u.dsn.User.Password()
+		req.SetBasicAuth(u.dsn.User.Username(), password)
+	}
+
+	return req.WithContext(ctx), nil
+}
+
+// location builds the URL of a file below the root.
+func (u *webdav) location(name string) *url.URL {
+	scheme := "https"
+
+	if !u.ssl() {
+		scheme = "http"
+	}
+
+	return &url.URL{
+		Scheme: scheme,
+		Host:   u.dsn.Host,
+		Path:   path.Join(u.root(), name),
+	}
+}
+
+// root retrieves the root collection from the dsn.
+func (u *webdav) root() string {
+	return path.Join("/", u.dsn.Path)
+}
+
+// ssl retrieves the ssl option from dsn or fallback.
+func (u *webdav) ssl() bool {
+	if val := u.dsn.Query().Get("ssl"); val != "" {
+		b, err := strconv.ParseBool(val)
+
+		if err != nil {
+			return true
+		}
+
+		return b
+	}
+
+	return true
+}
+
+// New initializes a new webdav handler.
+func New(dsn *url.URL) (upload.Upload, error) {
+	return remote.New(&webdav{
+		dsn:    dsn,
+		client: &http.Client{},
+	}).Prepare()
+}
+
+// Must simply calls New and panics on an error.
+func Must(dsn *url.URL) upload.Upload {
+	db, err := New(dsn)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return db
+}
+
+// multistatus defines the response of a propfind.
+type multistatus struct {
+	XMLName   xml.Name   `xml:"DAV: multistatus"`
+	Responses []response `xml:"DAV: response"`
+}
+
+// response defines the properties of a single file.
+type response struct {
+	Href     string `xml:"DAV: href"`
+	Propstat []struct {
+		Status string `xml:"DAV: status"`
+		Prop   struct {
+			ResourceType struct {
+				Collection *struct{} `xml:"DAV: collection"`
+			} `xml:"DAV: resourcetype"`
+			ContentLength string `xml:"DAV: getcontentlength"`
+			LastModified  string `xml:"DAV: getlastmodified"`
+		} `xml:"DAV: prop"`
+	} `xml:"DAV: propstat"`
+}
+
+// info converts the response into a file info, properties which haven't
+// been found get ignored.
+func (r response) info() (os.FileInfo, error) {
+	href, err := url.Parse(r.Href)
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse href")
+	}
+
+	result := &info{
+		path: strings.TrimSuffix(href.Path, "/"),
+	}
+
+	result.name = path.Base(result.path)
+
+	for _, propstat := range r.Propstat {
+		if !strings.Contains(propstat.Status, " 200 ") {
+			continue
+		}
+
+		if propstat.Prop.ResourceType.Collection != nil {
+			result.dir = true
+		}
+
+		if val := propstat.Prop.ContentLength; val != "" {
+			if result.size, err = strconv.ParseInt(val, 10, 64); err != nil {
+				return nil, errors.Wrap(err, "failed to parse content length")
+			}
+		}
+
+		if val := propstat.Prop.LastModified; val != "" {
+			if result.modTime, err = http.ParseTime(val); err != nil {
+				return nil, errors.Wrap(err, "failed to parse last modified")
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// info implements the os.FileInfo interface for the properties.
+type info struct {
+	path    string
+	name    string
+	size    int64
+	modTime time.Time
+	dir     bool
+}
+
+// Name implements the os.FileInfo interface.
+func (i *info) Name() string {
+	return i.name
+}
+
+// Size implements the os.FileInfo interface.
+func (i *info) Size() int64 {
+	return i.size
+}
+
+// Mode implements the os.FileInfo interface.
+func (i *info) Mode() os.FileMode {
+	if i.dir {
+		return os.ModeDir | 0755
+	}
+
+	return 0644
+}
+
+// ModTime implements the os.FileInfo interface.
+func (i *info) ModTime() time.Time {
+	return i.modTime
+}
+
+// IsDir implements the os.FileInfo interface.
+func (i *info) IsDir() bool {
+	return i.dir
+}
+
+// Sys implements the os.FileInfo interface.
+func (i *info) Sys() interface{} {
+	return nil
+}
+
+// seeker reads a file by ranges, the content gets requested from the
+// current offset on the first read after seeking.
+type seeker struct {
+	ctx    context.Context
+	webdav *webdav
+	name   string
+	size   int64
+	offset int64
+	body   io.ReadCloser
+}
+
+// Read implements the io.Reader interface.
+func (s *seeker) Read(p []byte) (int, error) {
+	if s.size >= 0 && s.offset >= s.size {
+		return 0, io.EOF
+	}
+
+	if s.body == nil {
+		req, err := s.webdav.request(s.ctx, http.MethodGet, s.name, nil)
+
+		if err != nil {
+			return 0, err
+		}
+
+		if s.offset > 0 {
+			req.Header.Set("Range", fmt.Sprintf("bytes=%d-", s.offset))
+		}
+
+		resp, err := s.webdav.client.Do(req)
+
+		if err != nil {
+			return 0, err
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusOK && s.offset == 0:
+		case resp.StatusCode == http.StatusPartialContent:
+		default:
+			resp.Body.Close()
+			return 0, status("get", s.name, resp)
+		}
+
+		s.body = resp.Body
+	}
+
+	n, err := s.body.Read(p)
+	s.offset += int64(n)
+
+	return n, err
+}
+
+// Seek implements the io.Seeker interface.
+func (s *seeker) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += s.offset
+	case io.SeekEnd:
+		if s.size < 0 {
+			return 0, errors.New("unknown size")
+		}
+
+		offset += s.size
+	default:
+		return 0, errors.New("invalid whence")
+	}
+
+	if offset < 0 {
+		return 0, errors.New("negative position")
+	}
+
+	if offset != s.offset {
+		s.Close()
+		s.offset = offset
+	}
+
+	return offset, nil
+}
+
+// Close implements the io.Closer interface.
+func (s *seeker) Close() error {
+	if s.body == nil {
+		return nil
+	}
+
+	err := s.body.Close()
+	s.body = nil
+
+	return err
+}
+
+// status converts an unexpected response into an error, missing files get
+// reported as such.
+func status(op, name string, resp *http.Response) error {
+	if resp.StatusCode == http.StatusNotFound {
+		return notExist(op, name)
+	}
+
+	message, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+
+	return &os.PathError{
+		Op:   op,
+		Path: name,
+		Err:  errors.Errorf("unexpected status %s: %s", resp.Status, bytes.TrimSpace(message)),
+	}
+}
+
+// notExist builds an error for missing files.
+func notExist(op, name string) error {
+	return &os.PathError{
+		Op:   op,
+		Path: name,
+		Err:  os.ErrNotExist,
+	}
+}
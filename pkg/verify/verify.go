@@ -0,0 +1,333 @@
+// Package verify re-reads the uploaded files of mod versions and pack images
+// and compares them with the recorded sizes and checksums, this way bit rot
+// or manual changes on the storage get detected.
+package verify
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+
+	"github.com/kleister/kleister-api/pkg/images"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store"
+	"github.com/kleister/kleister-api/pkg/upload"
+	"github.com/kleister/kleister-api/pkg/upload/dedup"
+	"github.com/kleister/kleister-api/pkg/upload/file"
+	"github.com/pkg/errors"
+)
+
+const (
+	// KindVersion marks the file of a mod version.
+	KindVersion = "version"
+
+	// KindImage marks a variant of a pack image.
+	KindImage = "image"
+
+	// StateOK marks a file matching the recorded size and checksums.
+	StateOK = "ok"
+
+	// StateMissing marks a file which doesn't exist on the storage.
+	StateMissing = "missing"
+
+	// StateTruncated marks a file which is shorter than recorded.
+	StateTruncated = "truncated"
+
+	// StateAltered marks a file with a different size or checksum.
+	StateAltered = "altered"
+
+	// StateFailed marks a file which could not be read.
+	StateFailed = "failed"
+)
+
+// Result describes the verification of a single file, the owner names the
+// referencing record by slugs and the position counts the verified files
+// including this one.
+type Result struct {
+	Kind     string
+	Owner    string
+	Key      string
+	Expected int64
+	Size     int64
+	State    string
+	Err      error
+	Marked   bool
+	Position int
+	Total    int
+}
+
+// Damaged checks if the file is missing, truncated or altered.
+func (r *Result) Damaged() bool {
+	switch r.State {
+	case StateMissing, StateTruncated, StateAltered:
+		return true
+	}
+
+	return false
+}
+
+// Report sums up the results of all files.
+type Report struct {
+	Total     int
+	OK        int
+	Missing   int
+	Truncated int
+	Altered   int
+	Failed    int
+	Marked    int
+}
+
+// Damaged returns the number of missing, truncated and altered files.
+func (r *Report) Damaged() int {
+	return r.Missing + r.Truncated + r.Altered
+}
+
+// target defines a referenced file together with the recorded metadata, a
+// negative size or an empty checksum haven't been recorded.
+type target struct {
+	kind    string
+	owner   string
+	version *model.Version
+	key     string
+	size    int64
+	md5     string
+	sha1    string
+	sha256  string
+}
+
+// Verify reads every file referenced by the store from the plain uploads,
+// the deduplicated content gets resolved by the links. If mark is set the
+// versions of damaged files get flagged as broken while the flag gets
+// removed from versions which have been verified successfully. The progress
+// function gets called for every file.
+func Verify(ctx context.Context, s store.Store, uploads upload.Upload, mark bool, progress func(*Result)) (*Report, error) {
+	var targets []*target
+
+	if err := s.Snapshot(ctx, func(tx store.Repositories) error {
+		var err error
+		targets, err = references(ctx, tx)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	var (
+		content = dedup.New(uploads, s)
+		report  = &Report{Total: len(targets)}
+	)
+
+	for i, t := range targets {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		result := check(ctx, content, t)
+		result.Position = i + 1
+		result.Total = len(targets)
+
+		switch result.State {
+		case StateOK:
+			report.OK++
+		case StateMissing:
+			report.Missing++
+		case StateTruncated:
+			report.Truncated++
+		case StateAltered:
+			report.Altered++
+		default:
+			report.Failed++
+		}
+
+		if mark && t.version != nil && result.State != StateFailed {
+			marked, err := flag(ctx, s, t.version, result.Damaged())
+
+			if err != nil {
+				return report, errors.Wrapf(err, "failed to mark %s", t.owner)
+			}
+
+			if marked {
+				result.Marked = true
+				report.Marked++
+			}
+		}
+
+		if progress != nil {
+			progress(result)
+		}
+	}
+
+	return report, nil
+}
+
+// check reads the whole file while all checksums are calculated.
+func check(ctx context.Context, uploads upload.Upload, t *target) *Result {
+	result := &Result{
+		Kind:     t.kind,
+		Owner:    t.owner,
+		Key:      t.key,
+		Expected: t.size,
+	}
+
+	r, _, err := uploads.Get(ctx, t.key)
+
+	if err != nil {
+		result.State, result.Err = failure(err)
+		return result
+	}
+
+	defer r.Close()
+
+	var (
+		md5sum    = md5.New()
+		sha1sum   = sha1.New()
+		sha256sum = sha256.New()
+	)
+
+	size, err := io.Copy(io.MultiWriter(md5sum, sha1sum, sha256sum), r)
+	result.Size = size
+
+	if err != nil {
+		result.State, result.Err = failure(err)
+		return result
+	}
+
+	switch {
+	case t.size >= 0 && size < t.size:
+		result.State = StateTruncated
+	case t.size >= 0 && size > t.size,
+		!matches(t.md5, md5sum.Sum(nil)),
+		!matches(t.sha1, sha1sum.Sum(nil)),
+		!matches(t.sha256, sha256sum.Sum(nil)):
+		result.State = StateAltered
+	default:
+		result.State = StateOK
+	}
+
+	return result
+}
+
+// failure maps an error while reading a file to the matching state.
+func failure(err error) (string, error) {
+	switch errors.Cause(err) {
+	case upload.ErrNotFound:
+		return StateMissing, nil
+	case io.ErrUnexpectedEOF:
+		return StateTruncated, err
+	case file.ErrCorruptObject:
+		return StateAltered, err
+	}
+
+	return StateFailed, err
+}
+
+// matches compares the recorded checksum if it has been recorded at all.
+func matches(expected string, sum []byte) bool {
+	return expected == "" || expected == hex.EncodeToString(sum)
+}
+
+// flag updates the broken flag of the version if the file is still the
+// same, it returns true if the flag has been changed.
+func flag(ctx context.Context, s store.Store, version *model.Version, broken bool) (bool, error) {
+	changed := false
+
+	err := s.Transaction(ctx, func(tx store.Repositories) error {
+		record, err := tx.Versions().Show(ctx, version.ModID, version.ID)
+
+		if err == store.ErrNotFound {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if record.FileKey != version.FileKey || record.FileBroken == broken {
+			return nil
+		}
+
+		record.FileBroken = broken
+
+		if _, err := tx.Versions().Update(ctx, record); err != nil {
+			return err
+		}
+
+		changed = true
+		return nil
+	})
+
+	return changed, err
+}
+
+// references collects the files of all records within the store.
+func references(ctx context.Context, s store.Repositories) ([]*target, error) {
+	mods, err := s.Mods().List(ctx)
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list mods")
+	}
+
+	targets := make([]*target, 0)
+
+	for _, mod := range mods {
+		versions, err := s.Versions().List(ctx, mod.ID)
+
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list versions")
+		}
+
+		for _, version := range versions {
+			if version.FileKey == "" {
+				continue
+			}
+
+			targets = append(targets, &target{
+				kind:    KindVersion,
+				owner:   mod.Slug + "/" + version.Slug,
+				version: version,
+				key:     version.FileKey,
+				size:    version.FileSize,
+				md5:     version.FileMD5,
+				sha1:    version.FileSHA1,
+				sha256:  version.FileSHA256,
+			})
+		}
+	}
+
+	packs, err := s.Packs().List(ctx)
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list packs")
+	}
+
+	for _, pack := range packs {
+		for _, kind := range images.Kinds {
+			key, md5 := kind.Get(pack)
+
+			if key == "" {
+				continue
+			}
+
+			// Only the checksum of the launcher variant gets recorded, the
+			// other variants are checked to be readable.
+			for _, variant := range images.Keys(key) {
+				t := &target{
+					kind:  KindImage,
+					owner: pack.Slug + "/" + kind.Name,
+					key:   variant,
+					size:  -1,
+				}
+
+				if variant == key {
+					t.md5 = md5
+				}
+
+				targets = append(targets, t)
+			}
+		}
+	}
+
+	return targets, nil
+}
@@ -0,0 +1,170 @@
+package verify
+
+import (
+	"context"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/kleister/kleister-api/pkg/files"
+	"github.com/kleister/kleister-api/pkg/images"
+	"github.com/kleister/kleister-api/pkg/model"
+	"github.com/kleister/kleister-api/pkg/store/memory"
+	"github.com/kleister/kleister-api/pkg/upload/dedup"
+	"github.com/kleister/kleister-api/pkg/upload/file"
+)
+
+func TestVerify(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kleister-verify")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	raw := file.Must(&url.URL{Scheme: "file", Path: dir})
+	s := memory.Must(&url.URL{Scheme: "memory"})
+	content := dedup.New(raw, s)
+
+	mod, err := s.Mods().Create(ctx, &model.Mod{Name: "Foo"})
+	must(t, err)
+
+	for name, body := range map[string]string{
+		"1.0": "intact",
+		"2.0": "missing",
+		"3.0": "truncated",
+		"4.0": "altered",
+		"5.0": "legacy",
+	} {
+		key := "mods/foo-" + name + ".jar"
+		uploads := content
+
+		if name == "5.0" {
+			uploads = raw
+		}
+
+		digest, err := files.Put(ctx, uploads, key, strings.NewReader(body), -1, "")
+		must(t, err)
+
+		version := &model.Version{ModID: mod.ID, Name: name}
+		files.Assign(version, key, "foo.jar", digest)
+
+		_, err = s.Versions().Create(ctx, version)
+		must(t, err)
+	}
+
+	report, err := Verify(ctx, s, raw, true, nil)
+	must(t, err)
+
+	if report.Total != 5 || report.OK != 5 || report.Marked != 0 {
+		t.Fatalf("expected 5 intact files, got %d of %d", report.OK, report.Total)
+	}
+
+	for key, body := range map[string]string{
+		"mods/foo-2.0.jar": "",
+		"mods/foo-3.0.jar": "trunc",
+		"mods/foo-4.0.jar": "ALTERED",
+	} {
+		if body == "" {
+			must(t, content.Delete(ctx, key))
+			continue
+		}
+
+		link, err := s.Links().Show(ctx, key)
+		must(t, err)
+
+		blob := dedup.Key(link.Digest)
+
+		must(t, raw.Delete(ctx, blob))
+		_, err = raw.Put(ctx, blob, strings.NewReader(body), -1, "")
+		must(t, err)
+	}
+
+	states := make(map[string]string)
+
+	report, err = Verify(ctx, s, raw, true, func(result *Result) {
+		states[result.Owner] = result.State
+	})
+	must(t, err)
+
+	for owner, state := range map[string]string{
+		"foo/1.0": StateOK,
+		"foo/2.0": StateMissing,
+		"foo/3.0": StateTruncated,
+		"foo/4.0": StateAltered,
+		"foo/5.0": StateOK,
+	} {
+		if states[owner] != state {
+			t.Errorf("expected %s to be %s, got %s", owner, state, states[owner])
+		}
+	}
+
+	if report.Damaged() != 3 || report.Marked != 3 {
+		t.Fatalf("expected 3 damaged and marked files, got %d and %d", report.Damaged(), report.Marked)
+	}
+
+	versions, err := s.Versions().List(ctx, mod.ID)
+	must(t, err)
+
+	for _, version := range versions {
+		if expected := states[mod.Slug+"/"+version.Slug] != StateOK; version.FileBroken != expected {
+			t.Errorf("expected %s to be broken %v", version.Name, expected)
+		}
+	}
+
+	report, err = Verify(ctx, s, raw, true, nil)
+	must(t, err)
+
+	if report.Damaged() != 3 || report.Marked != 0 {
+		t.Fatalf("expected broken versions to stay marked, got %d changes", report.Marked)
+	}
+
+	pack, err := s.Packs().Create(ctx, &model.Pack{
+		Name:    "Hexxit",
+		LogoKey: "packs/hexxit/logo/launcher.png",
+		LogoMD5: "a0a7f1b5ec0a3a1e8c3c4ab4b3e1dd5e",
+	})
+	must(t, err)
+
+	for _, key := range images.Keys(pack.LogoKey)[:2] {
+		_, err := raw.Put(ctx, key, strings.NewReader("logo"), -1, "image/png")
+		must(t, err)
+	}
+
+	variants := make(map[string]string)
+
+	report, err = Verify(ctx, s, raw, false, func(result *Result) {
+		if result.Kind == KindImage {
+			variants[path.Base(result.Key)] = result.State
+		}
+	})
+	must(t, err)
+
+	for name, state := range map[string]string{
+		"original.png": StateOK,
+		"launcher.png": StateAltered,
+		"small.png":    StateMissing,
+	} {
+		if variants[name] != state {
+			t.Errorf("expected %s to be %s, got %s", name, state, variants[name])
+		}
+	}
+
+	if report.Total != 8 || report.Damaged() != 5 {
+		t.Fatalf("expected 5 damaged of 8 files, got %d of %d", report.Damaged(), report.Total)
+	}
+}
+
+// must fails the test on any unexpected error.
+func must(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}